@@ -46,6 +46,13 @@ The component label for the tenant controller.
 app.kubernetes.io/component: tenant-controller
 {{- end -}}
 
+{{/*
+The component label for the admission webhook.
+*/}}
+{{- define "steward.webhook.componentLabel" -}}
+app.kubernetes.io/component: webhook
+{{- end -}}
+
 {{/*
 The additional labels for the service monitors.
 */}}
@@ -2,6 +2,9 @@
 Expands to a complete hook spec that updates a custom resource definition
 from the `crds` directory.
 
+If the webhook is enabled, the custom resource definition is configured to
+use it for conversion between API versions and all API versions are served.
+
 Expects dot to be a list with two entries:
 
 1. the original dot providing .Values and so on
@@ -10,6 +13,18 @@ Expects dot to be a list with two entries:
 {{- define "steward.hooks.crd-update" }}
 {{- $crdName := first ( slice . 1 ) }}
 {{- with first . -}}
+{{- $crd := .Files.Get ( printf "crds/%s.yaml" $crdName ) | fromYaml }}
+{{- if .Values.webhook.enabled }}
+{{- /* the webhook converts between the API versions, so all can be served */}}
+{{- range $crd.spec.versions }}
+{{- $_ := set . "served" true }}
+{{- end }}
+{{- $service := dict "namespace" .Values.targetNamespace.name "name" "steward-webhook" "path" "/convert" "port" 443 }}
+{{- $caBundle := required "webhook.tls.caBundle is required if the webhook is enabled" .Values.webhook.tls.caBundle }}
+{{- $clientConfig := dict "service" $service "caBundle" $caBundle }}
+{{- $webhook := dict "clientConfig" $clientConfig "conversionReviewVersions" ( list "v1" ) }}
+{{- $_ := set $crd.spec "conversion" ( dict "strategy" "Webhook" "webhook" $webhook ) }}
+{{- end }}
 
 apiVersion: v1
 kind: ServiceAccount
@@ -79,7 +94,7 @@ spec:
         {{- end }}
         env:
         - name: CRD_SPEC
-          value: {{ .Files.Get ( printf "crds/%s.yaml" $crdName ) | quote }}
+          value: {{ toYaml $crd | quote }}
         command:
         - "bin/sh"
         - "-c"
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/SAP/stewardci-core/pkg/k8s"
+	cachedsecretprovider "github.com/SAP/stewardci-core/pkg/k8s/secrets/providers/cached"
 	"github.com/SAP/stewardci-core/pkg/metrics"
 	"github.com/SAP/stewardci-core/pkg/runctl"
 	"github.com/SAP/stewardci-core/pkg/signals"
@@ -23,6 +24,10 @@ const (
 	// metricsPort is the TCP port number to be used by the metrics
 	// HTTP server.
 	metricsPort = 9090
+
+	// secretCacheIdleTimeout is the duration after which the secret cache
+	// of a tenant namespace is dropped if not used.
+	secretCacheIdleTimeout = 1 * time.Hour
 )
 
 var (
@@ -34,6 +39,10 @@ var (
 	heartbeatLogLevel int
 
 	k8sAPIRequestTimeout time.Duration
+
+	disableTekton bool
+
+	enableSecretCache bool
 )
 
 func init() {
@@ -88,6 +97,18 @@ func init() {
 		15*time.Minute,
 		"The maximum length of time to wait before giving up on a server request. A value of zero means no timeout.",
 	)
+	flag.BoolVar(
+		&disableTekton,
+		"disable-tekton",
+		false,
+		"Whether Tekton is unavailable in the cluster. If true, only the pod run backend can be used.",
+	)
+	flag.BoolVar(
+		&enableSecretCache,
+		"secret-cache",
+		false,
+		"Whether secrets in tenant namespaces should be read from informer caches instead of fetching them for each pipeline run.",
+	)
 
 	flag.Parse()
 }
@@ -123,23 +144,31 @@ func main() {
 	klog.V(2).Infof("Provide metrics on http://0.0.0.0:%d/metrics", metricsPort)
 	metrics.StartServer(metricsPort)
 
+	klog.V(3).Infof("Create Signal Handlers")
+	stopCh := signals.SetupShutdownSignalHandler()
+	signals.SetupThreadDumpSignalHandler()
+
 	klog.V(3).Infof("Create Controller")
 	controllerOpts := runctl.ControllerOpts{
 		HeartbeatInterval: heartbeatInterval,
+		TektonDisabled:    disableTekton,
 	}
 	if heartbeatLogging {
 		tmp := klog.Level(heartbeatLogLevel)
 		controllerOpts.HeartbeatLogLevel = &tmp
 	}
+	if enableSecretCache {
+		klog.V(3).Infof("Create secret cache (idle timeout: %s)", secretCacheIdleTimeout.String())
+		controllerOpts.SecretCache = cachedsecretprovider.NewSecretCache(factory.CoreV1(), secretCacheIdleTimeout, stopCh)
+	}
 	controller := runctl.NewController(factory, controllerOpts)
 
-	klog.V(3).Infof("Create Signal Handlers")
-	stopCh := signals.SetupShutdownSignalHandler()
-	signals.SetupThreadDumpSignalHandler()
-
 	klog.V(2).Infof("Start Informer")
 	factory.StewardInformerFactory().Start(stopCh)
-	factory.TektonInformerFactory().Start(stopCh)
+	factory.KubernetesInformerFactory().Start(stopCh)
+	if !disableTekton {
+		factory.TektonInformerFactory().Start(stopCh)
+	}
 
 	klog.V(2).Infof("Run controller (threadiness=%d)", threadiness)
 	if err = controller.Run(threadiness, stopCh); err != nil {
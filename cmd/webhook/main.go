@@ -0,0 +1,138 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/SAP/stewardci-core/pkg/metrics"
+	"github.com/SAP/stewardci-core/pkg/webhook"
+	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	klog "k8s.io/klog/v2"
+	"knative.dev/pkg/system"
+)
+
+const (
+	// resyncPeriod is the period between full resyncs of informers.
+	// The webhook does not use informers, but the client factory
+	// requires a value.
+	resyncPeriod = 1 * time.Minute
+
+	// metricsPort is the TCP port number to be used by the metrics
+	// HTTP server.
+	metricsPort = 9090
+)
+
+var (
+	kubeconfig  string
+	burst, qps  int
+	port        int
+	tlsCertFile string
+	tlsKeyFile  string
+
+	k8sAPIRequestTimeout time.Duration
+)
+
+func init() {
+	klog.InitFlags(nil)
+
+	flag.StringVar(
+		&kubeconfig,
+		"kubeconfig",
+		"",
+		"The path to a kubeconfig file configuring access to the Kubernetes cluster."+
+			" If not specified or empty, assume running in-cluster.",
+	)
+	flag.IntVar(
+		&qps,
+		"qps",
+		5,
+		"The queries per seconds (QPS) for Kubernetes API client-side rate limiting.",
+	)
+	flag.IntVar(
+		&burst,
+		"burst",
+		10,
+		"The size of the burst bucket for Kubernetes API client-side rate limiting.",
+	)
+	flag.IntVar(
+		&port,
+		"port",
+		8443,
+		"The TCP port number the webhook HTTPS server listens on.",
+	)
+	flag.StringVar(
+		&tlsCertFile,
+		"tls-cert-file",
+		"/etc/webhook/tls/tls.crt",
+		"The path to the file containing the TLS server certificate.",
+	)
+	flag.StringVar(
+		&tlsKeyFile,
+		"tls-key-file",
+		"/etc/webhook/tls/tls.key",
+		"The path to the file containing the private key of the TLS server certificate.",
+	)
+	flag.DurationVar(
+		&k8sAPIRequestTimeout,
+		"k8s-api-request-timeout",
+		10*time.Second,
+		"The maximum length of time to wait before giving up on a server request. A value of zero means no timeout.",
+	)
+
+	flag.Parse()
+}
+
+func main() {
+	defer klog.Flush()
+
+	system.Namespace() // ensure that namespace is set in environment
+
+	var config *rest.Config
+	var err error
+
+	if kubeconfig == "" {
+		klog.Infof("In cluster")
+		config, err = rest.InClusterConfig()
+		if err != nil {
+			klog.Exitf("failed to load kubeconfig: %s; Hint: You can use parameter '-kubeconfig' for local testing", err.Error())
+		}
+	} else {
+		klog.Infof("Outside cluster")
+		config, err = clientcmd.BuildConfigFromFlags("", kubeconfig)
+		if err != nil {
+			klog.Exitln(err.Error())
+		}
+	}
+
+	klog.V(3).Infof("Create Factory (QPS: %d, burst: %d, k8s-api-request-timeout: %s)", qps, burst, k8sAPIRequestTimeout.String())
+	config.QPS = float32(qps)
+	config.Burst = burst
+	config.Timeout = k8sAPIRequestTimeout
+	factory := k8s.NewClientFactory(config, resyncPeriod)
+
+	klog.V(2).Infof("Provide metrics on http://0.0.0.0:%d/metrics", metricsPort)
+	metrics.StartServer(metricsPort)
+
+	mux := http.NewServeMux()
+	admissionWebhook := webhook.NewWebhook(factory)
+	mux.Handle("/validate", admissionWebhook)
+	mux.Handle("/mutate", admissionWebhook.MutatingHandler())
+	mux.Handle(webhook.ConversionPath, admissionWebhook.ConversionHandler())
+	mux.HandleFunc("/healthz", func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	})
+	server := &http.Server{
+		Addr:    fmt.Sprintf(":%d", port),
+		Handler: mux,
+	}
+
+	klog.V(2).Infof("Serve admission reviews on https://0.0.0.0:%d/validate and https://0.0.0.0:%d/mutate", port, port)
+	if err = server.ListenAndServeTLS(tlsCertFile, tlsKeyFile); err != nil {
+		klog.Fatalf("Error running webhook server: %s", err.Error())
+	}
+}
@@ -5,7 +5,7 @@ go 1.16
 require (
 	github.com/benbjohnson/clock v1.3.0
 	github.com/davecgh/go-spew v1.1.1
-	github.com/evanphx/json-patch v5.6.0+incompatible // indirect
+	github.com/evanphx/json-patch v5.6.0+incompatible
 	github.com/evanphx/json-patch/v5 v5.6.0 // indirect
 	github.com/ghodss/yaml v1.0.0
 	github.com/go-kit/log v0.2.0 // indirect
@@ -22,9 +22,11 @@ require (
 	github.com/prometheus/statsd_exporter v0.22.4 // indirect
 	github.com/tektoncd/pipeline v0.34.0
 	go.uber.org/zap v1.19.1
+	golang.org/x/crypto v0.0.0-20220214200702-86341886e292
 	gopkg.in/yaml.v2 v2.4.0
 	gotest.tools v2.2.0+incompatible
 	k8s.io/api v0.23.4
+	k8s.io/apiextensions-apiserver v0.23.4
 	k8s.io/apimachinery v0.23.4
 	k8s.io/client-go v1.5.2
 	k8s.io/klog/v2 v2.40.1
@@ -212,6 +212,7 @@ if ! is_verify_mode; then
             "${PROJECT_ROOT}/pkg/client" \
             "${PROJECT_ROOT}/pkg/tektonclient" \
             "${PROJECT_ROOT}/pkg/apis/steward/v1alpha1/zz_generated.deepcopy.go" \
+            "${PROJECT_ROOT}/pkg/apis/steward/v1beta1/zz_generated.deepcopy.go" \
             || die "Cleanup failed"
         { set +x; } 2>/dev/null
     fi
@@ -236,7 +237,7 @@ if is_generate_clients; then
         all \
         github.com/SAP/stewardci-core/pkg/client \
         github.com/SAP/stewardci-core/pkg/apis \
-        steward:v1alpha1 \
+        steward:v1alpha1,v1beta1 \
         --go-header-file "${PROJECT_ROOT}/hack/boilerplate.go.txt" \
         --output-base "${GEN_DIR}" \
         || die "Code generation failed"
@@ -257,6 +258,7 @@ if is_generate_clients; then
         diff -Naupr ${GEN_DIR}/github.com/SAP/stewardci-core/pkg/client/ ${PROJECT_ROOT}/pkg/client/ || die "Regeneration required for clients"
         diff -Naupr ${GEN_DIR}/github.com/SAP/stewardci-core/pkg/tektonclient/ ${PROJECT_ROOT}/pkg/tektonclient/ || die "Regeneration required for tektonclients"
         diff -Naupr ${GEN_DIR}/github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1/zz_generated.deepcopy.go ${PROJECT_ROOT}/pkg/apis/steward/v1alpha1/zz_generated.deepcopy.go || die "Regeneration required for apis"
+        diff -Naupr ${GEN_DIR}/github.com/SAP/stewardci-core/pkg/apis/steward/v1beta1/zz_generated.deepcopy.go ${PROJECT_ROOT}/pkg/apis/steward/v1beta1/zz_generated.deepcopy.go || die "Regeneration required for apis"
         { set +x; } 2>/dev/null
     else
         echo "## Move generated files ###########################"
@@ -21,10 +21,90 @@ const (
 	// default service account of a tenant namespace.
 	AnnotationTenantRole = steward.GroupName + "/tenant-role"
 
+	// AnnotationMaxActivePipelineRunsPerTenant is the key of the annotation
+	// of a Steward client namespace defining the maximum number of
+	// concurrently active pipeline runs in each tenant namespace belonging
+	// to this client. It overrides the value configured for the whole
+	// system. A value of zero means unlimited.
+	AnnotationMaxActivePipelineRunsPerTenant = steward.GroupName + "/max-active-pipeline-runs-per-tenant"
+
+	// AnnotationAllowedResourceProfiles is the key of the annotation of a
+	// Steward client namespace defining a comma-separated list of the
+	// resource profiles pipeline runs in tenant namespaces belonging to
+	// this client may use. If the annotation is not set, all resource
+	// profiles may be used. If a tenant restricts the resource profiles
+	// via `spec.pipelineRuns.allowedProfiles.resources` too, only profiles
+	// allowed by both may be used.
+	AnnotationAllowedResourceProfiles = steward.GroupName + "/allowed-resource-profiles"
+
+	// AnnotationSecretProvider is the key of the annotation of a Steward
+	// client namespace selecting the provider of the secrets referenced by
+	// pipeline runs in tenant namespaces belonging to this client. It is
+	// one of `SecretProviderKubernetes` (default), `SecretProviderVault` and
+	// `SecretProviderEncrypted`.
+	AnnotationSecretProvider = steward.GroupName + "/secret-provider"
+
+	// AnnotationVaultTokenSecret is the key of the annotation of a Steward
+	// client namespace defining the name of a secret in the client
+	// namespace whose key `token` holds the Vault token to be used by the
+	// Vault secret provider. If not set, the Vault secret provider logs in
+	// via the Kubernetes auth method configured for the system.
+	AnnotationVaultTokenSecret = steward.GroupName + "/vault-token-secret"
+
+	// AnnotationSecretType is the key of the annotation of an encrypted
+	// secret defining the type of the decrypted secret. If not set, the
+	// decrypted secret is of type `Opaque`.
+	AnnotationSecretType = steward.GroupName + "/secret-type"
+
 	// AnnotationSecretRename is the key of the annotation used to rename a secret.
 	// If this annotation is set on a secret it will be created in the run namespace
 	// with this name if it is listed in the pipelineRuns spec.secrets list.
 	AnnotationSecretRename = steward.GroupName + "/secret-rename-to"
+
+	// AnnotationAbortRequestedBy is the key of the annotation of a pipeline
+	// run holding the name of the user who set the intent to `abort`.
+	// It is set by the admission webhook. If the webhook is disabled, the
+	// requester is reported as unknown.
+	AnnotationAbortRequestedBy = steward.GroupName + "/abort-requested-by"
+
+	// AnnotationForceDeletion is the key of the annotation of a tenant
+	// which, if set to `true`, lets the tenant controller delete the tenant
+	// namespace even if pipeline runs in it are still active.
+	// It is meant to be set by operators only.
+	AnnotationForceDeletion = steward.GroupName + "/force-deletion"
+
+	// AnnotationAdoptNamespace is the key of the annotation of a tenant
+	// defining the name of an existing namespace to be used as tenant
+	// namespace instead of creating a new one. The annotation is only
+	// evaluated when the tenant gets initialized.
+	AnnotationAdoptNamespace = steward.GroupName + "/adopt-namespace"
+
+	// AnnotationAdoptedNamespaceOriginalMetadata is the key of the
+	// annotation of an adopted tenant namespace holding the values the
+	// labels and annotations configured for tenant namespaces had before
+	// the adoption (JSON). It is used to restore them if the initialization
+	// of the tenant fails.
+	AnnotationAdoptedNamespaceOriginalMetadata = steward.GroupName + "/adopted-namespace-original-metadata"
+
+	// AnnotationDeletionDue is the key of the annotation of a tenant
+	// namespace pending deletion holding the point in time (RFC 3339) after
+	// which the namespace gets deleted.
+	AnnotationDeletionDue = steward.GroupName + "/deletion-due"
+)
+
+// secret providers selectable via AnnotationSecretProvider
+const (
+	// SecretProviderKubernetes provides the secrets stored in the tenant
+	// namespace.
+	SecretProviderKubernetes = "kubernetes"
+
+	// SecretProviderVault provides the secrets stored in a Vault KV
+	// version 2 secrets engine.
+	SecretProviderVault = "vault"
+
+	// SecretProviderEncrypted provides the secrets stored encrypted in
+	// config maps in the tenant namespace.
+	SecretProviderEncrypted = "encrypted"
 )
 
 // labels
@@ -40,6 +120,16 @@ const (
 	// The value of the label is ignored and should be empty.
 	LabelIgnore = steward.GroupName + "/ignore"
 
+	// LabelPendingDeletion is the key of the label whose presence indicates
+	// that this tenant namespace belongs to a deleted tenant and will be
+	// deleted when the retention period is over.
+	// The value of the label is ignored and should be empty.
+	LabelPendingDeletion = steward.GroupName + "/pending-deletion"
+
+	// LabelEncryptedSecret is the key of the label marking a config map
+	// as encrypted secret to be decrypted by the encrypted secret provider.
+	LabelEncryptedSecret = steward.GroupName + "/encrypted-secret"
+
 	// LabelOwnerClientName is the key of the label that identifies the Steward
 	// _client_ that the labelled object is owned by.
 	// As Steward clients are currently represented by K8s namespaces only,
@@ -83,6 +173,15 @@ const (
 	// faces an intermittent error during running phase.
 	EventReasonRunningFailed = "RunningFailed"
 
+	// EventReasonAbortingFailed is the reason for an event occuring when the run controller
+	// faces an error while stopping the Jenkinsfile Runner of an aborted pipeline run.
+	EventReasonAbortingFailed = "AbortingFailed"
+
+	// EventReasonAborted is the reason for an event occuring when a pipeline
+	// run has been aborted. The message names the user who requested the
+	// abortion.
+	EventReasonAborted = "Aborted"
+
 	// EventReasonCleaningFailed is the reason for a event occuring when the run controller
 	// faces an intermittent error during cleanup phase.
 	EventReasonCleaningFailed = "CleaningFailed"
@@ -95,6 +194,10 @@ const (
 	// run is not started due to maintenance mode
 	EventReasonMaintenanceMode = "MaintenanceMode"
 
+	// EventReasonRetrying is the reason for an event occuring when a failed
+	// pipeline run gets started again according to its retry policy
+	EventReasonRetrying = "Retrying"
+
 	// MaintenanceModeConfigMapName is the name of the config map to enable the maintenance mode
 	MaintenanceModeConfigMapName = "steward-maintenance-mode"
 
@@ -3,6 +3,8 @@ package v1alpha1
 import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	knativeapis "knative.dev/pkg/apis"
+	knativeduck "knative.dev/pkg/apis/duck/v1"
 )
 
 // PipelineRun is a Kubernetes custom resource type representing the execution
@@ -49,6 +51,13 @@ type PipelineSpec struct {
 	// +optional
 	Secrets []string `json:"secrets,omitempty"`
 
+	// SecretRefs is the list of secrets to be made available to the pipeline
+	// execution in addition to Secrets. In contrast to Secrets, each entry
+	// can rename the secret, select and rename its keys and mark it as
+	// optional.
+	// +optional
+	SecretRefs []PipelineSecret `json:"secretRefs,omitempty"`
+
 	// ImagePullSecrets is the list of image pull secrets required by the
 	// pipeline run to pull images of custom containers from private registries.
 	// Each entry in the list is the name of a Kubernetes `v1/Secret` resource
@@ -62,10 +71,16 @@ type PipelineSpec struct {
 	// run to completion, while the value `abort` indicates that the pipeline
 	// processing should be stopped as soon as possible. An empty string value
 	// is equivalent to value `run`.
-	// TODO: Controller should set intent=run explicitely if not set
+	// If not set, it is defaulted to `run` when the pipeline run gets created.
 	// +optional
 	Intent Intent `json:"intent,omitempty"`
 
+	// AbortReason is a human-readable explanation why the pipeline run
+	// should be aborted. It is only evaluated if the intent is `abort`
+	// and becomes part of the status message of the aborted pipeline run.
+	// +optional
+	AbortReason string `json:"abortReason,omitempty"`
+
 	// Logging contains the logging configuration.
 	// +optional
 	Logging *Logging `json:"logging,omitempty"`
@@ -76,6 +91,50 @@ type PipelineSpec struct {
 	RunDetails *PipelineRunDetails `json:"runDetails,omitempty"`
 
 	Profiles *Profiles `json:"profiles,omitempty"`
+
+	// Timeout is the maximum execution time of the pipeline run.
+	// If not set, the default timeout configured for the system is used.
+	// It must not exceed the maximum timeout configured for the system.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// RetryPolicy defines whether and how the pipeline run gets retried
+	// if it fails.
+	// If not set, the pipeline run is not retried.
+	// +optional
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
+
+	// TTLSecondsAfterFinished is the number of seconds a finished pipeline
+	// run is kept before it gets deleted automatically.
+	// If not set, the default configured for the system is used.
+	// +optional
+	TTLSecondsAfterFinished *int64 `json:"ttlSecondsAfterFinished,omitempty"`
+
+	// Sidecars is the list of containers to run next to the Jenkinsfile
+	// Runner. Only the Tekton run backend supports sidecars.
+	// +optional
+	Sidecars []Sidecar `json:"sidecars,omitempty"`
+}
+
+// RetryPolicy defines the automatic retry of failed pipeline runs.
+// Each retry is executed in a new sandbox.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts including the first
+	// one. Values less than 2 disable retrying.
+	// +optional
+	MaxAttempts int32 `json:"maxAttempts,omitempty"`
+
+	// Backoff is the time to wait after a failed attempt before the next
+	// attempt gets started.
+	// If not set, the next attempt is started immediately.
+	// +optional
+	Backoff *metav1.Duration `json:"backoff,omitempty"`
+
+	// RetryOn is the list of results that cause a retry. Only
+	// `error_infra` and `timeout` are allowed.
+	// If empty, only `error_infra` is retried.
+	// +optional
+	RetryOn []Result `json:"retryOn,omitempty"`
 }
 
 // JenkinsfileRunnerSpec carries configuration options for the Jenkinsfile Runner container.
@@ -85,6 +144,74 @@ type JenkinsfileRunnerSpec struct {
 
 	// ImagePullPolicy is the pull policy for the image
 	ImagePullPolicy string `json:"imagePullPolicy,omitempty"`
+
+	// Resources are the compute resource requirements of the Jenkinsfile
+	// Runner container. They are merged into the defaults of the pipeline
+	// runs configuration and must be within the configured bounds.
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// PipelineSecret refers to a secret to be made available to the pipeline
+// execution.
+type PipelineSecret struct {
+	// Name is the name of the secret to be copied. It must be a valid
+	// secret name (DNS-1123 subdomain).
+	Name string `json:"name"`
+
+	// TargetName is the name of the secret in the pipeline execution.
+	// If empty, the name given by annotation `steward.sap.com/secret-rename-to`
+	// of the secret is used, or otherwise `Name`.
+	// +optional
+	TargetName string `json:"targetName,omitempty"`
+
+	// Keys is the list of keys of the secret to be copied. If empty, all
+	// keys are copied. Each key and each target key may only be listed once.
+	// +optional
+	Keys []PipelineSecretKey `json:"keys,omitempty"`
+
+	// Optional defines whether the pipeline run can be executed if the
+	// secret or some of the listed keys do not exist.
+	// +optional
+	Optional bool `json:"optional,omitempty"`
+}
+
+// PipelineSecretKey is a key of a secret to be made available to the
+// pipeline execution.
+type PipelineSecretKey struct {
+	// Key is the key in the secret.
+	Key string `json:"key"`
+
+	// TargetKey is the key in the secret in the pipeline execution.
+	// If empty, `Key` is used.
+	// +optional
+	TargetKey string `json:"targetKey,omitempty"`
+}
+
+// Sidecar is a container running next to the Jenkinsfile Runner for the
+// whole pipeline run, e.g. a database or a browser for integration tests.
+// It shares the network namespace with the Jenkinsfile Runner, i.e. its
+// ports are reachable via `localhost`.
+type Sidecar struct {
+	// Name is the name of the sidecar container. It must be a DNS label
+	// and unique within the pipeline run.
+	Name string `json:"name"`
+
+	// Image is the image name including the tag or digest. It must be
+	// permitted by the pipeline runs configuration.
+	Image string `json:"image"`
+
+	// Env is the list of environment variables to set in the container.
+	// +optional
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// Ports is the list of ports the container listens on.
+	// +optional
+	Ports []corev1.ContainerPort `json:"ports,omitempty"`
+
+	// Resources are the compute resources required by the container.
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
 }
 
 // JenkinsFile represents the location from where to get the pipeline
@@ -99,7 +226,10 @@ type JenkinsFile struct {
 	Revision string `json:"revision"`
 
 	// Path is the relative pathname of the pipeline definition file in the
-	// repository check-out, typically `Jenkinsfile`.
+	// repository check-out.
+	// If not set, it is defaulted to `Jenkinsfile` when the pipeline run
+	// gets created.
+	// +optional
 	Path string `json:"relativePath"`
 
 	// RepoAuthSecret is the name of the Kubernetes `v1/Secret` resource object
@@ -145,6 +275,9 @@ type Elasticsearch struct {
 
 // PipelineStatus represents the status of the pipeline
 type PipelineStatus struct {
+	// Status contains the conditions of the pipeline run. The conditions
+	// are derived from the other status fields.
+	knativeduck.Status `json:",inline"`
 
 	// StartedAt is the time the pipeline run has been started.
 	// +optional
@@ -164,6 +297,123 @@ type PipelineStatus struct {
 	History            []string              `json:"history"`
 	Namespace          string                `json:"namespace"`
 	AuxiliaryNamespace string                `json:"auxiliaryNamespace"`
+
+	// RunBackend is the name of the backend executing the pipeline run.
+	// It is determined when the pipeline run gets started and does not
+	// change afterwards. Empty means the Tekton backend.
+	// +optional
+	RunBackend string `json:"runBackend,omitempty"`
+
+	// Timeout is the effective maximum execution time of the pipeline run.
+	// It is determined when the pipeline run gets started.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// JenkinsfileRunnerResources are the effective compute resource
+	// requirements of the Jenkinsfile Runner container. They are determined
+	// when the pipeline run gets started.
+	// +optional
+	JenkinsfileRunnerResources *corev1.ResourceRequirements `json:"jenkinsfileRunnerResources,omitempty"`
+
+	// TenantSettings are the effective settings of the pipeline runs
+	// configuration which can be overridden per tenant, i.e. the values
+	// the pipeline run has been started with after applying the settings
+	// of its tenant. They are determined when the pipeline run gets
+	// started.
+	// +optional
+	TenantSettings *TenantPipelineRunsSettings `json:"tenantSettings,omitempty"`
+
+	// AbortRequestedAt is the time the run backend has been requested to
+	// stop the Jenkinsfile Runner of an aborted pipeline run. The namespaces
+	// of the pipeline run are cleaned up once the Jenkinsfile Runner has
+	// terminated or the abort grace period has expired.
+	// +optional
+	AbortRequestedAt *metav1.Time `json:"abortRequestedAt,omitempty"`
+
+	// Attempts is the list of previous attempts of the pipeline run which
+	// have been retried according to the retry policy. The current attempt
+	// is described by the other status fields.
+	// +optional
+	Attempts []Attempt `json:"attempts,omitempty"`
+
+	// Sidecars contains the states of the sidecar containers.
+	// +optional
+	Sidecars []SidecarState `json:"sidecars,omitempty"`
+}
+
+const (
+	// PipelineRunConditionSucceeded is the condition type indicating
+	// whether the pipeline run has finished successfully. It is unknown as
+	// long as the pipeline run has not finished.
+	PipelineRunConditionSucceeded = knativeapis.ConditionSucceeded
+
+	// PipelineRunConditionPrepared is the condition type indicating
+	// whether the sandbox of the pipeline run has been prepared
+	// successfully.
+	PipelineRunConditionPrepared knativeapis.ConditionType = "Prepared"
+
+	// PipelineRunConditionSandboxCleanedUp is the condition type
+	// indicating whether the sandbox of the pipeline run has been
+	// cleaned up.
+	PipelineRunConditionSandboxCleanedUp knativeapis.ConditionType = "SandboxCleanedUp"
+
+	// PipelineRunConditionReasonCleanupFailed is the reason of condition
+	// `SandboxCleanedUp` being false because the last attempt to clean up
+	// the sandbox has failed.
+	PipelineRunConditionReasonCleanupFailed = "CleanupFailed"
+)
+
+var pipelineRunConditionSet = knativeapis.NewBatchConditionSet(
+	PipelineRunConditionPrepared,
+	PipelineRunConditionSandboxCleanedUp,
+)
+
+// GetCondition returns the condition matching the given condition type.
+func (s *PipelineStatus) GetCondition(condType knativeapis.ConditionType) *knativeapis.Condition {
+	return pipelineRunConditionSet.Manage(s).GetCondition(condType)
+}
+
+// SetCondition sets the given condition.
+func (s *PipelineStatus) SetCondition(cond *knativeapis.Condition) {
+	if cond != nil {
+		pipelineRunConditionSet.Manage(s).SetCondition(*cond)
+	}
+}
+
+// SidecarState is the state of a sidecar container.
+type SidecarState struct {
+	// Name is the name of the sidecar.
+	Name string `json:"name"`
+
+	// Container is the state of the sidecar container.
+	// +optional
+	Container corev1.ContainerState `json:"container,omitempty"`
+}
+
+// Attempt describes a finished attempt to execute a pipeline run.
+type Attempt struct {
+	// StartedAt is the time the attempt has been started.
+	// +optional
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+
+	// FinishedAt is the time the attempt has been finished.
+	// +optional
+	FinishedAt *metav1.Time `json:"finishedAt,omitempty"`
+
+	// Result is the result of the attempt.
+	Result Result `json:"result"`
+
+	// Message is the message describing the result of the attempt.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Namespace is the run namespace the attempt was executed in.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// AuxiliaryNamespace is the auxiliary namespace of the attempt.
+	// +optional
+	AuxiliaryNamespace string `json:"auxiliaryNamespace,omitempty"`
 }
 
 // StateItem holds start and end time of a state in the history
@@ -181,6 +431,9 @@ const (
 	StateUndefined State = ""
 	// StateNew - pipeline run is first checked by the controller
 	StateNew State = "new"
+	// StateQueued - the pipeline run waits until it may be started without
+	// exceeding the limits of concurrently active pipeline runs
+	StateQueued State = "queued"
 	// StatePreparing - the namespace for the execution is prepared
 	StatePreparing State = "preparing"
 	// StateWaiting - the pipeline run is waiting to be processed
@@ -256,4 +509,15 @@ type Profiles struct {
 	// are allowed. The scope of the network profile might be extended in the future.
 	// If empty, a default profile will be used.
 	Network string `json:"network,omitempty"`
+
+	// Scheduling selects the scheduling profile. It determines the nodes
+	// the pods of the pipeline run are scheduled to and their priority.
+	// If empty, a default profile will be used, if configured.
+	Scheduling string `json:"scheduling,omitempty"`
+
+	// Resources selects the resource profile. It determines the limit
+	// range and resource quota of the sandbox namespace and the default
+	// resource requirements of the Jenkinsfile Runner.
+	// If empty, a default profile will be used, if configured.
+	Resources string `json:"resources,omitempty"`
 }
@@ -8,4 +8,9 @@ const (
 	// StatusReasonDependentResourceState indicates that the reason for the
 	// status is the state of another resource controlled by this resource.
 	StatusReasonDependentResourceState = "InvalidDependentResource"
+
+	// StatusReasonActivePipelineRuns indicates that the reason for the
+	// status is pipeline runs being still active, e.g. blocking the
+	// deletion of a tenant.
+	StatusReasonActivePipelineRuns = "ActivePipelineRuns"
 )
@@ -14,6 +14,8 @@ type Tenant struct {
 	// +optional
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 	// +optional
+	Spec TenantSpec `json:"spec,omitempty"`
+	// +optional
 	Status TenantStatus `json:"status"`
 }
 
@@ -26,6 +28,62 @@ type TenantList struct {
 	Items           []Tenant `json:"items"`
 }
 
+// TenantSpec is the spec of a Tenant.
+type TenantSpec struct {
+	// PipelineRuns overrides settings of the pipeline runs configuration
+	// for the pipeline runs in the tenant namespace.
+	PipelineRuns *TenantPipelineRunsSettings `json:"pipelineRuns,omitempty"`
+}
+
+// TenantPipelineRunsSettings are settings of the pipeline runs
+// configuration which can be overridden per tenant.
+type TenantPipelineRunsSettings struct {
+	// DefaultNetworkProfile is the name of the network profile used for
+	// pipeline runs not selecting one.
+	// If empty, the default network profile of the pipeline runs
+	// configuration is used.
+	DefaultNetworkProfile string `json:"defaultNetworkProfile,omitempty"`
+
+	// AllowedProfiles restricts the profiles pipeline runs may use.
+	// If `nil`, all profiles may be used.
+	AllowedProfiles *AllowedProfiles `json:"allowedProfiles,omitempty"`
+
+	// MaxTimeout is the maximum timeout pipeline runs may request. It can
+	// only lower the maximum timeout of the pipeline runs configuration.
+	MaxTimeout *metav1.Duration `json:"maxTimeout,omitempty"`
+
+	// MaxActivePipelineRuns is the maximum number of concurrently active
+	// pipeline runs in the tenant namespace. It can only lower the limit
+	// of the pipeline runs configuration and the client namespace.
+	// Zero means unlimited.
+	MaxActivePipelineRuns *int64 `json:"maxActivePipelineRuns,omitempty"`
+
+	// JenkinsfileRunnerImage is the Jenkinsfile Runner image used for
+	// pipeline runs not specifying one.
+	// If empty, the image of the pipeline runs configuration is used.
+	JenkinsfileRunnerImage string `json:"jenkinsfileRunnerImage,omitempty"`
+
+	// JenkinsfileRunnerImagePullPolicy is the pull policy for
+	// `JenkinsfileRunnerImage`. It defaults to `IfNotPresent`.
+	JenkinsfileRunnerImagePullPolicy string `json:"jenkinsfileRunnerImagePullPolicy,omitempty"`
+}
+
+// AllowedProfiles lists the profiles pipeline runs may use per aspect.
+// An empty list means that all profiles of this aspect may be used.
+type AllowedProfiles struct {
+	// Network lists the allowed network profiles.
+	Network []string `json:"network,omitempty"`
+
+	// Scheduling lists the allowed scheduling profiles.
+	Scheduling []string `json:"scheduling,omitempty"`
+
+	// Resources lists the allowed resource profiles. If the client
+	// namespace restricts the resource profiles via annotation
+	// `steward.sap.com/allowed-resource-profiles` too, only profiles
+	// allowed by both may be used.
+	Resources []string `json:"resources,omitempty"`
+}
+
 // TenantStatus contains the status of a Tenant
 type TenantStatus struct {
 	knativeduck.Status `json:",inline"`
@@ -0,0 +1,490 @@
+package v1beta1
+
+import (
+	"encoding/json"
+
+	"github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Objects are stored as v1alpha1. The conversion functions below convert
+// between the storage version and this version. The conversion must be
+// lossless in both directions, as clients may read and write objects in
+// either version.
+
+// ConvertTo converts the receiver into `dst`.
+func (r *PipelineRun) ConvertTo(dst *v1alpha1.PipelineRun) error {
+	dst.ObjectMeta = *r.ObjectMeta.DeepCopy()
+	dst.TypeMeta = metav1.TypeMeta{
+		APIVersion: v1alpha1.SchemeGroupVersion.String(),
+		Kind:       "PipelineRun",
+	}
+
+	spec := r.Spec.DeepCopy()
+	dst.Spec = v1alpha1.PipelineSpec{
+		Args:                    spec.Args,
+		Secrets:                 spec.Secrets,
+		ImagePullSecrets:        spec.ImagePullSecrets,
+		Intent:                  v1alpha1.Intent(spec.Intent),
+		AbortReason:             spec.AbortReason,
+		Timeout:                 spec.Timeout,
+		TTLSecondsAfterFinished: spec.TTLSecondsAfterFinished,
+		JenkinsFile: v1alpha1.JenkinsFile{
+			URL:            spec.JenkinsFile.URL,
+			Revision:       spec.JenkinsFile.Revision,
+			Path:           spec.JenkinsFile.Path,
+			RepoAuthSecret: spec.JenkinsFile.RepoAuthSecret,
+		},
+	}
+	if spec.JenkinsfileRunner != nil {
+		dst.Spec.JenkinsfileRunner = &v1alpha1.JenkinsfileRunnerSpec{
+			Image:           spec.JenkinsfileRunner.Image,
+			ImagePullPolicy: spec.JenkinsfileRunner.ImagePullPolicy,
+			Resources:       spec.JenkinsfileRunner.Resources,
+		}
+	}
+	if spec.Logging != nil {
+		dst.Spec.Logging = &v1alpha1.Logging{}
+		if es := spec.Logging.Elasticsearch; es != nil {
+			runID, err := toCustomJSON(es.RunID)
+			if err != nil {
+				return err
+			}
+			dst.Spec.Logging.Elasticsearch = &v1alpha1.Elasticsearch{
+				RunID:      runID,
+				IndexURL:   es.IndexURL,
+				AuthSecret: es.AuthSecret,
+			}
+		}
+	}
+	if spec.RunDetails != nil {
+		dst.Spec.RunDetails = &v1alpha1.PipelineRunDetails{
+			JobName:        spec.RunDetails.JobName,
+			SequenceNumber: spec.RunDetails.SequenceNumber,
+			Cause:          spec.RunDetails.Cause,
+		}
+	}
+	if spec.Profiles != nil {
+		dst.Spec.Profiles = &v1alpha1.Profiles{
+			Network:    spec.Profiles.Network,
+			Scheduling: spec.Profiles.Scheduling,
+			Resources:  spec.Profiles.Resources,
+		}
+	}
+	if spec.RetryPolicy != nil {
+		dst.Spec.RetryPolicy = &v1alpha1.RetryPolicy{
+			MaxAttempts: spec.RetryPolicy.MaxAttempts,
+			Backoff:     spec.RetryPolicy.Backoff,
+		}
+		for _, result := range spec.RetryPolicy.RetryOn {
+			dst.Spec.RetryPolicy.RetryOn = append(dst.Spec.RetryPolicy.RetryOn, v1alpha1.Result(result))
+		}
+	}
+	for _, secret := range spec.SecretRefs {
+		converted := v1alpha1.PipelineSecret{
+			Name:       secret.Name,
+			TargetName: secret.TargetName,
+			Optional:   secret.Optional,
+		}
+		for _, key := range secret.Keys {
+			converted.Keys = append(converted.Keys, v1alpha1.PipelineSecretKey(key))
+		}
+		dst.Spec.SecretRefs = append(dst.Spec.SecretRefs, converted)
+	}
+	for _, sidecar := range spec.Sidecars {
+		dst.Spec.Sidecars = append(dst.Spec.Sidecars, v1alpha1.Sidecar{
+			Name:      sidecar.Name,
+			Image:     sidecar.Image,
+			Env:       sidecar.Env,
+			Ports:     sidecar.Ports,
+			Resources: sidecar.Resources,
+		})
+	}
+
+	status := r.Status.DeepCopy()
+	dst.Status = v1alpha1.PipelineStatus{
+		Status:                     status.Status,
+		StartedAt:                  status.StartedAt,
+		FinishedAt:                 status.FinishedAt,
+		State:                      v1alpha1.State(status.State),
+		StateDetails:               stateItemToV1alpha1(status.StateDetails),
+		Result:                     v1alpha1.Result(status.Result),
+		Container:                  containerStatusToV1alpha1(status.JenkinsfileRunner),
+		MessageShort:               status.MessageShort,
+		Message:                    status.Message,
+		Namespace:                  status.Namespace,
+		AuxiliaryNamespace:         status.AuxiliaryNamespace,
+		RunBackend:                 status.RunBackend,
+		Timeout:                    status.Timeout,
+		JenkinsfileRunnerResources: status.JenkinsfileRunnerResources,
+		TenantSettings:             tenantPipelineRunsSettingsToV1alpha1(status.TenantSettings),
+		AbortRequestedAt:           status.AbortRequestedAt,
+	}
+	for _, item := range status.StateHistory {
+		dst.Status.StateHistory = append(dst.Status.StateHistory, stateItemToV1alpha1(item))
+	}
+	for _, message := range status.MessageHistory {
+		dst.Status.History = append(dst.Status.History, message.Message)
+	}
+	for _, attempt := range status.Attempts {
+		dst.Status.Attempts = append(dst.Status.Attempts, v1alpha1.Attempt{
+			StartedAt:          attempt.StartedAt,
+			FinishedAt:         attempt.FinishedAt,
+			Result:             v1alpha1.Result(attempt.Result),
+			Message:            attempt.Message,
+			Namespace:          attempt.Namespace,
+			AuxiliaryNamespace: attempt.AuxiliaryNamespace,
+		})
+	}
+	for _, sidecar := range status.Sidecars {
+		dst.Status.Sidecars = append(dst.Status.Sidecars, v1alpha1.SidecarState{
+			Name:      sidecar.Name,
+			Container: containerStatusToV1alpha1(sidecar.Container),
+		})
+	}
+	return nil
+}
+
+// ConvertFrom converts `src` into the receiver.
+func (r *PipelineRun) ConvertFrom(src *v1alpha1.PipelineRun) error {
+	r.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	r.TypeMeta = metav1.TypeMeta{
+		APIVersion: SchemeGroupVersion.String(),
+		Kind:       "PipelineRun",
+	}
+
+	spec := src.Spec.DeepCopy()
+	r.Spec = PipelineRunSpec{
+		Args:                    spec.Args,
+		Secrets:                 spec.Secrets,
+		ImagePullSecrets:        spec.ImagePullSecrets,
+		Intent:                  Intent(spec.Intent),
+		AbortReason:             spec.AbortReason,
+		Timeout:                 spec.Timeout,
+		TTLSecondsAfterFinished: spec.TTLSecondsAfterFinished,
+		JenkinsFile: JenkinsFile{
+			URL:            spec.JenkinsFile.URL,
+			Revision:       spec.JenkinsFile.Revision,
+			Path:           spec.JenkinsFile.Path,
+			RepoAuthSecret: spec.JenkinsFile.RepoAuthSecret,
+		},
+	}
+	if spec.JenkinsfileRunner != nil {
+		r.Spec.JenkinsfileRunner = &JenkinsfileRunnerSpec{
+			Image:           spec.JenkinsfileRunner.Image,
+			ImagePullPolicy: spec.JenkinsfileRunner.ImagePullPolicy,
+			Resources:       spec.JenkinsfileRunner.Resources,
+		}
+	}
+	if spec.Logging != nil {
+		r.Spec.Logging = &Logging{}
+		if es := spec.Logging.Elasticsearch; es != nil {
+			runID, err := fromCustomJSON(es.RunID)
+			if err != nil {
+				return err
+			}
+			r.Spec.Logging.Elasticsearch = &Elasticsearch{
+				RunID:      runID,
+				IndexURL:   es.IndexURL,
+				AuthSecret: es.AuthSecret,
+			}
+		}
+	}
+	if spec.RunDetails != nil {
+		r.Spec.RunDetails = &PipelineRunDetails{
+			JobName:        spec.RunDetails.JobName,
+			SequenceNumber: spec.RunDetails.SequenceNumber,
+			Cause:          spec.RunDetails.Cause,
+		}
+	}
+	if spec.Profiles != nil {
+		r.Spec.Profiles = &Profiles{
+			Network:    spec.Profiles.Network,
+			Scheduling: spec.Profiles.Scheduling,
+			Resources:  spec.Profiles.Resources,
+		}
+	}
+	if spec.RetryPolicy != nil {
+		r.Spec.RetryPolicy = &RetryPolicy{
+			MaxAttempts: spec.RetryPolicy.MaxAttempts,
+			Backoff:     spec.RetryPolicy.Backoff,
+		}
+		for _, result := range spec.RetryPolicy.RetryOn {
+			r.Spec.RetryPolicy.RetryOn = append(r.Spec.RetryPolicy.RetryOn, Result(result))
+		}
+	}
+	for _, secret := range spec.SecretRefs {
+		converted := PipelineSecret{
+			Name:       secret.Name,
+			TargetName: secret.TargetName,
+			Optional:   secret.Optional,
+		}
+		for _, key := range secret.Keys {
+			converted.Keys = append(converted.Keys, PipelineSecretKey(key))
+		}
+		r.Spec.SecretRefs = append(r.Spec.SecretRefs, converted)
+	}
+	for _, sidecar := range spec.Sidecars {
+		r.Spec.Sidecars = append(r.Spec.Sidecars, Sidecar{
+			Name:      sidecar.Name,
+			Image:     sidecar.Image,
+			Env:       sidecar.Env,
+			Ports:     sidecar.Ports,
+			Resources: sidecar.Resources,
+		})
+	}
+
+	status := src.Status.DeepCopy()
+	r.Status = PipelineRunStatus{
+		Status:                     status.Status,
+		StartedAt:                  status.StartedAt,
+		FinishedAt:                 status.FinishedAt,
+		State:                      State(status.State),
+		StateDetails:               stateItemFromV1alpha1(status.StateDetails),
+		Result:                     Result(status.Result),
+		JenkinsfileRunner:          containerStatusFromV1alpha1(status.Container),
+		MessageShort:               status.MessageShort,
+		Message:                    status.Message,
+		Namespace:                  status.Namespace,
+		AuxiliaryNamespace:         status.AuxiliaryNamespace,
+		RunBackend:                 status.RunBackend,
+		Timeout:                    status.Timeout,
+		JenkinsfileRunnerResources: status.JenkinsfileRunnerResources,
+		TenantSettings:             tenantPipelineRunsSettingsFromV1alpha1(status.TenantSettings),
+		AbortRequestedAt:           status.AbortRequestedAt,
+	}
+	for _, item := range status.StateHistory {
+		r.Status.StateHistory = append(r.Status.StateHistory, stateItemFromV1alpha1(item))
+	}
+	for _, message := range status.History {
+		r.Status.MessageHistory = append(r.Status.MessageHistory, HistoricMessage{Message: message})
+	}
+	for _, attempt := range status.Attempts {
+		r.Status.Attempts = append(r.Status.Attempts, Attempt{
+			StartedAt:          attempt.StartedAt,
+			FinishedAt:         attempt.FinishedAt,
+			Result:             Result(attempt.Result),
+			Message:            attempt.Message,
+			Namespace:          attempt.Namespace,
+			AuxiliaryNamespace: attempt.AuxiliaryNamespace,
+		})
+	}
+	for _, sidecar := range status.Sidecars {
+		r.Status.Sidecars = append(r.Status.Sidecars, SidecarStatus{
+			Name:      sidecar.Name,
+			Container: containerStatusFromV1alpha1(sidecar.Container),
+		})
+	}
+	return nil
+}
+
+// ConvertTo converts the receiver into `dst`.
+func (t *Tenant) ConvertTo(dst *v1alpha1.Tenant) error {
+	dst.ObjectMeta = *t.ObjectMeta.DeepCopy()
+	dst.TypeMeta = metav1.TypeMeta{
+		APIVersion: v1alpha1.SchemeGroupVersion.String(),
+		Kind:       "Tenant",
+	}
+	spec := t.Spec.DeepCopy()
+	dst.Spec = v1alpha1.TenantSpec{
+		PipelineRuns: tenantPipelineRunsSettingsToV1alpha1(spec.PipelineRuns),
+	}
+	status := t.Status.DeepCopy()
+	dst.Status = v1alpha1.TenantStatus{
+		Status:              status.Status,
+		TenantNamespaceName: status.TenantNamespaceName,
+	}
+	return nil
+}
+
+// ConvertFrom converts `src` into the receiver.
+func (t *Tenant) ConvertFrom(src *v1alpha1.Tenant) error {
+	t.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	t.TypeMeta = metav1.TypeMeta{
+		APIVersion: SchemeGroupVersion.String(),
+		Kind:       "Tenant",
+	}
+	status := src.Status.DeepCopy()
+	spec := src.Spec.DeepCopy()
+	t.Spec = TenantSpec{
+		PipelineRuns: tenantPipelineRunsSettingsFromV1alpha1(spec.PipelineRuns),
+	}
+	t.Status = TenantStatus{
+		Status:              status.Status,
+		TenantNamespaceName: status.TenantNamespaceName,
+	}
+	return nil
+}
+
+func toCustomJSON(raw *runtime.RawExtension) (*v1alpha1.CustomJSON, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	result := &v1alpha1.CustomJSON{}
+	if len(raw.Raw) == 0 {
+		return result, nil
+	}
+	if err := result.UnmarshalJSON(raw.Raw); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func fromCustomJSON(value *v1alpha1.CustomJSON) (*runtime.RawExtension, error) {
+	if value == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(value.Value)
+	if err != nil {
+		return nil, err
+	}
+	return &runtime.RawExtension{Raw: data}, nil
+}
+
+func stateItemToV1alpha1(item StateItem) v1alpha1.StateItem {
+	result := v1alpha1.StateItem{
+		State:     v1alpha1.State(item.State),
+		StartedAt: item.StartedAt,
+	}
+	if item.FinishedAt != nil {
+		result.FinishedAt = *item.FinishedAt
+	}
+	return result
+}
+
+func stateItemFromV1alpha1(item v1alpha1.StateItem) StateItem {
+	result := StateItem{
+		State:     State(item.State),
+		StartedAt: item.StartedAt,
+	}
+	if !item.FinishedAt.IsZero() {
+		finishedAt := item.FinishedAt
+		result.FinishedAt = &finishedAt
+	}
+	return result
+}
+
+func containerStatusToV1alpha1(status *ContainerStatus) corev1.ContainerState {
+	if status == nil {
+		return corev1.ContainerState{}
+	}
+	switch status.State {
+	case ContainerStateWaiting:
+		return corev1.ContainerState{
+			Waiting: &corev1.ContainerStateWaiting{
+				Reason:  status.Reason,
+				Message: status.Message,
+			},
+		}
+	case ContainerStateRunning:
+		result := corev1.ContainerState{
+			Running: &corev1.ContainerStateRunning{},
+		}
+		if status.StartedAt != nil {
+			result.Running.StartedAt = *status.StartedAt
+		}
+		return result
+	case ContainerStateTerminated:
+		result := corev1.ContainerState{
+			Terminated: &corev1.ContainerStateTerminated{
+				Signal:      status.Signal,
+				Reason:      status.Reason,
+				Message:     status.Message,
+				ContainerID: status.ContainerID,
+			},
+		}
+		if status.ExitCode != nil {
+			result.Terminated.ExitCode = *status.ExitCode
+		}
+		if status.StartedAt != nil {
+			result.Terminated.StartedAt = *status.StartedAt
+		}
+		if status.FinishedAt != nil {
+			result.Terminated.FinishedAt = *status.FinishedAt
+		}
+		return result
+	default:
+		return corev1.ContainerState{}
+	}
+}
+
+func containerStatusFromV1alpha1(state corev1.ContainerState) *ContainerStatus {
+	switch {
+	case state.Terminated != nil:
+		exitCode := state.Terminated.ExitCode
+		return &ContainerStatus{
+			State:       ContainerStateTerminated,
+			Reason:      state.Terminated.Reason,
+			Message:     state.Terminated.Message,
+			ExitCode:    &exitCode,
+			Signal:      state.Terminated.Signal,
+			StartedAt:   timePtr(state.Terminated.StartedAt),
+			FinishedAt:  timePtr(state.Terminated.FinishedAt),
+			ContainerID: state.Terminated.ContainerID,
+		}
+	case state.Running != nil:
+		return &ContainerStatus{
+			State:     ContainerStateRunning,
+			StartedAt: timePtr(state.Running.StartedAt),
+		}
+	case state.Waiting != nil:
+		return &ContainerStatus{
+			State:   ContainerStateWaiting,
+			Reason:  state.Waiting.Reason,
+			Message: state.Waiting.Message,
+		}
+	default:
+		return nil
+	}
+}
+
+func tenantPipelineRunsSettingsToV1alpha1(settings *TenantPipelineRunsSettings) *v1alpha1.TenantPipelineRunsSettings {
+	if settings == nil {
+		return nil
+	}
+	result := &v1alpha1.TenantPipelineRunsSettings{
+		DefaultNetworkProfile:            settings.DefaultNetworkProfile,
+		MaxTimeout:                       settings.MaxTimeout,
+		MaxActivePipelineRuns:            settings.MaxActivePipelineRuns,
+		JenkinsfileRunnerImage:           settings.JenkinsfileRunnerImage,
+		JenkinsfileRunnerImagePullPolicy: settings.JenkinsfileRunnerImagePullPolicy,
+	}
+	if settings.AllowedProfiles != nil {
+		result.AllowedProfiles = &v1alpha1.AllowedProfiles{
+			Network:    settings.AllowedProfiles.Network,
+			Scheduling: settings.AllowedProfiles.Scheduling,
+			Resources:  settings.AllowedProfiles.Resources,
+		}
+	}
+	return result
+}
+
+func tenantPipelineRunsSettingsFromV1alpha1(settings *v1alpha1.TenantPipelineRunsSettings) *TenantPipelineRunsSettings {
+	if settings == nil {
+		return nil
+	}
+	result := &TenantPipelineRunsSettings{
+		DefaultNetworkProfile:            settings.DefaultNetworkProfile,
+		MaxTimeout:                       settings.MaxTimeout,
+		MaxActivePipelineRuns:            settings.MaxActivePipelineRuns,
+		JenkinsfileRunnerImage:           settings.JenkinsfileRunnerImage,
+		JenkinsfileRunnerImagePullPolicy: settings.JenkinsfileRunnerImagePullPolicy,
+	}
+	if settings.AllowedProfiles != nil {
+		result.AllowedProfiles = &AllowedProfiles{
+			Network:    settings.AllowedProfiles.Network,
+			Scheduling: settings.AllowedProfiles.Scheduling,
+			Resources:  settings.AllowedProfiles.Resources,
+		}
+	}
+	return result
+}
+
+func timePtr(t metav1.Time) *metav1.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
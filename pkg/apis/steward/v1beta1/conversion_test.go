@@ -0,0 +1,253 @@
+package v1beta1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	assert "gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	knativeapis "knative.dev/pkg/apis"
+	knativeduck "knative.dev/pkg/apis/duck/v1"
+)
+
+func newV1alpha1PipelineRun() *v1alpha1.PipelineRun {
+	ttl := int64(60)
+	maxActive := int64(3)
+	now := metav1.NewTime(time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC))
+	return &v1alpha1.PipelineRun{
+		TypeMeta: metav1.TypeMeta{APIVersion: "steward.sap.com/v1alpha1", Kind: "PipelineRun"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "run1",
+			Namespace:   "ns1",
+			Labels:      map[string]string{"label1": "value1"},
+			Annotations: map[string]string{"annotation1": "value1"},
+		},
+		Spec: v1alpha1.PipelineSpec{
+			JenkinsfileRunner: &v1alpha1.JenkinsfileRunnerSpec{
+				Image:           "jfr:1",
+				ImagePullPolicy: "Always",
+				Resources: &corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")},
+				},
+			},
+			JenkinsFile: v1alpha1.JenkinsFile{
+				URL:            "https://github.com/foo/bar",
+				Revision:       "master",
+				Path:           "Jenkinsfile",
+				RepoAuthSecret: "secret1",
+			},
+			Args:             map[string]string{"arg1": "value1"},
+			Secrets:          []string{"secret2"},
+			SecretRefs:       []v1alpha1.PipelineSecret{{Name: "secret5", TargetName: "target5", Keys: []v1alpha1.PipelineSecretKey{{Key: "key1", TargetKey: "key2"}}, Optional: true}},
+			ImagePullSecrets: []string{"secret3"},
+			Intent:           v1alpha1.IntentAbort,
+			AbortReason:      "reason2",
+			Logging: &v1alpha1.Logging{Elasticsearch: &v1alpha1.Elasticsearch{
+				RunID:      &v1alpha1.CustomJSON{Value: map[string]interface{}{"id": "1"}},
+				IndexURL:   "https://es.example.com/index1/_doc",
+				AuthSecret: "secret4",
+			}},
+			RunDetails: &v1alpha1.PipelineRunDetails{JobName: "job1", SequenceNumber: 3, Cause: "cause1"},
+			Profiles:   &v1alpha1.Profiles{Network: "profile1", Scheduling: "profile2", Resources: "profile3"},
+			Timeout:    &metav1.Duration{Duration: time.Hour},
+			RetryPolicy: &v1alpha1.RetryPolicy{
+				MaxAttempts: 2,
+				Backoff:     &metav1.Duration{Duration: time.Minute},
+				RetryOn:     []v1alpha1.Result{v1alpha1.ResultErrorInfra},
+			},
+			TTLSecondsAfterFinished: &ttl,
+			Sidecars: []v1alpha1.Sidecar{
+				{
+					Name:  "db",
+					Image: "postgres:14",
+					Env:   []corev1.EnvVar{{Name: "POSTGRES_PASSWORD", Value: "secret"}},
+					Ports: []corev1.ContainerPort{{ContainerPort: 5432}},
+					Resources: &corev1.ResourceRequirements{
+						Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+					},
+				},
+			},
+		},
+		Status: v1alpha1.PipelineStatus{
+			Status: knativeduck.Status{
+				Conditions: knativeduck.Conditions{
+					{Type: knativeapis.ConditionSucceeded, Status: corev1.ConditionTrue},
+				},
+			},
+			StartedAt:    &now,
+			FinishedAt:   &now,
+			State:        v1alpha1.StateFinished,
+			StateDetails: v1alpha1.StateItem{State: v1alpha1.StateFinished, StartedAt: now},
+			StateHistory: []v1alpha1.StateItem{
+				{State: v1alpha1.StateNew, StartedAt: now, FinishedAt: now},
+			},
+			Result: v1alpha1.ResultSuccess,
+			Container: corev1.ContainerState{
+				Terminated: &corev1.ContainerStateTerminated{
+					ExitCode:    1,
+					Signal:      9,
+					Reason:      "reason1",
+					Message:     "message1",
+					StartedAt:   now,
+					FinishedAt:  now,
+					ContainerID: "container1",
+				},
+			},
+			MessageShort:       "short",
+			Message:            "message",
+			History:            []string{"old message"},
+			Namespace:          "run-ns",
+			AuxiliaryNamespace: "aux-ns",
+			RunBackend:         "pod",
+			Timeout:            &metav1.Duration{Duration: time.Hour},
+			JenkinsfileRunnerResources: &corev1.ResourceRequirements{
+				Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")},
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+			},
+			TenantSettings: &v1alpha1.TenantPipelineRunsSettings{
+				DefaultNetworkProfile: "profile1",
+				AllowedProfiles: &v1alpha1.AllowedProfiles{
+					Network:    []string{"profile1"},
+					Scheduling: []string{"profile2"},
+					Resources:  []string{"profile3"},
+				},
+				MaxTimeout:                       &metav1.Duration{Duration: time.Hour},
+				MaxActivePipelineRuns:            &maxActive,
+				JenkinsfileRunnerImage:           "jfr:1",
+				JenkinsfileRunnerImagePullPolicy: "Always",
+			},
+			AbortRequestedAt: &now,
+			Attempts: []v1alpha1.Attempt{
+				{StartedAt: &now, FinishedAt: &now, Result: v1alpha1.ResultErrorInfra, Message: "failed"},
+			},
+			Sidecars: []v1alpha1.SidecarState{
+				{Name: "db", Container: corev1.ContainerState{Running: &corev1.ContainerStateRunning{StartedAt: now}}},
+			},
+		},
+	}
+}
+
+func Test_PipelineRun_Conversion_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name      string
+		container corev1.ContainerState
+	}{
+		{"terminated", newV1alpha1PipelineRun().Status.Container},
+		{"running", corev1.ContainerState{Running: &corev1.ContainerStateRunning{StartedAt: metav1.Now().Rfc3339Copy()}}},
+		{"waiting", corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "reason1", Message: "message1"}}},
+		{"none", corev1.ContainerState{}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			src := newV1alpha1PipelineRun()
+			src.Status.Container = tc.container
+			beta := &PipelineRun{}
+			result := &v1alpha1.PipelineRun{}
+
+			// EXERCISE
+			err := beta.ConvertFrom(src)
+			assert.NilError(t, err)
+			err = beta.ConvertTo(result)
+			assert.NilError(t, err)
+
+			// VERIFY
+			assert.DeepEqual(t, src, result)
+		})
+	}
+}
+
+func Test_PipelineRun_ConvertFrom(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	src := newV1alpha1PipelineRun()
+	examinee := &PipelineRun{}
+
+	// EXERCISE
+	err := examinee.ConvertFrom(src)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, "steward.sap.com/v1beta1", examinee.APIVersion)
+	assert.Equal(t, "https://github.com/foo/bar", examinee.Spec.JenkinsFile.URL)
+	assert.Equal(t, "Jenkinsfile", examinee.Spec.JenkinsFile.Path)
+	assert.Equal(t, `{"id":"1"}`, string(examinee.Spec.Logging.Elasticsearch.RunID.Raw))
+	assert.DeepEqual(t, []HistoricMessage{{Message: "old message"}}, examinee.Status.MessageHistory)
+	assert.Equal(t, ContainerStateTerminated, examinee.Status.JenkinsfileRunner.State)
+	assert.Equal(t, int32(1), *examinee.Status.JenkinsfileRunner.ExitCode)
+	assert.Assert(t, examinee.Status.StateDetails.FinishedAt == nil)
+	assert.Assert(t, examinee.Status.StateHistory[0].FinishedAt != nil)
+}
+
+func Test_PipelineRun_ConvertTo_NullRunID(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee := &PipelineRun{
+		Spec: PipelineRunSpec{
+			Logging: &Logging{Elasticsearch: &Elasticsearch{RunID: &runtime.RawExtension{Raw: []byte("null")}}},
+		},
+	}
+	result := &v1alpha1.PipelineRun{}
+
+	// EXERCISE
+	err := examinee.ConvertTo(result)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.DeepEqual(t, &v1alpha1.CustomJSON{}, result.Spec.Logging.Elasticsearch.RunID)
+}
+
+func Test_Tenant_Conversion_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	maxActive := int64(3)
+	src := &v1alpha1.Tenant{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "steward.sap.com/v1alpha1", Kind: "Tenant"},
+		ObjectMeta: metav1.ObjectMeta{Name: "tenant1", Namespace: "client1"},
+		Spec: v1alpha1.TenantSpec{
+			PipelineRuns: &v1alpha1.TenantPipelineRunsSettings{
+				DefaultNetworkProfile: "profile1",
+				AllowedProfiles: &v1alpha1.AllowedProfiles{
+					Network:    []string{"profile1"},
+					Scheduling: []string{"profile2"},
+					Resources:  []string{"profile3"},
+				},
+				MaxTimeout:                       &metav1.Duration{Duration: time.Hour},
+				MaxActivePipelineRuns:            &maxActive,
+				JenkinsfileRunnerImage:           "jfr:1",
+				JenkinsfileRunnerImagePullPolicy: "Always",
+			},
+		},
+		Status: v1alpha1.TenantStatus{
+			Status: knativeduck.Status{
+				Conditions: knativeduck.Conditions{
+					{Type: knativeapis.ConditionReady, Status: corev1.ConditionTrue},
+				},
+			},
+			TenantNamespaceName: "tenant-ns1",
+		},
+	}
+	beta := &Tenant{}
+	result := &v1alpha1.Tenant{}
+
+	// EXERCISE
+	err := beta.ConvertFrom(src)
+	assert.NilError(t, err)
+	err = beta.ConvertTo(result)
+	assert.NilError(t, err)
+
+	// VERIFY
+	assert.Equal(t, "steward.sap.com/v1beta1", beta.APIVersion)
+	assert.DeepEqual(t, src, result)
+}
@@ -0,0 +1,8 @@
+// +k8s:deepcopy-gen=package
+// +k8s:defaulter-gen=TypeMeta
+// +groupName=steward.sap.com
+
+// Package v1beta1 contains API version v1beta1 of the Steward resources.
+// The storage version is v1alpha1. Objects are converted between the
+// versions by the conversion webhook.
+package v1beta1
@@ -0,0 +1,599 @@
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	knativeapis "knative.dev/pkg/apis"
+	knativeduck "knative.dev/pkg/apis/duck/v1"
+)
+
+// PipelineRun is a Kubernetes custom resource type representing the execution
+// of a pipeline.
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type PipelineRun struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec PipelineRunSpec `json:"spec"`
+
+	// +optional
+	Status PipelineRunStatus `json:"status"`
+}
+
+// PipelineRunList is a list of PipelineRun objects.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type PipelineRunList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PipelineRun `json:"items"`
+}
+
+// PipelineRunSpec is the spec of a PipelineRun.
+type PipelineRunSpec struct {
+	// JenkinsfileRunner configures the Jenkinsfile Runner container.
+	// +optional
+	JenkinsfileRunner *JenkinsfileRunnerSpec `json:"jenkinsfileRunner,omitempty"`
+
+	// JenkinsFile contains the configuration of the Jenkins pipeline definition
+	// to be executed.
+	JenkinsFile JenkinsFile `json:"jenkinsFile"`
+
+	// Args contains the key-value parameters to pass to the pipeline.
+	// +optional
+	Args map[string]string `json:"args,omitempty"`
+
+	// Secrets is the list of secrets to be made available to the pipeline
+	// execution. Each entry in the list is the name of a Kubernetes `v1/Secret`
+	// resource object in the same namespace as the PipelineRun object itself.
+	// +optional
+	Secrets []string `json:"secrets,omitempty"`
+
+	// SecretRefs is the list of secrets to be made available to the pipeline
+	// execution in addition to Secrets. In contrast to Secrets, each entry
+	// can rename the secret, select and rename its keys and mark it as
+	// optional.
+	// +optional
+	SecretRefs []PipelineSecret `json:"secretRefs,omitempty"`
+
+	// ImagePullSecrets is the list of image pull secrets required by the
+	// pipeline run to pull images of custom containers from private registries.
+	// Each entry in the list is the name of a Kubernetes `v1/Secret` resource
+	// object of type `kubernetes.io/dockerconfigjson` in the same namespace as
+	// the PipelineRun object itself.
+	// +optional
+	ImagePullSecrets []string `json:"imagePullSecrets,omitempty"`
+
+	// Intent is the intention of the client regarding the way this pipeline run
+	// should be processed. The value `run` indicates that the pipeline should
+	// run to completion, while the value `abort` indicates that the pipeline
+	// processing should be stopped as soon as possible.
+	// If not set, it is defaulted to `run` when the pipeline run gets created.
+	// +optional
+	Intent Intent `json:"intent,omitempty"`
+
+	// AbortReason is a human-readable explanation why the pipeline run
+	// should be aborted. It is only evaluated if the intent is `abort`
+	// and becomes part of the status message of the aborted pipeline run.
+	// +optional
+	AbortReason string `json:"abortReason,omitempty"`
+
+	// Logging contains the logging configuration.
+	// +optional
+	Logging *Logging `json:"logging,omitempty"`
+
+	// RunDetails provides metadata for a pipeline run which is evaluated by the
+	// Jenkinsfile Runner.
+	// +optional
+	RunDetails *PipelineRunDetails `json:"runDetails,omitempty"`
+
+	// Profiles selects configuration profiles for different aspects.
+	// +optional
+	Profiles *Profiles `json:"profiles,omitempty"`
+
+	// Timeout is the maximum execution time of the pipeline run.
+	// If not set, the default timeout configured for the system is used.
+	// It must not exceed the maximum timeout configured for the system.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// RetryPolicy defines whether and how the pipeline run gets retried
+	// if it fails.
+	// If not set, the pipeline run is not retried.
+	// +optional
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
+
+	// TTLSecondsAfterFinished is the number of seconds a finished pipeline
+	// run is kept before it gets deleted automatically.
+	// If not set, the default configured for the system is used.
+	// +optional
+	TTLSecondsAfterFinished *int64 `json:"ttlSecondsAfterFinished,omitempty"`
+
+	// Sidecars is the list of containers to run next to the Jenkinsfile
+	// Runner. Only the Tekton run backend supports sidecars.
+	// +optional
+	Sidecars []Sidecar `json:"sidecars,omitempty"`
+}
+
+// RetryPolicy defines the automatic retry of failed pipeline runs.
+// Each retry is executed in a new sandbox.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts including the first
+	// one. Values less than 2 disable retrying.
+	// +optional
+	MaxAttempts int32 `json:"maxAttempts,omitempty"`
+
+	// Backoff is the time to wait after a failed attempt before the next
+	// attempt gets started.
+	// If not set, the next attempt is started immediately.
+	// +optional
+	Backoff *metav1.Duration `json:"backoff,omitempty"`
+
+	// RetryOn is the list of results that cause a retry. Only
+	// `error_infra` and `timeout` are allowed.
+	// If empty, only `error_infra` is retried.
+	// +optional
+	RetryOn []Result `json:"retryOn,omitempty"`
+}
+
+// JenkinsfileRunnerSpec carries configuration options for the Jenkinsfile
+// Runner container.
+type JenkinsfileRunnerSpec struct {
+	// Image is the image name including the tag or digest.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// ImagePullPolicy is the pull policy for the image.
+	// +optional
+	ImagePullPolicy string `json:"imagePullPolicy,omitempty"`
+
+	// Resources are the compute resource requirements of the Jenkinsfile
+	// Runner container. They are merged into the defaults of the pipeline
+	// runs configuration and must be within the configured bounds.
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// PipelineSecret refers to a secret to be made available to the pipeline
+// execution.
+type PipelineSecret struct {
+	// Name is the name of the secret to be copied. It must be a valid
+	// secret name (DNS-1123 subdomain).
+	Name string `json:"name"`
+
+	// TargetName is the name of the secret in the pipeline execution.
+	// If empty, the name given by annotation `steward.sap.com/secret-rename-to`
+	// of the secret is used, or otherwise `Name`.
+	// +optional
+	TargetName string `json:"targetName,omitempty"`
+
+	// Keys is the list of keys of the secret to be copied. If empty, all
+	// keys are copied. Each key and each target key may only be listed once.
+	// +optional
+	Keys []PipelineSecretKey `json:"keys,omitempty"`
+
+	// Optional defines whether the pipeline run can be executed if the
+	// secret or some of the listed keys do not exist.
+	// +optional
+	Optional bool `json:"optional,omitempty"`
+}
+
+// PipelineSecretKey is a key of a secret to be made available to the
+// pipeline execution.
+type PipelineSecretKey struct {
+	// Key is the key in the secret.
+	Key string `json:"key"`
+
+	// TargetKey is the key in the secret in the pipeline execution.
+	// If empty, `Key` is used.
+	// +optional
+	TargetKey string `json:"targetKey,omitempty"`
+}
+
+// Sidecar is a container running next to the Jenkinsfile Runner for the
+// whole pipeline run, e.g. a database or a browser for integration tests.
+// It shares the network namespace with the Jenkinsfile Runner, i.e. its
+// ports are reachable via `localhost`.
+type Sidecar struct {
+	// Name is the name of the sidecar container. It must be a DNS label
+	// and unique within the pipeline run.
+	Name string `json:"name"`
+
+	// Image is the image name including the tag or digest. It must be
+	// permitted by the pipeline runs configuration.
+	Image string `json:"image"`
+
+	// Env is the list of environment variables to set in the container.
+	// +optional
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// Ports is the list of ports the container listens on.
+	// +optional
+	Ports []corev1.ContainerPort `json:"ports,omitempty"`
+
+	// Resources are the compute resources required by the container.
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// JenkinsFile represents the location from where to get the pipeline.
+type JenkinsFile struct {
+	// URL is the URL of the Git repository containing the pipeline definition
+	// (aka `Jenkinsfile`).
+	URL string `json:"url"`
+
+	// Revision is the revision of the pipeline Git repository to be used, e.g.
+	// `master`.
+	Revision string `json:"revision"`
+
+	// Path is the relative pathname of the pipeline definition file in the
+	// repository check-out.
+	// If not set, it is defaulted to `Jenkinsfile` when the pipeline run
+	// gets created.
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// RepoAuthSecret is the name of the Kubernetes `v1/Secret` resource object
+	// of type `kubernetes.io/basic-auth` that contains the username and
+	// password for authentication when cloning from `spec.jenkinsFile.url`.
+	// +optional
+	RepoAuthSecret string `json:"repoAuthSecret,omitempty"`
+}
+
+// Logging contains all logging-specific configuration.
+type Logging struct {
+	// Elasticsearch is the configuration for pipeline logging to Elasticsearch.
+	// If not specified, logging to Elasticsearch is disabled and the default
+	// Jenkins log implementation is used (stdout of Jenkinsfile Runner
+	// container).
+	// +optional
+	Elasticsearch *Elasticsearch `json:"elasticsearch,omitempty"`
+}
+
+// Elasticsearch contains logging configuration for the
+// Elasticsearch log implementation.
+type Elasticsearch struct {
+	// RunID is the identifier of this pipeline run, attached as field
+	// `runId` to each log entry. It can by any JSON value (object, array,
+	// string, number, bool).
+	// +optional
+	RunID *runtime.RawExtension `json:"runID,omitempty"`
+
+	// IndexURL is the HTTP(S) URL of the Elasticsearch index to write
+	// logs to.
+	// If not set, a default log destination will be used.
+	// +optional
+	IndexURL string `json:"indexURL,omitempty"`
+
+	// AuthSecret is the name of the Kubernetes `v1/Secret` resource object
+	// of type `kubernetes.io/basic-auth` that contains the username and
+	// password for authenticating requests to `IndexURL`.
+	// It is ignored when `IndexURL` is not set.
+	// +optional
+	AuthSecret string `json:"authSecret,omitempty"`
+}
+
+// PipelineRunDetails provides metadata for a pipeline run which is evaluated by
+// the Jenkinsfile Runner.
+type PipelineRunDetails struct {
+	// JobName is the name of the job this pipeline run belongs to. It is used
+	// as the name of the Jenkins job and therefore must be a valid Jenkins job
+	// name. If empty, a default name will be used for the Jenkins job.
+	// +optional
+	JobName string `json:"jobName,omitempty"`
+
+	// SequenceNumber is the sequence number of the pipeline run, which
+	// translates into the build number of the Jenkins job.
+	// +optional
+	SequenceNumber int32 `json:"sequenceNumber,omitempty"`
+
+	// Cause is a textual description of the cause of this pipeline run. Will be
+	// set as cause of the Jenkins job. If empty, no cause information
+	// will be available.
+	// +optional
+	Cause string `json:"cause,omitempty"`
+}
+
+// Profiles selects configuration profiles for different aspects.
+type Profiles struct {
+	// Network selects the network profile. It currently determines which network connections
+	// are allowed. The scope of the network profile might be extended in the future.
+	// If empty, a default profile will be used.
+	// +optional
+	Network string `json:"network,omitempty"`
+
+	// Scheduling selects the scheduling profile. It determines the nodes
+	// the pods of the pipeline run are scheduled to and their priority.
+	// If empty, a default profile will be used, if configured.
+	// +optional
+	Scheduling string `json:"scheduling,omitempty"`
+
+	// Resources selects the resource profile. It determines the limit
+	// range and resource quota of the sandbox namespace and the default
+	// resource requirements of the Jenkinsfile Runner.
+	// If empty, a default profile will be used, if configured.
+	// +optional
+	Resources string `json:"resources,omitempty"`
+}
+
+// PipelineRunStatus represents the status of a PipelineRun.
+type PipelineRunStatus struct {
+	// Status contains the conditions of the pipeline run. The conditions
+	// are derived from the other status fields.
+	knativeduck.Status `json:",inline"`
+
+	// StartedAt is the time the pipeline run has been started.
+	// +optional
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+
+	// FinishedAt is the time the pipeline run has been finished.
+	// +optional
+	FinishedAt *metav1.Time `json:"finishedAt,omitempty"`
+
+	// State is the current state of the pipeline run.
+	// +optional
+	State State `json:"state,omitempty"`
+
+	// StateDetails describes the current state of the pipeline run.
+	// +optional
+	StateDetails StateItem `json:"stateDetails,omitempty"`
+
+	// StateHistory is the list of states the pipeline run has been in
+	// before the current one.
+	// +optional
+	StateHistory []StateItem `json:"stateHistory,omitempty"`
+
+	// Result is the result of the pipeline run. It is empty as long as
+	// the pipeline run has not finished.
+	// +optional
+	Result Result `json:"result,omitempty"`
+
+	// JenkinsfileRunner is the status of the Jenkinsfile Runner container.
+	// +optional
+	JenkinsfileRunner *ContainerStatus `json:"jenkinsfileRunner,omitempty"`
+
+	// MessageShort is a shortened version of `Message`.
+	// +optional
+	MessageShort string `json:"messageShort,omitempty"`
+
+	// Message is a human-readable message describing the current status.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// MessageHistory is the list of previous messages, the oldest first.
+	// +optional
+	MessageHistory []HistoricMessage `json:"messageHistory,omitempty"`
+
+	// Namespace is the name of the run namespace.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// AuxiliaryNamespace is the name of the auxiliary namespace.
+	// +optional
+	AuxiliaryNamespace string `json:"auxiliaryNamespace,omitempty"`
+
+	// RunBackend is the name of the backend executing the pipeline run.
+	// It is determined when the pipeline run gets started and does not
+	// change afterwards. Empty means the Tekton backend.
+	// +optional
+	RunBackend string `json:"runBackend,omitempty"`
+
+	// Timeout is the effective maximum execution time of the pipeline run.
+	// It is determined when the pipeline run gets started.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// JenkinsfileRunnerResources are the effective compute resource
+	// requirements of the Jenkinsfile Runner container. They are determined
+	// when the pipeline run gets started.
+	// +optional
+	JenkinsfileRunnerResources *corev1.ResourceRequirements `json:"jenkinsfileRunnerResources,omitempty"`
+
+	// TenantSettings are the effective settings of the pipeline runs
+	// configuration which can be overridden per tenant, i.e. the values
+	// the pipeline run has been started with after applying the settings
+	// of its tenant. They are determined when the pipeline run gets
+	// started.
+	// +optional
+	TenantSettings *TenantPipelineRunsSettings `json:"tenantSettings,omitempty"`
+
+	// AbortRequestedAt is the time the run backend has been requested to
+	// stop the Jenkinsfile Runner of an aborted pipeline run. The namespaces
+	// of the pipeline run are cleaned up once the Jenkinsfile Runner has
+	// terminated or the abort grace period has expired.
+	// +optional
+	AbortRequestedAt *metav1.Time `json:"abortRequestedAt,omitempty"`
+
+	// Attempts is the list of previous attempts of the pipeline run which
+	// have been retried according to the retry policy. The current attempt
+	// is described by the other status fields.
+	// +optional
+	Attempts []Attempt `json:"attempts,omitempty"`
+
+	// Sidecars contains the statuses of the sidecar containers.
+	// +optional
+	Sidecars []SidecarStatus `json:"sidecars,omitempty"`
+}
+
+const (
+	// PipelineRunConditionSucceeded is the condition type indicating
+	// whether the pipeline run has finished successfully. It is unknown as
+	// long as the pipeline run has not finished.
+	PipelineRunConditionSucceeded = knativeapis.ConditionSucceeded
+
+	// PipelineRunConditionPrepared is the condition type indicating
+	// whether the sandbox of the pipeline run has been prepared
+	// successfully.
+	PipelineRunConditionPrepared knativeapis.ConditionType = "Prepared"
+
+	// PipelineRunConditionSandboxCleanedUp is the condition type
+	// indicating whether the sandbox of the pipeline run has been
+	// cleaned up.
+	PipelineRunConditionSandboxCleanedUp knativeapis.ConditionType = "SandboxCleanedUp"
+)
+
+var pipelineRunConditionSet = knativeapis.NewBatchConditionSet(
+	PipelineRunConditionPrepared,
+	PipelineRunConditionSandboxCleanedUp,
+)
+
+// GetCondition returns the condition matching the given condition type.
+func (s *PipelineRunStatus) GetCondition(condType knativeapis.ConditionType) *knativeapis.Condition {
+	return pipelineRunConditionSet.Manage(s).GetCondition(condType)
+}
+
+// ContainerStatus describes the state of a container.
+type ContainerStatus struct {
+	// State is the state of the container, one of `waiting`, `running`
+	// and `terminated`.
+	State ContainerState `json:"state"`
+
+	// Reason is a brief reason for the state of the container.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Message is a message describing the state of the container.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// ExitCode is the exit code of the terminated container.
+	// +optional
+	ExitCode *int32 `json:"exitCode,omitempty"`
+
+	// Signal is the signal that terminated the container.
+	// +optional
+	Signal int32 `json:"signal,omitempty"`
+
+	// StartedAt is the time the container has been started.
+	// +optional
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+
+	// FinishedAt is the time the container has terminated.
+	// +optional
+	FinishedAt *metav1.Time `json:"finishedAt,omitempty"`
+
+	// ContainerID is the ID of the terminated container.
+	// +optional
+	ContainerID string `json:"containerID,omitempty"`
+}
+
+// ContainerState is the state of a container.
+type ContainerState string
+
+const (
+	// ContainerStateWaiting - the container is waiting to be started
+	ContainerStateWaiting ContainerState = "waiting"
+	// ContainerStateRunning - the container is running
+	ContainerStateRunning ContainerState = "running"
+	// ContainerStateTerminated - the container has terminated
+	ContainerStateTerminated ContainerState = "terminated"
+)
+
+// HistoricMessage is a message which has been replaced by a newer one.
+type HistoricMessage struct {
+	// Message is the text of the message.
+	Message string `json:"message"`
+}
+
+// SidecarStatus is the status of a sidecar container.
+type SidecarStatus struct {
+	// Name is the name of the sidecar.
+	Name string `json:"name"`
+
+	// Container is the status of the sidecar container.
+	// +optional
+	Container *ContainerStatus `json:"container,omitempty"`
+}
+
+// Attempt describes a finished attempt to execute a pipeline run.
+type Attempt struct {
+	// StartedAt is the time the attempt has been started.
+	// +optional
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+
+	// FinishedAt is the time the attempt has been finished.
+	// +optional
+	FinishedAt *metav1.Time `json:"finishedAt,omitempty"`
+
+	// Result is the result of the attempt.
+	Result Result `json:"result"`
+
+	// Message is the message describing the result of the attempt.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Namespace is the run namespace the attempt was executed in.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// AuxiliaryNamespace is the auxiliary namespace of the attempt.
+	// +optional
+	AuxiliaryNamespace string `json:"auxiliaryNamespace,omitempty"`
+}
+
+// StateItem holds start and end time of a state in the history.
+type StateItem struct {
+	State      State        `json:"state"`
+	StartedAt  metav1.Time  `json:"startedAt"`
+	FinishedAt *metav1.Time `json:"finishedAt,omitempty"`
+}
+
+// State represents the state of a pipeline run.
+type State string
+
+const (
+	// StateUndefined - the state was not yet set
+	StateUndefined State = ""
+	// StateNew - pipeline run is first checked by the controller
+	StateNew State = "new"
+	// StateQueued - the pipeline run waits until it may be started without
+	// exceeding the limits of concurrently active pipeline runs
+	StateQueued State = "queued"
+	// StatePreparing - the namespace for the execution is prepared
+	StatePreparing State = "preparing"
+	// StateWaiting - the pipeline run is waiting to be processed
+	StateWaiting State = "waiting"
+	// StateRunning - the pipeline is running
+	StateRunning State = "running"
+	// StateCleaning - cleanup is ongoing
+	StateCleaning State = "cleaning"
+	// StateFinished - the pipeline run has finished
+	StateFinished State = "finished"
+)
+
+// Result is the result of a pipeline run.
+type Result string
+
+const (
+	// ResultUndefined - undefined result
+	ResultUndefined Result = ""
+	// ResultSuccess - the pipeline run was processed successfully
+	ResultSuccess Result = "success"
+	// ResultErrorInfra - the pipeline run failed due to an infrastructure problem
+	ResultErrorInfra Result = "error_infra"
+	// ResultErrorContent -  the pipeline run failed due to an content problem
+	ResultErrorContent Result = "error_content"
+	// ResultErrorConfig - the pipeline run failed due to a client-side configuration error
+	ResultErrorConfig Result = "error_config"
+	// ResultAborted - the pipeline run has been aborted
+	ResultAborted Result = "aborted"
+	// ResultTimeout - the pipeline run timed out
+	ResultTimeout Result = "timeout"
+	// ResultDeleted - the pipeline run was deleted
+	ResultDeleted Result = "deleted"
+)
+
+// Intent denotes how the pipeline run should be handled.
+type Intent string
+
+const (
+	// IntentRun indicates that the pipeline should run to completion.
+	IntentRun Intent = "run"
+	// IntentAbort indicates that the pipeline run should be aborted
+	// if it is not completed already.
+	IntentAbort Intent = "abort"
+)
@@ -0,0 +1,112 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	knativeapis "knative.dev/pkg/apis"
+	knativeduck "knative.dev/pkg/apis/duck/v1"
+)
+
+// Tenant is representing a Tenant and its status
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type Tenant struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// +optional
+	Spec TenantSpec `json:"spec,omitempty"`
+	// +optional
+	Status TenantStatus `json:"status"`
+}
+
+// TenantList is a list of Tenants
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type TenantList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Tenant `json:"items"`
+}
+
+// TenantSpec is the spec of a Tenant.
+type TenantSpec struct {
+	// PipelineRuns overrides settings of the pipeline runs configuration
+	// for the pipeline runs in the tenant namespace.
+	// +optional
+	PipelineRuns *TenantPipelineRunsSettings `json:"pipelineRuns,omitempty"`
+}
+
+// TenantPipelineRunsSettings are settings of the pipeline runs
+// configuration which can be overridden per tenant.
+type TenantPipelineRunsSettings struct {
+	// DefaultNetworkProfile is the name of the network profile used for
+	// pipeline runs not selecting one.
+	// If empty, the default network profile of the pipeline runs
+	// configuration is used.
+	// +optional
+	DefaultNetworkProfile string `json:"defaultNetworkProfile,omitempty"`
+
+	// AllowedProfiles restricts the profiles pipeline runs may use.
+	// If `nil`, all profiles may be used.
+	// +optional
+	AllowedProfiles *AllowedProfiles `json:"allowedProfiles,omitempty"`
+
+	// MaxTimeout is the maximum timeout pipeline runs may request. It can
+	// only lower the maximum timeout of the pipeline runs configuration.
+	// +optional
+	MaxTimeout *metav1.Duration `json:"maxTimeout,omitempty"`
+
+	// MaxActivePipelineRuns is the maximum number of concurrently active
+	// pipeline runs in the tenant namespace. It can only lower the limit
+	// of the pipeline runs configuration and the client namespace.
+	// Zero means unlimited.
+	// +optional
+	MaxActivePipelineRuns *int64 `json:"maxActivePipelineRuns,omitempty"`
+
+	// JenkinsfileRunnerImage is the Jenkinsfile Runner image used for
+	// pipeline runs not specifying one.
+	// If empty, the image of the pipeline runs configuration is used.
+	// +optional
+	JenkinsfileRunnerImage string `json:"jenkinsfileRunnerImage,omitempty"`
+
+	// JenkinsfileRunnerImagePullPolicy is the pull policy for
+	// `JenkinsfileRunnerImage`. It defaults to `IfNotPresent`.
+	// +optional
+	JenkinsfileRunnerImagePullPolicy string `json:"jenkinsfileRunnerImagePullPolicy,omitempty"`
+}
+
+// AllowedProfiles lists the profiles pipeline runs may use per aspect.
+// An empty list means that all profiles of this aspect may be used.
+type AllowedProfiles struct {
+	// Network lists the allowed network profiles.
+	// +optional
+	Network []string `json:"network,omitempty"`
+
+	// Scheduling lists the allowed scheduling profiles.
+	// +optional
+	Scheduling []string `json:"scheduling,omitempty"`
+
+	// Resources lists the allowed resource profiles. If the client
+	// namespace restricts the resource profiles via annotation
+	// `steward.sap.com/allowed-resource-profiles` too, only profiles
+	// allowed by both may be used.
+	// +optional
+	Resources []string `json:"resources,omitempty"`
+}
+
+// TenantStatus contains the status of a Tenant
+type TenantStatus struct {
+	knativeduck.Status `json:",inline"`
+
+	// TenantNamespaceName is the name of the namespace assigned to the
+	// tenant.
+	// +optional
+	TenantNamespaceName string `json:"tenantNamespaceName,omitempty"`
+}
+
+var tenantConditionSet = knativeapis.NewLivingConditionSet()
+
+// GetCondition returns the condition matching the given condition type.
+func (s *TenantStatus) GetCondition(condType knativeapis.ConditionType) *knativeapis.Condition {
+	return tenantConditionSet.Manage(s).GetCondition(condType)
+}
@@ -28,6 +28,7 @@ import (
 	"fmt"
 
 	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/client/clientset/versioned/typed/steward/v1alpha1"
+	stewardv1beta1 "github.com/SAP/stewardci-core/pkg/client/clientset/versioned/typed/steward/v1beta1"
 	discovery "k8s.io/client-go/discovery"
 	rest "k8s.io/client-go/rest"
 	flowcontrol "k8s.io/client-go/util/flowcontrol"
@@ -36,6 +37,7 @@ import (
 type Interface interface {
 	Discovery() discovery.DiscoveryInterface
 	StewardV1alpha1() stewardv1alpha1.StewardV1alpha1Interface
+	StewardV1beta1() stewardv1beta1.StewardV1beta1Interface
 }
 
 // Clientset contains the clients for groups. Each group has exactly one
@@ -43,6 +45,7 @@ type Interface interface {
 type Clientset struct {
 	*discovery.DiscoveryClient
 	stewardV1alpha1 *stewardv1alpha1.StewardV1alpha1Client
+	stewardV1beta1  *stewardv1beta1.StewardV1beta1Client
 }
 
 // StewardV1alpha1 retrieves the StewardV1alpha1Client
@@ -50,6 +53,11 @@ func (c *Clientset) StewardV1alpha1() stewardv1alpha1.StewardV1alpha1Interface {
 	return c.stewardV1alpha1
 }
 
+// StewardV1beta1 retrieves the StewardV1beta1Client
+func (c *Clientset) StewardV1beta1() stewardv1beta1.StewardV1beta1Interface {
+	return c.stewardV1beta1
+}
+
 // Discovery retrieves the DiscoveryClient
 func (c *Clientset) Discovery() discovery.DiscoveryInterface {
 	if c == nil {
@@ -75,6 +83,10 @@ func NewForConfig(c *rest.Config) (*Clientset, error) {
 	if err != nil {
 		return nil, err
 	}
+	cs.stewardV1beta1, err = stewardv1beta1.NewForConfig(&configShallowCopy)
+	if err != nil {
+		return nil, err
+	}
 
 	cs.DiscoveryClient, err = discovery.NewDiscoveryClientForConfig(&configShallowCopy)
 	if err != nil {
@@ -88,6 +100,7 @@ func NewForConfig(c *rest.Config) (*Clientset, error) {
 func NewForConfigOrDie(c *rest.Config) *Clientset {
 	var cs Clientset
 	cs.stewardV1alpha1 = stewardv1alpha1.NewForConfigOrDie(c)
+	cs.stewardV1beta1 = stewardv1beta1.NewForConfigOrDie(c)
 
 	cs.DiscoveryClient = discovery.NewDiscoveryClientForConfigOrDie(c)
 	return &cs
@@ -97,6 +110,7 @@ func NewForConfigOrDie(c *rest.Config) *Clientset {
 func New(c rest.Interface) *Clientset {
 	var cs Clientset
 	cs.stewardV1alpha1 = stewardv1alpha1.New(c)
+	cs.stewardV1beta1 = stewardv1beta1.New(c)
 
 	cs.DiscoveryClient = discovery.NewDiscoveryClient(c)
 	return &cs
@@ -28,6 +28,8 @@ import (
 	clientset "github.com/SAP/stewardci-core/pkg/client/clientset/versioned"
 	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/client/clientset/versioned/typed/steward/v1alpha1"
 	fakestewardv1alpha1 "github.com/SAP/stewardci-core/pkg/client/clientset/versioned/typed/steward/v1alpha1/fake"
+	stewardv1beta1 "github.com/SAP/stewardci-core/pkg/client/clientset/versioned/typed/steward/v1beta1"
+	fakestewardv1beta1 "github.com/SAP/stewardci-core/pkg/client/clientset/versioned/typed/steward/v1beta1/fake"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/discovery"
@@ -86,3 +88,8 @@ var _ clientset.Interface = &Clientset{}
 func (c *Clientset) StewardV1alpha1() stewardv1alpha1.StewardV1alpha1Interface {
 	return &fakestewardv1alpha1.FakeStewardV1alpha1{Fake: &c.Fake}
 }
+
+// StewardV1beta1 retrieves the StewardV1beta1Client
+func (c *Clientset) StewardV1beta1() stewardv1beta1.StewardV1beta1Interface {
+	return &fakestewardv1beta1.FakeStewardV1beta1{Fake: &c.Fake}
+}
@@ -0,0 +1,201 @@
+/*
+#########################
+#  SAP Steward-CI       #
+#########################
+
+THIS CODE IS GENERATED! DO NOT TOUCH!
+
+Copyright SAP SE.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	"context"
+	"time"
+
+	v1beta1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1beta1"
+	scheme "github.com/SAP/stewardci-core/pkg/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// TenantsGetter has a method to return a TenantInterface.
+// A group's client should implement this interface.
+type TenantsGetter interface {
+	Tenants(namespace string) TenantInterface
+}
+
+// TenantInterface has methods to work with Tenant resources.
+type TenantInterface interface {
+	Create(ctx context.Context, tenant *v1beta1.Tenant, opts v1.CreateOptions) (*v1beta1.Tenant, error)
+	Update(ctx context.Context, tenant *v1beta1.Tenant, opts v1.UpdateOptions) (*v1beta1.Tenant, error)
+	UpdateStatus(ctx context.Context, tenant *v1beta1.Tenant, opts v1.UpdateOptions) (*v1beta1.Tenant, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1beta1.Tenant, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1beta1.TenantList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1beta1.Tenant, err error)
+	TenantExpansion
+}
+
+// tenants implements TenantInterface
+type tenants struct {
+	client rest.Interface
+	ns     string
+}
+
+// newTenants returns a Tenants
+func newTenants(c *StewardV1beta1Client, namespace string) *tenants {
+	return &tenants{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the tenant, and returns the corresponding tenant object, and an error if there is any.
+func (c *tenants) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1beta1.Tenant, err error) {
+	result = &v1beta1.Tenant{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("tenants").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of Tenants that match those selectors.
+func (c *tenants) List(ctx context.Context, opts v1.ListOptions) (result *v1beta1.TenantList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1beta1.TenantList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("tenants").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested tenants.
+func (c *tenants) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("tenants").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a tenant and creates it.  Returns the server's representation of the tenant, and an error, if there is any.
+func (c *tenants) Create(ctx context.Context, tenant *v1beta1.Tenant, opts v1.CreateOptions) (result *v1beta1.Tenant, err error) {
+	result = &v1beta1.Tenant{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("tenants").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(tenant).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a tenant and updates it. Returns the server's representation of the tenant, and an error, if there is any.
+func (c *tenants) Update(ctx context.Context, tenant *v1beta1.Tenant, opts v1.UpdateOptions) (result *v1beta1.Tenant, err error) {
+	result = &v1beta1.Tenant{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("tenants").
+		Name(tenant.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(tenant).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *tenants) UpdateStatus(ctx context.Context, tenant *v1beta1.Tenant, opts v1.UpdateOptions) (result *v1beta1.Tenant, err error) {
+	result = &v1beta1.Tenant{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("tenants").
+		Name(tenant.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(tenant).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the tenant and deletes it. Returns an error if one occurs.
+func (c *tenants) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("tenants").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *tenants) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("tenants").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched tenant.
+func (c *tenants) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1beta1.Tenant, err error) {
+	result = &v1beta1.Tenant{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("tenants").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
@@ -28,6 +28,7 @@ import (
 	"fmt"
 
 	v1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	v1beta1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1beta1"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
 	cache "k8s.io/client-go/tools/cache"
 )
@@ -64,6 +65,12 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 	case v1alpha1.SchemeGroupVersion.WithResource("tenants"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Steward().V1alpha1().Tenants().Informer()}, nil
 
+		// Group=steward.sap.com, Version=v1beta1
+	case v1beta1.SchemeGroupVersion.WithResource("pipelineruns"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Steward().V1beta1().PipelineRuns().Informer()}, nil
+	case v1beta1.SchemeGroupVersion.WithResource("tenants"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Steward().V1beta1().Tenants().Informer()}, nil
+
 	}
 
 	return nil, fmt.Errorf("no informer found for %v", resource)
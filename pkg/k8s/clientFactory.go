@@ -3,13 +3,16 @@ package k8s
 import (
 	"time"
 
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
 	stewardclients "github.com/SAP/stewardci-core/pkg/client/clientset/versioned"
 	stewardv1alpha1client "github.com/SAP/stewardci-core/pkg/client/clientset/versioned/typed/steward/v1alpha1"
 	stewardinformers "github.com/SAP/stewardci-core/pkg/client/informers/externalversions"
 	tektonclients "github.com/SAP/stewardci-core/pkg/tektonclient/clientset/versioned"
 	tektonv1beta1client "github.com/SAP/stewardci-core/pkg/tektonclient/clientset/versioned/typed/pipeline/v1beta1"
 	tektoninformers "github.com/SAP/stewardci-core/pkg/tektonclient/informers/externalversions"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	dynamic "k8s.io/client-go/dynamic"
+	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 	networkingv1client "k8s.io/client-go/kubernetes/typed/networking/v1"
@@ -43,6 +46,11 @@ type ClientFactory interface {
 
 	// TektonInformerFactory returns the informer factory for Tekton
 	TektonInformerFactory() tektoninformers.SharedInformerFactory
+
+	// KubernetesInformerFactory returns the informer factory for
+	// Kubernetes resources. Informers only see objects labelled as
+	// system-managed by Steward.
+	KubernetesInformerFactory() kubeinformers.SharedInformerFactory
 }
 
 type clientFactory struct {
@@ -52,6 +60,7 @@ type clientFactory struct {
 	stewardInformerFactory stewardinformers.SharedInformerFactory
 	tektonClientset        *tektonclients.Clientset
 	tektonInformerFactory  tektoninformers.SharedInformerFactory
+	kubeInformerFactory    kubeinformers.SharedInformerFactory
 }
 
 // NewClientFactory creates new client factory based on rest config
@@ -68,6 +77,7 @@ func NewClientFactory(config *rest.Config, resyncPeriod time.Duration) ClientFac
 		klog.ErrorS(err, "could not create Kubernetes clientset: %s")
 		return nil
 	}
+	kubeInformerFactory := newSystemManagedKubeInformerFactory(kubernetesClientset, resyncPeriod)
 
 	dynamicClient, err := dynamic.NewForConfig(config)
 	if err != nil {
@@ -89,9 +99,19 @@ func NewClientFactory(config *rest.Config, resyncPeriod time.Duration) ClientFac
 		stewardInformerFactory: stewardInformerFactory,
 		tektonClientset:        tektonClientset,
 		tektonInformerFactory:  tektonInformerFactory,
+		kubeInformerFactory:    kubeInformerFactory,
 	}
 }
 
+func newSystemManagedKubeInformerFactory(clientset kubernetes.Interface, resyncPeriod time.Duration) kubeinformers.SharedInformerFactory {
+	return kubeinformers.NewSharedInformerFactoryWithOptions(
+		clientset, resyncPeriod,
+		kubeinformers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.LabelSelector = api.LabelSystemManaged
+		}),
+	)
+}
+
 // StewardInformerFactory implements interface ClientFactory
 func (f *clientFactory) StewardInformerFactory() stewardinformers.SharedInformerFactory {
 	return f.stewardInformerFactory
@@ -127,6 +147,11 @@ func (f *clientFactory) TektonInformerFactory() tektoninformers.SharedInformerFa
 	return f.tektonInformerFactory
 }
 
+// KubernetesInformerFactory implements interface ClientFactory
+func (f *clientFactory) KubernetesInformerFactory() kubeinformers.SharedInformerFactory {
+	return f.kubeInformerFactory
+}
+
 // TektonV1beta1 implements interface ClientFactory
 func (f *clientFactory) TektonV1beta1() tektonv1beta1client.TektonV1beta1Interface {
 	return f.tektonClientset.TektonV1beta1()
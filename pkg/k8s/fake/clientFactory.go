@@ -15,6 +15,7 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	dynamic "k8s.io/client-go/dynamic"
 	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubeinformers "k8s.io/client-go/informers"
 	k8sclientfake "k8s.io/client-go/kubernetes/fake"
 	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 	networkingv1client "k8s.io/client-go/kubernetes/typed/networking/v1"
@@ -30,6 +31,7 @@ type ClientFactory struct {
 	stewardInformerFactory stewardinformer.SharedInformerFactory
 	tektonClientset        *tektonclientfake.Clientset
 	tektonInformerFactory  tektoninformers.SharedInformerFactory
+	kubeInformerFactory    kubeinformers.SharedInformerFactory
 	sleepDuration          time.Duration
 }
 
@@ -40,14 +42,17 @@ func NewClientFactory(objects ...runtime.Object) *ClientFactory {
 	stewardInformerFactory := stewardinformer.NewSharedInformerFactory(stewardClientset, 10*time.Minute)
 	tektonClientset := tektonclientfake.NewSimpleClientset(tektonObjects...)
 	tektonInformerFactory := tektoninformers.NewSharedInformerFactory(tektonClientset, 10*time.Minute)
+	kubernetesClientset := k8sclientfake.NewSimpleClientset(kubernetesObjects...)
+	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(kubernetesClientset, 10*time.Minute)
 
 	return &ClientFactory{
-		kubernetesClientset:    k8sclientfake.NewSimpleClientset(kubernetesObjects...),
+		kubernetesClientset:    kubernetesClientset,
 		DynamicClient:          dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()),
 		stewardClientset:       stewardClientset,
 		stewardInformerFactory: stewardInformerFactory,
 		tektonClientset:        tektonClientset,
 		tektonInformerFactory:  tektonInformerFactory,
+		kubeInformerFactory:    kubeInformerFactory,
 		sleepDuration:          300 * time.Millisecond,
 	}
 }
@@ -123,6 +128,11 @@ func (f *ClientFactory) TektonInformerFactory() tektoninformers.SharedInformerFa
 	return f.tektonInformerFactory
 }
 
+// KubernetesInformerFactory implements interface "github.com/SAP/stewardci-core/pkg/k8s".ClientFactory
+func (f *ClientFactory) KubernetesInformerFactory() kubeinformers.SharedInformerFactory {
+	return f.kubeInformerFactory
+}
+
 // TektonClientset returns the Tekton fake clientset.
 func (f *ClientFactory) TektonClientset() *tektonclientfake.Clientset {
 	return f.tektonClientset
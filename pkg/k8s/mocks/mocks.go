@@ -38,6 +38,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	v10 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	dynamic "k8s.io/client-go/dynamic"
+	informers "k8s.io/client-go/informers"
 	v11 "k8s.io/client-go/kubernetes/typed/core/v1"
 	v12 "k8s.io/client-go/kubernetes/typed/networking/v1"
 	v13 "k8s.io/client-go/kubernetes/typed/rbac/v1"
@@ -95,6 +96,20 @@ func (mr *MockClientFactoryMockRecorder) Dynamic() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Dynamic", reflect.TypeOf((*MockClientFactory)(nil).Dynamic))
 }
 
+// KubernetesInformerFactory mocks base method
+func (m *MockClientFactory) KubernetesInformerFactory() informers.SharedInformerFactory {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "KubernetesInformerFactory")
+	ret0, _ := ret[0].(informers.SharedInformerFactory)
+	return ret0
+}
+
+// KubernetesInformerFactory indicates an expected call of KubernetesInformerFactory
+func (mr *MockClientFactoryMockRecorder) KubernetesInformerFactory() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "KubernetesInformerFactory", reflect.TypeOf((*MockClientFactory)(nil).KubernetesInformerFactory))
+}
+
 // NetworkingV1 mocks base method
 func (m *MockClientFactory) NetworkingV1() v12.NetworkingV1Interface {
 	m.ctrl.T.Helper()
@@ -202,6 +217,20 @@ func (m *MockNamespaceManager) EXPECT() *MockNamespaceManagerMockRecorder {
 	return m.recorder
 }
 
+// Adopt mocks base method
+func (m *MockNamespaceManager) Adopt(arg0 context.Context, arg1 *v1.Namespace, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Adopt", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Adopt indicates an expected call of Adopt
+func (mr *MockNamespaceManagerMockRecorder) Adopt(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Adopt", reflect.TypeOf((*MockNamespaceManager)(nil).Adopt), arg0, arg1, arg2)
+}
+
 // Create mocks base method
 func (m *MockNamespaceManager) Create(arg0 context.Context, arg1 string, arg2 map[string]string) (string, error) {
 	m.ctrl.T.Helper()
@@ -231,6 +260,20 @@ func (mr *MockNamespaceManagerMockRecorder) Delete(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockNamespaceManager)(nil).Delete), arg0, arg1)
 }
 
+// Release mocks base method
+func (m *MockNamespaceManager) Release(arg0 context.Context, arg1 *v1.Namespace) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release
+func (mr *MockNamespaceManagerMockRecorder) Release(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockNamespaceManager)(nil).Release), arg0, arg1)
+}
+
 // MockPipelineRun is a mock of PipelineRun interface
 type MockPipelineRun struct {
 	ctrl     *gomock.Controller
@@ -297,6 +340,18 @@ func (mr *MockPipelineRunMockRecorder) DeleteFinalizerIfExists(arg0 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFinalizerIfExists", reflect.TypeOf((*MockPipelineRun)(nil).DeleteFinalizerIfExists), arg0)
 }
 
+// FinishAttempt mocks base method
+func (m *MockPipelineRun) FinishAttempt() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "FinishAttempt")
+}
+
+// FinishAttempt indicates an expected call of FinishAttempt
+func (mr *MockPipelineRunMockRecorder) FinishAttempt() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishAttempt", reflect.TypeOf((*MockPipelineRun)(nil).FinishAttempt))
+}
+
 // GetAPIObject mocks base method
 func (m *MockPipelineRun) GetAPIObject() *v1alpha1.PipelineRun {
 	m.ctrl.T.Helper()
@@ -480,6 +535,18 @@ func (mr *MockPipelineRunMockRecorder) String() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "String", reflect.TypeOf((*MockPipelineRun)(nil).String))
 }
 
+// UpdateAbortRequestedAt mocks base method
+func (m *MockPipelineRun) UpdateAbortRequestedAt(arg0 v10.Time) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateAbortRequestedAt", arg0)
+}
+
+// UpdateAbortRequestedAt indicates an expected call of UpdateAbortRequestedAt
+func (mr *MockPipelineRunMockRecorder) UpdateAbortRequestedAt(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAbortRequestedAt", reflect.TypeOf((*MockPipelineRun)(nil).UpdateAbortRequestedAt), arg0)
+}
+
 // UpdateAuxNamespace mocks base method
 func (m *MockPipelineRun) UpdateAuxNamespace(arg0 string) {
 	m.ctrl.T.Helper()
@@ -492,6 +559,18 @@ func (mr *MockPipelineRunMockRecorder) UpdateAuxNamespace(arg0 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAuxNamespace", reflect.TypeOf((*MockPipelineRun)(nil).UpdateAuxNamespace), arg0)
 }
 
+// UpdateCleanupFailed mocks base method
+func (m *MockPipelineRun) UpdateCleanupFailed(arg0 error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateCleanupFailed", arg0)
+}
+
+// UpdateCleanupFailed indicates an expected call of UpdateCleanupFailed
+func (mr *MockPipelineRunMockRecorder) UpdateCleanupFailed(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCleanupFailed", reflect.TypeOf((*MockPipelineRun)(nil).UpdateCleanupFailed), arg0)
+}
+
 // UpdateContainer mocks base method
 func (m *MockPipelineRun) UpdateContainer(arg0 *v1.ContainerState) {
 	m.ctrl.T.Helper()
@@ -504,6 +583,18 @@ func (mr *MockPipelineRunMockRecorder) UpdateContainer(arg0 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateContainer", reflect.TypeOf((*MockPipelineRun)(nil).UpdateContainer), arg0)
 }
 
+// UpdateJenkinsfileRunnerResources mocks base method
+func (m *MockPipelineRun) UpdateJenkinsfileRunnerResources(arg0 *v1.ResourceRequirements) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateJenkinsfileRunnerResources", arg0)
+}
+
+// UpdateJenkinsfileRunnerResources indicates an expected call of UpdateJenkinsfileRunnerResources
+func (mr *MockPipelineRunMockRecorder) UpdateJenkinsfileRunnerResources(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateJenkinsfileRunnerResources", reflect.TypeOf((*MockPipelineRun)(nil).UpdateJenkinsfileRunnerResources), arg0)
+}
+
 // UpdateMessage mocks base method
 func (m *MockPipelineRun) UpdateMessage(arg0 string) {
 	m.ctrl.T.Helper()
@@ -528,6 +619,18 @@ func (mr *MockPipelineRunMockRecorder) UpdateResult(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateResult", reflect.TypeOf((*MockPipelineRun)(nil).UpdateResult), arg0, arg1)
 }
 
+// UpdateRunBackend mocks base method
+func (m *MockPipelineRun) UpdateRunBackend(arg0 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateRunBackend", arg0)
+}
+
+// UpdateRunBackend indicates an expected call of UpdateRunBackend
+func (mr *MockPipelineRunMockRecorder) UpdateRunBackend(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRunBackend", reflect.TypeOf((*MockPipelineRun)(nil).UpdateRunBackend), arg0)
+}
+
 // UpdateRunNamespace mocks base method
 func (m *MockPipelineRun) UpdateRunNamespace(arg0 string) {
 	m.ctrl.T.Helper()
@@ -540,6 +643,18 @@ func (mr *MockPipelineRunMockRecorder) UpdateRunNamespace(arg0 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRunNamespace", reflect.TypeOf((*MockPipelineRun)(nil).UpdateRunNamespace), arg0)
 }
 
+// UpdateSidecars mocks base method
+func (m *MockPipelineRun) UpdateSidecars(arg0 []v1alpha1.SidecarState) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateSidecars", arg0)
+}
+
+// UpdateSidecars indicates an expected call of UpdateSidecars
+func (mr *MockPipelineRunMockRecorder) UpdateSidecars(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSidecars", reflect.TypeOf((*MockPipelineRun)(nil).UpdateSidecars), arg0)
+}
+
 // UpdateState mocks base method
 func (m *MockPipelineRun) UpdateState(arg0 v1alpha1.State, arg1 v10.Time) error {
 	m.ctrl.T.Helper()
@@ -554,6 +669,30 @@ func (mr *MockPipelineRunMockRecorder) UpdateState(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateState", reflect.TypeOf((*MockPipelineRun)(nil).UpdateState), arg0, arg1)
 }
 
+// UpdateTenantSettings mocks base method
+func (m *MockPipelineRun) UpdateTenantSettings(arg0 *v1alpha1.TenantPipelineRunsSettings) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateTenantSettings", arg0)
+}
+
+// UpdateTenantSettings indicates an expected call of UpdateTenantSettings
+func (mr *MockPipelineRunMockRecorder) UpdateTenantSettings(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTenantSettings", reflect.TypeOf((*MockPipelineRun)(nil).UpdateTenantSettings), arg0)
+}
+
+// UpdateTimeout mocks base method
+func (m *MockPipelineRun) UpdateTimeout(arg0 *v10.Duration) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateTimeout", arg0)
+}
+
+// UpdateTimeout indicates an expected call of UpdateTimeout
+func (mr *MockPipelineRunMockRecorder) UpdateTimeout(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTimeout", reflect.TypeOf((*MockPipelineRun)(nil).UpdateTimeout), arg0)
+}
+
 // MockPipelineRunFetcher is a mock of PipelineRunFetcher interface
 type MockPipelineRunFetcher struct {
 	ctrl     *gomock.Controller
@@ -17,6 +17,8 @@ import (
 //NamespaceManager manages namespaces
 type NamespaceManager interface {
 	Create(ctx context.Context, name string, annotations map[string]string) (string, error)
+	Adopt(ctx context.Context, namespace *v1.Namespace, nameCustomPart string) error
+	Release(ctx context.Context, namespace *v1.Namespace) error
 	Delete(ctx context.Context, name string) error
 }
 
@@ -40,6 +42,13 @@ const (
 	labelID     = "id"
 )
 
+// GetManagedNamespacePrefix returns the prefix of the namespace manager
+// which manages the given namespace or the empty string if the namespace
+// is not managed.
+func GetManagedNamespacePrefix(namespace metav1.Object) string {
+	return namespace.GetLabels()[labelPrefix]
+}
+
 //Create creates a new namespace.
 //    nameCustomPart	the namespace name will be <prefix>-<nameCustomPart>-<random>
 //    annotations       annotations to create on the namespace
@@ -68,6 +77,53 @@ func (m *namespaceManager) Create(ctx context.Context, nameCustomPart string, an
 	return createdNamespace.GetName(), nil
 }
 
+//Adopt turns an existing namespace into a namespace managed by this
+//manager, i.e. it can be deleted via Delete afterwards.
+//    namespace         the namespace to adopt, including labels to be set in addition
+//    nameCustomPart    the same as for Create
+func (m *namespaceManager) Adopt(ctx context.Context, namespace *v1.Namespace, nameCustomPart string) error {
+	name := namespace.GetName()
+	if !strings.HasPrefix(name, m.prefix) {
+		return errors.Errorf("refused to adopt namespace '%s': name does not start with '%s'", name, m.prefix)
+	}
+	if value, found := namespace.GetLabels()[labelPrefix]; found && value != m.prefix {
+		return errors.Errorf("refused to adopt namespace '%s': managed by another Steward client (label mismatch)", name)
+	}
+	namespace = namespace.DeepCopy()
+	labels := namespace.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[labelPrefix] = m.prefix
+	labels[labelID] = nameCustomPart
+	namespace.SetLabels(labels)
+	if _, err := m.nsInterface.Update(ctx, namespace, metav1.UpdateOptions{}); err != nil {
+		return errors.WithMessagef(err, "error updating namespace '%s'", name)
+	}
+	klog.V(2).Infof("Namespace '%s' adopted", name)
+	return nil
+}
+
+//Release reverts Adopt, i.e. the namespace is not managed by this manager
+//anymore and cannot be deleted via Delete.
+//    namespace         the namespace to release, including label changes to be applied in addition
+func (m *namespaceManager) Release(ctx context.Context, namespace *v1.Namespace) error {
+	name := namespace.GetName()
+	if value, found := namespace.GetLabels()[labelPrefix]; found && value != m.prefix {
+		return errors.Errorf("refused to release namespace '%s': managed by another Steward client (label mismatch)", name)
+	}
+	namespace = namespace.DeepCopy()
+	labels := namespace.GetLabels()
+	delete(labels, labelPrefix)
+	delete(labels, labelID)
+	namespace.SetLabels(labels)
+	if _, err := m.nsInterface.Update(ctx, namespace, metav1.UpdateOptions{}); err != nil {
+		return errors.WithMessagef(err, "error updating namespace '%s'", name)
+	}
+	klog.V(2).Infof("Namespace '%s' released", name)
+	return nil
+}
+
 // Delete removes a namespace if existing
 // returns nil error if deletion was successful or namespace did not exist before
 func (m *namespaceManager) Delete(ctx context.Context, name string) error {
@@ -139,6 +139,111 @@ func Test_namespaceManager_Create_ExistsAlready(t *testing.T) {
 	assert.Equal(t, "", result)
 }
 
+func Test_namespaceManager_Adopt_Success(t *testing.T) {
+	// SETUP
+	ctx := context.Background()
+	namespace := fake.Namespace("prefix1-foo")
+	namespace.SetLabels(map[string]string{"key1": "value1"})
+	cf := fake.NewClientFactory(namespace)
+	examinee := NewNamespaceManager(cf, "prefix1", 0)
+	adoptedNamespace := namespace.DeepCopy()
+	adoptedNamespace.Labels["key2"] = "value2"
+
+	// EXERCISE
+	err := examinee.Adopt(ctx, adoptedNamespace, "tenant1")
+
+	// VERIFY
+	assert.NilError(t, err)
+	result, err := cf.CoreV1().Namespaces().Get(ctx, "prefix1-foo", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, map[string]string{
+		"key1":      "value1",
+		"key2":      "value2",
+		labelPrefix: "prefix1",
+		labelID:     "tenant1",
+	}, result.GetLabels())
+
+	// adopted namespace can be deleted
+	err = examinee.Delete(ctx, "prefix1-foo")
+	assert.NilError(t, err)
+	assert.Equal(t, 0, countNamespaces(ctx, cf))
+}
+
+func Test_namespaceManager_Release_Success(t *testing.T) {
+	// SETUP
+	ctx := context.Background()
+	namespace := fake.Namespace("prefix1-foo")
+	namespace.SetLabels(map[string]string{"key1": "value1"})
+	cf := fake.NewClientFactory(namespace)
+	examinee := NewNamespaceManager(cf, "prefix1", 0)
+	err := examinee.Adopt(ctx, namespace, "tenant1")
+	assert.NilError(t, err)
+	adoptedNamespace, err := cf.CoreV1().Namespaces().Get(ctx, "prefix1-foo", metav1.GetOptions{})
+	assert.NilError(t, err)
+	adoptedNamespace.Labels["key2"] = "value2"
+
+	// EXERCISE
+	err = examinee.Release(ctx, adoptedNamespace)
+
+	// VERIFY
+	assert.NilError(t, err)
+	result, err := cf.CoreV1().Namespaces().Get(ctx, "prefix1-foo", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, map[string]string{
+		"key1": "value1",
+		"key2": "value2",
+	}, result.GetLabels())
+
+	// released namespace cannot be deleted
+	err = examinee.Delete(ctx, "prefix1-foo")
+	assert.Error(t, err, "refused to delete namespace 'prefix1-foo': not a Steward namespace (label mismatch)")
+	assert.Equal(t, 1, countNamespaces(ctx, cf))
+}
+
+func Test_namespaceManager_Release_FailsIfPrefixLabelDoesNotMatch(t *testing.T) {
+	// SETUP
+	ctx := context.Background()
+	namespace := fake.Namespace("prefix1-foo")
+	namespace.SetLabels(map[string]string{labelPrefix: "prefix"})
+	cf := fake.NewClientFactory(namespace)
+	examinee := NewNamespaceManager(cf, "prefix1", 0)
+
+	// EXERCISE
+	err := examinee.Release(ctx, namespace)
+
+	// VERIFY
+	assert.Error(t, err, "refused to release namespace 'prefix1-foo': managed by another Steward client (label mismatch)")
+}
+
+func Test_namespaceManager_Adopt_FailsIfNameDoesNotStartWithPrefix(t *testing.T) {
+	// SETUP
+	ctx := context.Background()
+	namespace := fake.Namespace("foo")
+	cf := fake.NewClientFactory(namespace)
+	examinee := NewNamespaceManager(cf, "prefix1", 0)
+
+	// EXERCISE
+	err := examinee.Adopt(ctx, namespace, "tenant1")
+
+	// VERIFY
+	assert.Error(t, err, "refused to adopt namespace 'foo': name does not start with 'prefix1'")
+}
+
+func Test_namespaceManager_Adopt_FailsIfPrefixLabelDoesNotMatch(t *testing.T) {
+	// SETUP
+	ctx := context.Background()
+	namespace := fake.Namespace("prefix1-foo")
+	namespace.SetLabels(map[string]string{labelPrefix: "prefix"})
+	cf := fake.NewClientFactory(namespace)
+	examinee := NewNamespaceManager(cf, "prefix1", 0)
+
+	// EXERCISE
+	err := examinee.Adopt(ctx, namespace, "tenant1")
+
+	// VERIFY
+	assert.Error(t, err, "refused to adopt namespace 'prefix1-foo': managed by another Steward client (label mismatch)")
+}
+
 func Test_namespaceManager_Delete_Success(t *testing.T) {
 	// SETUP
 	const namespaceName = "namespace1"
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"regexp"
+	"strings"
 	"time"
 
 	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
@@ -19,6 +21,10 @@ import (
 	klog "k8s.io/klog/v2"
 )
 
+// RepoURLSchemeSSH is the scheme of pipeline repository server URLs
+// returned for repositories accessed via SSH.
+const RepoURLSchemeSSH = "ssh"
+
 // PipelineRun is a wrapper for the K8s PipelineRun resource
 type PipelineRun interface {
 	fmt.Stringer
@@ -39,9 +45,17 @@ type PipelineRun interface {
 	UpdateState(api.State, metav1.Time) error
 	UpdateResult(api.Result, metav1.Time)
 	UpdateContainer(*corev1.ContainerState)
+	UpdateSidecars([]api.SidecarState)
 	StoreErrorAsMessage(error, string) error
 	UpdateRunNamespace(string)
 	UpdateAuxNamespace(string)
+	UpdateRunBackend(string)
+	UpdateTimeout(*metav1.Duration)
+	UpdateJenkinsfileRunnerResources(*corev1.ResourceRequirements)
+	UpdateTenantSettings(*api.TenantPipelineRunsSettings)
+	UpdateAbortRequestedAt(metav1.Time)
+	UpdateCleanupFailed(error)
+	FinishAttempt()
 	UpdateMessage(string)
 }
 
@@ -115,14 +129,30 @@ func (r *pipelineRun) GetNamespace() string {
 	return r.apiObj.GetNamespace()
 }
 
+// scpLikeRepoURLPattern matches scp-like Git URLs, e.g. `git@github.com:org/repo`.
+var scpLikeRepoURLPattern = regexp.MustCompile(`^(?:[^@/:\s]+@)?([a-zA-Z0-9][-.a-zA-Z0-9]*):[^/\s].*$`)
+
 // GetPipelineRepoServerURL returns the server hosting the Jenkinsfile repository
+// as `<scheme>://<host>[:<port>]`. The scheme is `ssh` for SSH URLs, including
+// scp-like URLs like `git@github.com:org/repo`.
 func (r *pipelineRun) GetPipelineRepoServerURL() (string, error) {
 	urlString := r.GetSpec().JenkinsFile.URL
+	if !strings.Contains(urlString, "://") {
+		if match := scpLikeRepoURLPattern.FindStringSubmatch(urlString); match != nil {
+			return fmt.Sprintf("%s://%s", RepoURLSchemeSSH, match[1]), nil
+		}
+	}
 	repoURL, err := url.Parse(urlString)
 	if err != nil {
 		return "", errors.Wrapf(err, "value %q of field spec.jenkinsFile.url is invalid [%s]", urlString, r.String())
 	}
-	if !(repoURL.Scheme == "http") && !(repoURL.Scheme == "https") {
+	switch repoURL.Scheme {
+	case "http", "https":
+	case RepoURLSchemeSSH:
+		if repoURL.Host == "" {
+			return "", fmt.Errorf("value %q of field spec.jenkinsFile.url is invalid [%s]: host is missing", urlString, r.String())
+		}
+	default:
 		return "", fmt.Errorf("value %q of field spec.jenkinsFile.url is invalid [%s]: scheme not supported: %q", urlString, r.String(), repoURL.Scheme)
 	}
 	return fmt.Sprintf("%s://%s", repoURL.Scheme, repoURL.Host), nil
@@ -163,6 +193,7 @@ func (r *pipelineRun) InitState() error {
 		}
 		s.StateDetails = newStateDetails
 		s.State = api.StateNew
+		updateConditions(s)
 		return nil, nil
 	})
 }
@@ -185,7 +216,7 @@ func (r *pipelineRun) UpdateState(state api.State, ts metav1.Time) error {
 		if currentStateDetails.State != oldStateDetails.State {
 			return nil, fmt.Errorf("State cannot be updated as it was changed concurrently from %q to %q", oldStateDetails.State, currentStateDetails.State)
 		}
-		if state == api.StatePreparing {
+		if state == api.StatePreparing && s.StartedAt == nil {
 			s.StartedAt = &ts
 		}
 		currentStateDetails.FinishedAt = ts
@@ -203,6 +234,7 @@ func (r *pipelineRun) UpdateState(state api.State, ts metav1.Time) error {
 		s.StateDetails = newStateDetails
 		s.StateHistory = his
 		s.State = state
+		updateConditions(s)
 		return commitRecorderFunc, nil
 	})
 }
@@ -234,6 +266,19 @@ func (r *pipelineRun) UpdateContainer(c *corev1.ContainerState) {
 	})
 }
 
+// UpdateSidecars stores the states of the sidecar containers.
+// If `sidecars` is empty, the stored states are kept.
+func (r *pipelineRun) UpdateSidecars(sidecars []api.SidecarState) {
+	if len(sidecars) == 0 {
+		return
+	}
+	r.ensureCopy()
+	r.mustChangeStatusAndStoreForRetry(func(s *api.PipelineStatus) (commitRecorderFunc, error) {
+		s.Sidecars = append([]api.SidecarState(nil), sidecars...)
+		return nil, nil
+	})
+}
+
 // StoreErrorAsMessage stores the error as message in the status
 func (r *pipelineRun) StoreErrorAsMessage(err error, message string) error {
 	if err != nil {
@@ -280,6 +325,101 @@ func (r *pipelineRun) UpdateAuxNamespace(ns string) {
 	})
 }
 
+// UpdateRunBackend sets the name of the backend executing the pipeline run.
+func (r *pipelineRun) UpdateRunBackend(backend string) {
+	r.ensureCopy()
+	r.mustChangeStatusAndStoreForRetry(func(s *api.PipelineStatus) (commitRecorderFunc, error) {
+		s.RunBackend = backend
+		return nil, nil
+	})
+}
+
+// UpdateTimeout sets the effective timeout of the pipeline run.
+func (r *pipelineRun) UpdateTimeout(timeout *metav1.Duration) {
+	r.ensureCopy()
+	r.mustChangeStatusAndStoreForRetry(func(s *api.PipelineStatus) (commitRecorderFunc, error) {
+		s.Timeout = timeout.DeepCopy()
+		return nil, nil
+	})
+}
+
+// UpdateJenkinsfileRunnerResources sets the effective resource requirements
+// of the Jenkinsfile Runner container.
+func (r *pipelineRun) UpdateJenkinsfileRunnerResources(resources *corev1.ResourceRequirements) {
+	r.ensureCopy()
+	r.mustChangeStatusAndStoreForRetry(func(s *api.PipelineStatus) (commitRecorderFunc, error) {
+		s.JenkinsfileRunnerResources = resources.DeepCopy()
+		return nil, nil
+	})
+}
+
+// UpdateTenantSettings sets the pipeline run settings effective for the
+// tenant the pipeline run belongs to.
+func (r *pipelineRun) UpdateTenantSettings(settings *api.TenantPipelineRunsSettings) {
+	r.ensureCopy()
+	r.mustChangeStatusAndStoreForRetry(func(s *api.PipelineStatus) (commitRecorderFunc, error) {
+		s.TenantSettings = settings.DeepCopy()
+		return nil, nil
+	})
+}
+
+// UpdateAbortRequestedAt sets the time the run backend has been requested
+// to stop the Jenkinsfile Runner of the aborted pipeline run.
+func (r *pipelineRun) UpdateAbortRequestedAt(ts metav1.Time) {
+	r.ensureCopy()
+	r.mustChangeStatusAndStoreForRetry(func(s *api.PipelineStatus) (commitRecorderFunc, error) {
+		s.AbortRequestedAt = &ts
+		return nil, nil
+	})
+}
+
+// UpdateCleanupFailed records that cleaning up the sandbox of the pipeline
+// run has failed with the given error. The condition `SandboxCleanedUp`
+// becomes false until the next state change.
+func (r *pipelineRun) UpdateCleanupFailed(err error) {
+	r.ensureCopy()
+	r.mustChangeStatusAndStoreForRetry(func(s *api.PipelineStatus) (commitRecorderFunc, error) {
+		setCleanupFailedCondition(s, err)
+		return nil, nil
+	})
+}
+
+// FinishAttempt stores the outcome of the current attempt in the list of
+// attempts and resets the status fields describing the current attempt,
+// so that the pipeline run can be started again.
+func (r *pipelineRun) FinishAttempt() {
+	r.ensureCopy()
+	r.mustChangeStatusAndStoreForRetry(func(s *api.PipelineStatus) (commitRecorderFunc, error) {
+		attempt := api.Attempt{
+			FinishedAt:         s.FinishedAt,
+			Result:             s.Result,
+			Message:            s.Message,
+			Namespace:          s.Namespace,
+			AuxiliaryNamespace: s.AuxiliaryNamespace,
+		}
+		// the attempt has been started when the preparing state was
+		// entered the last time
+		for i := len(s.StateHistory) - 1; i >= 0; i-- {
+			if s.StateHistory[i].State == api.StatePreparing {
+				startedAt := s.StateHistory[i].StartedAt
+				attempt.StartedAt = &startedAt
+				break
+			}
+		}
+		s.Attempts = append(s.Attempts, attempt)
+
+		s.FinishedAt = nil
+		s.Result = api.ResultUndefined
+		s.Message = ""
+		s.MessageShort = ""
+		s.Namespace = ""
+		s.AuxiliaryNamespace = ""
+		s.Container = corev1.ContainerState{}
+		s.Sidecars = nil
+		return nil, nil
+	})
+}
+
 //HasDeletionTimestamp returns true if deletion timestamp is set
 func (r *pipelineRun) HasDeletionTimestamp() bool {
 	return !r.apiObj.ObjectMeta.DeletionTimestamp.IsZero()
@@ -0,0 +1,103 @@
+package k8s
+
+import (
+	"strings"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	knativeapis "knative.dev/pkg/apis"
+)
+
+// updateConditions derives the conditions of a pipeline run from the
+// state and result in the given status.
+// Condition `Succeeded` is derived from the result as soon as it is set,
+// i.e. already while the sandbox gets cleaned up. Conditions are updated
+// on state changes only, so a cleanup failure recorded via
+// `setCleanupFailedCondition` is kept until the next state change.
+func updateConditions(s *api.PipelineStatus) {
+	stateReason := conditionReason(string(s.State))
+	resultReason := conditionReason(string(s.Result))
+
+	switch {
+	case s.Result == api.ResultUndefined:
+		s.SetCondition(&knativeapis.Condition{
+			Type:   api.PipelineRunConditionSucceeded,
+			Status: corev1.ConditionUnknown,
+			Reason: stateReason,
+		})
+	case s.Result == api.ResultSuccess:
+		s.SetCondition(&knativeapis.Condition{
+			Type:   api.PipelineRunConditionSucceeded,
+			Status: corev1.ConditionTrue,
+		})
+	default:
+		s.SetCondition(&knativeapis.Condition{
+			Type:    api.PipelineRunConditionSucceeded,
+			Status:  corev1.ConditionFalse,
+			Reason:  resultReason,
+			Message: s.Message,
+		})
+	}
+
+	switch s.State {
+	case api.StateFinished:
+		s.SetCondition(&knativeapis.Condition{
+			Type:   api.PipelineRunConditionSandboxCleanedUp,
+			Status: corev1.ConditionTrue,
+		})
+	default:
+		s.SetCondition(&knativeapis.Condition{
+			Type:   api.PipelineRunConditionSandboxCleanedUp,
+			Status: corev1.ConditionUnknown,
+			Reason: stateReason,
+		})
+	}
+
+	switch s.State {
+	case api.StateWaiting, api.StateRunning:
+		s.SetCondition(&knativeapis.Condition{
+			Type:   api.PipelineRunConditionPrepared,
+			Status: corev1.ConditionTrue,
+		})
+	case api.StateCleaning, api.StateFinished:
+		// a pipeline run which got a result before the preparation
+		// has been completed has failed to prepare
+		if !s.GetCondition(api.PipelineRunConditionPrepared).IsTrue() {
+			s.SetCondition(&knativeapis.Condition{
+				Type:    api.PipelineRunConditionPrepared,
+				Status:  corev1.ConditionFalse,
+				Reason:  resultReason,
+				Message: s.Message,
+			})
+		}
+	default:
+		s.SetCondition(&knativeapis.Condition{
+			Type:   api.PipelineRunConditionPrepared,
+			Status: corev1.ConditionUnknown,
+			Reason: stateReason,
+		})
+	}
+}
+
+// setCleanupFailedCondition records in the given status that cleaning up
+// the sandbox has failed with the given error.
+func setCleanupFailedCondition(s *api.PipelineStatus, err error) {
+	s.SetCondition(&knativeapis.Condition{
+		Type:    api.PipelineRunConditionSandboxCleanedUp,
+		Status:  corev1.ConditionFalse,
+		Reason:  api.PipelineRunConditionReasonCleanupFailed,
+		Message: err.Error(),
+	})
+}
+
+// conditionReason converts a state or result name like `error_infra` into
+// a condition reason like `ErrorInfra`.
+func conditionReason(name string) string {
+	var b strings.Builder
+	for _, word := range strings.Split(name, "_") {
+		if word != "" {
+			b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+	return b.String()
+}
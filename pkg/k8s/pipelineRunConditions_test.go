@@ -0,0 +1,176 @@
+package k8s
+
+import (
+	"errors"
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	knativeapis "knative.dev/pkg/apis"
+)
+
+func Test_updateConditions(t *testing.T) {
+	t.Parallel()
+
+	type expectedCondition struct {
+		status corev1.ConditionStatus
+		reason string
+	}
+
+	for _, tc := range []struct {
+		name             string
+		states           []api.State
+		result           api.Result
+		expectedSucc     expectedCondition
+		expectedPrepared expectedCondition
+		expectedCleaned  expectedCondition
+	}{
+		{
+			"new",
+			[]api.State{api.StateNew},
+			api.ResultUndefined,
+			expectedCondition{corev1.ConditionUnknown, "New"},
+			expectedCondition{corev1.ConditionUnknown, "New"},
+			expectedCondition{corev1.ConditionUnknown, "New"},
+		},
+		{
+			"preparing",
+			[]api.State{api.StateNew, api.StateQueued, api.StatePreparing},
+			api.ResultUndefined,
+			expectedCondition{corev1.ConditionUnknown, "Preparing"},
+			expectedCondition{corev1.ConditionUnknown, "Preparing"},
+			expectedCondition{corev1.ConditionUnknown, "Preparing"},
+		},
+		{
+			"running",
+			[]api.State{api.StatePreparing, api.StateWaiting, api.StateRunning},
+			api.ResultUndefined,
+			expectedCondition{corev1.ConditionUnknown, "Running"},
+			expectedCondition{corev1.ConditionTrue, ""},
+			expectedCondition{corev1.ConditionUnknown, "Running"},
+		},
+		{
+			"cleaning_after_run",
+			[]api.State{api.StatePreparing, api.StateWaiting, api.StateRunning, api.StateCleaning},
+			api.ResultErrorContent,
+			expectedCondition{corev1.ConditionFalse, "ErrorContent"},
+			expectedCondition{corev1.ConditionTrue, ""},
+			expectedCondition{corev1.ConditionUnknown, "Cleaning"},
+		},
+		{
+			"cleaning_after_success",
+			[]api.State{api.StatePreparing, api.StateWaiting, api.StateRunning, api.StateCleaning},
+			api.ResultSuccess,
+			expectedCondition{corev1.ConditionTrue, ""},
+			expectedCondition{corev1.ConditionTrue, ""},
+			expectedCondition{corev1.ConditionUnknown, "Cleaning"},
+		},
+		{
+			"finished_success",
+			[]api.State{api.StatePreparing, api.StateWaiting, api.StateRunning, api.StateCleaning, api.StateFinished},
+			api.ResultSuccess,
+			expectedCondition{corev1.ConditionTrue, ""},
+			expectedCondition{corev1.ConditionTrue, ""},
+			expectedCondition{corev1.ConditionTrue, ""},
+		},
+		{
+			"finished_content_error",
+			[]api.State{api.StatePreparing, api.StateWaiting, api.StateRunning, api.StateCleaning, api.StateFinished},
+			api.ResultErrorContent,
+			expectedCondition{corev1.ConditionFalse, "ErrorContent"},
+			expectedCondition{corev1.ConditionTrue, ""},
+			expectedCondition{corev1.ConditionTrue, ""},
+		},
+		{
+			"preparing_failed",
+			[]api.State{api.StatePreparing, api.StateCleaning, api.StateFinished},
+			api.ResultErrorInfra,
+			expectedCondition{corev1.ConditionFalse, "ErrorInfra"},
+			expectedCondition{corev1.ConditionFalse, "ErrorInfra"},
+			expectedCondition{corev1.ConditionTrue, ""},
+		},
+		{
+			"retried",
+			[]api.State{api.StatePreparing, api.StateWaiting, api.StateCleaning, api.StatePreparing},
+			api.ResultUndefined,
+			expectedCondition{corev1.ConditionUnknown, "Preparing"},
+			expectedCondition{corev1.ConditionUnknown, "Preparing"},
+			expectedCondition{corev1.ConditionUnknown, "Preparing"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			status := &api.PipelineStatus{
+				Result:  tc.result,
+				Message: "message1",
+			}
+
+			// EXERCISE
+			for _, state := range tc.states {
+				status.State = state
+				updateConditions(status)
+			}
+
+			// VERIFY
+			for condType, expected := range map[knativeapis.ConditionType]expectedCondition{
+				api.PipelineRunConditionSucceeded:        tc.expectedSucc,
+				api.PipelineRunConditionPrepared:         tc.expectedPrepared,
+				api.PipelineRunConditionSandboxCleanedUp: tc.expectedCleaned,
+			} {
+				cond := status.GetCondition(condType)
+				assert.Assert(t, cond != nil, "condition %s", condType)
+				assert.Equal(t, expected.status, cond.Status, "condition %s", condType)
+				assert.Equal(t, expected.reason, cond.Reason, "condition %s", condType)
+				if cond.Status == corev1.ConditionFalse {
+					assert.Equal(t, "message1", cond.Message, "condition %s", condType)
+				}
+			}
+		})
+	}
+}
+
+func Test_setCleanupFailedCondition(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	status := &api.PipelineStatus{
+		State:  api.StateCleaning,
+		Result: api.ResultSuccess,
+	}
+	updateConditions(status)
+
+	// EXERCISE
+	setCleanupFailedCondition(status, errors.New("error1"))
+
+	// VERIFY
+	cond := status.GetCondition(api.PipelineRunConditionSandboxCleanedUp)
+	assert.Equal(t, corev1.ConditionFalse, cond.Status)
+	assert.Equal(t, api.PipelineRunConditionReasonCleanupFailed, cond.Reason)
+	assert.Equal(t, "error1", cond.Message)
+	assert.Assert(t, status.GetCondition(api.PipelineRunConditionSucceeded).IsTrue())
+
+	// the failure is reset by the next state change
+	status.State = api.StateFinished
+	updateConditions(status)
+	assert.Assert(t, status.GetCondition(api.PipelineRunConditionSandboxCleanedUp).IsTrue())
+}
+
+func Test_conditionReason(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     string
+		expected string
+	}{
+		{"", ""},
+		{"new", "New"},
+		{"error_infra", "ErrorInfra"},
+		{"a__b_", "AB"},
+	} {
+		assert.Equal(t, tc.expected, conditionReason(tc.name))
+	}
+}
@@ -5,13 +5,16 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
 	"github.com/SAP/stewardci-core/pkg/k8s/fake"
 	"gotest.tools/assert"
 	"gotest.tools/assert/cmp"
 	is "gotest.tools/assert/cmp"
+	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	k8stesting "k8s.io/client-go/testing"
@@ -437,6 +440,206 @@ func Test_pipelineRun_UpdateResult(t *testing.T) {
 	assert.Equal(t, api.ResultSuccess, status.Result)
 	assert.Assert(t, !examinee.GetStatus().FinishedAt.IsZero())
 }
+func Test_pipelineRun_UpdateState_KeepsStartTimeOnRetry(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	pipelineRun := newPipelineRunWithEmptySpec(ns1, run1)
+	factory := fake.NewClientFactory(pipelineRun)
+	examinee, err := NewPipelineRun(ctx, pipelineRun, factory)
+	assert.NilError(t, err)
+	firstStart := metav1.NewTime(time.Date(2022, 3, 4, 5, 6, 7, 0, time.UTC))
+	assert.NilError(t, examinee.UpdateState(api.StatePreparing, firstStart))
+	assert.NilError(t, examinee.UpdateState(api.StateCleaning, metav1.Now()))
+
+	// EXERCISE
+	resultErr := examinee.UpdateState(api.StatePreparing, metav1.Now())
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assert.DeepEqual(t, &firstStart, examinee.GetStatus().StartedAt)
+}
+
+func Test_pipelineRun_FinishAttempt(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	pipelineRun := newPipelineRunWithEmptySpec(ns1, run1)
+	factory := fake.NewClientFactory(pipelineRun)
+	examinee, err := NewPipelineRun(ctx, pipelineRun, factory)
+	assert.NilError(t, err)
+	preparingStart := metav1.NewTime(time.Date(2022, 3, 4, 5, 6, 7, 0, time.UTC))
+	finishedAt := metav1.NewTime(time.Date(2022, 3, 4, 6, 0, 0, 0, time.UTC))
+	assert.NilError(t, examinee.UpdateState(api.StatePreparing, preparingStart))
+	assert.NilError(t, examinee.UpdateState(api.StateCleaning, finishedAt))
+	examinee.UpdateRunNamespace("runNamespace1")
+	examinee.UpdateAuxNamespace("auxNamespace1")
+	examinee.UpdateMessage("message1")
+	examinee.UpdateResult(api.ResultErrorInfra, finishedAt)
+	examinee.UpdateContainer(&corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1}})
+	examinee.UpdateSidecars([]api.SidecarState{
+		{Name: "sidecar1", Container: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+	})
+
+	// EXERCISE
+	examinee.FinishAttempt()
+
+	// VERIFY
+	status := examinee.GetStatus()
+	assert.DeepEqual(t, []api.Attempt{
+		{
+			StartedAt:          &preparingStart,
+			FinishedAt:         &finishedAt,
+			Result:             api.ResultErrorInfra,
+			Message:            "message1",
+			Namespace:          "runNamespace1",
+			AuxiliaryNamespace: "auxNamespace1",
+		},
+	}, status.Attempts)
+	assert.Equal(t, api.ResultUndefined, status.Result)
+	assert.Assert(t, status.FinishedAt == nil)
+	assert.Equal(t, "", status.Message)
+	assert.Equal(t, "", status.Namespace)
+	assert.Equal(t, "", status.AuxiliaryNamespace)
+	assert.DeepEqual(t, corev1.ContainerState{}, status.Container)
+	assert.Equal(t, 0, len(status.Sidecars))
+	assert.DeepEqual(t, &preparingStart, status.StartedAt)
+}
+
+func Test_pipelineRun_UpdateAbortRequestedAt(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	pipelineRun := newPipelineRunWithEmptySpec(ns1, run1)
+	factory := fake.NewClientFactory(pipelineRun)
+	examinee, err := NewPipelineRun(ctx, pipelineRun, factory)
+	assert.NilError(t, err)
+	ts := metav1.NewTime(time.Date(2022, 3, 4, 5, 6, 7, 0, time.UTC))
+
+	// EXERCISE
+	examinee.UpdateAbortRequestedAt(ts)
+
+	// VERIFY
+	assert.DeepEqual(t, &ts, examinee.GetStatus().AbortRequestedAt)
+	_, err = examinee.CommitStatus(ctx)
+	assert.NilError(t, err)
+	stored, err := factory.StewardV1alpha1().PipelineRuns(ns1).Get(ctx, run1, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, &ts, stored.Status.AbortRequestedAt)
+}
+
+func Test_pipelineRun_UpdateCleanupFailed(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	pipelineRun := newPipelineRunWithEmptySpec(ns1, run1)
+	factory := fake.NewClientFactory(pipelineRun)
+	examinee, err := NewPipelineRun(ctx, pipelineRun, factory)
+	assert.NilError(t, err)
+
+	// EXERCISE
+	examinee.UpdateCleanupFailed(fmt.Errorf("error1"))
+
+	// VERIFY
+	_, err = examinee.CommitStatus(ctx)
+	assert.NilError(t, err)
+	stored, err := factory.StewardV1alpha1().PipelineRuns(ns1).Get(ctx, run1, metav1.GetOptions{})
+	assert.NilError(t, err)
+	cond := stored.Status.GetCondition(api.PipelineRunConditionSandboxCleanedUp)
+	assert.Equal(t, corev1.ConditionFalse, cond.Status)
+	assert.Equal(t, api.PipelineRunConditionReasonCleanupFailed, cond.Reason)
+	assert.Equal(t, "error1", cond.Message)
+}
+
+func Test_pipelineRun_UpdateJenkinsfileRunnerResources(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	pipelineRun := newPipelineRunWithEmptySpec(ns1, run1)
+	factory := fake.NewClientFactory(pipelineRun)
+	examinee, err := NewPipelineRun(ctx, pipelineRun, factory)
+	assert.NilError(t, err)
+	resources := &corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")},
+	}
+
+	// EXERCISE
+	examinee.UpdateJenkinsfileRunnerResources(resources)
+
+	// VERIFY
+	assert.DeepEqual(t, resources, examinee.GetStatus().JenkinsfileRunnerResources)
+	_, err = examinee.CommitStatus(ctx)
+	assert.NilError(t, err)
+	stored, err := factory.StewardV1alpha1().PipelineRuns(ns1).Get(ctx, run1, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, resources, stored.Status.JenkinsfileRunnerResources)
+}
+
+func Test_pipelineRun_UpdateTenantSettings(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	pipelineRun := newPipelineRunWithEmptySpec(ns1, run1)
+	factory := fake.NewClientFactory(pipelineRun)
+	examinee, err := NewPipelineRun(ctx, pipelineRun, factory)
+	assert.NilError(t, err)
+	maxActive := int64(2)
+	settings := &api.TenantPipelineRunsSettings{
+		DefaultNetworkProfile: "network1",
+		AllowedProfiles:       &api.AllowedProfiles{Network: []string{"network1"}},
+		MaxTimeout:            &metav1.Duration{Duration: 10 * time.Minute},
+		MaxActivePipelineRuns: &maxActive,
+	}
+
+	// EXERCISE
+	examinee.UpdateTenantSettings(settings)
+
+	// VERIFY
+	assert.DeepEqual(t, settings, examinee.GetStatus().TenantSettings)
+	_, err = examinee.CommitStatus(ctx)
+	assert.NilError(t, err)
+	stored, err := factory.StewardV1alpha1().PipelineRuns(ns1).Get(ctx, run1, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, settings, stored.Status.TenantSettings)
+}
+
+func Test_pipelineRun_UpdateSidecars(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	pipelineRun := newPipelineRunWithEmptySpec(ns1, run1)
+	factory := fake.NewClientFactory(pipelineRun)
+	examinee, err := NewPipelineRun(ctx, pipelineRun, factory)
+	assert.NilError(t, err)
+	sidecars := []api.SidecarState{
+		{
+			Name: "sidecar1",
+			Container: corev1.ContainerState{
+				Running: &corev1.ContainerStateRunning{},
+			},
+		},
+	}
+
+	// EXERCISE
+	examinee.UpdateSidecars(sidecars)
+	examinee.UpdateSidecars(nil)
+
+	// VERIFY
+	assert.DeepEqual(t, sidecars, examinee.GetStatus().Sidecars)
+	_, err = examinee.CommitStatus(ctx)
+	assert.NilError(t, err)
+	stored, err := factory.StewardV1alpha1().PipelineRuns(ns1).Get(ctx, run1, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, sidecars, stored.Status.Sidecars)
+}
+
 func Test_pipelineRun_GetPipelineRepoServerURL_CorrectURLs(t *testing.T) {
 	t.Parallel()
 
@@ -450,6 +653,10 @@ func Test_pipelineRun_GetPipelineRepoServerURL_CorrectURLs(t *testing.T) {
 		{url: "HTTPS://foo.com/Path", expectedURL: "https://foo.com"},
 		{url: "https://foo.com:1234/Path", expectedURL: "https://foo.com:1234"},
 		{url: "http://foo.com:1234/Path", expectedURL: "http://foo.com:1234"},
+		{url: "ssh://git@foo.com/Path", expectedURL: "ssh://foo.com"},
+		{url: "ssh://git@foo.com:2222/Path", expectedURL: "ssh://foo.com:2222"},
+		{url: "git@foo.com:org/repo.git", expectedURL: "ssh://foo.com"},
+		{url: "foo.com:org/repo", expectedURL: "ssh://foo.com"},
 	} {
 		t.Run(test.url, func(t *testing.T) {
 			// SETUP
@@ -476,6 +683,8 @@ func Test_pipelineRun_GetPipelineRepoServerURL_WrongURLs(t *testing.T) {
 	}{
 		{url: "&:", expectedErrorPattern: `value "&:" of field spec.jenkinsFile.url is invalid \[.*\]: .*`},
 		{url: "ftp://foo/bar", expectedErrorPattern: `value "ftp://foo/bar" of field spec.jenkinsFile.url is invalid \[.*\]: scheme not supported: .*`},
+		{url: "git@foo.com:/", expectedErrorPattern: `value "git@foo.com:/" of field spec.jenkinsFile.url is invalid \[.*\]: .*`},
+		{url: "ssh:///Path", expectedErrorPattern: `value "ssh:///Path" of field spec.jenkinsFile.url is invalid \[.*\]: host is missing`},
 	} {
 		t.Run(test.url, func(t *testing.T) {
 			// SETUP
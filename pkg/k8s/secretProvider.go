@@ -0,0 +1,217 @@
+package k8s
+
+import (
+	"context"
+	"sync"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	secrets "github.com/SAP/stewardci-core/pkg/k8s/secrets"
+	cachedsecretprovider "github.com/SAP/stewardci-core/pkg/k8s/secrets/providers/cached"
+	encryptedsecretprovider "github.com/SAP/stewardci-core/pkg/k8s/secrets/providers/encrypted"
+	k8ssecretprovider "github.com/SAP/stewardci-core/pkg/k8s/secrets/providers/k8s"
+	vaultsecretprovider "github.com/SAP/stewardci-core/pkg/k8s/secrets/providers/vault"
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/system"
+)
+
+const (
+	// vaultConfigMapName is the name of the config map in the system
+	// namespace configuring the Vault secret provider.
+	vaultConfigMapName = "steward-pipelineruns-vault"
+
+	vaultConfigKeyAddress        = "address"
+	vaultConfigKeyMountPath      = "mountPath"
+	vaultConfigKeyPathTemplate   = "pathTemplate"
+	vaultConfigKeyKubernetesRole = "auth.kubernetes.role"
+	vaultConfigKeyKubernetesPath = "auth.kubernetes.mountPath"
+
+	// vaultTokenSecretKey is the key of the Vault token in the secret
+	// denoted by annotation `AnnotationVaultTokenSecret`.
+	vaultTokenSecretKey = "token"
+
+	// encryptedSecretsKeySecretName is the name of the secret in the
+	// system namespace containing the private key of the encrypted secret
+	// provider.
+	encryptedSecretsKeySecretName = "steward-encrypted-secrets-key"
+
+	// encryptedSecretsKeySecretKey is the key of the base64-encoded private
+	// key in secret `encryptedSecretsKeySecretName`.
+	encryptedSecretsKeySecretKey = "privateKey"
+)
+
+// tenantSecretProvider is the secret provider of a tenant namespace. It
+// delegates to the provider selected by the client namespace owning the
+// tenant namespace, which is determined when the first secret is requested.
+type tenantSecretProvider struct {
+	factory     ClientFactory
+	namespace   string
+	secretCache *cachedsecretprovider.SecretCache
+
+	mutex    sync.Mutex
+	delegate secrets.SecretProvider
+}
+
+// GetSecret returns the secret with the given name from the secret
+// provider selected for the tenant namespace.
+func (p *tenantSecretProvider) GetSecret(ctx context.Context, name string) (*v1.Secret, error) {
+	delegate, err := p.getDelegate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return delegate.GetSecret(ctx, name)
+}
+
+func (p *tenantSecretProvider) getDelegate(ctx context.Context) (secrets.SecretProvider, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.delegate == nil {
+		delegate, err := newSecretProvider(ctx, p.factory, p.namespace, p.secretCache)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed to create secret provider for namespace %q", p.namespace)
+		}
+		p.delegate = delegate
+	}
+	return p.delegate, nil
+}
+
+/*
+newSecretProvider creates the secret provider for the given tenant
+namespace as selected by annotation `AnnotationSecretProvider` of the
+client namespace owning it.
+
+The Kubernetes secret provider is used if the tenant namespace is not
+labelled as owned by a client namespace or the client namespace does not
+exist. If a secret cache is given, the Kubernetes secret provider of tenant
+namespaces owned by a client namespace reads from the cache.
+*/
+func newSecretProvider(ctx context.Context, factory ClientFactory, namespace string, secretCache *cachedsecretprovider.SecretCache) (secrets.SecretProvider, error) {
+	tenantNamespace, clientNamespace, err := getOwnerClientNamespace(ctx, factory, namespace)
+	if err != nil {
+		return nil, err
+	}
+	var providerName string
+	if clientNamespace != nil {
+		providerName = clientNamespace.GetAnnotations()[api.AnnotationSecretProvider]
+	}
+
+	switch providerName {
+	case "", api.SecretProviderKubernetes:
+		provider := k8ssecretprovider.NewProvider(factory.CoreV1().Secrets(namespace), namespace)
+		if secretCache != nil && clientNamespace != nil {
+			return secretCache.NewProvider(namespace, provider), nil
+		}
+		return provider, nil
+	case api.SecretProviderVault:
+		return newVaultSecretProvider(ctx, factory, tenantNamespace, clientNamespace)
+	case api.SecretProviderEncrypted:
+		return newEncryptedSecretProvider(ctx, factory, namespace)
+	default:
+		return nil, errors.Errorf(
+			"annotation %q of client namespace %q has unsupported value %q",
+			api.AnnotationSecretProvider, clientNamespace.GetName(), providerName,
+		)
+	}
+}
+
+// getOwnerClientNamespace returns the given tenant namespace and the client
+// namespace owning it. Both are `nil` if not existing.
+func getOwnerClientNamespace(ctx context.Context, factory ClientFactory, namespace string) (*v1.Namespace, *v1.Namespace, error) {
+	namespaces := factory.CoreV1().Namespaces()
+	tenantNamespace, err := namespaces.Get(ctx, namespace, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+	clientNamespaceName := tenantNamespace.GetLabels()[api.LabelOwnerClientNamespace]
+	if clientNamespaceName == "" {
+		return tenantNamespace, nil, nil
+	}
+	clientNamespace, err := namespaces.Get(ctx, clientNamespaceName, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return tenantNamespace, nil, nil
+		}
+		return nil, nil, err
+	}
+	return tenantNamespace, clientNamespace, nil
+}
+
+/*
+newVaultSecretProvider creates a Vault secret provider for the given tenant
+namespace.
+
+The Vault server, secrets engine and path template are configured by the
+operator in the system namespace, so that clients cannot access secrets of
+other clients. Clients may provide their own Vault token via annotation
+`AnnotationVaultTokenSecret`, otherwise the provider logs in via the
+Kubernetes auth method with the service account of the run controller.
+*/
+func newVaultSecretProvider(ctx context.Context, factory ClientFactory, tenantNamespace, clientNamespace *v1.Namespace) (secrets.SecretProvider, error) {
+	tenantName := tenantNamespace.GetLabels()[api.LabelOwnerTenantName]
+	if tenantName == "" {
+		return nil, errors.Errorf("label %q is missing on tenant namespace %q", api.LabelOwnerTenantName, tenantNamespace.GetName())
+	}
+
+	configMap, err := factory.CoreV1().ConfigMaps(system.Namespace()).Get(ctx, vaultConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, errors.Errorf("the vault secret provider is not configured: config map %q does not exist", vaultConfigMapName)
+		}
+		return nil, err
+	}
+	config := vaultsecretprovider.Config{
+		Address:      configMap.Data[vaultConfigKeyAddress],
+		MountPath:    configMap.Data[vaultConfigKeyMountPath],
+		PathTemplate: configMap.Data[vaultConfigKeyPathTemplate],
+		Auth: vaultsecretprovider.Auth{
+			KubernetesRole:     configMap.Data[vaultConfigKeyKubernetesRole],
+			KubernetesAuthPath: configMap.Data[vaultConfigKeyKubernetesPath],
+		},
+	}
+
+	if tokenSecretName := clientNamespace.GetAnnotations()[api.AnnotationVaultTokenSecret]; tokenSecretName != "" {
+		tokenSecret, err := factory.CoreV1().Secrets(clientNamespace.GetName()).Get(ctx, tokenSecretName, metav1.GetOptions{})
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed to get vault token secret %q", tokenSecretName)
+		}
+		token := string(tokenSecret.Data[vaultTokenSecretKey])
+		if token == "" {
+			return nil, errors.Errorf("vault token secret %q has no value for key %q", tokenSecretName, vaultTokenSecretKey)
+		}
+		config.Auth = vaultsecretprovider.Auth{Token: token}
+	}
+
+	return vaultsecretprovider.NewProvider(config, vaultsecretprovider.PathData{
+		ClientNamespace: clientNamespace.GetName(),
+		TenantName:      tenantName,
+		TenantNamespace: tenantNamespace.GetName(),
+	})
+}
+
+/*
+newEncryptedSecretProvider creates a secret provider decrypting the
+encrypted secrets in the given tenant namespace.
+
+The private key is read from a secret in the system namespace, which is not
+accessible by clients. Hence decrypted secrets only exist in run namespaces.
+*/
+func newEncryptedSecretProvider(ctx context.Context, factory ClientFactory, namespace string) (secrets.SecretProvider, error) {
+	keySecret, err := factory.CoreV1().Secrets(system.Namespace()).Get(ctx, encryptedSecretsKeySecretName, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, errors.Errorf("the encrypted secret provider is not configured: secret %q does not exist", encryptedSecretsKeySecretName)
+		}
+		return nil, err
+	}
+	privateKey, err := encryptedsecretprovider.ParseKey(string(keySecret.Data[encryptedSecretsKeySecretKey]))
+	if err != nil {
+		return nil, errors.WithMessagef(err, "the encrypted secret provider is misconfigured: key %q of secret %q", encryptedSecretsKeySecretKey, encryptedSecretsKeySecretName)
+	}
+	return encryptedsecretprovider.NewProvider(factory.CoreV1().ConfigMaps(namespace), namespace, privateKey), nil
+}
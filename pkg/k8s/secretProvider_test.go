@@ -0,0 +1,232 @@
+package k8s
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/k8s/fake"
+	cachedsecretprovider "github.com/SAP/stewardci-core/pkg/k8s/secrets/providers/cached"
+	encryptedsecretprovider "github.com/SAP/stewardci-core/pkg/k8s/secrets/providers/encrypted"
+	"gotest.tools/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/system"
+	_ "knative.dev/pkg/system/testing"
+)
+
+func newTenantNamespaceOfClient(name, clientNamespace, tenantName string) *v1.Namespace {
+	namespace := fake.Namespace(name)
+	namespace.SetLabels(map[string]string{
+		api.LabelOwnerClientNamespace: clientNamespace,
+		api.LabelOwnerTenantName:      tenantName,
+	})
+	return namespace
+}
+
+func Test_tenantSecretProvider_GetSecret_Kubernetes(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name    string
+		objects []*v1.Namespace
+	}{
+		{"no_tenant_namespace", nil},
+		{"no_client_namespace", []*v1.Namespace{
+			newTenantNamespaceOfClient("tn1", "client1", "tenant1"),
+		}},
+		{"no_annotation", []*v1.Namespace{
+			newTenantNamespaceOfClient("tn1", "client1", "tenant1"),
+			fake.Namespace("client1"),
+		}},
+		{"explicit", []*v1.Namespace{
+			newTenantNamespaceOfClient("tn1", "client1", "tenant1"),
+			fake.NamespaceWithAnnotations("client1", map[string]string{
+				api.AnnotationSecretProvider: api.SecretProviderKubernetes,
+			}),
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			ctx := context.Background()
+			cf := fake.NewClientFactory(fake.SecretOpaque("secret1", "tn1"))
+			for _, namespace := range tc.objects {
+				_, err := cf.CoreV1().Namespaces().Create(ctx, namespace, metav1.CreateOptions{})
+				assert.NilError(t, err)
+			}
+			examinee := NewTenantNamespace(cf, "tn1", nil).GetSecretProvider()
+
+			// EXERCISE
+			result, resultErr := examinee.GetSecret(ctx, "secret1")
+
+			// VERIFY
+			assert.NilError(t, resultErr)
+			assert.Equal(t, "secret1", result.GetName())
+		})
+	}
+}
+
+func Test_tenantSecretProvider_GetSecret_KubernetesCached(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	cf := fake.NewClientFactory(
+		fake.SecretOpaque("secret1", "tn1"),
+		newTenantNamespaceOfClient("tn1", "client1", "tenant1"),
+		fake.Namespace("client1"),
+	)
+	stopCh := make(chan struct{})
+	t.Cleanup(func() { close(stopCh) })
+	secretCache := cachedsecretprovider.NewSecretCache(cf.CoreV1(), time.Hour, stopCh)
+	examinee := NewTenantNamespace(cf, "tn1", secretCache).GetSecretProvider()
+
+	// EXERCISE
+	result, resultErr := examinee.GetSecret(ctx, "secret1")
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assert.Equal(t, "secret1", result.GetName())
+	delegate := examinee.(*tenantSecretProvider).delegate
+	assert.Equal(t, "*cached.provider", fmt.Sprintf("%T", delegate))
+}
+
+func Test_tenantSecretProvider_GetSecret_Vault(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token1" || r.URL.Path != "/v1/kv/data/client1/tenant1/secret1" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"data": map[string]string{"key1": "value1"},
+			},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	cf := fake.NewClientFactory(
+		newTenantNamespaceOfClient("tn1", "client1", "tenant1"),
+		fake.NamespaceWithAnnotations("client1", map[string]string{
+			api.AnnotationSecretProvider:   api.SecretProviderVault,
+			api.AnnotationVaultTokenSecret: "vault-token",
+		}),
+		&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "vault-token", Namespace: "client1"},
+			Data:       map[string][]byte{"token": []byte("token1")},
+		},
+		&v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "steward-pipelineruns-vault", Namespace: system.Namespace()},
+			Data: map[string]string{
+				"address":      server.URL,
+				"mountPath":    "kv",
+				"pathTemplate": "{{ .ClientNamespace }}/{{ .TenantName }}/{{ .SecretName }}",
+			},
+		},
+	)
+	examinee := NewTenantNamespace(cf, "tn1", nil).GetSecretProvider()
+
+	// EXERCISE
+	result, resultErr := examinee.GetSecret(ctx, "secret1")
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assert.Equal(t, "secret1", result.GetName())
+	assert.DeepEqual(t, map[string][]byte{"key1": []byte("value1")}, result.Data)
+}
+
+func Test_tenantSecretProvider_GetSecret_Encrypted(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	const encodedKey = "0P8r0tZfJ4YMG9fHV7L+LPY0WcJ6xCh3AxP9HqhdnEo="
+	privateKey, err := encryptedsecretprovider.ParseKey(encodedKey)
+	assert.NilError(t, err)
+	ciphertext, err := encryptedsecretprovider.Encrypt("tn1", "secret1", "key1", []byte("value1"), encryptedsecretprovider.PublicKey(privateKey))
+	assert.NilError(t, err)
+
+	cf := fake.NewClientFactory(
+		newTenantNamespaceOfClient("tn1", "client1", "tenant1"),
+		fake.NamespaceWithAnnotations("client1", map[string]string{
+			api.AnnotationSecretProvider: api.SecretProviderEncrypted,
+		}),
+		&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "steward-encrypted-secrets-key", Namespace: system.Namespace()},
+			Data:       map[string][]byte{"privateKey": []byte(encodedKey)},
+		},
+		&v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "secret1",
+				Namespace: "tn1",
+				Labels:    map[string]string{api.LabelEncryptedSecret: ""},
+			},
+			Data: map[string]string{"key1": base64.StdEncoding.EncodeToString(ciphertext)},
+		},
+	)
+	examinee := NewTenantNamespace(cf, "tn1", nil).GetSecretProvider()
+
+	// EXERCISE
+	result, resultErr := examinee.GetSecret(ctx, "secret1")
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assert.Equal(t, "secret1", result.GetName())
+	assert.DeepEqual(t, map[string][]byte{"key1": []byte("value1")}, result.Data)
+}
+
+func Test_tenantSecretProvider_GetSecret_InvalidConfig(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		annotations   map[string]string
+		expectedError string
+	}{
+		{"unsupported_provider",
+			map[string]string{api.AnnotationSecretProvider: "foo"},
+			`failed to create secret provider for namespace "tn1": annotation "steward.sap.com/secret-provider" of client namespace "client1" has unsupported value "foo"`,
+		},
+		{"vault_not_configured",
+			map[string]string{api.AnnotationSecretProvider: api.SecretProviderVault},
+			`failed to create secret provider for namespace "tn1": the vault secret provider is not configured: config map "steward-pipelineruns-vault" does not exist`,
+		},
+		{"encrypted_not_configured",
+			map[string]string{api.AnnotationSecretProvider: api.SecretProviderEncrypted},
+			`failed to create secret provider for namespace "tn1": the encrypted secret provider is not configured: secret "steward-encrypted-secrets-key" does not exist`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			ctx := context.Background()
+			cf := fake.NewClientFactory(
+				newTenantNamespaceOfClient("tn1", "client1", "tenant1"),
+				fake.NamespaceWithAnnotations("client1", tc.annotations),
+			)
+			examinee := NewTenantNamespace(cf, "tn1", nil).GetSecretProvider()
+
+			// EXERCISE
+			result, resultErr := examinee.GetSecret(ctx, "secret1")
+
+			// VERIFY
+			assert.Error(t, resultErr, tc.expectedError)
+			assert.Assert(t, result == nil)
+		})
+	}
+}
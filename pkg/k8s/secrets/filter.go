@@ -17,3 +17,23 @@ var _ SecretFilter = DockerOnly
 func DockerOnly(secret *v1.Secret) bool {
 	return secret.Type == v1.SecretTypeDockerConfigJson || secret.Type == v1.SecretTypeDockercfg
 }
+
+// OfType returns a secret filter that selects only secrets of the given type.
+func OfType(secretType v1.SecretType) SecretFilter {
+	return func(secret *v1.Secret) bool {
+		return secret.Type == secretType
+	}
+}
+
+// HasKeys returns a secret filter that selects only secrets containing all
+// the given data keys.
+func HasKeys(keys ...string) SecretFilter {
+	return func(secret *v1.Secret) bool {
+		for _, key := range keys {
+			if _, ok := secret.Data[key]; !ok {
+				return false
+			}
+		}
+		return true
+	}
+}
@@ -29,3 +29,43 @@ func Test_DockerOnly(t *testing.T) {
 		assert.Assert(t, result == test.expectedResult)
 	}
 }
+
+func Test_OfType(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee := OfType(v1.SecretTypeSSHAuth)
+
+	// EXERCISE and VERIFY
+	assert.Assert(t, examinee(fake.SecretWithType("foo", "bar", v1.SecretTypeSSHAuth)))
+	assert.Assert(t, !examinee(fake.SecretWithType("foo", "bar", v1.SecretTypeBasicAuth)))
+	assert.Assert(t, !examinee(fake.SecretOpaque("foo", "bar")))
+}
+
+func Test_HasKeys(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name           string
+		keys           []string
+		expectedResult bool
+	}{
+		{"no_keys", nil, true},
+		{"all_existing", []string{"key1", "key2"}, true},
+		{"one_missing", []string{"key1", "missing"}, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			secret := fake.SecretOpaque("foo", "bar")
+			secret.Data = map[string][]byte{"key1": []byte("value1"), "key2": []byte("value2")}
+
+			// EXERCISE
+			result := HasKeys(tc.keys...)(secret)
+
+			// VERIFY
+			assert.Equal(t, tc.expectedResult, result)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package cached
+
+import (
+	"sync"
+
+	"github.com/SAP/stewardci-core/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	subsystem = metrics.Subsystem + "_secretcache"
+
+	// LookupResultHit is the lookup result if a secret has been served
+	// from the cache.
+	LookupResultHit = "hit"
+
+	// LookupResultMiss is the lookup result if a secret has been read
+	// from the fallback provider.
+	LookupResultMiss = "miss"
+)
+
+var (
+	// Lookups counts secret lookups by result.
+	Lookups LookupsMetric = &lookups{}
+)
+
+func init() {
+	Lookups.(*lookups).init()
+}
+
+// LookupsMetric counts secret lookups by result.
+type LookupsMetric interface {
+	Inc(result string)
+}
+
+type lookups struct {
+	initOnlyOnce sync.Once
+	metric       *prometheus.CounterVec
+}
+
+func (m *lookups) init() {
+	m.initOnlyOnce.Do(func() {
+		m.metric = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Subsystem: subsystem,
+				Name:      "lookups_total",
+				Help:      "The number of secret lookups partitioned by result (hit or miss).",
+			},
+			[]string{
+				"result",
+			},
+		)
+		metrics.Registerer().MustRegister(m.metric)
+	})
+}
+
+func (m *lookups) Inc(result string) {
+	m.metric.WithLabelValues(result).Inc()
+}
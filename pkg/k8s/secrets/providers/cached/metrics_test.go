@@ -0,0 +1,45 @@
+package cached
+
+import (
+	"testing"
+
+	"github.com/SAP/stewardci-core/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"gotest.tools/assert"
+)
+
+func Test_Lookups_isInitialized(t *testing.T) {
+	t.Parallel()
+
+	// VERIFY
+	assert.Assert(t, Lookups.(*lookups).metric != nil)
+}
+
+func Test_lookups_Inc(t *testing.T) {
+	// no parallel: patching global state
+
+	// SETUP
+	reg := prometheus.NewPedanticRegistry()
+	t.Cleanup(metrics.Testing{}.PatchRegistry(reg))
+
+	examinee := &lookups{}
+	examinee.init()
+
+	// EXERCISE
+	examinee.Inc(LookupResultHit)
+	examinee.Inc(LookupResultHit)
+	examinee.Inc(LookupResultMiss)
+
+	// VERIFY
+	metricFamily, err := reg.Gather()
+	assert.NilError(t, err)
+	assert.Equal(t, len(metricFamily), 1)
+	assert.Equal(t, metricFamily[0].GetName(), "steward_secretcache_lookups_total")
+
+	counts := map[string]float64{}
+	for _, metric := range metricFamily[0].GetMetric() {
+		assert.Equal(t, metric.Label[0].GetName(), "result")
+		counts[metric.Label[0].GetValue()] = metric.Counter.GetValue()
+	}
+	assert.DeepEqual(t, map[string]float64{LookupResultHit: 2, LookupResultMiss: 1}, counts)
+}
@@ -0,0 +1,164 @@
+package cached
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	secrets "github.com/SAP/stewardci-core/pkg/k8s/secrets"
+	"github.com/SAP/stewardci-core/pkg/k8s/secrets/providers"
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+/*
+SecretCache holds informer-based caches of the secrets in tenant namespaces.
+
+An informer for a namespace is started when secrets of this namespace are
+requested for the first time. It is stopped again if no secrets of this
+namespace have been requested for the idle timeout, so that only secrets of
+namespaces with recent pipeline runs are kept in memory.
+*/
+type SecretCache struct {
+	client      corev1.CoreV1Interface
+	idleTimeout time.Duration
+	stopCh      <-chan struct{}
+
+	mutex      sync.Mutex
+	namespaces map[string]*namespaceCache
+
+	// now returns the current time. Can be replaced in tests.
+	now func() time.Time
+}
+
+type namespaceCache struct {
+	informer cache.SharedIndexInformer
+	lister   corev1listers.SecretNamespaceLister
+	lastUsed time.Time
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewSecretCache creates a new secret cache. All informers are stopped
+// when stopCh is closed.
+func NewSecretCache(client corev1.CoreV1Interface, idleTimeout time.Duration, stopCh <-chan struct{}) *SecretCache {
+	return &SecretCache{
+		client:      client,
+		idleTimeout: idleTimeout,
+		stopCh:      stopCh,
+		namespaces:  map[string]*namespaceCache{},
+		now:         time.Now,
+	}
+}
+
+// NewProvider creates a secret provider for the given namespace serving
+// secrets from the cache. Secrets not contained in the cache, e.g. because
+// the informer has not synced yet, are read from the fallback provider.
+func (c *SecretCache) NewProvider(namespace string, fallback secrets.SecretProvider) secrets.SecretProvider {
+	return &provider{
+		cache:     c,
+		namespace: namespace,
+		fallback:  fallback,
+	}
+}
+
+// getLister returns the secret lister for the given namespace and whether
+// the underlying informer has synced. The informer is started if not
+// running yet. Informers of other namespaces being idle are stopped.
+func (c *SecretCache) getLister(namespace string) (corev1listers.SecretNamespaceLister, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := c.now()
+	for name, nc := range c.namespaces {
+		if name != namespace && now.Sub(nc.lastUsed) > c.idleTimeout {
+			nc.stop()
+			delete(c.namespaces, name)
+		}
+	}
+
+	nc := c.namespaces[namespace]
+	if nc == nil {
+		nc = c.startInformer(namespace)
+		c.namespaces[namespace] = nc
+	}
+	nc.lastUsed = now
+	return nc.lister, nc.informer.HasSynced()
+}
+
+func (c *SecretCache) startInformer(namespace string) *namespaceCache {
+	secretsClient := c.client.Secrets(namespace)
+	listWatch := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			return secretsClient.List(context.Background(), options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			return secretsClient.Watch(context.Background(), options)
+		},
+	}
+	informer := cache.NewSharedIndexInformer(
+		listWatch,
+		&v1.Secret{},
+		0, // no resync: there are no event handlers
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+	)
+	nc := &namespaceCache{
+		informer: informer,
+		lister:   corev1listers.NewSecretLister(informer.GetIndexer()).Secrets(namespace),
+		stopCh:   make(chan struct{}),
+	}
+	go func() {
+		select {
+		case <-c.stopCh:
+			nc.stop()
+		case <-nc.stopCh:
+		}
+	}()
+	go informer.Run(nc.stopCh)
+	return nc
+}
+
+func (nc *namespaceCache) stop() {
+	nc.stopOnce.Do(func() {
+		close(nc.stopCh)
+	})
+}
+
+type provider struct {
+	cache     *SecretCache
+	namespace string
+	fallback  secrets.SecretProvider
+}
+
+// GetSecret returns the secret with the given name from the cache if
+// existing there, otherwise from the fallback provider.
+func (p *provider) GetSecret(ctx context.Context, name string) (*v1.Secret, error) {
+	lister, synced := p.cache.getLister(p.namespace)
+	if synced {
+		secret, err := lister.Get(name)
+		if err == nil {
+			Lookups.Inc(LookupResultHit)
+			if !secret.ObjectMeta.DeletionTimestamp.IsZero() {
+				return nil, nil
+			}
+			// objects in the informer cache must not be modified
+			secret = secret.DeepCopy()
+			providers.StripMetadata(secret)
+			return secret, nil
+		}
+		if !k8serrors.IsNotFound(err) {
+			return nil, errors.WithMessagef(err, "failed to get secret %q from cache of namespace %q", name, p.namespace)
+		}
+		// The secret may have been created recently and the informer did
+		// not receive it yet.
+	}
+	Lookups.Inc(LookupResultMiss)
+	return p.fallback.GetSecret(ctx, name)
+}
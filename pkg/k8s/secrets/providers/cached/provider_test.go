@@ -0,0 +1,193 @@
+package cached
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/SAP/stewardci-core/pkg/k8s/fake"
+	secrets "github.com/SAP/stewardci-core/pkg/k8s/secrets"
+	fakesecretprovider "github.com/SAP/stewardci-core/pkg/k8s/secrets/providers/fake"
+	"gotest.tools/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+)
+
+type countingProvider struct {
+	delegate secrets.SecretProvider
+	calls    int
+}
+
+func (p *countingProvider) GetSecret(ctx context.Context, name string) (*v1.Secret, error) {
+	p.calls++
+	return p.delegate.GetSecret(ctx, name)
+}
+
+type lookupsStub struct {
+	results []string
+}
+
+func (m *lookupsStub) Inc(result string) {
+	m.results = append(m.results, result)
+}
+
+func patchLookups(t *testing.T) *lookupsStub {
+	stub := &lookupsStub{}
+	origValue := Lookups
+	Lookups = stub
+	t.Cleanup(func() { Lookups = origValue })
+	return stub
+}
+
+func newSyncedCache(t *testing.T, namespace string, objects ...*v1.Secret) *SecretCache {
+	t.Helper()
+	clientset := kubefake.NewSimpleClientset()
+	for _, secret := range objects {
+		_, err := clientset.CoreV1().Secrets(secret.GetNamespace()).Create(context.Background(), secret, metav1.CreateOptions{})
+		assert.NilError(t, err)
+	}
+	stopCh := make(chan struct{})
+	t.Cleanup(func() { close(stopCh) })
+	examinee := NewSecretCache(clientset.CoreV1(), time.Hour, stopCh)
+	examinee.getLister(namespace)
+	assert.Assert(t, cache.WaitForCacheSync(stopCh, examinee.namespaces[namespace].informer.HasSynced))
+	return examinee
+}
+
+func Test_provider_GetSecret_Cached(t *testing.T) {
+	// no parallel: patching global state
+
+	// SETUP
+	ctx := context.Background()
+	lookups := patchLookups(t)
+	storedSecret := fake.SecretOpaque("foo", "ns1")
+	storedSecret.SetUID(types.UID("dummy"))
+	storedSecret.SetResourceVersion("dummy")
+	storedSecret.SetLabels(map[string]string{"lbar": "lbaz"})
+	storedSecret.SetAnnotations(map[string]string{"abar": "abaz"})
+	secretCache := newSyncedCache(t, "ns1", storedSecret)
+	fallback := &countingProvider{delegate: fakesecretprovider.NewProvider("ns1")}
+	examinee := secretCache.NewProvider("ns1", fallback)
+
+	// EXERCISE
+	result, resultErr := examinee.GetSecret(ctx, "foo")
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	expectedSecret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "foo",
+			Labels:      storedSecret.GetLabels(),
+			Annotations: storedSecret.GetAnnotations(),
+		},
+		Type: v1.SecretTypeOpaque,
+	}
+	assert.DeepEqual(t, expectedSecret, result)
+	assert.Equal(t, 0, fallback.calls)
+	assert.DeepEqual(t, []string{LookupResultHit}, lookups.results)
+
+	// cached object must not be modified
+	cached, err := secretCache.namespaces["ns1"].lister.Get("foo")
+	assert.NilError(t, err)
+	assert.Equal(t, "ns1", cached.GetNamespace())
+}
+
+func Test_provider_GetSecret_InDeletion(t *testing.T) {
+	// no parallel: patching global state
+
+	// SETUP
+	ctx := context.Background()
+	patchLookups(t)
+	storedSecret := fake.SecretOpaque("foo", "ns1")
+	now := metav1.Now()
+	storedSecret.SetDeletionTimestamp(&now)
+	secretCache := newSyncedCache(t, "ns1", storedSecret)
+	fallback := &countingProvider{delegate: fakesecretprovider.NewProvider("ns1")}
+	examinee := secretCache.NewProvider("ns1", fallback)
+
+	// EXERCISE
+	result, resultErr := examinee.GetSecret(ctx, "foo")
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assert.Assert(t, result == nil)
+	assert.Equal(t, 0, fallback.calls)
+}
+
+func Test_provider_GetSecret_NotInCache(t *testing.T) {
+	// no parallel: patching global state
+
+	// SETUP
+	ctx := context.Background()
+	lookups := patchLookups(t)
+	secretCache := newSyncedCache(t, "ns1", fake.SecretOpaque("foo", "ns2"))
+	fallback := &countingProvider{delegate: fakesecretprovider.NewProvider("ns1", fake.SecretOpaque("foo", "ns1"))}
+	examinee := secretCache.NewProvider("ns1", fallback)
+
+	// EXERCISE
+	result, resultErr := examinee.GetSecret(ctx, "foo")
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assert.Equal(t, "foo", result.GetName())
+	assert.Equal(t, 1, fallback.calls)
+	assert.DeepEqual(t, []string{LookupResultMiss}, lookups.results)
+}
+
+func Test_provider_GetSecret_NotSynced(t *testing.T) {
+	// no parallel: patching global state
+
+	// SETUP
+	ctx := context.Background()
+	lookups := patchLookups(t)
+	stopCh := make(chan struct{})
+	close(stopCh) // informers never sync
+	secretCache := NewSecretCache(kubefake.NewSimpleClientset(fake.SecretOpaque("foo", "ns1")).CoreV1(), time.Hour, stopCh)
+	fallback := &countingProvider{delegate: fakesecretprovider.NewProvider("ns1", fake.SecretOpaque("foo", "ns1"))}
+	examinee := secretCache.NewProvider("ns1", fallback)
+
+	// EXERCISE
+	result, resultErr := examinee.GetSecret(ctx, "foo")
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assert.Equal(t, "foo", result.GetName())
+	assert.Equal(t, 1, fallback.calls)
+	assert.DeepEqual(t, []string{LookupResultMiss}, lookups.results)
+}
+
+func Test_SecretCache_getLister_StopsIdleInformers(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	stopCh := make(chan struct{})
+	t.Cleanup(func() { close(stopCh) })
+	examinee := NewSecretCache(kubefake.NewSimpleClientset().CoreV1(), time.Hour, stopCh)
+	now := time.Unix(1000, 0)
+	examinee.now = func() time.Time { return now }
+
+	examinee.getLister("ns1")
+	examinee.getLister("ns2")
+	ns1Cache := examinee.namespaces["ns1"]
+	ns2Cache := examinee.namespaces["ns2"]
+	now = now.Add(30 * time.Minute)
+	examinee.getLister("ns2")
+	now = now.Add(31 * time.Minute)
+
+	// EXERCISE
+	examinee.getLister("ns3")
+
+	// VERIFY
+	assert.Equal(t, 2, len(examinee.namespaces))
+	assert.Assert(t, examinee.namespaces["ns1"] == nil)
+	assert.Assert(t, examinee.namespaces["ns2"] == ns2Cache)
+	assert.Assert(t, examinee.namespaces["ns3"] != nil)
+	select {
+	case <-ns1Cache.stopCh:
+	default:
+		t.Fatal("informer of idle namespace ns1 has not been stopped")
+	}
+}
@@ -0,0 +1,175 @@
+package encrypted
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	secrets "github.com/SAP/stewardci-core/pkg/k8s/secrets"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/nacl/box"
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// KeySize is the size of private and public keys in bytes.
+const KeySize = 32
+
+// scopeSeparator terminates the scope prefix of a plaintext. It cannot occur
+// in namespace names, config map names or config map keys.
+const scopeSeparator = '\x00'
+
+type provider struct {
+	namespace        string
+	configMapsClient corev1.ConfigMapInterface
+	privateKey       *[KeySize]byte
+	publicKey        *[KeySize]byte
+}
+
+/*
+NewProvider creates a secret provider reading encrypted secrets from config
+maps in the given namespace and decrypting them with the given private key.
+
+An encrypted secret is a config map labelled with `LabelEncryptedSecret`.
+Each entry contains a value encrypted as anonymous NaCl box ("sealed box")
+for the public key belonging to the private key. Entries in `data` are
+base64-encoded, entries in `binaryData` are not. Each plaintext is bound to
+the entry it is stored in by the prefix `<namespace>/<config map name>/<key>`
+followed by a NUL byte, so that values cannot be copied to config maps in
+other namespaces, other config maps or other keys. The type of the decrypted
+secret is taken from annotation `AnnotationSecretType`, all other labels and
+annotations are retained.
+*/
+func NewProvider(configMapsClient corev1.ConfigMapInterface, namespace string, privateKey *[KeySize]byte) secrets.SecretProvider {
+	return &provider{
+		namespace:        namespace,
+		configMapsClient: configMapsClient,
+		privateKey:       privateKey,
+		publicKey:        PublicKey(privateKey),
+	}
+}
+
+// GetSecret returns the decrypted secret with the given name from the
+// defined namespace if existing. Config maps not labelled as encrypted
+// secret are treated as not existing.
+func (p *provider) GetSecret(ctx context.Context, name string) (*v1.Secret, error) {
+	configMap, err := p.configMapsClient.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.WithMessagef(err, "failed to get encrypted secret %q from namespace %q", name, p.namespace)
+	}
+	if !configMap.ObjectMeta.DeletionTimestamp.IsZero() {
+		return nil, nil
+	}
+	if _, ok := configMap.GetLabels()[api.LabelEncryptedSecret]; !ok {
+		return nil, nil
+	}
+	return p.decrypt(configMap)
+}
+
+func (p *provider) decrypt(configMap *v1.ConfigMap) (*v1.Secret, error) {
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        configMap.GetName(),
+			Labels:      copyWithout(configMap.GetLabels(), api.LabelEncryptedSecret),
+			Annotations: copyWithout(configMap.GetAnnotations(), api.AnnotationSecretType),
+		},
+		Type: v1.SecretTypeOpaque,
+		Data: make(map[string][]byte, len(configMap.Data)+len(configMap.BinaryData)),
+	}
+	if secretType := configMap.GetAnnotations()[api.AnnotationSecretType]; secretType != "" {
+		secret.Type = v1.SecretType(secretType)
+	}
+
+	for key, value := range configMap.Data {
+		ciphertext, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decrypt key %q of encrypted secret %q", key, configMap.GetName())
+		}
+		if secret.Data[key], err = p.open(ciphertext, Scope(p.namespace, configMap.GetName(), key)); err != nil {
+			return nil, errors.WithMessagef(err, "failed to decrypt key %q of encrypted secret %q", key, configMap.GetName())
+		}
+	}
+	for key, ciphertext := range configMap.BinaryData {
+		var err error
+		if secret.Data[key], err = p.open(ciphertext, Scope(p.namespace, configMap.GetName(), key)); err != nil {
+			return nil, errors.WithMessagef(err, "failed to decrypt key %q of encrypted secret %q", key, configMap.GetName())
+		}
+	}
+	return secret, nil
+}
+
+func (p *provider) open(ciphertext []byte, scope string) ([]byte, error) {
+	plaintext, ok := box.OpenAnonymous(nil, ciphertext, p.publicKey, p.privateKey)
+	if !ok {
+		return nil, errors.New("value is not encrypted for the configured key")
+	}
+	i := bytes.IndexByte(plaintext, scopeSeparator)
+	if i < 0 {
+		return nil, errors.New("value is not bound to a config map entry")
+	}
+	if boundScope := string(plaintext[:i]); boundScope != scope {
+		return nil, errors.Errorf("value is bound to %q instead of %q", boundScope, scope)
+	}
+	return append([]byte{}, plaintext[i+1:]...), nil
+}
+
+func copyWithout(m map[string]string, key string) map[string]string {
+	var result map[string]string
+	for k, v := range m {
+		if k == key {
+			continue
+		}
+		if result == nil {
+			result = map[string]string{}
+		}
+		result[k] = v
+	}
+	return result
+}
+
+// ParseKey parses a base64-encoded private or public key.
+func ParseKey(encoded string) (*[KeySize]byte, error) {
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid key")
+	}
+	if len(decoded) != KeySize {
+		return nil, errors.Errorf("invalid key: expected %d bytes, got %d", KeySize, len(decoded))
+	}
+	key := new([KeySize]byte)
+	copy(key[:], decoded)
+	return key, nil
+}
+
+// PublicKey returns the public key belonging to the given private key.
+func PublicKey(privateKey *[KeySize]byte) *[KeySize]byte {
+	publicKey := new([KeySize]byte)
+	curve25519.ScalarBaseMult(publicKey, privateKey)
+	return publicKey
+}
+
+// Scope returns the scope a value stored in the given key of the given
+// config map in the given namespace is bound to.
+func Scope(namespace, configMapName, key string) string {
+	return namespace + "/" + configMapName + "/" + key
+}
+
+// Encrypt encrypts the given value for the given public key so that it can
+// be decrypted by a provider with the corresponding private key when stored
+// in the given key of the given config map in the given namespace.
+func Encrypt(namespace, configMapName, key string, value []byte, publicKey *[KeySize]byte) ([]byte, error) {
+	plaintext := make([]byte, 0, len(namespace)+len(configMapName)+len(key)+3+len(value))
+	plaintext = append(plaintext, Scope(namespace, configMapName, key)...)
+	plaintext = append(plaintext, scopeSeparator)
+	plaintext = append(plaintext, value...)
+	return box.SealAnonymous(nil, plaintext, publicKey, rand.Reader)
+}
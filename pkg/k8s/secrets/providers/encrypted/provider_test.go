@@ -0,0 +1,227 @@
+package encrypted
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"golang.org/x/crypto/nacl/box"
+	"gotest.tools/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+const (
+	privateKey1 = "0P8r0tZfJ4YMG9fHV7L+LPY0WcJ6xCh3AxP9HqhdnEo="
+	privateKey2 = "2C0VoKNBmHXZ8Yj3RxqDjJcDF4eWaoT3bAQ2zbO6bls="
+)
+
+func mustParseKey(t *testing.T, encoded string) *[KeySize]byte {
+	t.Helper()
+	key, err := ParseKey(encoded)
+	assert.NilError(t, err)
+	return key
+}
+
+func mustEncrypt(t *testing.T, namespace, configMapName, key, value string, privateKey *[KeySize]byte) []byte {
+	t.Helper()
+	ciphertext, err := Encrypt(namespace, configMapName, key, []byte(value), PublicKey(privateKey))
+	assert.NilError(t, err)
+	return ciphertext
+}
+
+func mustSealUnbound(t *testing.T, value string, privateKey *[KeySize]byte) []byte {
+	t.Helper()
+	ciphertext, err := box.SealAnonymous(nil, []byte(value), PublicKey(privateKey), rand.Reader)
+	assert.NilError(t, err)
+	return ciphertext
+}
+
+func newEncryptedSecret(name string, data map[string]string, binaryData map[string][]byte) *v1.ConfigMap {
+	return &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "ns1",
+			Labels: map[string]string{
+				api.LabelEncryptedSecret: "",
+				"lbar":                   "lbaz",
+			},
+			Annotations: map[string]string{
+				api.AnnotationSecretType: string(v1.SecretTypeBasicAuth),
+				"abar":                   "abaz",
+			},
+		},
+		Data:       data,
+		BinaryData: binaryData,
+	}
+}
+
+func Test_provider_GetSecret_Existing(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	key := mustParseKey(t, privateKey1)
+	cs := kubefake.NewSimpleClientset(newEncryptedSecret("secret1",
+		map[string]string{
+			"username": base64.StdEncoding.EncodeToString(mustEncrypt(t, "ns1", "secret1", "username", "user1", key)),
+			"empty":    base64.StdEncoding.EncodeToString(mustEncrypt(t, "ns1", "secret1", "empty", "", key)),
+		},
+		map[string][]byte{
+			"password": mustEncrypt(t, "ns1", "secret1", "password", "pass1", key),
+		},
+	))
+	examinee := NewProvider(cs.CoreV1().ConfigMaps("ns1"), "ns1", key)
+
+	// EXERCISE
+	result, resultErr := examinee.GetSecret(ctx, "secret1")
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assert.DeepEqual(t, &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "secret1",
+			Labels:      map[string]string{"lbar": "lbaz"},
+			Annotations: map[string]string{"abar": "abaz"},
+		},
+		Type: v1.SecretTypeBasicAuth,
+		Data: map[string][]byte{
+			"username": []byte("user1"),
+			"password": []byte("pass1"),
+			"empty":    []byte{},
+		},
+	}, result)
+}
+
+func Test_provider_GetSecret_NotExisting(t *testing.T) {
+	t.Parallel()
+
+	now := metav1.Now()
+	deleted := newEncryptedSecret("secret1", nil, nil)
+	deleted.SetDeletionTimestamp(&now)
+	unlabelled := newEncryptedSecret("secret1", nil, nil)
+	unlabelled.SetLabels(nil)
+
+	for _, tc := range []struct {
+		name    string
+		objects []*v1.ConfigMap
+	}{
+		{"missing", nil},
+		{"deleted", []*v1.ConfigMap{deleted}},
+		{"not_labelled", []*v1.ConfigMap{unlabelled}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			ctx := context.Background()
+			cs := kubefake.NewSimpleClientset()
+			for _, configMap := range tc.objects {
+				_, err := cs.CoreV1().ConfigMaps("ns1").Create(ctx, configMap, metav1.CreateOptions{})
+				assert.NilError(t, err)
+			}
+			examinee := NewProvider(cs.CoreV1().ConfigMaps("ns1"), "ns1", mustParseKey(t, privateKey1))
+
+			// EXERCISE
+			result, resultErr := examinee.GetSecret(ctx, "secret1")
+
+			// VERIFY
+			assert.NilError(t, resultErr)
+			assert.Assert(t, result == nil)
+		})
+	}
+}
+
+func Test_provider_GetSecret_DecryptionFails(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		data          map[string]string
+		binaryData    map[string][]byte
+		expectedError string
+	}{
+		{"wrong_key",
+			map[string]string{"key1": base64.StdEncoding.EncodeToString(mustEncrypt(t, "ns1", "secret1", "key1", "value1", mustParseKey(t, privateKey2)))},
+			nil,
+			`failed to decrypt key "key1" of encrypted secret "secret1": value is not encrypted for the configured key`,
+		},
+		{"other_namespace",
+			map[string]string{"key1": base64.StdEncoding.EncodeToString(mustEncrypt(t, "ns2", "secret1", "key1", "value1", mustParseKey(t, privateKey1)))},
+			nil,
+			`failed to decrypt key "key1" of encrypted secret "secret1": value is bound to "ns2/secret1/key1" instead of "ns1/secret1/key1"`,
+		},
+		{"other_config_map",
+			nil,
+			map[string][]byte{"key1": mustEncrypt(t, "ns1", "secret2", "key1", "value1", mustParseKey(t, privateKey1))},
+			`failed to decrypt key "key1" of encrypted secret "secret1": value is bound to "ns1/secret2/key1" instead of "ns1/secret1/key1"`,
+		},
+		{"other_key",
+			map[string]string{"key1": base64.StdEncoding.EncodeToString(mustEncrypt(t, "ns1", "secret1", "key2", "value1", mustParseKey(t, privateKey1)))},
+			nil,
+			`failed to decrypt key "key1" of encrypted secret "secret1": value is bound to "ns1/secret1/key2" instead of "ns1/secret1/key1"`,
+		},
+		{"not_bound",
+			nil,
+			map[string][]byte{"key1": mustSealUnbound(t, "value1", mustParseKey(t, privateKey1))},
+			`failed to decrypt key "key1" of encrypted secret "secret1": value is not bound to a config map entry`,
+		},
+		{"not_base64",
+			map[string]string{"key1": "not base64"},
+			nil,
+			`failed to decrypt key "key1" of encrypted secret "secret1": illegal base64 data at input byte 3`,
+		},
+		{"plaintext",
+			nil,
+			map[string][]byte{"key1": []byte("value1")},
+			`failed to decrypt key "key1" of encrypted secret "secret1": value is not encrypted for the configured key`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			ctx := context.Background()
+			cs := kubefake.NewSimpleClientset(newEncryptedSecret("secret1", tc.data, tc.binaryData))
+			examinee := NewProvider(cs.CoreV1().ConfigMaps("ns1"), "ns1", mustParseKey(t, privateKey1))
+
+			// EXERCISE
+			result, resultErr := examinee.GetSecret(ctx, "secret1")
+
+			// VERIFY
+			assert.Error(t, resultErr, tc.expectedError)
+			assert.Assert(t, result == nil)
+		})
+	}
+}
+
+func Test_ParseKey_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		encoded       string
+		expectedError string
+	}{
+		{"empty", "", "invalid key: expected 32 bytes, got 0"},
+		{"too_short", "AAAA", "invalid key: expected 32 bytes, got 3"},
+		{"not_base64", "%%%%", "invalid key: illegal base64 data at input byte 0"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// EXERCISE
+			result, resultErr := ParseKey(tc.encoded)
+
+			// VERIFY
+			assert.Error(t, resultErr, tc.expectedError)
+			assert.Assert(t, result == nil)
+		})
+	}
+}
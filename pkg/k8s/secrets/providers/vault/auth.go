@@ -0,0 +1,197 @@
+package vault
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	headerVaultToken = "X-Vault-Token"
+
+	// DefaultKubernetesAuthPath is the mount path of the Kubernetes auth
+	// method used if none is configured.
+	DefaultKubernetesAuthPath = "kubernetes"
+
+	// DefaultServiceAccountTokenFile is the file containing the service
+	// account token used to log in via the Kubernetes auth method if none
+	// is configured.
+	DefaultServiceAccountTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+	// loginRenewalMargin is the time before the expiry of a login token
+	// from which on a new token gets requested.
+	loginRenewalMargin = 30 * time.Second
+
+	// maxLoginCacheDuration is the time a login token without expiry is
+	// cached.
+	maxLoginCacheDuration = time.Hour
+)
+
+// Auth defines how to authenticate at the Vault server. Either `Token`
+// or `KubernetesRole` must be set.
+type Auth struct {
+	// Token is a static Vault token.
+	Token string
+
+	// KubernetesRole is the Vault role to log in with via the Kubernetes
+	// auth method.
+	KubernetesRole string
+
+	// KubernetesAuthPath is the mount path of the Kubernetes auth method.
+	// If empty, `DefaultKubernetesAuthPath` is used.
+	KubernetesAuthPath string
+
+	// ServiceAccountTokenFile is the file containing the service account
+	// token presented at login via the Kubernetes auth method.
+	// If empty, `DefaultServiceAccountTokenFile` is used.
+	ServiceAccountTokenFile string
+}
+
+type authenticator interface {
+	// token returns the Vault token to be used for requests. If `renew`
+	// is true, a cached token must not be used.
+	token(ctx context.Context, renew bool) (string, error)
+
+	// renewable returns whether a rejected token may be replaced by
+	// a new one.
+	renewable() bool
+}
+
+func newAuthenticator(config Config) (authenticator, error) {
+	auth := config.Auth
+	switch {
+	case auth.Token != "" && auth.KubernetesRole != "":
+		return nil, errors.New("vault auth: token and kubernetes role must not be set both")
+	case auth.Token != "":
+		return staticToken(auth.Token), nil
+	case auth.KubernetesRole != "":
+		if auth.KubernetesAuthPath == "" {
+			auth.KubernetesAuthPath = DefaultKubernetesAuthPath
+		}
+		if auth.ServiceAccountTokenFile == "" {
+			auth.ServiceAccountTokenFile = DefaultServiceAccountTokenFile
+		}
+		return &kubernetesAuth{
+			address:    strings.TrimRight(config.Address, "/"),
+			auth:       auth,
+			httpClient: config.HTTPClient,
+		}, nil
+	default:
+		return nil, errors.New("vault auth: either token or kubernetes role must be set")
+	}
+}
+
+type staticToken string
+
+func (t staticToken) token(context.Context, bool) (string, error) {
+	return string(t), nil
+}
+
+func (t staticToken) renewable() bool {
+	return false
+}
+
+// kubernetesAuth logs in via the Kubernetes auth method of Vault. Login
+// tokens are shared by all providers with the same server, auth path and
+// role until shortly before they expire.
+type kubernetesAuth struct {
+	address    string
+	auth       Auth
+	httpClient *http.Client
+}
+
+type cachedLogin struct {
+	token   string
+	expires time.Time
+}
+
+var loginCache = struct {
+	sync.Mutex
+	entries map[string]cachedLogin
+}{
+	entries: map[string]cachedLogin{},
+}
+
+func (a *kubernetesAuth) cacheKey() string {
+	return fmt.Sprintf("%s|%s|%s", a.address, a.auth.KubernetesAuthPath, a.auth.KubernetesRole)
+}
+
+func (a *kubernetesAuth) token(ctx context.Context, renew bool) (string, error) {
+	key := a.cacheKey()
+	loginCache.Lock()
+	defer loginCache.Unlock()
+
+	if cached, ok := loginCache.entries[key]; ok && !renew && time.Now().Before(cached.expires) {
+		return cached.token, nil
+	}
+	delete(loginCache.entries, key)
+
+	login, err := a.login(ctx)
+	if err != nil {
+		return "", errors.WithMessage(err, "vault auth: kubernetes login failed")
+	}
+	loginCache.entries[key] = login
+	return login.token, nil
+}
+
+func (a *kubernetesAuth) renewable() bool {
+	return true
+}
+
+func (a *kubernetesAuth) login(ctx context.Context) (cachedLogin, error) {
+	jwt, err := ioutil.ReadFile(a.auth.ServiceAccountTokenFile)
+	if err != nil {
+		return cachedLogin{}, errors.Wrap(err, "failed to read service account token")
+	}
+	body, err := json.Marshal(map[string]string{
+		"role": a.auth.KubernetesRole,
+		"jwt":  strings.TrimSpace(string(jwt)),
+	})
+	if err != nil {
+		return cachedLogin{}, err
+	}
+	loginURL := fmt.Sprintf("%s/v1/auth/%s/login", a.address, strings.Trim(a.auth.KubernetesAuthPath, "/"))
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, loginURL, bytes.NewReader(body))
+	if err != nil {
+		return cachedLogin{}, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := a.httpClient.Do(request)
+	if err != nil {
+		return cachedLogin{}, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return cachedLogin{}, newResponseError(response)
+	}
+
+	result := struct {
+		Auth *struct {
+			ClientToken   string `json:"client_token"`
+			LeaseDuration int64  `json:"lease_duration"`
+		} `json:"auth"`
+	}{}
+	if err := json.NewDecoder(response.Body).Decode(&result); err != nil {
+		return cachedLogin{}, errors.Wrap(err, "failed to decode vault response")
+	}
+	if result.Auth == nil || result.Auth.ClientToken == "" {
+		return cachedLogin{}, errors.New("vault response contains no client token")
+	}
+	login := cachedLogin{token: result.Auth.ClientToken}
+	if result.Auth.LeaseDuration > 0 {
+		login.expires = time.Now().Add(time.Duration(result.Auth.LeaseDuration)*time.Second - loginRenewalMargin)
+	} else {
+		// the token does not expire, but may be revoked
+		login.expires = time.Now().Add(maxLoginCacheDuration)
+	}
+	return login, nil
+}
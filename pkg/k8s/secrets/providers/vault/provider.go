@@ -0,0 +1,281 @@
+package vault
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/template"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	serrors "github.com/SAP/stewardci-core/pkg/errors"
+	secrets "github.com/SAP/stewardci-core/pkg/k8s/secrets"
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	// DefaultMountPath is the mount path of the KV secrets engine used if
+	// none is configured.
+	DefaultMountPath = "secret"
+
+	// DefaultPathTemplate is the template of the secret path within the
+	// KV secrets engine used if none is configured.
+	DefaultPathTemplate = "steward/{{ .ClientNamespace }}/{{ .TenantName }}/{{ .SecretName }}"
+
+	// Custom metadata keys of Vault secrets which are mapped to the
+	// returned Kubernetes secrets.
+	customMetadataKeyType             = "type"
+	customMetadataKeyPrefixLabel      = "label."
+	customMetadataKeyPrefixAnnotation = "annotation."
+)
+
+// Config is the configuration of a Vault secret provider.
+type Config struct {
+	// Address is the base URL of the Vault server, e.g.
+	// `https://vault.example.com:8200`.
+	Address string
+
+	// MountPath is the mount path of the KV version 2 secrets engine.
+	// If empty, `DefaultMountPath` is used.
+	MountPath string
+
+	// PathTemplate is a Go text template of the path of secrets within
+	// the secrets engine. It gets a `PathData` object as input.
+	// If empty, `DefaultPathTemplate` is used.
+	PathTemplate string
+
+	// Auth defines how the provider authenticates at the Vault server.
+	Auth Auth
+
+	// HTTPClient is the client used to send requests to the Vault server.
+	// If `nil`, `http.DefaultClient` is used.
+	HTTPClient *http.Client
+}
+
+// PathData is the input of the secret path template.
+type PathData struct {
+	ClientNamespace string
+	TenantName      string
+	TenantNamespace string
+
+	// SecretName is the name of the requested secret. It is set by
+	// the provider.
+	SecretName string
+}
+
+type provider struct {
+	config       Config
+	pathTemplate *template.Template
+	pathData     PathData
+	auth         authenticator
+}
+
+// NewProvider creates a secret provider reading secrets from a Vault KV
+// version 2 secrets engine. `pathData` is the input of the path template
+// for all secrets requested via this provider.
+func NewProvider(config Config, pathData PathData) (secrets.SecretProvider, error) {
+	if config.Address == "" {
+		return nil, errors.New("vault address must not be empty")
+	}
+	if _, err := url.Parse(config.Address); err != nil {
+		return nil, errors.Wrap(err, "invalid vault address")
+	}
+	if config.MountPath == "" {
+		config.MountPath = DefaultMountPath
+	}
+	if config.PathTemplate == "" {
+		config.PathTemplate = DefaultPathTemplate
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = http.DefaultClient
+	}
+	pathTemplate, err := template.New("path").Option("missingkey=error").Parse(config.PathTemplate)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid vault path template")
+	}
+	auth, err := newAuthenticator(config)
+	if err != nil {
+		return nil, err
+	}
+	return &provider{
+		config:       config,
+		pathTemplate: pathTemplate,
+		pathData:     pathData,
+		auth:         auth,
+	}, nil
+}
+
+// GetSecret returns the latest version of the secret with the given name
+// if existing. Deleted and destroyed versions are treated as not existing.
+// Names which are not valid Kubernetes secret names are rejected, so that
+// they cannot address Vault paths outside the configured path template.
+func (p *provider) GetSecret(ctx context.Context, name string) (*v1.Secret, error) {
+	if msgs := validation.IsDNS1123Subdomain(name); len(msgs) > 0 {
+		err := errors.Errorf("invalid secret name %q: %s", name, strings.Join(msgs, "; "))
+		return nil, serrors.Classify(err, stewardv1alpha1.ResultErrorConfig)
+	}
+	path, err := p.secretPath(name)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := p.readSecret(ctx, path, false)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to get secret %q from vault path %q", name, path)
+	}
+	if response == nil || response.Data == nil || response.Data.Data == nil ||
+		response.Data.Metadata.DeletionTime != "" || response.Data.Metadata.Destroyed {
+		return nil, nil
+	}
+	return toSecret(name, response.Data)
+}
+
+func (p *provider) secretPath(name string) (string, error) {
+	data := p.pathData
+	data.SecretName = name
+	var path bytes.Buffer
+	if err := p.pathTemplate.Execute(&path, &data); err != nil {
+		return "", errors.Wrap(err, "failed to render vault path template")
+	}
+	result := strings.Trim(path.String(), "/")
+	for _, segment := range strings.Split(result, "/") {
+		if segment == "." || segment == ".." {
+			return "", errors.Errorf("vault path %q must not contain relative segments", result)
+		}
+	}
+	return result, nil
+}
+
+// readSecret reads the secret at the given path. It returns `nil` if the
+// secret does not exist. If the token is rejected, the authentication is
+// renewed and the request is retried once.
+func (p *provider) readSecret(ctx context.Context, path string, isRetry bool) (*kvV2Response, error) {
+	token, err := p.auth.token(ctx, isRetry)
+	if err != nil {
+		return nil, err
+	}
+	secretURL := fmt.Sprintf("%s/v1/%s/data/%s",
+		strings.TrimRight(p.config.Address, "/"),
+		escapePath(strings.Trim(p.config.MountPath, "/")),
+		escapePath(path),
+	)
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, secretURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set(headerVaultToken, token)
+
+	response, err := p.config.HTTPClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK:
+		result := &kvV2Response{}
+		if err := json.NewDecoder(response.Body).Decode(result); err != nil {
+			return nil, errors.Wrap(err, "failed to decode vault response")
+		}
+		return result, nil
+	case http.StatusNotFound:
+		return nil, nil
+	case http.StatusForbidden:
+		if !isRetry && p.auth.renewable() {
+			return p.readSecret(ctx, path, true)
+		}
+	}
+	return nil, newResponseError(response)
+}
+
+// escapePath escapes each segment of the given slash-separated path for
+// use in a URL path.
+func escapePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+type kvV2Response struct {
+	Data *kvV2Data `json:"data"`
+}
+
+type kvV2Data struct {
+	Data     map[string]interface{} `json:"data"`
+	Metadata struct {
+		DeletionTime   string            `json:"deletion_time"`
+		Destroyed      bool              `json:"destroyed"`
+		CustomMetadata map[string]string `json:"custom_metadata"`
+	} `json:"metadata"`
+}
+
+/*
+toSecret converts a Vault secret into a Kubernetes secret with the given
+name. String values are taken as is, all other values are JSON-encoded.
+
+The custom metadata of the Vault secret defines the remaining fields of
+the Kubernetes secret:
+  - `type` is the secret type, `Opaque` by default,
+  - `label.<key>` is a label with key `<key>`,
+  - `annotation.<key>` is an annotation with key `<key>`.
+
+Other custom metadata is ignored.
+*/
+func toSecret(name string, data *kvV2Data) (*v1.Secret, error) {
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Type:       v1.SecretTypeOpaque,
+		Data:       make(map[string][]byte, len(data.Data)),
+	}
+	for key, value := range data.Data {
+		if s, ok := value.(string); ok {
+			secret.Data[key] = []byte(s)
+			continue
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to encode value of key %q of secret %q", key, name)
+		}
+		secret.Data[key] = encoded
+	}
+	for key, value := range data.Metadata.CustomMetadata {
+		switch {
+		case key == customMetadataKeyType:
+			secret.Type = v1.SecretType(value)
+		case strings.HasPrefix(key, customMetadataKeyPrefixLabel):
+			if secret.Labels == nil {
+				secret.Labels = map[string]string{}
+			}
+			secret.Labels[strings.TrimPrefix(key, customMetadataKeyPrefixLabel)] = value
+		case strings.HasPrefix(key, customMetadataKeyPrefixAnnotation):
+			if secret.Annotations == nil {
+				secret.Annotations = map[string]string{}
+			}
+			secret.Annotations[strings.TrimPrefix(key, customMetadataKeyPrefixAnnotation)] = value
+		}
+	}
+	return secret, nil
+}
+
+// newResponseError returns an error describing an unexpected response of
+// the Vault server, including the error messages sent by the server.
+func newResponseError(response *http.Response) error {
+	body := struct {
+		Errors []string `json:"errors"`
+	}{}
+	content, _ := ioutil.ReadAll(io.LimitReader(response.Body, 64*1024))
+	if err := json.Unmarshal(content, &body); err == nil && len(body.Errors) > 0 {
+		return errors.Errorf("vault responded with status %d: %s", response.StatusCode, strings.Join(body.Errors, "; "))
+	}
+	return errors.Errorf("vault responded with status %d", response.StatusCode)
+}
@@ -0,0 +1,367 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	serrors "github.com/SAP/stewardci-core/pkg/errors"
+	"gotest.tools/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// vaultStandIn is a minimal HTTP stand-in for the Vault KV version 2 and
+// Kubernetes auth APIs.
+type vaultStandIn struct {
+	mutex       sync.Mutex
+	secrets     map[string]interface{}
+	validTokens map[string]bool
+	loginJWT    string
+	loginRole   string
+	loginToken  string
+	loginCount  int
+	requests    []string
+}
+
+func newVaultStandIn(t *testing.T) (*vaultStandIn, *httptest.Server) {
+	standIn := &vaultStandIn{
+		secrets:     map[string]interface{}{},
+		validTokens: map[string]bool{},
+	}
+	server := httptest.NewServer(standIn)
+	t.Cleanup(server.Close)
+	return standIn, server
+}
+
+func (s *vaultStandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.requests = append(s.requests, r.URL.EscapedPath())
+
+	if r.Method == http.MethodPost && r.URL.Path == "/v1/auth/kubernetes/login" {
+		body := map[string]string{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["jwt"] != s.loginJWT || body["role"] != s.loginRole {
+			writeJSON(w, http.StatusBadRequest, map[string]interface{}{"errors": []string{"invalid role or jwt"}})
+			return
+		}
+		s.loginCount++
+		s.validTokens[s.loginToken] = true
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"auth": map[string]interface{}{"client_token": s.loginToken, "lease_duration": 3600},
+		})
+		return
+	}
+
+	if !s.validTokens[r.Header.Get("X-Vault-Token")] {
+		writeJSON(w, http.StatusForbidden, map[string]interface{}{"errors": []string{"permission denied"}})
+		return
+	}
+	secret, found := s.secrets[r.URL.Path]
+	if !found {
+		writeJSON(w, http.StatusNotFound, map[string]interface{}{"errors": []string{}})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": secret})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func Test_provider_GetSecret_Existing(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	standIn, server := newVaultStandIn(t)
+	standIn.validTokens["token1"] = true
+	standIn.secrets["/v1/secret/data/steward/client1/tenant1/secret1"] = map[string]interface{}{
+		"data": map[string]interface{}{
+			"username": "user1",
+			"password": "pass1",
+			"port":     8080,
+		},
+		"metadata": map[string]interface{}{
+			"deletion_time": "",
+			"destroyed":     false,
+			"custom_metadata": map[string]string{
+				"type":                              "kubernetes.io/basic-auth",
+				"label.jenkins.io/credentials-type": "usernamePassword",
+				"annotation.steward.sap.com/secret-rename-to": "renamed1",
+				"unknown": "ignored",
+			},
+		},
+	}
+	examinee, err := NewProvider(
+		Config{Address: server.URL, Auth: Auth{Token: "token1"}},
+		PathData{ClientNamespace: "client1", TenantName: "tenant1", TenantNamespace: "tn1"},
+	)
+	assert.NilError(t, err)
+
+	// EXERCISE
+	result, resultErr := examinee.GetSecret(ctx, "secret1")
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assert.DeepEqual(t, &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "secret1",
+			Labels:      map[string]string{"jenkins.io/credentials-type": "usernamePassword"},
+			Annotations: map[string]string{"steward.sap.com/secret-rename-to": "renamed1"},
+		},
+		Type: v1.SecretTypeBasicAuth,
+		Data: map[string][]byte{
+			"username": []byte("user1"),
+			"password": []byte("pass1"),
+			"port":     []byte("8080"),
+		},
+	}, result)
+}
+
+func Test_provider_GetSecret_NotExisting(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name   string
+		secret interface{}
+	}{
+		{"missing", nil},
+		{"deleted", map[string]interface{}{
+			"data":     nil,
+			"metadata": map[string]interface{}{"deletion_time": "2022-01-01T00:00:00Z"},
+		}},
+		{"destroyed", map[string]interface{}{
+			"data":     map[string]interface{}{"key1": "value1"},
+			"metadata": map[string]interface{}{"destroyed": true},
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			ctx := context.Background()
+			standIn, server := newVaultStandIn(t)
+			standIn.validTokens["token1"] = true
+			if tc.secret != nil {
+				standIn.secrets["/v1/kv/data/tn1/secret1"] = tc.secret
+			}
+			examinee, err := NewProvider(
+				Config{
+					Address:      server.URL,
+					MountPath:    "kv",
+					PathTemplate: "{{ .TenantNamespace }}/{{ .SecretName }}",
+					Auth:         Auth{Token: "token1"},
+				},
+				PathData{TenantNamespace: "tn1"},
+			)
+			assert.NilError(t, err)
+
+			// EXERCISE
+			result, resultErr := examinee.GetSecret(ctx, "secret1")
+
+			// VERIFY
+			assert.NilError(t, resultErr)
+			assert.Assert(t, result == nil)
+		})
+	}
+}
+
+func Test_provider_GetSecret_InvalidName(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name       string
+		secretName string
+	}{
+		{"traversal", "../../client2/tenant2/secret1"},
+		{"slash", "tenant2/secret1"},
+		{"dot_dot", "secret..1"},
+		{"percent_encoded_slash", "..%2F..%2Fclient2"},
+		{"upper_case", "Secret1"},
+		{"empty", ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			ctx := context.Background()
+			standIn, server := newVaultStandIn(t)
+			standIn.validTokens["token1"] = true
+			standIn.secrets["/v1/secret/data/steward/client2/tenant2/secret1"] = map[string]interface{}{
+				"data": map[string]interface{}{"key1": "value1"},
+			}
+			examinee, err := NewProvider(
+				Config{Address: server.URL, Auth: Auth{Token: "token1"}},
+				PathData{ClientNamespace: "client1", TenantName: "tenant1"},
+			)
+			assert.NilError(t, err)
+
+			// EXERCISE
+			result, resultErr := examinee.GetSecret(ctx, tc.secretName)
+
+			// VERIFY
+			assert.ErrorContains(t, resultErr, fmt.Sprintf("invalid secret name %q", tc.secretName))
+			assert.Equal(t, stewardv1alpha1.ResultErrorConfig, serrors.GetClass(resultErr))
+			assert.Assert(t, result == nil)
+			assert.Equal(t, 0, len(standIn.requests))
+		})
+	}
+}
+
+func Test_provider_GetSecret_EscapesPath(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	standIn, server := newVaultStandIn(t)
+	standIn.validTokens["token1"] = true
+	standIn.secrets["/v1/my kv/data/team a/secret1"] = map[string]interface{}{
+		"data": map[string]interface{}{"key1": "value1"},
+	}
+	examinee, err := NewProvider(
+		Config{
+			Address:      server.URL,
+			MountPath:    "my kv",
+			PathTemplate: "{{ .TenantName }}/{{ .SecretName }}",
+			Auth:         Auth{Token: "token1"},
+		},
+		PathData{TenantName: "team a"},
+	)
+	assert.NilError(t, err)
+
+	// EXERCISE
+	result, resultErr := examinee.GetSecret(ctx, "secret1")
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assert.Equal(t, "secret1", result.GetName())
+	assert.DeepEqual(t, []string{"/v1/my%20kv/data/team%20a/secret1"}, standIn.requests)
+}
+
+func Test_provider_GetSecret_RelativePathSegment(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	standIn, server := newVaultStandIn(t)
+	standIn.validTokens["token1"] = true
+	examinee, err := NewProvider(
+		Config{
+			Address:      server.URL,
+			PathTemplate: "{{ .TenantName }}/{{ .SecretName }}",
+			Auth:         Auth{Token: "token1"},
+		},
+		PathData{TenantName: ".."},
+	)
+	assert.NilError(t, err)
+
+	// EXERCISE
+	_, resultErr := examinee.GetSecret(ctx, "secret1")
+
+	// VERIFY
+	assert.Error(t, resultErr, `vault path "../secret1" must not contain relative segments`)
+	assert.Equal(t, 0, len(standIn.requests))
+}
+
+func Test_provider_GetSecret_PermissionDenied(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	_, server := newVaultStandIn(t)
+	examinee, err := NewProvider(
+		Config{Address: server.URL, Auth: Auth{Token: "invalid1"}},
+		PathData{ClientNamespace: "client1", TenantName: "tenant1"},
+	)
+	assert.NilError(t, err)
+
+	// EXERCISE
+	result, resultErr := examinee.GetSecret(ctx, "secret1")
+
+	// VERIFY
+	assert.Error(t, resultErr, `failed to get secret "secret1" from vault path "steward/client1/tenant1/secret1": vault responded with status 403: permission denied`)
+	assert.Assert(t, result == nil)
+}
+
+func Test_provider_GetSecret_KubernetesAuth(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	standIn, server := newVaultStandIn(t)
+	standIn.loginJWT = "jwt1"
+	standIn.loginRole = "role1"
+	standIn.loginToken = "login-token1"
+	standIn.secrets["/v1/secret/data/steward/client1/tenant1/secret1"] = map[string]interface{}{
+		"data": map[string]interface{}{"key1": "value1"},
+	}
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	assert.NilError(t, ioutil.WriteFile(tokenFile, []byte("jwt1\n"), 0600))
+	config := Config{
+		Address: server.URL,
+		Auth: Auth{
+			KubernetesRole:          "role1",
+			ServiceAccountTokenFile: tokenFile,
+		},
+	}
+	examinee, err := NewProvider(config, PathData{ClientNamespace: "client1", TenantName: "tenant1"})
+	assert.NilError(t, err)
+
+	// EXERCISE
+	result1, resultErr1 := examinee.GetSecret(ctx, "secret1")
+	result2, resultErr2 := examinee.GetSecret(ctx, "secret1")
+
+	// VERIFY
+	assert.NilError(t, resultErr1)
+	assert.NilError(t, resultErr2)
+	assert.DeepEqual(t, map[string][]byte{"key1": []byte("value1")}, result1.Data)
+	assert.DeepEqual(t, result1, result2)
+	assert.Equal(t, 1, standIn.loginCount)
+
+	// revoked tokens get replaced
+	standIn.validTokens = map[string]bool{}
+	result3, resultErr3 := examinee.GetSecret(ctx, "secret1")
+	assert.NilError(t, resultErr3)
+	assert.DeepEqual(t, result1, result3)
+	assert.Equal(t, 2, standIn.loginCount)
+}
+
+func Test_NewProvider_InvalidConfig(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		config        Config
+		expectedError string
+	}{
+		{"no_address", Config{Auth: Auth{Token: "token1"}}, "vault address must not be empty"},
+		{"no_auth", Config{Address: "http://vault1"}, "vault auth: either token or kubernetes role must be set"},
+		{"ambiguous_auth", Config{Address: "http://vault1", Auth: Auth{Token: "token1", KubernetesRole: "role1"}}, "vault auth: token and kubernetes role must not be set both"},
+		{"invalid_template", Config{Address: "http://vault1", PathTemplate: "{{ .Foo", Auth: Auth{Token: "token1"}}, "invalid vault path template: template: path:1: unclosed action"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// EXERCISE
+			result, resultErr := NewProvider(tc.config, PathData{})
+
+			// VERIFY
+			assert.Error(t, resultErr, tc.expectedError)
+			assert.Assert(t, result == nil)
+		})
+	}
+}
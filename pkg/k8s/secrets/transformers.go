@@ -34,6 +34,37 @@ func RenameByAnnotationTransformer(key string) SecretTransformer {
 	}
 }
 
+// RenameTransformer returns a secret transformer function that sets
+// `metadata.name` to the given name. If the given name is empty,
+// `metadata.name` is kept unchanged.
+func RenameTransformer(newName string) SecretTransformer {
+	return func(secret *v1.Secret) {
+		if newName != "" {
+			secret.SetName(newName)
+		}
+	}
+}
+
+// ProjectKeysTransformer returns a secret transformer function that keeps
+// only the data keys contained in the given mapping and renames them to the
+// mapped target keys. Keys of the mapping missing in the secret are ignored.
+// A nil mapping keeps all keys unchanged.
+func ProjectKeysTransformer(keyMapping map[string]string) SecretTransformer {
+	return func(secret *v1.Secret) {
+		if keyMapping == nil {
+			return
+		}
+		data := make(map[string][]byte, len(keyMapping))
+		for key, targetKey := range keyMapping {
+			if value, ok := secret.Data[key]; ok {
+				data[targetKey] = value
+			}
+		}
+		secret.Data = data
+		secret.StringData = nil
+	}
+}
+
 // SetAnnotationTransformer returns a secret transformer function that sets the
 // annotation with the given key to the given value.
 func SetAnnotationTransformer(key string, value string) SecretTransformer {
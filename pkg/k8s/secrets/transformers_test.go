@@ -80,6 +80,67 @@ func Test_RenameByAnnotationTransformer(t *testing.T) {
 	}
 }
 
+func Test_RenameTransformer(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name         string
+		newName      string
+		expectedName string
+	}{
+		{"rename", "newName1", "newName1"},
+		{"empty_new_name", "", "orig1"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			orig := fake.SecretOpaque("orig1", "secret1")
+			transformed := orig.DeepCopy()
+
+			// EXERCISE
+			RenameTransformer(tc.newName)(transformed)
+
+			// VERIFY
+			expected := orig.DeepCopy()
+			expected.SetName(tc.expectedName)
+			assert.DeepEqual(t, expected, transformed)
+		})
+	}
+}
+
+func Test_ProjectKeysTransformer(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name         string
+		keyMapping   map[string]string
+		expectedData map[string][]byte
+	}{
+		{"nil_mapping", nil, map[string][]byte{"key1": []byte("value1"), "key2": []byte("value2")}},
+		{"select", map[string]string{"key1": "key1"}, map[string][]byte{"key1": []byte("value1")}},
+		{"rename", map[string]string{"key1": "foo", "key2": "bar"}, map[string][]byte{"foo": []byte("value1"), "bar": []byte("value2")}},
+		{"missing_key", map[string]string{"key1": "key1", "missing": "missing"}, map[string][]byte{"key1": []byte("value1")}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			orig := fake.SecretOpaque("name1", "secret1")
+			orig.Data = map[string][]byte{"key1": []byte("value1"), "key2": []byte("value2")}
+			transformed := orig.DeepCopy()
+
+			// EXERCISE
+			ProjectKeysTransformer(tc.keyMapping)(transformed)
+
+			// VERIFY
+			expected := orig.DeepCopy()
+			expected.Data = tc.expectedData
+			assert.DeepEqual(t, expected, transformed)
+		})
+	}
+}
+
 func Test_SetAnnotationTransformer_SetNew(t *testing.T) {
 	t.Parallel()
 
@@ -3,7 +3,7 @@ package k8s
 import (
 	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/client/clientset/versioned/typed/steward/v1alpha1"
 	secrets "github.com/SAP/stewardci-core/pkg/k8s/secrets"
-	k8ssecretprovider "github.com/SAP/stewardci-core/pkg/k8s/secrets/providers/k8s"
+	cachedsecretprovider "github.com/SAP/stewardci-core/pkg/k8s/secrets/providers/cached"
 )
 
 // TenantNamespace representing the client
@@ -18,11 +18,15 @@ type tenantNamespace struct {
 	secretProvider    secrets.SecretProvider
 }
 
-// NewTenantNamespace creates new TenantNamespace object
-func NewTenantNamespace(factory ClientFactory, namespace string) TenantNamespace {
-	secretsClient := factory.CoreV1().Secrets(namespace)
+// NewTenantNamespace creates new TenantNamespace object.
+// The secret cache is optional and may be nil.
+func NewTenantNamespace(factory ClientFactory, namespace string, secretCache *cachedsecretprovider.SecretCache) TenantNamespace {
 	pipelineRunClient := factory.StewardV1alpha1().PipelineRuns(namespace)
-	secretProvider := k8ssecretprovider.NewProvider(secretsClient, namespace)
+	secretProvider := &tenantSecretProvider{
+		factory:     factory,
+		namespace:   namespace,
+		secretCache: secretCache,
+	}
 	return &tenantNamespace{
 		secretProvider:    secretProvider,
 		pipelineRunClient: pipelineRunClient,
@@ -36,6 +40,7 @@ func (t *tenantNamespace) TargetClientFactory() ClientFactory {
 }
 
 //  GetSecretProvider returns a secret provider
+//  selected by the client namespace owning the tenant namespace.
 func (t *tenantNamespace) GetSecretProvider() secrets.SecretProvider {
 	return t.secretProvider
 }
@@ -17,7 +17,7 @@ func Test_tenantNamespace_GetSecretProvider_works(t *testing.T) {
 	cf := fake.NewClientFactory(
 		fake.SecretOpaque(name, ns1),
 	)
-	examinee := NewTenantNamespace(cf, ns1)
+	examinee := NewTenantNamespace(cf, ns1, nil)
 
 	// EXERCISE
 	result := examinee.GetSecretProvider()
@@ -0,0 +1,56 @@
+package runctl
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+)
+
+// defaultAbortGracePeriod is the time an aborted pipeline run is given to
+// terminate the Jenkinsfile Runner if the pipeline runs configuration does
+// not define one. It equals the default termination grace period of pods.
+const defaultAbortGracePeriod = 30 * time.Second
+
+// abortGracePeriod returns the maximum time to wait for the Jenkinsfile
+// Runner of an aborted pipeline run to terminate before cleaning up.
+func abortGracePeriod(pipelineRunsConfig *cfg.PipelineRunsConfigStruct) time.Duration {
+	if pipelineRunsConfig != nil && pipelineRunsConfig.AbortGracePeriod != nil {
+		return pipelineRunsConfig.AbortGracePeriod.Duration
+	}
+	return defaultAbortGracePeriod
+}
+
+// unknownAbortRequester is reported as the user who requested the abortion
+// of a pipeline run if it has not been recorded. The requester is recorded
+// by the admission webhook only, so it is unknown if the webhook is
+// disabled.
+const unknownAbortRequester = "unknown user"
+
+// abortMessage returns the status message of an aborted pipeline run
+// naming the user who requested the abortion and the reason, if given.
+// `finalMessage` is the message reported by the Jenkinsfile Runner when
+// it has been terminated and gets appended if not empty.
+func abortMessage(pipelineRun *stewardv1alpha1.PipelineRun, finalMessage string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Aborted by %s", abortRequester(pipelineRun))
+	if reason := strings.TrimSpace(pipelineRun.Spec.AbortReason); reason != "" {
+		fmt.Fprintf(&b, ": %s", reason)
+	}
+	if finalMessage = strings.TrimSpace(finalMessage); finalMessage != "" {
+		fmt.Fprintf(&b, "\n%s", finalMessage)
+	}
+	return b.String()
+}
+
+// abortRequester returns the user who requested the abortion of the given
+// pipeline run as recorded by the admission webhook, or
+// `unknownAbortRequester`.
+func abortRequester(pipelineRun *stewardv1alpha1.PipelineRun) string {
+	if requester := pipelineRun.GetAnnotations()[stewardv1alpha1.AnnotationAbortRequestedBy]; requester != "" {
+		return requester
+	}
+	return unknownAbortRequester
+}
@@ -0,0 +1,81 @@
+package runctl
+
+import (
+	"testing"
+	"time"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	assert "gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_abortGracePeriod(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     string
+		config   *cfg.PipelineRunsConfigStruct
+		expected time.Duration
+	}{
+		{"no_config", nil, defaultAbortGracePeriod},
+		{"default", &cfg.PipelineRunsConfigStruct{}, defaultAbortGracePeriod},
+		{"config", &cfg.PipelineRunsConfigStruct{AbortGracePeriod: metav1Duration(2 * time.Minute)}, 2 * time.Minute},
+		{"config_zero", &cfg.PipelineRunsConfigStruct{AbortGracePeriod: metav1Duration(0)}, 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// EXERCISE
+			result := abortGracePeriod(tc.config)
+
+			// VERIFY
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func Test_abortMessage(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name         string
+		requester    string
+		reason       string
+		finalMessage string
+		expected     string
+	}{
+		{"plain", "", "", "", "Aborted by unknown user"},
+		{"requester", "user1", "", "", "Aborted by user1"},
+		{"reason", "", " reason1 ", "", "Aborted by unknown user: reason1"},
+		{"requester_and_reason", "user1", "reason1", "", "Aborted by user1: reason1"},
+		{"final_message", "user1", "reason1", "message1\n", "Aborted by user1: reason1\nmessage1"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			pipelineRun := &stewardv1alpha1.PipelineRun{
+				Spec: stewardv1alpha1.PipelineSpec{
+					Intent:      stewardv1alpha1.IntentAbort,
+					AbortReason: tc.reason,
+				},
+			}
+			if tc.requester != "" {
+				pipelineRun.ObjectMeta = metav1.ObjectMeta{
+					Annotations: map[string]string{
+						stewardv1alpha1.AnnotationAbortRequestedBy: tc.requester,
+					},
+				}
+			}
+
+			// EXERCISE
+			result := abortMessage(pipelineRun, tc.finalMessage)
+
+			// VERIFY
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
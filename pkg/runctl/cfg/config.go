@@ -10,7 +10,10 @@ import (
 	serrors "github.com/SAP/stewardci-core/pkg/errors"
 	"github.com/SAP/stewardci-core/pkg/featureflag"
 	"github.com/SAP/stewardci-core/pkg/k8s"
+	slabels "github.com/SAP/stewardci-core/pkg/stewardlabels"
+	"github.com/ghodss/yaml"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"knative.dev/pkg/system"
@@ -19,6 +22,8 @@ import (
 const (
 	mainConfigMapName            = "steward-pipelineruns"
 	mainConfigKeyTimeout         = "timeout"
+	mainConfigKeyMaxTimeout      = "maxTimeout"
+	mainConfigKeyAbortGrace      = "abortGracePeriod"
 	mainConfigKeyLimitRange      = "limitRange"
 	mainConfigKeyResourceQuota   = "resourceQuota"
 	mainConfigKeyImage           = "jenkinsfileRunner.image"
@@ -26,17 +31,60 @@ const (
 	mainConfigKeyPSCRunAsUser    = "jenkinsfileRunner.podSecurityContext.runAsUser"
 	mainConfigKeyPSCRunAsGroup   = "jenkinsfileRunner.podSecurityContext.runAsGroup"
 	mainConfigKeyPSCFSGroup      = "jenkinsfileRunner.podSecurityContext.fsGroup"
+	mainConfigKeyRunBackend      = "runBackend"
+	mainConfigKeyJavaOpts        = "jenkinsfileRunner.javaOpts"
+	mainConfigKeyResources       = "jenkinsfileRunner.resources"
+	mainConfigKeyResourcesMin    = "jenkinsfileRunner.resourceBounds.min"
+	mainConfigKeyResourcesMax    = "jenkinsfileRunner.resourceBounds.max"
+	mainConfigKeyCloneRetryIntvl = "jenkinsfileRunner.pipelineCloneRetryIntervalSec"
+	mainConfigKeyCloneRetryTmout = "jenkinsfileRunner.pipelineCloneRetryTimeoutSec"
+	mainConfigKeyESIndexURL      = "logging.elasticsearch.indexURL"
+	mainConfigKeyMaxActive       = "maxActivePipelineRuns"
+	mainConfigKeyMaxActiveTenant = "maxActivePipelineRunsPerTenant"
+	mainConfigKeyTTLFinished     = "ttlSecondsAfterFinished"
+	mainConfigKeyKeepFinished    = "keepFinishedPipelineRunsPerTenant"
+	mainConfigKeySidecarImages   = "sidecars.allowedImages"
+	mainConfigKeyRunNSLabels     = "runNamespace.labels"
+	mainConfigKeyRunNSAnnots     = "runNamespace.annotations"
 
 	networkPoliciesConfigMapName    = "steward-pipelineruns-network-policies"
 	networkPoliciesConfigKeyDefault = "_default"
+
+	schedulingProfilesConfigMapName    = "steward-pipelineruns-scheduling-profiles"
+	schedulingProfilesConfigKeyDefault = "_default"
+
+	resourceProfilesConfigMapName    = "steward-pipelineruns-resource-profiles"
+	resourceProfilesConfigKeyDefault = "_default"
+)
+
+const (
+	// RunBackendTekton denotes the run backend executing the Jenkinsfile
+	// Runner as Tekton TaskRun.
+	RunBackendTekton = "tekton"
+
+	// RunBackendPod denotes the run backend executing the Jenkinsfile
+	// Runner as plain Kubernetes pod, not requiring Tekton.
+	RunBackendPod = "pod"
 )
 
 // PipelineRunsConfigStruct is a struct holding the pipeline runs configuration.
 type PipelineRunsConfigStruct struct {
 	// Timeout is the maximum execution time of a pipeline run.
 	// If `nil`, a default timeout should be used.
+	// Pipeline runs may override it via `spec.timeout`.
 	Timeout *metav1.Duration
 
+	// MaxTimeout is the maximum timeout pipeline runs may request via
+	// `spec.timeout`. It must not be lower than `Timeout`.
+	// If `nil`, the timeout requested by pipeline runs is not limited.
+	MaxTimeout *metav1.Duration
+
+	// AbortGracePeriod is the maximum time an aborted pipeline run is
+	// given to terminate the Jenkinsfile Runner before its namespaces
+	// get deleted.
+	// If `nil`, a default grace period should be used.
+	AbortGracePeriod *metav1.Duration
+
 	// The manifest (in YAML format) of a Kubernetes LimitRange object to be
 	// applied to each pipeline run sandbox namespace.
 	// If empty, no limit range will be defined.
@@ -78,6 +126,155 @@ type PipelineRunsConfigStruct struct {
 	// NetworkPolicies maps network profile names to network policies.
 	// Each value is a Kubernetes network policy manifest in YAML format.
 	NetworkPolicies map[string]string
+
+	// DefaultSchedulingProfile is the name of the scheduling profile that
+	// should be used in case the user has not explicitly chosen one.
+	// If empty, pipeline runs without scheduling profile are scheduled
+	// without constraints.
+	DefaultSchedulingProfile string
+
+	// SchedulingProfiles maps scheduling profile names to scheduling
+	// profiles.
+	SchedulingProfiles map[string]*SchedulingProfile
+
+	// DefaultResourceProfile is the name of the resource profile that
+	// should be used in case the user has not explicitly chosen one.
+	// If empty, pipeline runs without resource profile get the limit
+	// range, resource quota and Jenkinsfile Runner resources defined
+	// directly in the pipeline runs configuration.
+	DefaultResourceProfile string
+
+	// ResourceProfiles maps resource profile names to resource profiles.
+	ResourceProfiles map[string]*ResourceProfile
+
+	// RunBackend is the name of the backend executing pipeline runs.
+	// It is one of `RunBackendTekton` and `RunBackendPod`. An empty value
+	// is equivalent to `RunBackendTekton`.
+	RunBackend string
+
+	// The following fields are passed to the Jenkinsfile Runner by all run
+	// backends. The Tekton ClusterTask does not define own values.
+
+	// JenkinsfileRunnerJavaOpts is the value of the `JAVA_OPTS` environment
+	// variable of the Jenkinsfile Runner container.
+	JenkinsfileRunnerJavaOpts string
+
+	// JenkinsfileRunnerResources are the compute resource requirements of
+	// the Jenkinsfile Runner container. Pipeline runs may override them
+	// via `spec.jenkinsfileRunner.resources`.
+	// If `nil`, no resource requirements are set.
+	JenkinsfileRunnerResources *corev1.ResourceRequirements
+
+	// JenkinsfileRunnerPipelineCloneRetryIntervalSec is the interval in
+	// seconds between retries of cloning the pipeline repository.
+	// If empty, the Jenkinsfile Runner image default is used.
+	JenkinsfileRunnerPipelineCloneRetryIntervalSec string
+
+	// JenkinsfileRunnerPipelineCloneRetryTimeoutSec is the total time in
+	// seconds for retrying to clone the pipeline repository.
+	// If empty, the Jenkinsfile Runner image default is used.
+	JenkinsfileRunnerPipelineCloneRetryTimeoutSec string
+
+	// ElasticsearchIndexURL is the URL of the Elasticsearch index to send
+	// pipeline logs to.
+	// If empty, logging to Elasticsearch is disabled.
+	ElasticsearchIndexURL string
+
+	// MaxActivePipelineRuns is the maximum number of pipeline runs in
+	// the whole system which may be active (i.e. preparing, waiting or
+	// running) at the same time. Further pipeline runs stay queued.
+	// If `nil` or zero, the number is not limited.
+	MaxActivePipelineRuns *int64
+
+	// MaxActivePipelineRunsPerTenant is the maximum number of pipeline
+	// runs in a single tenant namespace which may be active at the same
+	// time. It can be overridden per client namespace.
+	// If `nil` or zero, the number is not limited.
+	MaxActivePipelineRunsPerTenant *int64
+
+	// TTLSecondsAfterFinished is the number of seconds finished pipeline
+	// runs are kept before they get deleted. Pipeline runs may override
+	// it via `spec.ttlSecondsAfterFinished`.
+	// If `nil`, finished pipeline runs are not deleted due to their age.
+	TTLSecondsAfterFinished *int64
+
+	// KeepFinishedPipelineRunsPerTenant is the maximum number of finished
+	// pipeline runs kept in a single tenant namespace. The oldest finished
+	// pipeline runs exceeding this number get deleted.
+	// If `nil` or zero, the number is not limited.
+	KeepFinishedPipelineRunsPerTenant *int64
+
+	// SidecarAllowedImages is the list of images pipeline runs may use
+	// for sidecars. An entry ending with `*` permits all images starting
+	// with the part before.
+	// If empty, sidecars are not permitted.
+	SidecarAllowedImages []string
+
+	// JenkinsfileRunnerResourcesMin and JenkinsfileRunnerResourcesMax are
+	// the bounds for the resource requests and limits pipeline runs may
+	// set via `spec.jenkinsfileRunner.resources`. Resources not contained
+	// are not bounded.
+	JenkinsfileRunnerResourcesMin corev1.ResourceList
+	JenkinsfileRunnerResourcesMax corev1.ResourceList
+
+	// RunNamespaceLabels and RunNamespaceAnnotations map label and
+	// annotation keys to Go text templates whose results are set on the
+	// namespaces created for pipeline runs. The templates get the fields
+	// `PipelineRunName`, `TenantNamespace` and `Purpose` as input.
+	// If empty, no additional labels or annotations are set.
+	RunNamespaceLabels      map[string]string
+	RunNamespaceAnnotations map[string]string
+
+	// The following fields are not loaded from the config maps but set
+	// from the pipeline run settings of a tenant.
+
+	// AllowedNetworkProfiles, AllowedSchedulingProfiles and
+	// AllowedResourceProfiles restrict the profiles pipeline runs may use.
+	// If empty, all profiles may be used. Resource profiles are further
+	// restricted by the annotation of the client namespace, if any.
+	AllowedNetworkProfiles    []string
+	AllowedSchedulingProfiles []string
+	AllowedResourceProfiles   []string
+
+	// TenantMaxActivePipelineRuns is the maximum number of pipeline runs
+	// in the tenant namespace which may be active at the same time. It
+	// can only lower the limit resulting from
+	// `MaxActivePipelineRunsPerTenant` and the client namespace.
+	// If `nil` or zero, the number is not limited by the tenant.
+	TenantMaxActivePipelineRuns *int64
+}
+
+// SchedulingProfile defines how the pods of pipeline runs get scheduled.
+type SchedulingProfile struct {
+	// NodeSelector restricts the nodes the pods may be scheduled to.
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations allow the pods to be scheduled to tainted nodes.
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Affinity defines the node, pod and pod anti-affinity of the pods.
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
+	// PriorityClassName is the name of the priority class of the pods.
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+}
+
+// ResourceProfile bundles the resource constraints of pipeline runs.
+// Empty fields fall back to the respective values of the pipeline runs
+// configuration.
+type ResourceProfile struct {
+	// LimitRange is the manifest (in YAML format) of a Kubernetes
+	// LimitRange object to be applied to the sandbox namespace.
+	LimitRange string `json:"limitRange,omitempty"`
+
+	// ResourceQuota is the manifest (in YAML format) of a Kubernetes
+	// ResourceQuota object to be applied to the sandbox namespace.
+	ResourceQuota string `json:"resourceQuota,omitempty"`
+
+	// JenkinsfileRunnerResources are the resource requirements of the
+	// Jenkinsfile Runner container. Pipeline runs may still override
+	// them via `spec.jenkinsfileRunner.resources`.
+	JenkinsfileRunnerResources *corev1.ResourceRequirements `json:"jenkinsfileRunnerResources,omitempty"`
 }
 
 // LoadPipelineRunsConfig loads the pipelineruns configuration and returns it.
@@ -99,6 +296,16 @@ func LoadPipelineRunsConfig(ctx context.Context, clientFactory k8s.ClientFactory
 			optional:      false,
 			processFunc:   processNetworkPoliciesConfig,
 		},
+		{
+			configMapName: schedulingProfilesConfigMapName,
+			optional:      true,
+			processFunc:   processSchedulingProfilesConfig,
+		},
+		{
+			configMapName: resourceProfilesConfigMapName,
+			optional:      true,
+			processFunc:   processResourceProfilesConfig,
+		},
 	} {
 		err := processConfigMap(
 			ctx,
@@ -198,6 +405,10 @@ func processMainConfig(configData map[string]string, dest *PipelineRunsConfigStr
 	dest.ResourceQuota = configData[mainConfigKeyResourceQuota]
 	dest.JenkinsfileRunnerImage = configData[mainConfigKeyImage]
 	dest.JenkinsfileRunnerImagePullPolicy = configData[mainConfigKeyImagePullPolicy]
+	dest.JenkinsfileRunnerJavaOpts = configData[mainConfigKeyJavaOpts]
+	dest.JenkinsfileRunnerPipelineCloneRetryIntervalSec = configData[mainConfigKeyCloneRetryIntvl]
+	dest.JenkinsfileRunnerPipelineCloneRetryTimeoutSec = configData[mainConfigKeyCloneRetryTmout]
+	dest.ElasticsearchIndexURL = configData[mainConfigKeyESIndexURL]
 
 	var err error
 
@@ -206,6 +417,36 @@ func processMainConfig(configData map[string]string, dest *PipelineRunsConfigStr
 		return err
 	}
 
+	if dest.MaxTimeout, err =
+		parseDuration(mainConfigKeyMaxTimeout); err != nil {
+		return err
+	}
+	if dest.MaxTimeout != nil && dest.MaxTimeout.Duration <= 0 {
+		return fmt.Errorf(
+			"key %q: value must be positive: %s",
+			mainConfigKeyMaxTimeout, dest.MaxTimeout.Duration,
+		)
+	}
+	// the default timeout is persisted in pipeline runs and must pass the
+	// same check as timeouts requested by clients
+	if dest.Timeout != nil && dest.MaxTimeout != nil && dest.Timeout.Duration > dest.MaxTimeout.Duration {
+		return fmt.Errorf(
+			"key %q: value %s exceeds %s from key %q",
+			mainConfigKeyTimeout, dest.Timeout.Duration, dest.MaxTimeout.Duration, mainConfigKeyMaxTimeout,
+		)
+	}
+
+	if dest.AbortGracePeriod, err =
+		parseDuration(mainConfigKeyAbortGrace); err != nil {
+		return err
+	}
+	if dest.AbortGracePeriod != nil && dest.AbortGracePeriod.Duration < 0 {
+		return fmt.Errorf(
+			"key %q: value must not be negative: %s",
+			mainConfigKeyAbortGrace, dest.AbortGracePeriod.Duration,
+		)
+	}
+
 	if dest.JenkinsfileRunnerPodSecurityContextRunAsUser, err =
 		parseInt64(mainConfigKeyPSCRunAsUser); err != nil {
 		return err
@@ -220,6 +461,89 @@ func processMainConfig(configData map[string]string, dest *PipelineRunsConfigStr
 		return err
 	}
 
+	if strVal := configData[mainConfigKeyResources]; strings.TrimSpace(strVal) != "" {
+		resources := &corev1.ResourceRequirements{}
+		if err = yaml.Unmarshal([]byte(strVal), resources); err != nil {
+			return wrapParseError(err, mainConfigKeyResources, strVal)
+		}
+		dest.JenkinsfileRunnerResources = resources
+	}
+
+	for _, p := range []struct {
+		key  string
+		dest *corev1.ResourceList
+	}{
+		{mainConfigKeyResourcesMin, &dest.JenkinsfileRunnerResourcesMin},
+		{mainConfigKeyResourcesMax, &dest.JenkinsfileRunnerResourcesMax},
+	} {
+		if strVal := configData[p.key]; strings.TrimSpace(strVal) != "" {
+			if err = yaml.Unmarshal([]byte(strVal), p.dest); err != nil {
+				return wrapParseError(err, p.key, strVal)
+			}
+		}
+	}
+	for name, min := range dest.JenkinsfileRunnerResourcesMin {
+		if max, ok := dest.JenkinsfileRunnerResourcesMax[name]; ok && min.Cmp(max) > 0 {
+			return fmt.Errorf(
+				"key %q: minimum %s of resource %q exceeds maximum %s from key %q",
+				mainConfigKeyResourcesMin, min.String(), name, max.String(), mainConfigKeyResourcesMax,
+			)
+		}
+	}
+
+	if strVal := configData[mainConfigKeySidecarImages]; strings.TrimSpace(strVal) != "" {
+		if err = yaml.Unmarshal([]byte(strVal), &dest.SidecarAllowedImages); err != nil {
+			return wrapParseError(err, mainConfigKeySidecarImages, strVal)
+		}
+	}
+
+	for _, p := range []struct {
+		key  string
+		dest *map[string]string
+	}{
+		{mainConfigKeyRunNSLabels, &dest.RunNamespaceLabels},
+		{mainConfigKeyRunNSAnnots, &dest.RunNamespaceAnnotations},
+	} {
+		if strVal := configData[p.key]; strings.TrimSpace(strVal) != "" {
+			if err = yaml.Unmarshal([]byte(strVal), p.dest); err != nil {
+				return wrapParseError(err, p.key, strVal)
+			}
+			if err = slabels.ValidateTemplates(*p.dest); err != nil {
+				return errors.Wrapf(err, "key %q", p.key)
+			}
+		}
+	}
+
+	for _, p := range []struct {
+		key  string
+		dest **int64
+	}{
+		{mainConfigKeyMaxActive, &dest.MaxActivePipelineRuns},
+		{mainConfigKeyMaxActiveTenant, &dest.MaxActivePipelineRunsPerTenant},
+		{mainConfigKeyTTLFinished, &dest.TTLSecondsAfterFinished},
+		{mainConfigKeyKeepFinished, &dest.KeepFinishedPipelineRunsPerTenant},
+	} {
+		if *p.dest, err = parseInt64(p.key); err != nil {
+			return err
+		}
+		if *p.dest != nil && **p.dest < 0 {
+			return fmt.Errorf(
+				"key %q: value must not be negative: %d",
+				p.key, **p.dest,
+			)
+		}
+	}
+
+	switch backend := configData[mainConfigKeyRunBackend]; backend {
+	case "", RunBackendTekton, RunBackendPod:
+		dest.RunBackend = backend
+	default:
+		return fmt.Errorf(
+			"key %q: unsupported run backend %q",
+			mainConfigKeyRunBackend, backend,
+		)
+	}
+
 	return nil
 }
 
@@ -272,3 +596,81 @@ func processNetworkPoliciesConfig(configData map[string]string, dest *PipelineRu
 
 	return nil
 }
+
+func processSchedulingProfilesConfig(configData map[string]string, dest *PipelineRunsConfigStruct) error {
+
+	isValidKey := func(key string) bool {
+		return key != "" && key == strings.TrimSpace(key) && !strings.HasPrefix(key, "_")
+	}
+
+	dest.DefaultSchedulingProfile = ""
+	dest.SchedulingProfiles = nil
+
+	schedulingProfiles := map[string]*SchedulingProfile{}
+	for key, value := range configData {
+		if !isValidKey(key) || strings.TrimSpace(value) == "" {
+			continue
+		}
+		profile := &SchedulingProfile{}
+		if err := yaml.Unmarshal([]byte(value), profile); err != nil {
+			return errors.Wrapf(err, "key %q: cannot parse scheduling profile", key)
+		}
+		schedulingProfiles[key] = profile
+	}
+
+	if defaultProfileKey := configData[schedulingProfilesConfigKeyDefault]; defaultProfileKey != "" {
+		if _, found := schedulingProfiles[defaultProfileKey]; !found {
+			return fmt.Errorf(
+				"key %q: value %q does not denote an existing scheduling profile key",
+				schedulingProfilesConfigKeyDefault,
+				defaultProfileKey,
+			)
+		}
+		dest.DefaultSchedulingProfile = defaultProfileKey
+	}
+
+	if len(schedulingProfiles) > 0 {
+		dest.SchedulingProfiles = schedulingProfiles
+	}
+
+	return nil
+}
+
+func processResourceProfilesConfig(configData map[string]string, dest *PipelineRunsConfigStruct) error {
+
+	isValidKey := func(key string) bool {
+		return key != "" && key == strings.TrimSpace(key) && !strings.HasPrefix(key, "_")
+	}
+
+	dest.DefaultResourceProfile = ""
+	dest.ResourceProfiles = nil
+
+	resourceProfiles := map[string]*ResourceProfile{}
+	for key, value := range configData {
+		if !isValidKey(key) || strings.TrimSpace(value) == "" {
+			continue
+		}
+		profile := &ResourceProfile{}
+		if err := yaml.Unmarshal([]byte(value), profile); err != nil {
+			return errors.Wrapf(err, "key %q: cannot parse resource profile", key)
+		}
+		resourceProfiles[key] = profile
+	}
+
+	if defaultProfileKey := configData[resourceProfilesConfigKeyDefault]; defaultProfileKey != "" {
+		if _, found := resourceProfiles[defaultProfileKey]; !found {
+			return fmt.Errorf(
+				"key %q: value %q does not denote an existing resource profile key",
+				resourceProfilesConfigKeyDefault,
+				defaultProfileKey,
+			)
+		}
+		dest.DefaultResourceProfile = defaultProfileKey
+	}
+
+	if len(resourceProfiles) > 0 {
+		dest.ResourceProfiles = resourceProfiles
+	}
+
+	return nil
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/pkg/errors"
 	"gotest.tools/assert"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"knative.dev/pkg/system"
 	_ "knative.dev/pkg/system/testing"
@@ -191,6 +192,7 @@ func Test_loadPipelineRunsConfig_CompleteConfig(t *testing.T) {
 				mainConfigKeyTimeout:         "4444m",
 				mainConfigKeyImage:           "jfrImage1",
 				mainConfigKeyImagePullPolicy: "jfrImagePullPolicy1",
+				mainConfigKeyRunBackend:      "pod",
 				"someKeyThatShouldBeIgnored": "34957349",
 			},
 		),
@@ -201,6 +203,16 @@ func Test_loadPipelineRunsConfig_CompleteConfig(t *testing.T) {
 			"networkPolicyKey2": "networkPolicy2",
 			"networkPolicyKey3": "networkPolicy3",
 		}),
+		newSchedulingProfilesConfigMap(map[string]string{
+			schedulingProfilesConfigKeyDefault: "schedulingProfileKey1",
+
+			"schedulingProfileKey1": "priorityClassName: low\n",
+		}),
+		newResourceProfilesConfigMap(map[string]string{
+			resourceProfilesConfigKeyDefault: "resourceProfileKey1",
+
+			"resourceProfileKey1": "limitRange: limitRange2\n",
+		}),
 	)
 
 	// EXERCISE
@@ -217,6 +229,7 @@ func Test_loadPipelineRunsConfig_CompleteConfig(t *testing.T) {
 		JenkinsfileRunnerPodSecurityContextRunAsUser:  int64Ptr(1111),
 		JenkinsfileRunnerPodSecurityContextRunAsGroup: int64Ptr(2222),
 		JenkinsfileRunnerPodSecurityContextFSGroup:    int64Ptr(3333),
+		RunBackend: RunBackendPod,
 
 		DefaultNetworkProfile: "networkPolicyKey2",
 		NetworkPolicies: map[string]string{
@@ -224,6 +237,16 @@ func Test_loadPipelineRunsConfig_CompleteConfig(t *testing.T) {
 			"networkPolicyKey2": "networkPolicy2",
 			"networkPolicyKey3": "networkPolicy3",
 		},
+
+		DefaultSchedulingProfile: "schedulingProfileKey1",
+		SchedulingProfiles: map[string]*SchedulingProfile{
+			"schedulingProfileKey1": {PriorityClassName: "low"},
+		},
+
+		DefaultResourceProfile: "resourceProfileKey1",
+		ResourceProfiles: map[string]*ResourceProfile{
+			"resourceProfileKey1": {LimitRange: "limitRange2"},
+		},
 	}
 	assert.DeepEqual(t, expectedConfig, resultConfig)
 }
@@ -275,6 +298,38 @@ func Test_loadPipelineRunsConfig_InvalidValues(t *testing.T) {
 
 		{mainConfigKeyTimeout, "a"},
 		{mainConfigKeyTimeout, "1a"},
+
+		{mainConfigKeyMaxTimeout, "a"},
+		{mainConfigKeyMaxTimeout, "0s"},
+		{mainConfigKeyMaxTimeout, "-1h"},
+
+		{mainConfigKeyAbortGrace, "a"},
+		{mainConfigKeyAbortGrace, "-1s"},
+
+		{mainConfigKeyRunBackend, "foo"},
+		{mainConfigKeyRunBackend, "Tekton"},
+
+		{mainConfigKeyResources, "limits: [1, 2]"},
+		{mainConfigKeyResourcesMin, "cpu: a"},
+		{mainConfigKeyResourcesMax, "- 1Gi"},
+
+		{mainConfigKeySidecarImages, "image1: foo"},
+
+		{mainConfigKeyRunNSLabels, "- foo"},
+		{mainConfigKeyRunNSLabels, "steward.sap.com/foo: bar"},
+		{mainConfigKeyRunNSAnnots, "example.com/foo: '{{ .Unclosed'"},
+
+		{mainConfigKeyMaxActive, "a"},
+		{mainConfigKeyMaxActive, "-1"},
+
+		{mainConfigKeyMaxActiveTenant, "a"},
+		{mainConfigKeyMaxActiveTenant, "-1"},
+
+		{mainConfigKeyTTLFinished, "a"},
+		{mainConfigKeyTTLFinished, "-1"},
+
+		{mainConfigKeyKeepFinished, "a"},
+		{mainConfigKeyKeepFinished, "-1"},
 	} {
 		t.Run(strconv.Itoa(i), func(t *testing.T) {
 			tc := tc // capture current value before going parallel
@@ -313,6 +368,8 @@ func Test_processMainConfig(t *testing.T) {
 				"_example": "exampleString",
 
 				mainConfigKeyTimeout:       "4444m",
+				mainConfigKeyMaxTimeout:    "5555m",
+				mainConfigKeyAbortGrace:    "90s",
 				mainConfigKeyLimitRange:    "limitRange1",
 				mainConfigKeyResourceQuota: "resourceQuota1",
 
@@ -321,25 +378,79 @@ func Test_processMainConfig(t *testing.T) {
 				mainConfigKeyPSCRunAsUser:    "1111",
 				mainConfigKeyPSCRunAsGroup:   "2222",
 				mainConfigKeyPSCFSGroup:      "3333",
+				mainConfigKeyJavaOpts:        "javaOpts1",
+				mainConfigKeyResources:       "limits:\n  cpu: 3\nrequests:\n  memory: 1Gi\n",
+				mainConfigKeyResourcesMin:    "memory: 512Mi\n",
+				mainConfigKeyResourcesMax:    "cpu: 8\nmemory: 16Gi\n",
+				mainConfigKeyCloneRetryIntvl: "10",
+				mainConfigKeyCloneRetryTmout: "60",
+
+				mainConfigKeyESIndexURL: "http://es.example.com/index1/_doc",
+				mainConfigKeyRunBackend: "pod",
+
+				mainConfigKeyMaxActive:       "100",
+				mainConfigKeyMaxActiveTenant: "5",
+				mainConfigKeyTTLFinished:     "86400",
+				mainConfigKeyKeepFinished:    "50",
+
+				mainConfigKeySidecarImages: "- postgres:14\n- docker.io/selenium/*\n",
+
+				mainConfigKeyRunNSLabels: "cost-center: cc1\n",
+				mainConfigKeyRunNSAnnots: "example.com/run: '{{ .PipelineRunName }}'\n",
 
 				"someKeyThatShouldBeIgnored": "34957349",
 			},
 			&PipelineRunsConfigStruct{
-				Timeout:       metav1Duration(time.Minute * 4444),
-				LimitRange:    "limitRange1",
-				ResourceQuota: "resourceQuota1",
+				Timeout:          metav1Duration(time.Minute * 4444),
+				MaxTimeout:       metav1Duration(time.Minute * 5555),
+				AbortGracePeriod: metav1Duration(time.Second * 90),
+				LimitRange:       "limitRange1",
+				ResourceQuota:    "resourceQuota1",
 
 				JenkinsfileRunnerImage:                        "jfrImage1",
 				JenkinsfileRunnerImagePullPolicy:              "jfrImagePullPolicy1",
 				JenkinsfileRunnerPodSecurityContextRunAsUser:  int64Ptr(1111),
 				JenkinsfileRunnerPodSecurityContextRunAsGroup: int64Ptr(2222),
 				JenkinsfileRunnerPodSecurityContextFSGroup:    int64Ptr(3333),
+				JenkinsfileRunnerJavaOpts:                     "javaOpts1",
+				JenkinsfileRunnerResources: &corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceCPU: resource.MustParse("3"),
+					},
+					Requests: corev1.ResourceList{
+						corev1.ResourceMemory: resource.MustParse("1Gi"),
+					},
+				},
+				JenkinsfileRunnerResourcesMin: corev1.ResourceList{
+					corev1.ResourceMemory: resource.MustParse("512Mi"),
+				},
+				JenkinsfileRunnerResourcesMax: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("8"),
+					corev1.ResourceMemory: resource.MustParse("16Gi"),
+				},
+				JenkinsfileRunnerPipelineCloneRetryIntervalSec: "10",
+				JenkinsfileRunnerPipelineCloneRetryTimeoutSec:  "60",
+
+				ElasticsearchIndexURL: "http://es.example.com/index1/_doc",
+				RunBackend:            RunBackendPod,
+
+				MaxActivePipelineRuns:             int64Ptr(100),
+				MaxActivePipelineRunsPerTenant:    int64Ptr(5),
+				TTLSecondsAfterFinished:           int64Ptr(86400),
+				KeepFinishedPipelineRunsPerTenant: int64Ptr(50),
+
+				SidecarAllowedImages: []string{"postgres:14", "docker.io/selenium/*"},
+
+				RunNamespaceLabels:      map[string]string{"cost-center": "cc1"},
+				RunNamespaceAnnotations: map[string]string{"example.com/run": "{{ .PipelineRunName }}"},
 			},
 		},
 		{
 			"all_empty",
 			map[string]string{
 				mainConfigKeyTimeout:       "",
+				mainConfigKeyMaxTimeout:    "",
+				mainConfigKeyAbortGrace:    "",
 				mainConfigKeyLimitRange:    "",
 				mainConfigKeyResourceQuota: "",
 
@@ -348,6 +459,21 @@ func Test_processMainConfig(t *testing.T) {
 				mainConfigKeyPSCRunAsUser:    "",
 				mainConfigKeyPSCRunAsGroup:   "",
 				mainConfigKeyPSCFSGroup:      "",
+				mainConfigKeyJavaOpts:        "",
+				mainConfigKeyResources:       "",
+				mainConfigKeyResourcesMin:    "",
+				mainConfigKeyResourcesMax:    "",
+				mainConfigKeyCloneRetryIntvl: "",
+				mainConfigKeyCloneRetryTmout: "",
+				mainConfigKeyESIndexURL:      "",
+				mainConfigKeyRunBackend:      "",
+				mainConfigKeyMaxActive:       "",
+				mainConfigKeyMaxActiveTenant: "",
+				mainConfigKeyTTLFinished:     "",
+				mainConfigKeyKeepFinished:    "",
+				mainConfigKeySidecarImages:   "",
+				mainConfigKeyRunNSLabels:     "",
+				mainConfigKeyRunNSAnnots:     "",
 			},
 			&PipelineRunsConfigStruct{},
 		},
@@ -370,6 +496,40 @@ func Test_processMainConfig(t *testing.T) {
 	}
 }
 
+func Test_processMainConfig_ResourceBoundsMinExceedsMax(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	configData := map[string]string{
+		mainConfigKeyResourcesMin: "memory: 2Gi\n",
+		mainConfigKeyResourcesMax: "memory: 1Gi\n",
+	}
+	dest := &PipelineRunsConfigStruct{}
+
+	// EXERCISE
+	resultErr := processMainConfig(configData, dest)
+
+	// VERIFY
+	assert.Error(t, resultErr, `key "jenkinsfileRunner.resourceBounds.min": minimum 2Gi of resource "memory" exceeds maximum 1Gi from key "jenkinsfileRunner.resourceBounds.max"`)
+}
+
+func Test_processMainConfig_TimeoutExceedsMaxTimeout(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	configData := map[string]string{
+		mainConfigKeyTimeout:    "2h",
+		mainConfigKeyMaxTimeout: "1h",
+	}
+	dest := &PipelineRunsConfigStruct{}
+
+	// EXERCISE
+	resultErr := processMainConfig(configData, dest)
+
+	// VERIFY
+	assert.Error(t, resultErr, `key "timeout": value 2h0m0s exceeds 1h0m0s from key "maxTimeout"`)
+}
+
 func Test_processNetworkPoliciesConfig(t *testing.T) {
 	t.Parallel()
 
@@ -522,6 +682,197 @@ func Test_processNetworkPoliciesConfig(t *testing.T) {
 	}
 }
 
+func Test_processSchedulingProfilesConfig(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		configData    map[string]string
+		expected      *PipelineRunsConfigStruct
+		expectedError string
+	}{
+		{
+			"empty",
+			map[string]string{},
+			&PipelineRunsConfigStruct{},
+			"",
+		},
+		{
+			"without_default",
+			map[string]string{
+				"_example": "foo",
+				"release":  "nodeSelector:\n  pool: release\ntolerations:\n- key: dedicated\n  operator: Equal\n  value: release\n  effect: NoSchedule\npriorityClassName: high\n",
+				"empty":    " \n",
+			},
+			&PipelineRunsConfigStruct{
+				SchedulingProfiles: map[string]*SchedulingProfile{
+					"release": {
+						NodeSelector: map[string]string{"pool": "release"},
+						Tolerations: []corev1.Toleration{
+							{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "release", Effect: corev1.TaintEffectNoSchedule},
+						},
+						PriorityClassName: "high",
+					},
+				},
+			},
+			"",
+		},
+		{
+			"with_default",
+			map[string]string{
+				"_default": "key1",
+				"key1":     "affinity:\n  nodeAffinity:\n    requiredDuringSchedulingIgnoredDuringExecution:\n      nodeSelectorTerms:\n      - matchExpressions:\n        - {key: pool, operator: In, values: [builds]}\n",
+			},
+			&PipelineRunsConfigStruct{
+				DefaultSchedulingProfile: "key1",
+				SchedulingProfiles: map[string]*SchedulingProfile{
+					"key1": {
+						Affinity: &corev1.Affinity{
+							NodeAffinity: &corev1.NodeAffinity{
+								RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+									NodeSelectorTerms: []corev1.NodeSelectorTerm{
+										{MatchExpressions: []corev1.NodeSelectorRequirement{
+											{Key: "pool", Operator: corev1.NodeSelectorOpIn, Values: []string{"builds"}},
+										}},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"",
+		},
+		{
+			"default_key_missing",
+			map[string]string{
+				"_default": "key1",
+				"key2":     "priorityClassName: low",
+			},
+			&PipelineRunsConfigStruct{},
+			`key "_default": value "key1" does not denote an existing scheduling profile key`,
+		},
+		{
+			"malformed_profile",
+			map[string]string{
+				"key1": "tolerations: foo",
+			},
+			&PipelineRunsConfigStruct{},
+			`key "key1": cannot parse scheduling profile: error unmarshaling JSON: json: cannot unmarshal string into Go struct field SchedulingProfile.tolerations of type []v1.Toleration`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			dest := &PipelineRunsConfigStruct{}
+
+			// EXERCISE
+			resultErr := processSchedulingProfilesConfig(tc.configData, dest)
+
+			// VERIFY
+			if tc.expectedError == "" {
+				assert.NilError(t, resultErr)
+				assert.DeepEqual(t, tc.expected, dest)
+			} else {
+				assert.Error(t, resultErr, tc.expectedError)
+			}
+		})
+	}
+}
+
+func Test_processResourceProfilesConfig(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		configData    map[string]string
+		expected      *PipelineRunsConfigStruct
+		expectedError string
+	}{
+		{
+			"empty",
+			map[string]string{},
+			&PipelineRunsConfigStruct{},
+			"",
+		},
+		{
+			"without_default",
+			map[string]string{
+				"_example": "foo",
+				"small":    "limitRange: |\n  kind: LimitRange\nresourceQuota: |\n  kind: ResourceQuota\njenkinsfileRunnerResources:\n  requests:\n    cpu: 500m\n  limits:\n    memory: 1Gi\n",
+				"empty":    " \n",
+			},
+			&PipelineRunsConfigStruct{
+				ResourceProfiles: map[string]*ResourceProfile{
+					"small": {
+						LimitRange:    "kind: LimitRange\n",
+						ResourceQuota: "kind: ResourceQuota\n",
+						JenkinsfileRunnerResources: &corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+							Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+						},
+					},
+				},
+			},
+			"",
+		},
+		{
+			"with_default",
+			map[string]string{
+				"_default": "key1",
+				"key1":     "resourceQuota: quota1",
+				"key2":     "limitRange: limitRange2",
+			},
+			&PipelineRunsConfigStruct{
+				DefaultResourceProfile: "key1",
+				ResourceProfiles: map[string]*ResourceProfile{
+					"key1": {ResourceQuota: "quota1"},
+					"key2": {LimitRange: "limitRange2"},
+				},
+			},
+			"",
+		},
+		{
+			"default_key_missing",
+			map[string]string{
+				"_default": "key1",
+				"key2":     "limitRange: limitRange2",
+			},
+			&PipelineRunsConfigStruct{},
+			`key "_default": value "key1" does not denote an existing resource profile key`,
+		},
+		{
+			"malformed_profile",
+			map[string]string{
+				"key1": "jenkinsfileRunnerResources: foo",
+			},
+			&PipelineRunsConfigStruct{},
+			`key "key1": cannot parse resource profile: error unmarshaling JSON: json: cannot unmarshal string into Go struct field ResourceProfile.jenkinsfileRunnerResources of type v1.ResourceRequirements`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			dest := &PipelineRunsConfigStruct{}
+
+			// EXERCISE
+			resultErr := processResourceProfilesConfig(tc.configData, dest)
+
+			// VERIFY
+			if tc.expectedError == "" {
+				assert.NilError(t, resultErr)
+				assert.DeepEqual(t, tc.expected, dest)
+			} else {
+				assert.Error(t, resultErr, tc.expectedError)
+			}
+		})
+	}
+}
+
 func newMainConfigMap(data map[string]string) *corev1.ConfigMap {
 	return &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
@@ -542,6 +893,26 @@ func newNetworkPolicyConfigMap(data map[string]string) *corev1.ConfigMap {
 	}
 }
 
+func newSchedulingProfilesConfigMap(data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      schedulingProfilesConfigMapName,
+			Namespace: system.Namespace(),
+		},
+		Data: data,
+	}
+}
+
+func newResourceProfilesConfigMap(data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      resourceProfilesConfigMapName,
+			Namespace: system.Namespace(),
+		},
+		Data: data,
+	}
+}
+
 func metav1Duration(d time.Duration) *metav1.Duration {
 	return &metav1.Duration{Duration: d}
 }
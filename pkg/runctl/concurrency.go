@@ -0,0 +1,253 @@
+package runctl
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	serrors "github.com/SAP/stewardci-core/pkg/errors"
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/cache"
+	klog "k8s.io/klog/v2"
+)
+
+// isActiveState returns whether pipeline runs in the given state count
+// against the limits of concurrently active pipeline runs.
+func isActiveState(state api.State) bool {
+	switch state {
+	case api.StatePreparing, api.StateWaiting, api.StateRunning:
+		return true
+	default:
+		return false
+	}
+}
+
+// isPendingState returns whether pipeline runs in the given state are
+// waiting to be started.
+func isPendingState(state api.State) bool {
+	switch state {
+	case api.StateUndefined, api.StateNew, api.StateQueued:
+		return true
+	default:
+		return false
+	}
+}
+
+// isQueuedBefore returns whether pipeline run `a` must be started before
+// pipeline run `b`. Pipeline runs are started in the order of their
+// creation. Pipeline runs created at the same time are ordered by
+// namespace and name to get a stable order.
+func isQueuedBefore(a, b *api.PipelineRun) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	if a.GetNamespace() != b.GetNamespace() {
+		return a.GetNamespace() < b.GetNamespace()
+	}
+	return a.GetName() < b.GetName()
+}
+
+// mayStartPipelineRun returns whether the given queued pipeline run can be
+// started without exceeding the limits of concurrently active pipeline
+// runs, both per tenant namespace and in the whole system.
+// Within a tenant namespace pipeline runs are started in the order they
+// have been queued: active pipeline runs and pending pipeline runs queued
+// before the given one occupy a slot each. The global limit counts active
+// pipeline runs only, so that pending pipeline runs blocked by the limit
+// of their own tenant do not block pipeline runs of other tenants.
+// `clientNamespace` is the client namespace the tenant belongs to or `nil`.
+// The check is based on the informer cache and therefore may exceed the
+// limits temporarily if the cache is not up to date.
+func (c *Controller) mayStartPipelineRun(pipelineRun k8s.PipelineRun, clientNamespace *corev1.Namespace, pipelineRunsConfig *cfg.PipelineRunsConfigStruct) (bool, error) {
+	if tenantLimit := getMaxActivePipelineRunsPerTenant(clientNamespace, pipelineRunsConfig); tenantLimit > 0 {
+		runs, err := c.pipelineRunLister.PipelineRuns(pipelineRun.GetNamespace()).List(labels.Everything())
+		if err != nil {
+			return false, err
+		}
+		if occupiedSlots(pipelineRun.GetAPIObject(), runs) >= tenantLimit {
+			klog.V(4).Infof("Keep [%s] queued: limit of %d active pipeline runs per tenant reached", pipelineRun.String(), tenantLimit)
+			return false, nil
+		}
+	}
+
+	if globalLimit := pipelineRunsConfig.MaxActivePipelineRuns; globalLimit != nil && *globalLimit > 0 {
+		runs, err := c.pipelineRunLister.List(labels.Everything())
+		if err != nil {
+			return false, err
+		}
+		if activeRuns(pipelineRun.GetAPIObject(), runs) >= *globalLimit {
+			klog.V(4).Infof("Keep [%s] queued: limit of %d active pipeline runs reached", pipelineRun.String(), *globalLimit)
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// occupiedSlots returns the number of pipeline runs in `runs` which are
+// either active or pending and queued before `pipelineRun`.
+func occupiedSlots(pipelineRun *api.PipelineRun, runs []*api.PipelineRun) int64 {
+	var count int64
+	for _, run := range runs {
+		if !isCompeting(pipelineRun, run) {
+			continue
+		}
+		state := run.Status.State
+		if isActiveState(state) || (isPendingState(state) && isQueuedBefore(run, pipelineRun)) {
+			count++
+		}
+	}
+	return count
+}
+
+// activeRuns returns the number of pipeline runs in `runs` which are
+// active, not counting `pipelineRun` itself.
+func activeRuns(pipelineRun *api.PipelineRun, runs []*api.PipelineRun) int64 {
+	var count int64
+	for _, run := range runs {
+		if isCompeting(pipelineRun, run) && isActiveState(run.Status.State) {
+			count++
+		}
+	}
+	return count
+}
+
+// isCompeting returns whether `run` is another pipeline run than
+// `pipelineRun` which may occupy a slot, i.e. is neither deleted nor
+// aborted.
+func isCompeting(pipelineRun, run *api.PipelineRun) bool {
+	if run.GetName() == pipelineRun.GetName() && run.GetNamespace() == pipelineRun.GetNamespace() {
+		return false
+	}
+	return run.DeletionTimestamp.IsZero() && run.Spec.Intent != api.IntentAbort
+}
+
+// getMaxActivePipelineRunsPerTenant returns the maximum number of active
+// pipeline runs in a tenant namespace. The value configured in the given
+// client namespace the tenant belongs to takes precedence over the value
+// from the pipeline runs configuration. The settings of the tenant may
+// lower the limit further. Zero means unlimited.
+func getMaxActivePipelineRunsPerTenant(clientNamespace *corev1.Namespace, pipelineRunsConfig *cfg.PipelineRunsConfigStruct) int64 {
+	limit := getMaxActivePipelineRunsPerClient(clientNamespace, pipelineRunsConfig)
+	if tenantLimit := pipelineRunsConfig.TenantMaxActivePipelineRuns; tenantLimit != nil && *tenantLimit > 0 {
+		if limit == 0 || *tenantLimit < limit {
+			limit = *tenantLimit
+		}
+	}
+	return limit
+}
+
+// getMaxActivePipelineRunsPerClient returns the maximum number of active
+// pipeline runs per tenant namespace resulting from the given client
+// namespace and the pipeline runs configuration. `clientNamespace` may be
+// `nil`. Zero means unlimited.
+func getMaxActivePipelineRunsPerClient(clientNamespace *corev1.Namespace, pipelineRunsConfig *cfg.PipelineRunsConfigStruct) int64 {
+	var limit int64
+	if pipelineRunsConfig.MaxActivePipelineRunsPerTenant != nil {
+		limit = *pipelineRunsConfig.MaxActivePipelineRunsPerTenant
+	}
+	if clientNamespace == nil {
+		return limit
+	}
+	value, ok := clientNamespace.GetAnnotations()[api.AnnotationMaxActivePipelineRunsPerTenant]
+	if !ok {
+		return limit
+	}
+	clientLimit, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || clientLimit < 0 {
+		klog.Warningf(
+			"ignoring invalid value %q of annotation %q on client namespace %q",
+			value, api.AnnotationMaxActivePipelineRunsPerTenant, clientNamespace.GetName(),
+		)
+		return limit
+	}
+	return clientLimit
+}
+
+// getOwnerNamespaces returns the given tenant namespace and the client
+// namespace it belongs to. Either of them is `nil` if it does not exist.
+// Both are fetched once per sync and passed to all checks depending on
+// them. Errors from the Kubernetes API are recoverable.
+func (c *Controller) getOwnerNamespaces(ctx context.Context, tenantNamespaceName string) (tenantNamespace, clientNamespace *corev1.Namespace, err error) {
+	namespaces := c.factory.CoreV1().Namespaces()
+	tenantNamespace, err = namespaces.Get(ctx, tenantNamespaceName, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil, nil
+		}
+		return nil, nil, serrors.Recoverable(errors.Wrapf(err, "failed to get tenant namespace %q", tenantNamespaceName))
+	}
+	clientNamespaceName := tenantNamespace.GetLabels()[api.LabelOwnerClientNamespace]
+	if clientNamespaceName == "" {
+		return tenantNamespace, nil, nil
+	}
+	clientNamespace, err = namespaces.Get(ctx, clientNamespaceName, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return tenantNamespace, nil, nil
+		}
+		return nil, nil, serrors.Recoverable(errors.Wrapf(err, "failed to get client namespace %q", clientNamespaceName))
+	}
+	return tenantNamespace, clientNamespace, nil
+}
+
+// handleReleasedCapacity puts all queued pipeline runs into the work queue
+// if the given update of a pipeline run ends its active phase, so that
+// the next queued pipeline run can be started without delay.
+func (c *Controller) handleReleasedCapacity(oldObj, newObj interface{}) {
+	oldRun, ok := oldObj.(*api.PipelineRun)
+	if !ok {
+		return
+	}
+	newRun, ok := newObj.(*api.PipelineRun)
+	if !ok {
+		return
+	}
+	if !isActiveState(oldRun.Status.State) || isActiveState(newRun.Status.State) {
+		return
+	}
+	c.enqueueQueuedPipelineRuns()
+}
+
+// handleDeletedPipelineRun puts all queued pipeline runs into the work queue
+// if the given deleted pipeline run occupied a slot, i.e. was active or
+// pending, so that the next queued pipeline run can be started without
+// delay.
+func (c *Controller) handleDeletedPipelineRun(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	run, ok := obj.(*api.PipelineRun)
+	if !ok {
+		utilruntime.HandleError(fmt.Errorf("error decoding deleted pipeline run, invalid type %T", obj))
+		return
+	}
+	state := run.Status.State
+	if !isActiveState(state) && !isPendingState(state) {
+		return
+	}
+	c.enqueueQueuedPipelineRuns()
+}
+
+// enqueueQueuedPipelineRuns puts all queued pipeline runs from the informer
+// cache into the work queue.
+func (c *Controller) enqueueQueuedPipelineRuns() {
+	runs, err := c.pipelineRunLister.List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	for _, run := range runs {
+		if run.Status.State == api.StateQueued {
+			c.addPipelineRun(run)
+		}
+	}
+}
@@ -0,0 +1,312 @@
+package runctl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	cfg "github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	runmocks "github.com/SAP/stewardci-core/pkg/runctl/run/mocks"
+	gomock "github.com/golang/mock/gomock"
+	assert "gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func newPipelineRunWithState(name, namespace string, state api.State, created time.Time) *api.PipelineRun {
+	run := fake.PipelineRun(name, namespace, api.PipelineSpec{})
+	run.CreationTimestamp = metav1.NewTime(created)
+	run.Status.State = state
+	run.Status.StateDetails.State = state
+	return run
+}
+
+func newRunsConfigWithLimits(global, perTenant int64) func(ctx context.Context) (*cfg.PipelineRunsConfigStruct, error) {
+	return func(ctx context.Context) (*cfg.PipelineRunsConfigStruct, error) {
+		return &cfg.PipelineRunsConfigStruct{
+			MaxActivePipelineRuns:          &global,
+			MaxActivePipelineRunsPerTenant: &perTenant,
+		}, nil
+	}
+}
+
+func Test_occupiedSlots(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	now := time.Now()
+	earlier := now.Add(-time.Minute)
+	later := now.Add(time.Minute)
+
+	examinee := newPipelineRunWithState("run1", "ns1", api.StateQueued, now)
+	deleted := newPipelineRunWithState("deleted", "ns1", api.StateRunning, earlier)
+	deleted.DeletionTimestamp = &metav1.Time{Time: now}
+	aborted := newPipelineRunWithState("aborted", "ns1", api.StateWaiting, earlier)
+	aborted.Spec.Intent = api.IntentAbort
+
+	runs := []*api.PipelineRun{
+		examinee,
+		newPipelineRunWithState("preparing", "ns1", api.StatePreparing, later),
+		newPipelineRunWithState("waiting", "ns1", api.StateWaiting, later),
+		newPipelineRunWithState("running", "ns1", api.StateRunning, later),
+		newPipelineRunWithState("cleaning", "ns1", api.StateCleaning, earlier),
+		newPipelineRunWithState("finished", "ns1", api.StateFinished, earlier),
+		newPipelineRunWithState("queuedBefore", "ns1", api.StateQueued, earlier),
+		newPipelineRunWithState("newBefore", "ns1", api.StateNew, earlier),
+		newPipelineRunWithState("queuedAfter", "ns1", api.StateQueued, later),
+		newPipelineRunWithState("run0", "ns1", api.StateQueued, now),
+		newPipelineRunWithState("run2", "ns1", api.StateQueued, now),
+		deleted,
+		aborted,
+	}
+
+	// EXERCISE
+	result := occupiedSlots(examinee, runs)
+
+	// VERIFY
+	// preparing, waiting, running, queuedBefore, newBefore, run0
+	assert.Equal(t, int64(6), result)
+}
+
+func Test_activeRuns(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	now := time.Now()
+	earlier := now.Add(-time.Minute)
+
+	examinee := newPipelineRunWithState("run1", "ns1", api.StateQueued, now)
+	deleted := newPipelineRunWithState("deleted", "ns1", api.StateRunning, earlier)
+	deleted.DeletionTimestamp = &metav1.Time{Time: now}
+	aborted := newPipelineRunWithState("aborted", "ns1", api.StateWaiting, earlier)
+	aborted.Spec.Intent = api.IntentAbort
+
+	runs := []*api.PipelineRun{
+		examinee,
+		newPipelineRunWithState("preparing", "ns1", api.StatePreparing, earlier),
+		newPipelineRunWithState("waiting", "ns2", api.StateWaiting, earlier),
+		newPipelineRunWithState("running", "ns3", api.StateRunning, earlier),
+		newPipelineRunWithState("cleaning", "ns1", api.StateCleaning, earlier),
+		newPipelineRunWithState("finished", "ns1", api.StateFinished, earlier),
+		newPipelineRunWithState("queuedBefore", "ns2", api.StateQueued, earlier),
+		newPipelineRunWithState("newBefore", "ns1", api.StateNew, earlier),
+		deleted,
+		aborted,
+	}
+
+	// EXERCISE
+	result := activeRuns(examinee, runs)
+
+	// VERIFY
+	// preparing, waiting, running
+	assert.Equal(t, int64(3), result)
+}
+
+func Test_Controller_getMaxActivePipelineRunsPerTenant(t *testing.T) {
+	t.Parallel()
+
+	int64Ptr := func(val int64) *int64 { return &val }
+
+	for _, tc := range []struct {
+		name            string
+		annotations     map[string]string
+		configuredLimit *int64
+		tenantLimit     *int64
+		expectedLimit   int64
+	}{
+		{"unlimited", nil, nil, nil, 0},
+		{"configured", nil, int64Ptr(3), nil, 3},
+		{"annotation_overrides_config", map[string]string{api.AnnotationMaxActivePipelineRunsPerTenant: "5"}, int64Ptr(3), nil, 5},
+		{"annotation_sets_unlimited", map[string]string{api.AnnotationMaxActivePipelineRunsPerTenant: "0"}, int64Ptr(3), nil, 0},
+		{"annotation_invalid", map[string]string{api.AnnotationMaxActivePipelineRunsPerTenant: "foo"}, int64Ptr(3), nil, 3},
+		{"annotation_negative", map[string]string{api.AnnotationMaxActivePipelineRunsPerTenant: "-1"}, int64Ptr(3), nil, 3},
+		{"tenant_lowers_config", nil, int64Ptr(3), int64Ptr(2), 2},
+		{"tenant_cannot_raise_config", nil, int64Ptr(3), int64Ptr(4), 3},
+		{"tenant_limits_unlimited", map[string]string{api.AnnotationMaxActivePipelineRunsPerTenant: "0"}, int64Ptr(3), int64Ptr(4), 4},
+		{"tenant_zero_ignored", nil, int64Ptr(3), int64Ptr(0), 3},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			clientNamespace := fake.NamespaceWithAnnotations("client1", tc.annotations)
+			config := &cfg.PipelineRunsConfigStruct{
+				MaxActivePipelineRunsPerTenant: tc.configuredLimit,
+				TenantMaxActivePipelineRuns:    tc.tenantLimit,
+			}
+
+			// EXERCISE
+			result := getMaxActivePipelineRunsPerTenant(clientNamespace, config)
+
+			// VERIFY
+			assert.Equal(t, tc.expectedLimit, result)
+		})
+	}
+}
+
+func Test_Controller_syncHandler_queued(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name                string
+		globalLimit         int64
+		tenantLimit         int64
+		otherRunNamespace   string
+		expectStart         bool
+		expectedState       api.State
+		expectedHistoryLast api.State
+	}{
+		{"unlimited", 0, 0, "ns1", true, api.StateWaiting, api.StatePreparing},
+		{"below_tenant_limit", 0, 2, "ns1", true, api.StateWaiting, api.StatePreparing},
+		{"tenant_limit_reached", 0, 1, "ns1", false, api.StateQueued, api.StateNew},
+		{"tenant_limit_other_tenant", 0, 1, "ns2", true, api.StateWaiting, api.StatePreparing},
+		{"global_limit_reached", 1, 0, "ns2", false, api.StateQueued, api.StateNew},
+		{"below_global_limit", 2, 1, "ns2", true, api.StateWaiting, api.StatePreparing},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			now := time.Now()
+			run := newPipelineRunWithState("foo", "ns1", api.StateNew, now)
+			otherRun := newPipelineRunWithState("other", tc.otherRunNamespace, api.StateRunning, now.Add(time.Minute))
+			examinee, cf := newController(run)
+			examinee.pipelineRunStore.Add(run)
+			examinee.pipelineRunStore.Add(otherRun)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			runManager := runmocks.NewMockManager(mockCtrl)
+			if tc.expectStart {
+				runManager.EXPECT().Start(gomock.Any(), gomock.Any(), gomock.Any()).Return("", "", nil)
+			}
+			examinee.testing = &controllerTesting{
+				createRunManagerStub:       runManager,
+				loadPipelineRunsConfigStub: newRunsConfigWithLimits(tc.globalLimit, tc.tenantLimit),
+				isMaintenanceModeStub:      newIsMaintenanceModeStub(false, nil),
+			}
+
+			// EXERCISE
+			resultErr := examinee.syncHandler("ns1/foo")
+
+			// VERIFY
+			assert.NilError(t, resultErr)
+			result, err := getAPIPipelineRun(cf, "foo", "ns1")
+			assert.NilError(t, err)
+			assert.Equal(t, tc.expectedState, result.Status.State)
+			history := result.Status.StateHistory
+			assert.Equal(t, tc.expectedHistoryLast, history[len(history)-1].State)
+		})
+	}
+}
+
+func Test_Controller_syncHandler_queued_OtherTenantBacklog(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	// Tenant ns2 has reached its limit of one active pipeline run and has
+	// a backlog queued before the pipeline run of tenant ns1. The backlog
+	// must not occupy slots of the global limit.
+	now := time.Now()
+	run := newPipelineRunWithState("foo", "ns1", api.StateNew, now)
+	examinee, cf := newController(run)
+	examinee.pipelineRunStore.Add(run)
+	examinee.pipelineRunStore.Add(newPipelineRunWithState("running", "ns2", api.StateRunning, now.Add(-3*time.Minute)))
+	examinee.pipelineRunStore.Add(newPipelineRunWithState("backlog1", "ns2", api.StateQueued, now.Add(-2*time.Minute)))
+	examinee.pipelineRunStore.Add(newPipelineRunWithState("backlog2", "ns2", api.StateQueued, now.Add(-time.Minute)))
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	runManager := runmocks.NewMockManager(mockCtrl)
+	runManager.EXPECT().Start(gomock.Any(), gomock.Any(), gomock.Any()).Return("", "", nil)
+	examinee.testing = &controllerTesting{
+		createRunManagerStub:       runManager,
+		loadPipelineRunsConfigStub: newRunsConfigWithLimits(2, 1),
+		isMaintenanceModeStub:      newIsMaintenanceModeStub(false, nil),
+	}
+
+	// EXERCISE
+	resultErr := examinee.syncHandler("ns1/foo")
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	result, err := getAPIPipelineRun(cf, "foo", "ns1")
+	assert.NilError(t, err)
+	assert.Equal(t, api.StateWaiting, result.Status.State)
+}
+
+func Test_Controller_handleReleasedCapacity(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name             string
+		oldState         api.State
+		newState         api.State
+		expectedEnqueued int
+	}{
+		{"running_to_cleaning", api.StateRunning, api.StateCleaning, 1},
+		{"preparing_to_finished", api.StatePreparing, api.StateFinished, 1},
+		{"waiting_to_running", api.StateWaiting, api.StateRunning, 0},
+		{"queued_to_preparing", api.StateQueued, api.StatePreparing, 0},
+		{"cleaning_to_finished", api.StateCleaning, api.StateFinished, 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			now := time.Now()
+			examinee, _ := newController()
+			examinee.pipelineRunStore.Add(newPipelineRunWithState("queued", "ns2", api.StateQueued, now))
+			examinee.pipelineRunStore.Add(newPipelineRunWithState("new", "ns1", api.StateNew, now))
+			oldRun := newPipelineRunWithState("foo", "ns1", tc.oldState, now)
+			newRun := oldRun.DeepCopy()
+			newRun.Status.State = tc.newState
+
+			// EXERCISE
+			examinee.handleReleasedCapacity(oldRun, newRun)
+
+			// VERIFY
+			assert.Equal(t, tc.expectedEnqueued, examinee.workqueue.Len())
+		})
+	}
+}
+
+func Test_Controller_handleDeletedPipelineRun(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	for _, tc := range []struct {
+		name             string
+		obj              interface{}
+		expectedEnqueued int
+	}{
+		{"running", newPipelineRunWithState("foo", "ns1", api.StateRunning, now), 1},
+		{"queued", newPipelineRunWithState("foo", "ns1", api.StateQueued, now), 1},
+		{"cleaning", newPipelineRunWithState("foo", "ns1", api.StateCleaning, now), 0},
+		{"finished", newPipelineRunWithState("foo", "ns1", api.StateFinished, now), 0},
+		{"tombstone_running", cache.DeletedFinalStateUnknown{
+			Key: "ns1/foo",
+			Obj: newPipelineRunWithState("foo", "ns1", api.StateRunning, now),
+		}, 1},
+		{"invalid_type", "foo", 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			examinee, _ := newController()
+			examinee.pipelineRunStore.Add(newPipelineRunWithState("queued", "ns2", api.StateQueued, now))
+			examinee.pipelineRunStore.Add(newPipelineRunWithState("new", "ns1", api.StateNew, now))
+
+			// EXERCISE
+			examinee.handleDeletedPipelineRun(tc.obj)
+
+			// VERIFY
+			assert.Equal(t, tc.expectedEnqueued, examinee.workqueue.Len())
+		})
+	}
+}
@@ -15,6 +15,7 @@ import (
 	serrors "github.com/SAP/stewardci-core/pkg/errors"
 	"github.com/SAP/stewardci-core/pkg/k8s"
 	"github.com/SAP/stewardci-core/pkg/k8s/secrets"
+	cachedsecretprovider "github.com/SAP/stewardci-core/pkg/k8s/secrets/providers/cached"
 	"github.com/SAP/stewardci-core/pkg/maintenancemode"
 	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
 	"github.com/SAP/stewardci-core/pkg/runctl/metrics"
@@ -51,6 +52,8 @@ type Controller struct {
 	pipelineRunFetcher   k8s.PipelineRunFetcher
 	pipelineRunSynced    cache.InformerSynced
 	tektonTaskRunsSynced cache.InformerSynced
+	podsSynced           cache.InformerSynced
+	tektonDisabled       bool
 	workqueue            workqueue.RateLimitingInterface
 	testing              *controllerTesting
 	recorder             record.EventRecorder
@@ -59,6 +62,8 @@ type Controller struct {
 
 	heartbeatInterval time.Duration
 	heartbeatLogLevel *klog.Level
+
+	secretCache *cachedsecretprovider.SecretCache
 }
 
 type controllerTesting struct {
@@ -80,6 +85,16 @@ type ControllerOpts struct {
 	// If nil, heartbeat logging is disabled and heartbeats are only
 	// exposed via metric.
 	HeartbeatLogLevel *klog.Level
+
+	// TektonDisabled indicates that Tekton is not available in the
+	// cluster. Tekton resources are not watched then and pipeline runs
+	// configured to use the Tekton run backend fail.
+	TektonDisabled bool
+
+	// SecretCache is an optional cache for the secrets in tenant
+	// namespaces. If nil, secrets are read from the Kubernetes API server
+	// for each pipeline run.
+	SecretCache *cachedsecretprovider.SecretCache
 }
 
 // NewController creates new Controller
@@ -87,7 +102,7 @@ func NewController(factory k8s.ClientFactory, opts ControllerOpts) *Controller {
 	pipelineRunInformer := factory.StewardInformerFactory().Steward().V1alpha1().PipelineRuns()
 	pipelineRunLister := pipelineRunInformer.Lister()
 	pipelineRunFetcher := k8s.NewListerBasedPipelineRunFetcher(pipelineRunInformer.Lister())
-	tektonTaskRunInformer := factory.TektonInformerFactory().Tekton().V1beta1().TaskRuns()
+	podInformer := factory.KubernetesInformerFactory().Core().V1().Pods()
 	eventBroadcaster := record.NewBroadcaster()
 	eventBroadcaster.StartLogging(klog.V(3).Infof)
 	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: factory.CoreV1().Events("")})
@@ -99,10 +114,12 @@ func NewController(factory k8s.ClientFactory, opts ControllerOpts) *Controller {
 		pipelineRunLister:  pipelineRunLister,
 		pipelineRunSynced:  pipelineRunInformer.Informer().HasSynced,
 
-		tektonTaskRunsSynced: tektonTaskRunInformer.Informer().HasSynced,
-		workqueue:            workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), metrics.WorkqueueName),
-		recorder:             recorder,
-		pipelineRunStore:     pipelineRunInformer.Informer().GetStore(),
+		podsSynced:       podInformer.Informer().HasSynced,
+		tektonDisabled:   opts.TektonDisabled,
+		secretCache:      opts.SecretCache,
+		workqueue:        workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), metrics.WorkqueueName),
+		recorder:         recorder,
+		pipelineRunStore: pipelineRunInformer.Informer().GetStore(),
 	}
 
 	controller.heartbeatInterval = opts.HeartbeatInterval
@@ -115,14 +132,26 @@ func NewController(factory k8s.ClientFactory, opts ControllerOpts) *Controller {
 		AddFunc: controller.addPipelineRun,
 		UpdateFunc: func(old, new interface{}) {
 			controller.addPipelineRun(new)
+			controller.handleReleasedCapacity(old, new)
 		},
+		DeleteFunc: controller.handleDeletedPipelineRun,
 	})
-	tektonTaskRunInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc: controller.handleTektonTaskRun,
+	podInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: controller.handleRunObject,
 		UpdateFunc: func(old, new interface{}) {
-			controller.handleTektonTaskRun(new)
+			controller.handleRunObject(new)
 		},
 	})
+	if !opts.TektonDisabled {
+		tektonTaskRunInformer := factory.TektonInformerFactory().Tekton().V1beta1().TaskRuns()
+		controller.tektonTaskRunsSynced = tektonTaskRunInformer.Informer().HasSynced
+		tektonTaskRunInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc: controller.handleRunObject,
+			UpdateFunc: func(old, new interface{}) {
+				controller.handleRunObject(new)
+			},
+		})
+	}
 
 	return controller
 }
@@ -131,6 +160,7 @@ func NewController(factory k8s.ClientFactory, opts ControllerOpts) *Controller {
 func (c *Controller) meterAllPipelineRunsPeriodic() {
 	klog.V(4).Infof("metering all pipeline runs")
 	objs := c.pipelineRunStore.List()
+	queued := 0
 	for _, obj := range objs {
 		pipelineRun := obj.(*api.PipelineRun)
 
@@ -138,7 +168,11 @@ func (c *Controller) meterAllPipelineRunsPeriodic() {
 		if pipelineRun.DeletionTimestamp.IsZero() {
 			metrics.PipelineRunsPeriodic.Observe(pipelineRun)
 		}
+		if pipelineRun.Status.State == api.StateQueued {
+			queued++
+		}
 	}
+	metrics.PipelineRunsQueued.Set(float64(queued))
 }
 
 // Run runs the controller
@@ -147,13 +181,20 @@ func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
 	defer c.workqueue.ShutDown()
 
 	klog.V(2).Infof("Sync cache")
-	if ok := cache.WaitForCacheSync(stopCh, c.pipelineRunSynced, c.tektonTaskRunsSynced); !ok {
+	cacheSyncs := []cache.InformerSynced{c.pipelineRunSynced, c.podsSynced}
+	if c.tektonTaskRunsSynced != nil {
+		cacheSyncs = append(cacheSyncs, c.tektonTaskRunsSynced)
+	}
+	if ok := cache.WaitForCacheSync(stopCh, cacheSyncs...); !ok {
 		return fmt.Errorf("failed to wait for caches to sync")
 	}
 
 	klog.V(2).Infof("Starting metering of pipeline runs with interval %v", meteringInterval)
 	go wait.Until(c.meterAllPipelineRunsPeriodic, meteringInterval, stopCh)
 
+	klog.V(2).Infof("Starting garbage collection of finished pipeline runs with interval %v", gcInterval)
+	go wait.Until(c.collectGarbage, gcInterval, stopCh)
+
 	if c.heartbeatInterval > 0 {
 		klog.V(2).Infof("Starting controller heartbeat stimulator with interval %s", c.heartbeatInterval)
 		go wait.Until(c.heartbeatStimulus, c.heartbeatInterval, stopCh)
@@ -257,16 +298,20 @@ func (c *Controller) createRunManager(pipelineRun k8s.PipelineRun) run.Manager {
 	if c.testing != nil && c.testing.createRunManagerStub != nil {
 		return c.testing.createRunManagerStub
 	}
-	tenant := k8s.NewTenantNamespace(c.factory, pipelineRun.GetNamespace())
+	tenant := k8s.NewTenantNamespace(c.factory, pipelineRun.GetNamespace(), c.secretCache)
 	workFactory := tenant.TargetClientFactory()
-	return c.newRunManager(workFactory, tenant.GetSecretProvider())
+	return c.newRunManager(workFactory, tenant.GetSecretProvider(), pipelineRun.GetStatus().RunBackend)
 }
 
-func (c *Controller) newRunManager(workFactory k8s.ClientFactory, secretProvider secrets.SecretProvider) run.Manager {
+// newRunManager returns the run manager implementing the given run backend.
+func (c *Controller) newRunManager(workFactory k8s.ClientFactory, secretProvider secrets.SecretProvider, runBackend string) run.Manager {
 	if c.testing != nil && c.testing.newRunManagerStub != nil {
 		return c.testing.newRunManagerStub(workFactory, secretProvider)
 
 	}
+	if runBackend == cfg.RunBackendPod {
+		return newPodRunManager(workFactory, secretProvider)
+	}
 	return newRunManager(workFactory, secretProvider)
 }
 
@@ -277,6 +322,25 @@ func (c *Controller) loadPipelineRunsConfig(ctx context.Context) (*cfg.PipelineR
 	return cfg.LoadPipelineRunsConfig(ctx, c.factory)
 }
 
+// pipelineRunsConfigOnce loads the pipeline runs configuration on first
+// use and returns the same result on all further calls. An instance is
+// used for a single sync only, so that all steps of the sync see the same
+// configuration.
+type pipelineRunsConfigOnce struct {
+	load   func(ctx context.Context) (*cfg.PipelineRunsConfigStruct, error)
+	loaded bool
+	config *cfg.PipelineRunsConfigStruct
+	err    error
+}
+
+func (o *pipelineRunsConfigOnce) get(ctx context.Context) (*cfg.PipelineRunsConfigStruct, error) {
+	if !o.loaded {
+		o.config, o.err = o.load(ctx)
+		o.loaded = true
+	}
+	return o.config, o.err
+}
+
 func (c *Controller) isMaintenanceMode(ctx context.Context) (bool, error) {
 	if c.testing != nil && c.testing.isMaintenanceModeStub != nil {
 		return c.testing.isMaintenanceModeStub(ctx)
@@ -335,16 +399,19 @@ func (c *Controller) syncHandler(key string) error {
 		runManager := c.createRunManager(pipelineRun)
 		err = runManager.Cleanup(ctx, pipelineRun)
 		if err != nil {
-			c.recorder.Event(pipelineRunAPIObj, corev1.EventTypeWarning, api.EventReasonCleaningFailed, err.Error())
-			return err
+			return c.onCleanupError(ctx, pipelineRunAPIObj, pipelineRun, err)
 		}
 		return c.updateStateAndResult(ctx, pipelineRun, api.StateFinished, api.ResultDeleted, metav1.Now())
 	}
 	// ... if not, try to add finalizer if missing
 	pipelineRun.AddFinalizer(ctx)
 
+	// the configuration should be loaded once per sync to avoid inconsistencies
+	// in case of concurrent configuration changes
+	pipelineRunsConfigOnce := &pipelineRunsConfigOnce{load: c.loadPipelineRunsConfig}
+
 	// Check if pipeline run is aborted
-	if err := c.handleAborted(ctx, pipelineRun); err != nil {
+	if aborting, err := c.handleAborted(ctx, key, pipelineRun, pipelineRunsConfigOnce); aborting || err != nil {
 		return err
 	}
 
@@ -365,7 +432,7 @@ func (c *Controller) syncHandler(key string) error {
 		}
 	}
 
-	if pipelineRun.GetStatus().State == api.StateNew {
+	if state := pipelineRun.GetStatus().State; state == api.StateNew || state == api.StateQueued {
 		maintenanceMode, err := c.isMaintenanceMode(ctx)
 		if err != nil {
 			return err
@@ -376,6 +443,45 @@ func (c *Controller) syncHandler(key string) error {
 			// Return error that the pipeline stays in the queue and will be processed after switching back to normal mode.
 			return err
 		}
+	}
+
+	if pipelineRun.GetStatus().State == api.StateNew {
+		if err = c.changeAndCommitStateAndMeter(ctx, pipelineRun, api.StateQueued, metav1.Now()); err != nil {
+			return err
+		}
+	}
+
+	var pipelineRunsConfig *cfg.PipelineRunsConfigStruct
+	var tenantSettings *api.TenantPipelineRunsSettings
+	// the tenant and client namespace are fetched once per sync, too
+	var clientNamespace *corev1.Namespace
+	if state := pipelineRun.GetStatus().State; state == api.StateQueued || state == api.StatePreparing {
+		pipelineRunsConfig, err = pipelineRunsConfigOnce.get(ctx)
+		if err != nil {
+			return c.onGetRunError(ctx, pipelineRunAPIObj, pipelineRun, err, api.StateFinished, api.ResultErrorInfra, "failed to load configuration for pipeline runs")
+		}
+		var tenantNamespace *corev1.Namespace
+		tenantNamespace, clientNamespace, err = c.getOwnerNamespaces(ctx, pipelineRun.GetNamespace())
+		if err != nil {
+			return c.onGetRunError(ctx, pipelineRunAPIObj, pipelineRun, err, api.StateFinished, api.ResultErrorInfra, "failed to get tenant namespace")
+		}
+		tenantSettings, err = getTenantPipelineRunsSettings(ctx, c.factory, tenantNamespace)
+		if err != nil {
+			return c.onGetRunError(ctx, pipelineRunAPIObj, pipelineRun, err, api.StateFinished, api.ResultErrorInfra, "failed to load pipeline run settings of tenant")
+		}
+		pipelineRunsConfig = applyTenantPipelineRunsSettings(pipelineRunsConfig, tenantSettings)
+	}
+
+	if pipelineRun.GetStatus().State == api.StateQueued {
+		mayStart, err := c.mayStartPipelineRun(pipelineRun, clientNamespace, pipelineRunsConfig)
+		if err != nil {
+			return err
+		}
+		if !mayStart {
+			// The pipeline run gets synced again as soon as another
+			// pipeline run releases its slot.
+			return nil
+		}
 		if err = c.changeAndCommitStateAndMeter(ctx, pipelineRun, api.StatePreparing, metav1.Now()); err != nil {
 			return err
 		}
@@ -387,12 +493,34 @@ func (c *Controller) syncHandler(key string) error {
 	// Process pipeline run based on current state
 	switch state := pipelineRun.GetStatus().State; state {
 	case api.StatePreparing:
-		// the configuration should be loaded once per sync to avoid inconsistencies
-		// in case of concurrent configuration changes
-		pipelineRunsConfig, err := c.loadPipelineRunsConfig(ctx)
-		if err != nil {
-			return c.onGetRunError(ctx, pipelineRunAPIObj, pipelineRun, err, api.StateFinished, api.ResultErrorInfra, "failed to load configuration for pipeline runs")
+		if pipelineRunsConfig.RunBackend != cfg.RunBackendPod && c.tektonDisabled {
+			err = fmt.Errorf("the Tekton run backend is configured but Tekton is disabled")
+			return c.onGetRunError(ctx, pipelineRunAPIObj, pipelineRun, err, api.StateFinished, api.ResultErrorConfig, "failed to start pipeline run")
+		}
+		// a retried pipeline run waits for the backoff of its retry policy
+		if wait := retryBackoffRemaining(pipelineRun.GetSpec(), pipelineRun.GetStatus(), time.Now()); wait > 0 {
+			c.workqueue.AddAfter(key, wait)
+			return nil
+		}
+		if err = ValidatePipelineRun(pipelineRun.GetAPIObject(), pipelineRunsConfig); err != nil {
+			c.recorder.Event(pipelineRunAPIObj, corev1.EventTypeWarning, api.EventReasonPreparingFailed, err.Error())
+			pipelineRun.StoreErrorAsMessage(err, "preparing failed")
+			return c.updateStateAndResult(ctx, pipelineRun, api.StateFinished, api.ResultErrorConfig, metav1.Now())
 		}
+		if err = checkResourceProfileAllowed(pipelineRun.GetAPIObject(), clientNamespace, pipelineRunsConfig); err != nil {
+			c.recorder.Event(pipelineRunAPIObj, corev1.EventTypeWarning, api.EventReasonPreparingFailed, err.Error())
+			pipelineRun.StoreErrorAsMessage(err, "preparing failed")
+			return c.updateStateAndResult(ctx, pipelineRun, api.StateFinished, api.ResultErrorConfig, metav1.Now())
+		}
+		pipelineRun.UpdateTimeout(effectiveTimeout(pipelineRun.GetSpec(), pipelineRunsConfig))
+		pipelineRun.UpdateJenkinsfileRunnerResources(effectiveJenkinsfileRunnerResources(pipelineRun.GetSpec(), pipelineRunsConfig))
+		// the run backend is fixed for the whole lifetime of the pipeline run
+		pipelineRun.UpdateRunBackend(pipelineRunsConfig.RunBackend)
+		if tenantSettings != nil {
+			tenantLimit := getMaxActivePipelineRunsPerTenant(clientNamespace, pipelineRunsConfig)
+			pipelineRun.UpdateTenantSettings(effectiveTenantSettings(pipelineRunsConfig, tenantLimit))
+		}
+		runManager = c.createRunManager(pipelineRun)
 		namespace, auxNamespace, err := runManager.Start(ctx, pipelineRun, pipelineRunsConfig)
 		if err != nil {
 			c.recorder.Event(pipelineRunAPIObj, corev1.EventTypeWarning, api.EventReasonPreparingFailed, err.Error())
@@ -430,6 +558,7 @@ func (c *Controller) syncHandler(key string) error {
 		}
 		containerInfo := run.GetContainerInfo()
 		pipelineRun.UpdateContainer(containerInfo)
+		pipelineRun.UpdateSidecars(run.GetSidecarStates())
 		if finished, result := run.IsFinished(); finished {
 			pipelineRun.UpdateMessage(run.GetMessage())
 			return c.updateStateAndResult(ctx, pipelineRun, api.StateCleaning, result, *run.GetCompletionTime())
@@ -442,8 +571,30 @@ func (c *Controller) syncHandler(key string) error {
 
 	case api.StateCleaning:
 		err = runManager.Cleanup(ctx, pipelineRun)
+		retry := shouldRetry(pipelineRun.GetSpec(), pipelineRun.GetStatus())
 		if err != nil {
+			if retry {
+				// Do not retry before the sandbox has been removed.
+				// Otherwise the next attempt may start while the previous
+				// one is still running. The sync gets retried.
+				return c.onCleanupError(ctx, pipelineRunAPIObj, pipelineRun, err)
+			}
 			c.recorder.Event(pipelineRunAPIObj, corev1.EventTypeWarning, api.EventReasonCleaningFailed, err.Error())
+			if err := c.changeState(pipelineRun, api.StateFinished, metav1.Now()); err != nil {
+				return err
+			}
+			// keep the failed cleanup visible in the finished pipeline run
+			pipelineRun.UpdateCleanupFailed(err)
+			if err := c.commitStatusAndMeter(ctx, pipelineRun); err != nil {
+				return err
+			}
+			return pipelineRun.DeleteFinalizerIfExists(ctx)
+		}
+		if retry {
+			attempt := len(pipelineRun.GetStatus().Attempts) + 2
+			c.recorder.Eventf(pipelineRunAPIObj, corev1.EventTypeNormal, api.EventReasonRetrying, "starting attempt %d after result %q", attempt, pipelineRun.GetStatus().Result)
+			pipelineRun.FinishAttempt()
+			return c.changeAndCommitStateAndMeter(ctx, pipelineRun, api.StatePreparing, metav1.Now())
 		}
 		if err := c.changeAndCommitStateAndMeter(ctx, pipelineRun, api.StateFinished, metav1.Now()); err != nil {
 			return err
@@ -455,6 +606,18 @@ func (c *Controller) syncHandler(key string) error {
 	return nil
 }
 
+// onCleanupError records the failed cleanup of the given pipeline run in
+// its status and returns the error, so that the sync gets retried before
+// another attempt of the pipeline run is started.
+func (c *Controller) onCleanupError(ctx context.Context, pipelineRunAPIObj *api.PipelineRun, pipelineRun k8s.PipelineRun, err error) error {
+	c.recorder.Event(pipelineRunAPIObj, corev1.EventTypeWarning, api.EventReasonCleaningFailed, err.Error())
+	pipelineRun.UpdateCleanupFailed(err)
+	if commitErr := c.commitStatusAndMeter(ctx, pipelineRun); commitErr != nil {
+		klog.V(1).Infof("WARN: committing the failed cleanup of [%s] failed with: %s", pipelineRun.String(), commitErr.Error())
+	}
+	return err
+}
+
 func (c *Controller) onGetRunError(ctx context.Context, pipelineRunAPIObj *api.PipelineRun, pipelineRun k8s.PipelineRun, err error, state api.State, result api.Result, message string) error {
 	c.recorder.Event(pipelineRunAPIObj, corev1.EventTypeWarning, api.EventReasonRunningFailed, err.Error())
 	if serrors.IsRecoverable(err) {
@@ -501,15 +664,83 @@ func (c *Controller) commitStatusAndMeter(ctx context.Context, pipelineRun k8s.P
 }
 
 // handleAborted checks if pipeline run should be aborted.
-// If the user requested abortion it updates message, result and state
-// to trigger a cleanup.
-func (c *Controller) handleAborted(ctx context.Context, pipelineRun k8s.PipelineRun) error {
+// If the user requested abortion of a running pipeline run, the run
+// backend is asked to stop the Jenkinsfile Runner, which gets the abort
+// grace period to terminate, e.g. to execute post actions. Afterwards, or
+// immediately if the pipeline run is not running, it updates message,
+// result and state to trigger a cleanup.
+// The abort grace period is taken from the configuration loaded for the
+// current sync.
+// The returned bool is true as long as the pipeline run is waiting for
+// the Jenkinsfile Runner to terminate and must not be processed further.
+func (c *Controller) handleAborted(ctx context.Context, key string, pipelineRun k8s.PipelineRun, pipelineRunsConfigOnce *pipelineRunsConfigOnce) (bool, error) {
 	intent := pipelineRun.GetSpec().Intent
-	if intent == api.IntentAbort && pipelineRun.GetStatus().Result == api.ResultUndefined {
-		pipelineRun.UpdateMessage("Aborted")
-		return c.updateStateAndResult(ctx, pipelineRun, api.StateCleaning, api.ResultAborted, metav1.Now())
+	if intent != api.IntentAbort || pipelineRun.GetStatus().Result != api.ResultUndefined {
+		return false, nil
 	}
-	return nil
+	if pipelineRun.GetStatus().State != api.StateRunning {
+		return false, c.finishAbort(ctx, pipelineRun, "")
+	}
+
+	pipelineRunAPIObj := pipelineRun.GetAPIObject()
+	runManager := c.createRunManager(pipelineRun)
+	pipelineRunsConfig, err := pipelineRunsConfigOnce.get(ctx)
+	if err != nil {
+		klog.V(3).Infof("using the default abort grace period for %q as the configuration cannot be loaded: %s", pipelineRun.String(), err.Error())
+	}
+	gracePeriod := abortGracePeriod(pipelineRunsConfig)
+
+	abortRequestedAt := pipelineRun.GetStatus().AbortRequestedAt
+	if abortRequestedAt == nil {
+		if err := runManager.Cancel(ctx, pipelineRun); err != nil {
+			c.recorder.Event(pipelineRunAPIObj, corev1.EventTypeWarning, api.EventReasonAbortingFailed, err.Error())
+			if serrors.IsRecoverable(err) {
+				return true, err
+			}
+			return false, c.finishAbort(ctx, pipelineRun, "")
+		}
+		pipelineRun.UpdateAbortRequestedAt(metav1.Now())
+		if err := c.commitStatusAndMeter(ctx, pipelineRun); err != nil {
+			return true, err
+		}
+		c.workqueue.AddAfter(key, gracePeriod)
+		return true, nil
+	}
+
+	run, err := runManager.GetRun(ctx, pipelineRun)
+	if err != nil {
+		c.recorder.Event(pipelineRunAPIObj, corev1.EventTypeWarning, api.EventReasonAbortingFailed, err.Error())
+		if serrors.IsRecoverable(err) {
+			return true, err
+		}
+		return false, c.finishAbort(ctx, pipelineRun, "")
+	}
+	containerInfo := run.GetContainerInfo()
+	pipelineRun.UpdateContainer(containerInfo)
+	pipelineRun.UpdateSidecars(run.GetSidecarStates())
+	finished, _ := run.IsFinished()
+	if finished || (containerInfo != nil && containerInfo.Terminated != nil) {
+		return false, c.finishAbort(ctx, pipelineRun, run.GetMessage())
+	}
+	remaining := abortRequestedAt.Add(gracePeriod).Sub(time.Now())
+	if remaining <= 0 {
+		return false, c.finishAbort(ctx, pipelineRun, "")
+	}
+	// commit container update
+	if err := c.commitStatusAndMeter(ctx, pipelineRun); err != nil {
+		return true, err
+	}
+	c.workqueue.AddAfter(key, remaining)
+	return true, nil
+}
+
+// finishAbort updates message, result and state of an aborted pipeline run
+// to trigger a cleanup.
+func (c *Controller) finishAbort(ctx context.Context, pipelineRun k8s.PipelineRun, finalMessage string) error {
+	message := abortMessage(pipelineRun.GetAPIObject(), finalMessage)
+	c.recorder.Event(pipelineRun.GetAPIObject(), corev1.EventTypeNormal, api.EventReasonAborted, message)
+	pipelineRun.UpdateMessage(message)
+	return c.updateStateAndResult(ctx, pipelineRun, api.StateCleaning, api.ResultAborted, metav1.Now())
 }
 
 func (c *Controller) addPipelineRun(obj interface{}) {
@@ -523,11 +754,13 @@ func (c *Controller) addPipelineRun(obj interface{}) {
 	c.workqueue.Add(key)
 }
 
-// handleTektonTaskRun takes any resource implementing metav1.Object and attempts
+// handleRunObject takes any resource implementing metav1.Object and attempts
 // to find the PipelineRun resource that 'owns' it. It does this by looking for
 // a specific annotation. If such annotation exists, the named PipelineRun
 // is put into the controller's work queue to be processed.
-func (c *Controller) handleTektonTaskRun(obj interface{}) {
+// It is used for the objects created by the run backends, i.e. Tekton
+// TaskRuns and Jenkinsfile Runner pods.
+func (c *Controller) handleRunObject(obj interface{}) {
 	var object metav1.Object
 	var ok bool
 	if object, ok = obj.(metav1.Object); !ok {
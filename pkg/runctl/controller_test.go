@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
 	serrors "github.com/SAP/stewardci-core/pkg/errors"
@@ -25,6 +26,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	k8stesting "k8s.io/client-go/testing"
 	"k8s.io/client-go/tools/record"
 	klog "k8s.io/klog/v2"
 )
@@ -83,7 +85,9 @@ func Test_Controller_Success(t *testing.T) {
 
 	assert.Assert(t, !strings.Contains(status.Message, "ERROR"), status.Message)
 	assert.Equal(t, api.StateWaiting, status.State)
-	assert.Equal(t, 2, len(status.StateHistory))
+	assert.Equal(t, 3, len(status.StateHistory))
+	assert.Assert(t, status.GetCondition(api.PipelineRunConditionPrepared).IsTrue())
+	assert.Assert(t, status.GetCondition(api.PipelineRunConditionSucceeded).IsUnknown())
 }
 
 func Test_Controller_Running(t *testing.T) {
@@ -116,6 +120,36 @@ func Test_Controller_Running(t *testing.T) {
 	assert.Equal(t, api.StateRunning, status.State)
 }
 
+func Test_Controller_TaskRunRejected(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	cf := newFakeClientFactory(
+		fake.SecretOpaque("secret1", "ns1"),
+		fake.ClusterRole(string(runClusterRoleName)),
+	)
+	cf.TektonClientset().PrependReactor("create", "taskruns", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, k8serrors.NewInvalid(
+			tekton.SchemeGroupVersion.WithKind("TaskRun").GroupKind(),
+			tektonTaskRunName, nil,
+		)
+	})
+	pr := fake.PipelineRun("run1", "ns1", api.PipelineSpec{
+		Secrets: []string{"secret1"},
+	})
+
+	// EXERCISE
+	stopCh := startController(t, cf)
+	defer stopController(t, stopCh)
+	createRun(t, pr, cf)
+
+	// VERIFY
+	run := getPipelineRun(t, "run1", "ns1", cf)
+	status := run.GetStatus()
+	assert.Equal(t, api.ResultErrorConfig, status.Result)
+	assert.Assert(t, status.State == api.StateCleaning || status.State == api.StateFinished, status.State)
+}
+
 func Test_Controller_Deletion(t *testing.T) {
 	t.Parallel()
 
@@ -420,7 +454,7 @@ func Test_Controller_syncHandler_mock_start(t *testing.T) {
 				},
 				isMaintenanceModeStub: newIsMaintenanceModeStub(false, nil),
 				expectedResult:        api.ResultUndefined,
-				expectedState:         api.StatePreparing,
+				expectedState:         api.StateQueued,
 				expectedError:         errorRecover1,
 			},
 		} {
@@ -598,6 +632,7 @@ func Test_Controller_syncHandler_mock(t *testing.T) {
 					State: api.StateRunning,
 				},
 				runManagerExpectation: func(rm *runmocks.MockManager, run *runmocks.MockRun) {
+					run.EXPECT().GetSidecarStates().Return(nil)
 					run.EXPECT().GetContainerInfo().Return(nil)
 					run.EXPECT().IsFinished().Return(false, api.ResultUndefined)
 					rm.EXPECT().GetRun(gomock.Any(), gomock.Any()).Return(run, nil)
@@ -641,6 +676,7 @@ func Test_Controller_syncHandler_mock(t *testing.T) {
 					State: api.StateRunning,
 				},
 				runManagerExpectation: func(rm *runmocks.MockManager, run *runmocks.MockRun) {
+					run.EXPECT().GetSidecarStates().Return(nil)
 					run.EXPECT().GetContainerInfo().Return(
 						&corev1.ContainerState{
 							Running: &corev1.ContainerStateRunning{},
@@ -662,6 +698,7 @@ func Test_Controller_syncHandler_mock(t *testing.T) {
 					State: api.StateRunning,
 				},
 				runManagerExpectation: func(rm *runmocks.MockManager, run *runmocks.MockRun) {
+					run.EXPECT().GetSidecarStates().Return(nil)
 					run.EXPECT().GetContainerInfo().Return(
 						&corev1.ContainerState{
 							Terminated: &corev1.ContainerStateTerminated{
@@ -706,7 +743,7 @@ func Test_Controller_syncHandler_mock(t *testing.T) {
 				expectedState:              api.StateFinished,
 			},
 			{
-				name: "cleanup_abborted_running",
+				name: "cancel_abborted_running",
 				pipelineSpec: api.PipelineSpec{
 					Intent: api.IntentAbort,
 				},
@@ -714,11 +751,121 @@ func Test_Controller_syncHandler_mock(t *testing.T) {
 					State: api.StateRunning,
 				},
 				runManagerExpectation: func(rm *runmocks.MockManager, run *runmocks.MockRun) {
+					rm.EXPECT().Cancel(gomock.Any(), gomock.Any()).Return(nil)
+				},
+				loadPipelineRunsConfigStub: newEmptyRunsConfig,
+				expectedResult:             "",
+				expectedState:              api.StateRunning,
+			},
+			{
+				name: "cancel_abborted_running_recover",
+				pipelineSpec: api.PipelineSpec{
+					Intent: api.IntentAbort,
+				},
+				currentStatus: api.PipelineStatus{
+					State: api.StateRunning,
+				},
+				runManagerExpectation: func(rm *runmocks.MockManager, run *runmocks.MockRun) {
+					rm.EXPECT().Cancel(gomock.Any(), gomock.Any()).Return(errorRecover1)
+				},
+				loadPipelineRunsConfigStub: newEmptyRunsConfig,
+				expectedResult:             "",
+				expectedState:              api.StateRunning,
+				expectedError:              errorRecover1,
+			},
+			{
+				name: "cancel_abborted_running_error",
+				pipelineSpec: api.PipelineSpec{
+					Intent:      api.IntentAbort,
+					AbortReason: "reason1",
+				},
+				currentStatus: api.PipelineStatus{
+					State: api.StateRunning,
+				},
+				runManagerExpectation: func(rm *runmocks.MockManager, run *runmocks.MockRun) {
+					rm.EXPECT().Cancel(gomock.Any(), gomock.Any()).Return(error1)
 					rm.EXPECT().Cleanup(gomock.Any(), gomock.Any()).Return(nil)
 				},
 				loadPipelineRunsConfigStub: newEmptyRunsConfig,
 				expectedResult:             api.ResultAborted,
 				expectedState:              api.StateFinished,
+				expectedMessage:            "^Aborted by unknown user: reason1$",
+			},
+			{
+				name: "abborted_running_terminating",
+				pipelineSpec: api.PipelineSpec{
+					Intent: api.IntentAbort,
+				},
+				currentStatus: api.PipelineStatus{
+					State:            api.StateRunning,
+					AbortRequestedAt: metav1TimePtr(metav1.Now()),
+				},
+				runManagerExpectation: func(rm *runmocks.MockManager, run *runmocks.MockRun) {
+					run.EXPECT().GetSidecarStates().Return(nil)
+					run.EXPECT().GetContainerInfo().Return(
+						&corev1.ContainerState{
+							Running: &corev1.ContainerStateRunning{},
+						})
+					run.EXPECT().IsFinished().Return(false, api.ResultUndefined)
+					rm.EXPECT().GetRun(gomock.Any(), gomock.Any()).Return(run, nil)
+				},
+				loadPipelineRunsConfigStub: newEmptyRunsConfig,
+				expectedResult:             "",
+				expectedState:              api.StateRunning,
+			},
+			{
+				name: "cleanup_abborted_running_terminated",
+				pipelineSpec: api.PipelineSpec{
+					Intent:      api.IntentAbort,
+					AbortReason: "reason1",
+				},
+				currentStatus: api.PipelineStatus{
+					State:            api.StateRunning,
+					AbortRequestedAt: metav1TimePtr(metav1.Now()),
+				},
+				runManagerExpectation: func(rm *runmocks.MockManager, run *runmocks.MockRun) {
+					run.EXPECT().GetSidecarStates().Return(nil)
+					run.EXPECT().GetContainerInfo().Return(
+						&corev1.ContainerState{
+							Terminated: &corev1.ContainerStateTerminated{ExitCode: 143},
+						})
+					run.EXPECT().IsFinished().Return(false, api.ResultUndefined)
+					run.EXPECT().GetMessage().Return("post actions executed")
+					rm.EXPECT().GetRun(gomock.Any(), gomock.Any()).Return(run, nil)
+					rm.EXPECT().Cleanup(gomock.Any(), gomock.Any()).Return(nil)
+				},
+				loadPipelineRunsConfigStub: newEmptyRunsConfig,
+				expectedResult:             api.ResultAborted,
+				expectedState:              api.StateFinished,
+				expectedMessage:            "^Aborted by unknown user: reason1\npost actions executed$",
+			},
+			{
+				name: "cleanup_abborted_running_grace_period_expired",
+				pipelineSpec: api.PipelineSpec{
+					Intent: api.IntentAbort,
+				},
+				currentStatus: api.PipelineStatus{
+					State:            api.StateRunning,
+					AbortRequestedAt: metav1TimePtr(metav1.NewTime(time.Now().Add(-time.Minute))),
+				},
+				runManagerExpectation: func(rm *runmocks.MockManager, run *runmocks.MockRun) {
+					run.EXPECT().GetSidecarStates().Return(nil)
+					run.EXPECT().GetContainerInfo().Return(
+						&corev1.ContainerState{
+							Running: &corev1.ContainerStateRunning{},
+						})
+					run.EXPECT().IsFinished().Return(false, api.ResultUndefined)
+					rm.EXPECT().GetRun(gomock.Any(), gomock.Any()).Return(run, nil)
+					rm.EXPECT().Cleanup(gomock.Any(), gomock.Any()).Return(nil)
+				},
+				loadPipelineRunsConfigStub: func(ctx context.Context) (*cfg.PipelineRunsConfigStruct, error) {
+					return &cfg.PipelineRunsConfigStruct{
+						AbortGracePeriod: &metav1.Duration{Duration: 10 * time.Second},
+					}, nil
+				},
+				expectedResult:  api.ResultAborted,
+				expectedState:   api.StateFinished,
+				expectedMessage: "^Aborted by unknown user$",
 			},
 		} {
 			t.Run(fmt.Sprintf("%+s_maintenanceMode_%t", test.name, maintenanceMode), func(t *testing.T) {
@@ -770,6 +917,78 @@ func Test_Controller_syncHandler_mock(t *testing.T) {
 	}
 }
 
+func Test_Controller_syncHandler_abortedRunning_usesConfigOfSync(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	run := fake.PipelineRun("foo", "ns1", api.PipelineSpec{Intent: api.IntentAbort})
+	run.SetAnnotations(map[string]string{api.AnnotationAbortRequestedBy: "user1"})
+	run.Status = api.PipelineStatus{
+		State:            api.StateRunning,
+		AbortRequestedAt: metav1TimePtr(metav1.NewTime(time.Now().Add(-time.Minute))),
+	}
+	examinee, cf := newController(run)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	runManager := runmocks.NewMockManager(mockCtrl)
+	runmock := runmocks.NewMockRun(mockCtrl)
+	runmock.EXPECT().GetSidecarStates().Return(nil)
+	runmock.EXPECT().GetContainerInfo().Return(&corev1.ContainerState{Running: &corev1.ContainerStateRunning{}})
+	runmock.EXPECT().IsFinished().Return(false, api.ResultUndefined)
+	runManager.EXPECT().GetRun(gomock.Any(), gomock.Any()).Return(runmock, nil)
+	runManager.EXPECT().Cleanup(gomock.Any(), gomock.Any()).Return(nil)
+	configLoads := 0
+	examinee.testing = &controllerTesting{
+		createRunManagerStub: runManager,
+		loadPipelineRunsConfigStub: func(ctx context.Context) (*cfg.PipelineRunsConfigStruct, error) {
+			configLoads++
+			return &cfg.PipelineRunsConfigStruct{
+				AbortGracePeriod: &metav1.Duration{Duration: 10 * time.Second},
+			}, nil
+		},
+	}
+
+	// EXERCISE
+	resultErr := examinee.syncHandler("ns1/foo")
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assert.Equal(t, 1, configLoads)
+	result, err := getAPIPipelineRun(cf, "foo", "ns1")
+	assert.NilError(t, err)
+	assert.Equal(t, api.ResultAborted, result.Status.Result)
+	assert.Equal(t, "Aborted by user1", result.Status.Message)
+	recorder := examinee.recorder.(*record.FakeRecorder)
+	assert.Equal(t, "Normal Aborted Aborted by user1", <-recorder.Events)
+}
+
+func Test_pipelineRunsConfigOnce(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	loads := 0
+	config := &cfg.PipelineRunsConfigStruct{}
+	error1 := fmt.Errorf("error1")
+	examinee := &pipelineRunsConfigOnce{
+		load: func(ctx context.Context) (*cfg.PipelineRunsConfigStruct, error) {
+			loads++
+			return config, error1
+		},
+	}
+
+	// EXERCISE
+	result1, resultErr1 := examinee.get(ctx)
+	result2, resultErr2 := examinee.get(ctx)
+
+	// VERIFY
+	assert.Equal(t, 1, loads)
+	assert.Equal(t, config, result1)
+	assert.Equal(t, config, result2)
+	assert.Equal(t, error1, resultErr1)
+	assert.Equal(t, error1, resultErr2)
+}
+
 func Test_Controller_syncHandler_initiatesRetrying_on500DuringPipelineRunFetch(t *testing.T) {
 	t.Parallel()
 
@@ -876,6 +1095,367 @@ func Test_Controller_syncHandler_OnTimeout(t *testing.T) {
 	assert.Equal(t, "message from Succeeded condition", status.Message)
 }
 
+func Test_Controller_newRunManager_SelectsRunBackend(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee, cf := newController()
+
+	// EXERCISE & VERIFY
+	_, isTekton := examinee.newRunManager(cf, nil, "").(*runManager)
+	assert.Assert(t, isTekton)
+	_, isTekton = examinee.newRunManager(cf, nil, cfg.RunBackendTekton).(*runManager)
+	assert.Assert(t, isTekton)
+	_, isPod := examinee.newRunManager(cf, nil, cfg.RunBackendPod).(*podRunManager)
+	assert.Assert(t, isPod)
+}
+
+func Test_Controller_syncHandler_preparing_storesRunBackend(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	run := fake.PipelineRun("foo", "ns1", api.PipelineSpec{})
+	run.Status = api.PipelineStatus{State: api.StatePreparing}
+	examinee, cf := newController(run)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	runManager := runmocks.NewMockManager(mockCtrl)
+	runManager.EXPECT().Start(gomock.Any(), gomock.Any(), gomock.Any()).Return("", "", nil)
+	examinee.testing = &controllerTesting{
+		createRunManagerStub: runManager,
+		loadPipelineRunsConfigStub: func(ctx context.Context) (*cfg.PipelineRunsConfigStruct, error) {
+			return &cfg.PipelineRunsConfigStruct{RunBackend: cfg.RunBackendPod}, nil
+		},
+	}
+
+	// EXERCISE
+	resultErr := examinee.syncHandler("ns1/foo")
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	result, err := getAPIPipelineRun(cf, "foo", "ns1")
+	assert.NilError(t, err)
+	assert.Equal(t, api.StateWaiting, result.Status.State)
+	assert.Equal(t, cfg.RunBackendPod, result.Status.RunBackend)
+}
+
+func Test_Controller_syncHandler_preparing_storesEffectiveTimeout(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	run := fake.PipelineRun("foo", "ns1", api.PipelineSpec{
+		Timeout: metav1Duration(10 * time.Minute),
+	})
+	run.Status = api.PipelineStatus{State: api.StatePreparing}
+	examinee, cf := newController(run)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	runManager := runmocks.NewMockManager(mockCtrl)
+	runManager.EXPECT().Start(gomock.Any(), gomock.Any(), gomock.Any()).Return("", "", nil)
+	examinee.testing = &controllerTesting{
+		createRunManagerStub: runManager,
+		loadPipelineRunsConfigStub: func(ctx context.Context) (*cfg.PipelineRunsConfigStruct, error) {
+			return &cfg.PipelineRunsConfigStruct{
+				Timeout:    metav1Duration(time.Hour),
+				MaxTimeout: metav1Duration(2 * time.Hour),
+			}, nil
+		},
+	}
+
+	// EXERCISE
+	resultErr := examinee.syncHandler("ns1/foo")
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	result, err := getAPIPipelineRun(cf, "foo", "ns1")
+	assert.NilError(t, err)
+	assert.Equal(t, api.StateWaiting, result.Status.State)
+	assert.DeepEqual(t, metav1Duration(10*time.Minute), result.Status.Timeout)
+}
+
+func Test_Controller_syncHandler_preparing_failsIfTimeoutExceedsMaximum(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	run := fake.PipelineRun("foo", "ns1", api.PipelineSpec{
+		Timeout: metav1Duration(3 * time.Hour),
+	})
+	run.Status = api.PipelineStatus{State: api.StatePreparing}
+	examinee, cf := newController(run)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	examinee.testing = &controllerTesting{
+		createRunManagerStub: runmocks.NewMockManager(mockCtrl),
+		loadPipelineRunsConfigStub: func(ctx context.Context) (*cfg.PipelineRunsConfigStruct, error) {
+			return &cfg.PipelineRunsConfigStruct{
+				MaxTimeout: metav1Duration(2 * time.Hour),
+			}, nil
+		},
+	}
+
+	// EXERCISE
+	resultErr := examinee.syncHandler("ns1/foo")
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	result, err := getAPIPipelineRun(cf, "foo", "ns1")
+	assert.NilError(t, err)
+	assert.Equal(t, api.StateFinished, result.Status.State)
+	assert.Equal(t, api.ResultErrorConfig, result.Status.Result)
+	assert.Assert(t, is.Regexp("preparing failed .*exceeds the maximum timeout", result.Status.Message))
+}
+
+func Test_Controller_syncHandler_cleaning_retriesAccordingToRetryPolicy(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		result        api.Result
+		expectedState api.State
+		expectRetry   bool
+	}{
+		{"infra_error", api.ResultErrorInfra, api.StatePreparing, true},
+		{"content_error", api.ResultErrorContent, api.StateFinished, false},
+		{"success", api.ResultSuccess, api.StateFinished, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			run := fake.PipelineRun("foo", "ns1", api.PipelineSpec{
+				RetryPolicy: &api.RetryPolicy{MaxAttempts: 2},
+			})
+			run.Status = api.PipelineStatus{
+				State:        api.StateCleaning,
+				StateDetails: api.StateItem{State: api.StateCleaning},
+				Result:       tc.result,
+				Message:      "message1",
+				Namespace:    "runNamespace1",
+			}
+			examinee, cf := newController(run)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			runManager := runmocks.NewMockManager(mockCtrl)
+			runManager.EXPECT().Cleanup(gomock.Any(), gomock.Any()).Return(nil)
+			examinee.testing = &controllerTesting{
+				createRunManagerStub:       runManager,
+				loadPipelineRunsConfigStub: newEmptyRunsConfig,
+			}
+
+			// EXERCISE
+			resultErr := examinee.syncHandler("ns1/foo")
+
+			// VERIFY
+			assert.NilError(t, resultErr)
+			result, err := getAPIPipelineRun(cf, "foo", "ns1")
+			assert.NilError(t, err)
+			assert.Equal(t, tc.expectedState, result.Status.State)
+			if tc.expectRetry {
+				assert.Equal(t, api.ResultUndefined, result.Status.Result)
+				assert.Equal(t, "", result.Status.Namespace)
+				assert.Equal(t, 1, len(result.Status.Attempts))
+				attempt := result.Status.Attempts[0]
+				assert.Equal(t, tc.result, attempt.Result)
+				assert.Equal(t, "message1", attempt.Message)
+				assert.Equal(t, "runNamespace1", attempt.Namespace)
+				assert.Equal(t, 1, len(result.ObjectMeta.Finalizers))
+			} else {
+				assert.Equal(t, tc.result, result.Status.Result)
+				assert.Equal(t, 0, len(result.Status.Attempts))
+			}
+		})
+	}
+}
+
+func Test_Controller_syncHandler_cleaning_failingCleanupIsRetriedBeforeNextAttempt(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	run := fake.PipelineRun("foo", "ns1", api.PipelineSpec{
+		RetryPolicy: &api.RetryPolicy{MaxAttempts: 2},
+	})
+	run.Status = api.PipelineStatus{
+		State:        api.StateCleaning,
+		StateDetails: api.StateItem{State: api.StateCleaning},
+		Result:       api.ResultErrorInfra,
+		Namespace:    "runNamespace1",
+	}
+	examinee, cf := newController(run)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	runManager := runmocks.NewMockManager(mockCtrl)
+	cleanupErr := fmt.Errorf("cleanup error1")
+	runManager.EXPECT().Cleanup(gomock.Any(), gomock.Any()).Return(cleanupErr)
+	examinee.testing = &controllerTesting{
+		createRunManagerStub:       runManager,
+		loadPipelineRunsConfigStub: newEmptyRunsConfig,
+	}
+
+	// EXERCISE
+	resultErr := examinee.syncHandler("ns1/foo")
+
+	// VERIFY
+	assert.Equal(t, cleanupErr, resultErr)
+	result, err := getAPIPipelineRun(cf, "foo", "ns1")
+	assert.NilError(t, err)
+	assert.Equal(t, api.StateCleaning, result.Status.State)
+	assert.Equal(t, api.ResultErrorInfra, result.Status.Result)
+	assert.Equal(t, "runNamespace1", result.Status.Namespace)
+	assert.Equal(t, 0, len(result.Status.Attempts))
+}
+
+func Test_Controller_syncHandler_cleaning_failingCleanupFinishesWithoutRetry(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name        string
+		retryPolicy *api.RetryPolicy
+		result      api.Result
+	}{
+		{"no_retry_policy", nil, api.ResultErrorInfra},
+		{"result_not_retried", &api.RetryPolicy{MaxAttempts: 2}, api.ResultSuccess},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			run := fake.PipelineRun("foo", "ns1", api.PipelineSpec{
+				RetryPolicy: tc.retryPolicy,
+			})
+			run.SetFinalizers([]string{k8s.FinalizerName})
+			run.Status = api.PipelineStatus{
+				State:        api.StateCleaning,
+				StateDetails: api.StateItem{State: api.StateCleaning},
+				Result:       tc.result,
+				Namespace:    "runNamespace1",
+			}
+			examinee, cf := newController(run)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			runManager := runmocks.NewMockManager(mockCtrl)
+			cleanupErr := fmt.Errorf("cleanup error1")
+			runManager.EXPECT().Cleanup(gomock.Any(), gomock.Any()).Return(cleanupErr)
+			examinee.testing = &controllerTesting{
+				createRunManagerStub:       runManager,
+				loadPipelineRunsConfigStub: newEmptyRunsConfig,
+			}
+
+			// EXERCISE
+			resultErr := examinee.syncHandler("ns1/foo")
+
+			// VERIFY
+			assert.NilError(t, resultErr)
+			result, err := getAPIPipelineRun(cf, "foo", "ns1")
+			assert.NilError(t, err)
+			assert.Equal(t, api.StateFinished, result.Status.State)
+			assert.Equal(t, tc.result, result.Status.Result)
+			assert.Equal(t, 0, len(result.Status.Attempts))
+			assert.Equal(t, 0, len(result.GetFinalizers()))
+			condition := result.Status.GetCondition(api.PipelineRunConditionSandboxCleanedUp)
+			assert.Equal(t, corev1.ConditionFalse, condition.Status)
+			assert.Equal(t, api.PipelineRunConditionReasonCleanupFailed, condition.Reason)
+			assert.Equal(t, "cleanup error1", condition.Message)
+			cond := result.Status.GetCondition(api.PipelineRunConditionSandboxCleanedUp)
+			assert.Equal(t, corev1.ConditionFalse, cond.Status)
+			assert.Equal(t, api.PipelineRunConditionReasonCleanupFailed, cond.Reason)
+			assert.Equal(t, "cleanup error1", cond.Message)
+		})
+	}
+}
+
+func Test_Controller_syncHandler_preparing_waitsForRetryBackoff(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	finishedAt := metav1.Now()
+	run := fake.PipelineRun("foo", "ns1", api.PipelineSpec{
+		RetryPolicy: &api.RetryPolicy{
+			MaxAttempts: 2,
+			Backoff:     metav1Duration(time.Hour),
+		},
+	})
+	run.Status = api.PipelineStatus{
+		State: api.StatePreparing,
+		Attempts: []api.Attempt{
+			{Result: api.ResultErrorInfra, FinishedAt: &finishedAt},
+		},
+	}
+	examinee, cf := newController(run)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	examinee.testing = &controllerTesting{
+		createRunManagerStub:       runmocks.NewMockManager(mockCtrl),
+		loadPipelineRunsConfigStub: newEmptyRunsConfig,
+	}
+
+	// EXERCISE
+	resultErr := examinee.syncHandler("ns1/foo")
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	result, err := getAPIPipelineRun(cf, "foo", "ns1")
+	assert.NilError(t, err)
+	assert.Equal(t, api.StatePreparing, result.Status.State)
+}
+
+func Test_Controller_syncHandler_preparing_failsOnInvalidRetryPolicy(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	run := fake.PipelineRun("foo", "ns1", api.PipelineSpec{
+		RetryPolicy: &api.RetryPolicy{
+			MaxAttempts: 2,
+			RetryOn:     []api.Result{api.ResultErrorContent},
+		},
+	})
+	run.Status = api.PipelineStatus{State: api.StatePreparing}
+	examinee, cf := newController(run)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	examinee.testing = &controllerTesting{
+		createRunManagerStub:       runmocks.NewMockManager(mockCtrl),
+		loadPipelineRunsConfigStub: newEmptyRunsConfig,
+	}
+
+	// EXERCISE
+	resultErr := examinee.syncHandler("ns1/foo")
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	result, err := getAPIPipelineRun(cf, "foo", "ns1")
+	assert.NilError(t, err)
+	assert.Equal(t, api.StateFinished, result.Status.State)
+	assert.Equal(t, api.ResultErrorConfig, result.Status.Result)
+}
+
+func Test_Controller_syncHandler_preparing_failsIfTektonBackendIsDisabled(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	run := fake.PipelineRun("foo", "ns1", api.PipelineSpec{})
+	run.Status = api.PipelineStatus{State: api.StatePreparing}
+	examinee, cf := newController(run)
+	examinee.tektonDisabled = true
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	examinee.testing = &controllerTesting{
+		createRunManagerStub:       runmocks.NewMockManager(mockCtrl),
+		loadPipelineRunsConfigStub: newEmptyRunsConfig,
+	}
+
+	// EXERCISE
+	resultErr := examinee.syncHandler("ns1/foo")
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	result, err := getAPIPipelineRun(cf, "foo", "ns1")
+	assert.NilError(t, err)
+	assert.Equal(t, api.StateFinished, result.Status.State)
+	assert.Equal(t, api.ResultErrorConfig, result.Status.Result)
+}
+
 func newTestRunManager(workFactory k8s.ClientFactory, secretProvider secrets.SecretProvider) run.Manager {
 	runManager := newRunManager(workFactory, secretProvider)
 	runManager.testing = &runManagerTesting{
@@ -896,6 +1476,7 @@ func startController(t *testing.T, cf *fake.ClientFactory) chan struct{} {
 
 	cf.StewardInformerFactory().Start(stopCh)
 	cf.TektonInformerFactory().Start(stopCh)
+	cf.KubernetesInformerFactory().Start(stopCh)
 	go start(t, controller, stopCh)
 	cf.Sleep("Wait for controller")
 	return stopCh
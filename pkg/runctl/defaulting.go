@@ -0,0 +1,84 @@
+package runctl
+
+import (
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+)
+
+// defaultPipelineFile is the relative path of the pipeline definition file
+// in the pipeline repository if not specified otherwise.
+const defaultPipelineFile = "Jenkinsfile"
+
+// defaultJenkinsfileRunnerImagePullPolicy is the pull policy for a
+// Jenkinsfile Runner image specified in the pipeline run without pull
+// policy.
+const defaultJenkinsfileRunnerImagePullPolicy = "IfNotPresent"
+
+// DefaultPipelineRun sets all fields of the spec of the given pipeline run
+// which are not set to the values effectively used for the execution.
+// It is performed by the mutating admission webhook when a pipeline run
+// gets created, so that clients can read back what is going to be executed.
+// If `pipelineRunsConfig` is `nil`, only defaults independent of the
+// pipeline runs configuration are set.
+func DefaultPipelineRun(pipelineRun *api.PipelineRun, pipelineRunsConfig *cfg.PipelineRunsConfigStruct) {
+	spec := &pipelineRun.Spec
+
+	if spec.Intent == "" {
+		spec.Intent = api.IntentRun
+	}
+
+	if spec.JenkinsFile.Path == "" {
+		spec.JenkinsFile.Path = defaultPipelineFile
+	}
+
+	if spec.JenkinsfileRunner != nil && spec.JenkinsfileRunner.Image != "" {
+		if spec.JenkinsfileRunner.ImagePullPolicy == "" {
+			spec.JenkinsfileRunner.ImagePullPolicy = defaultJenkinsfileRunnerImagePullPolicy
+		}
+	}
+
+	if pipelineRunsConfig == nil {
+		return
+	}
+
+	if (spec.Profiles == nil || spec.Profiles.Network == "") && pipelineRunsConfig.DefaultNetworkProfile != "" {
+		if spec.Profiles == nil {
+			spec.Profiles = &api.Profiles{}
+		}
+		spec.Profiles.Network = pipelineRunsConfig.DefaultNetworkProfile
+	}
+
+	if (spec.Profiles == nil || spec.Profiles.Scheduling == "") && pipelineRunsConfig.DefaultSchedulingProfile != "" {
+		if spec.Profiles == nil {
+			spec.Profiles = &api.Profiles{}
+		}
+		spec.Profiles.Scheduling = pipelineRunsConfig.DefaultSchedulingProfile
+	}
+
+	if (spec.Profiles == nil || spec.Profiles.Resources == "") && pipelineRunsConfig.DefaultResourceProfile != "" {
+		if spec.Profiles == nil {
+			spec.Profiles = &api.Profiles{}
+		}
+		spec.Profiles.Resources = pipelineRunsConfig.DefaultResourceProfile
+	}
+
+	if (spec.JenkinsfileRunner == nil || spec.JenkinsfileRunner.Image == "") && pipelineRunsConfig.JenkinsfileRunnerImage != "" {
+		if spec.JenkinsfileRunner == nil {
+			spec.JenkinsfileRunner = &api.JenkinsfileRunnerSpec{}
+		}
+		spec.JenkinsfileRunner.Image = pipelineRunsConfig.JenkinsfileRunnerImage
+		spec.JenkinsfileRunner.ImagePullPolicy = pipelineRunsConfig.JenkinsfileRunnerImagePullPolicy
+		if spec.JenkinsfileRunner.ImagePullPolicy == "" {
+			spec.JenkinsfileRunner.ImagePullPolicy = defaultJenkinsfileRunnerImagePullPolicy
+		}
+	}
+
+	if spec.Timeout == nil {
+		spec.Timeout = effectiveTimeout(spec, pipelineRunsConfig)
+	}
+
+	if spec.TTLSecondsAfterFinished == nil && pipelineRunsConfig.TTLSecondsAfterFinished != nil {
+		ttl := *pipelineRunsConfig.TTLSecondsAfterFinished
+		spec.TTLSecondsAfterFinished = &ttl
+	}
+}
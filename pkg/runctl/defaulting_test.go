@@ -0,0 +1,126 @@
+package runctl
+
+import (
+	"testing"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	cfg "github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	assert "gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	k8sresource "k8s.io/apimachinery/pkg/api/resource"
+)
+
+func Test_DefaultPipelineRun(t *testing.T) {
+	t.Parallel()
+
+	int64Ptr := func(val int64) *int64 { return &val }
+	config := &cfg.PipelineRunsConfigStruct{
+		DefaultNetworkProfile:            "default1",
+		DefaultSchedulingProfile:         "default2",
+		DefaultResourceProfile:           "default3",
+		JenkinsfileRunnerImage:           "jfr:1",
+		JenkinsfileRunnerImagePullPolicy: "Always",
+		Timeout:                          metav1Duration(30 * time.Minute),
+		TTLSecondsAfterFinished:          int64Ptr(600),
+	}
+
+	for _, tc := range []struct {
+		name         string
+		spec         api.PipelineSpec
+		config       *cfg.PipelineRunsConfigStruct
+		expectedSpec api.PipelineSpec
+	}{
+		{
+			name:   "empty_spec",
+			spec:   api.PipelineSpec{},
+			config: config,
+			expectedSpec: api.PipelineSpec{
+				Intent:                  api.IntentRun,
+				JenkinsFile:             api.JenkinsFile{Path: "Jenkinsfile"},
+				Profiles:                &api.Profiles{Network: "default1", Scheduling: "default2", Resources: "default3"},
+				JenkinsfileRunner:       &api.JenkinsfileRunnerSpec{Image: "jfr:1", ImagePullPolicy: "Always"},
+				Timeout:                 metav1Duration(30 * time.Minute),
+				TTLSecondsAfterFinished: int64Ptr(600),
+			},
+		},
+		{
+			name: "all_set",
+			spec: api.PipelineSpec{
+				Intent:                  api.IntentAbort,
+				JenkinsFile:             api.JenkinsFile{Path: "ci/Jenkinsfile"},
+				Profiles:                &api.Profiles{Network: "profile1", Scheduling: "profile2", Resources: "profile3"},
+				JenkinsfileRunner:       &api.JenkinsfileRunnerSpec{Image: "myjfr:2", ImagePullPolicy: "Never"},
+				Timeout:                 metav1Duration(5 * time.Minute),
+				TTLSecondsAfterFinished: int64Ptr(0),
+			},
+			config: config,
+			expectedSpec: api.PipelineSpec{
+				Intent:                  api.IntentAbort,
+				JenkinsFile:             api.JenkinsFile{Path: "ci/Jenkinsfile"},
+				Profiles:                &api.Profiles{Network: "profile1", Scheduling: "profile2", Resources: "profile3"},
+				JenkinsfileRunner:       &api.JenkinsfileRunnerSpec{Image: "myjfr:2", ImagePullPolicy: "Never"},
+				Timeout:                 metav1Duration(5 * time.Minute),
+				TTLSecondsAfterFinished: int64Ptr(0),
+			},
+		},
+		{
+			name: "resources_without_image",
+			spec: api.PipelineSpec{
+				JenkinsfileRunner: &api.JenkinsfileRunnerSpec{
+					Resources: &corev1.ResourceRequirements{
+						Limits: corev1.ResourceList{corev1.ResourceMemory: k8sresource.MustParse("4Gi")},
+					},
+				},
+			},
+			config: &cfg.PipelineRunsConfigStruct{JenkinsfileRunnerImage: "jfr:1"},
+			expectedSpec: api.PipelineSpec{
+				Intent:      api.IntentRun,
+				JenkinsFile: api.JenkinsFile{Path: "Jenkinsfile"},
+				JenkinsfileRunner: &api.JenkinsfileRunnerSpec{
+					Image:           "jfr:1",
+					ImagePullPolicy: "IfNotPresent",
+					Resources: &corev1.ResourceRequirements{
+						Limits: corev1.ResourceList{corev1.ResourceMemory: k8sresource.MustParse("4Gi")},
+					},
+				},
+			},
+		},
+		{
+			name: "custom_image_without_pull_policy",
+			spec: api.PipelineSpec{
+				JenkinsfileRunner: &api.JenkinsfileRunnerSpec{Image: "myjfr:2"},
+			},
+			config: &cfg.PipelineRunsConfigStruct{},
+			expectedSpec: api.PipelineSpec{
+				Intent:            api.IntentRun,
+				JenkinsFile:       api.JenkinsFile{Path: "Jenkinsfile"},
+				JenkinsfileRunner: &api.JenkinsfileRunnerSpec{Image: "myjfr:2", ImagePullPolicy: "IfNotPresent"},
+			},
+		},
+		{
+			name:   "no_config",
+			spec:   api.PipelineSpec{},
+			config: nil,
+			expectedSpec: api.PipelineSpec{
+				Intent:      api.IntentRun,
+				JenkinsFile: api.JenkinsFile{Path: "Jenkinsfile"},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			run := fake.PipelineRun("run1", "ns1", tc.spec)
+
+			// EXERCISE
+			DefaultPipelineRun(run, tc.config)
+
+			// VERIFY
+			assert.DeepEqual(t, tc.expectedSpec, run.Spec)
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package runctl
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	"github.com/SAP/stewardci-core/pkg/runctl/metrics"
+	"github.com/SAP/stewardci-core/pkg/stewardlabels"
+	"github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	klog "k8s.io/klog/v2"
+)
+
+const (
+	// gcReasonTTL is the deletion reason for finished pipeline runs whose
+	// time to live has expired.
+	gcReasonTTL = "ttl"
+
+	// gcReasonHistoryLimit is the deletion reason for finished pipeline
+	// runs exceeding the number of finished pipeline runs to keep per
+	// tenant namespace.
+	gcReasonHistoryLimit = "history_limit"
+)
+
+var (
+	// Interval of the garbage collection of finished pipeline runs
+	gcInterval = 1 * time.Minute
+)
+
+// gcItem is a finished pipeline run to be deleted by the garbage collector.
+type gcItem struct {
+	pipelineRun *api.PipelineRun
+	reason      string
+}
+
+// collectGarbage deletes finished pipeline runs which are not to be kept
+// any longer according to their time to live and the limit of finished
+// pipeline runs per tenant namespace.
+func (c *Controller) collectGarbage() {
+	ctx := context.Background()
+
+	pipelineRunsConfig, err := c.loadPipelineRunsConfig(ctx)
+	if err != nil {
+		utilruntime.HandleError(errors.Wrap(err, "garbage collection of pipeline runs skipped"))
+		return
+	}
+	runs, err := c.pipelineRunLister.List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(errors.Wrap(err, "garbage collection of pipeline runs skipped"))
+		return
+	}
+
+	for _, item := range findGarbage(runs, pipelineRunsConfig, time.Now()) {
+		if err := c.deleteGarbage(ctx, item); err != nil {
+			utilruntime.HandleError(err)
+		}
+	}
+}
+
+// deleteGarbage deletes the pipeline run of the given garbage item.
+// The deletion is skipped if the pipeline run has been replaced by another
+// object with the same name in the meantime.
+func (c *Controller) deleteGarbage(ctx context.Context, item gcItem) error {
+	run := item.pipelineRun
+	uid := run.GetUID()
+	err := c.factory.StewardV1alpha1().PipelineRuns(run.GetNamespace()).Delete(ctx, run.GetName(), metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{UID: &uid},
+	})
+	if err != nil {
+		if k8serrors.IsNotFound(err) || k8serrors.IsConflict(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to delete finished pipeline run %s/%s", run.GetNamespace(), run.GetName())
+	}
+	klog.V(3).Infof("Deleted finished pipeline run %s/%s (reason: %s)", run.GetNamespace(), run.GetName(), item.reason)
+	metrics.PipelineRunsDeleted.Inc(item.reason)
+	return nil
+}
+
+// findGarbage returns the finished pipeline runs in `runs` to be deleted.
+// A finished pipeline run gets deleted if its time to live has expired at
+// time `now`, or if it is older than the newest finished pipeline runs in
+// the same namespace which are to be kept.
+func findGarbage(runs []*api.PipelineRun, pipelineRunsConfig *cfg.PipelineRunsConfigStruct, now time.Time) []gcItem {
+	finishedByNamespace := map[string][]*api.PipelineRun{}
+	for _, run := range runs {
+		if run.Status.State != api.StateFinished || !run.DeletionTimestamp.IsZero() || stewardlabels.IsLabelledAsIgnore(run) {
+			continue
+		}
+		finishedByNamespace[run.GetNamespace()] = append(finishedByNamespace[run.GetNamespace()], run)
+	}
+
+	namespaces := make([]string, 0, len(finishedByNamespace))
+	for namespace := range finishedByNamespace {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+
+	var keep int64
+	if pipelineRunsConfig.KeepFinishedPipelineRunsPerTenant != nil {
+		keep = *pipelineRunsConfig.KeepFinishedPipelineRunsPerTenant
+	}
+
+	var garbage []gcItem
+	for _, namespace := range namespaces {
+		finished := finishedByNamespace[namespace]
+		sort.SliceStable(finished, func(i, j int) bool {
+			return isFinishedAfter(finished[i], finished[j])
+		})
+		var kept int64
+		for _, run := range finished {
+			switch {
+			case isExpired(run, pipelineRunsConfig, now):
+				garbage = append(garbage, gcItem{pipelineRun: run, reason: gcReasonTTL})
+			case keep > 0 && kept >= keep:
+				garbage = append(garbage, gcItem{pipelineRun: run, reason: gcReasonHistoryLimit})
+			default:
+				kept++
+			}
+		}
+	}
+	return garbage
+}
+
+// isExpired returns whether the time to live of the given finished pipeline
+// run has expired at time `now`.
+func isExpired(run *api.PipelineRun, pipelineRunsConfig *cfg.PipelineRunsConfigStruct, now time.Time) bool {
+	ttl := run.Spec.TTLSecondsAfterFinished
+	if ttl == nil {
+		ttl = pipelineRunsConfig.TTLSecondsAfterFinished
+	}
+	if ttl == nil {
+		return false
+	}
+	expiresAt := finishedAt(run).Add(time.Duration(*ttl) * time.Second)
+	return !now.Before(expiresAt)
+}
+
+// isFinishedAfter returns whether pipeline run `a` has finished after
+// pipeline run `b`. Pipeline runs finished at the same time are ordered
+// by name to get a stable order.
+func isFinishedAfter(a, b *api.PipelineRun) bool {
+	finishedA, finishedB := finishedAt(a), finishedAt(b)
+	if !finishedA.Equal(finishedB) {
+		return finishedA.After(finishedB)
+	}
+	return a.GetName() > b.GetName()
+}
+
+// finishedAt returns the time the given finished pipeline run has finished
+// at. Pipeline runs finished without a result (e.g. deleted before start)
+// may not have `status.finishedAt` set, in which case the time the state
+// `finished` has been entered is used.
+func finishedAt(run *api.PipelineRun) time.Time {
+	if run.Status.FinishedAt != nil {
+		return run.Status.FinishedAt.Time
+	}
+	if !run.Status.StateDetails.StartedAt.IsZero() {
+		return run.Status.StateDetails.StartedAt.Time
+	}
+	return run.CreationTimestamp.Time
+}
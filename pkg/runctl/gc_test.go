@@ -0,0 +1,170 @@
+package runctl
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	cfg "github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	assert "gotest.tools/assert"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newFinishedPipelineRun(name, namespace string, finished time.Time) *api.PipelineRun {
+	run := newPipelineRunWithState(name, namespace, api.StateFinished, finished.Add(-time.Hour))
+	finishedAt := metav1.NewTime(finished)
+	run.Status.FinishedAt = &finishedAt
+	return run
+}
+
+func Test_findGarbage(t *testing.T) {
+	t.Parallel()
+
+	int64Ptr := func(val int64) *int64 { return &val }
+	now := time.Date(2022, 3, 4, 5, 6, 7, 0, time.UTC)
+
+	for _, tc := range []struct {
+		name       string
+		specTTL    *int64
+		configTTL  *int64
+		configKeep *int64
+		expectedGC map[string]string
+	}{
+		{"nothing_configured", nil, nil, nil, map[string]string{}},
+		{"config_ttl", nil, int64Ptr(3600), nil, map[string]string{
+			"ns1/old": gcReasonTTL,
+		}},
+		{"spec_ttl_overrides_config", int64Ptr(60), int64Ptr(3600), nil, map[string]string{
+			"ns1/old":    gcReasonTTL,
+			"ns1/recent": gcReasonTTL,
+		}},
+		{"spec_ttl_zero", int64Ptr(0), nil, nil, map[string]string{
+			"ns1/old":    gcReasonTTL,
+			"ns1/recent": gcReasonTTL,
+			"ns1/new":    gcReasonTTL,
+			"ns2/other":  gcReasonTTL,
+		}},
+		{"keep_one", nil, nil, int64Ptr(1), map[string]string{
+			"ns1/old":    gcReasonHistoryLimit,
+			"ns1/recent": gcReasonHistoryLimit,
+		}},
+		{"keep_zero_is_unlimited", nil, nil, int64Ptr(0), map[string]string{}},
+		{"ttl_and_keep", nil, int64Ptr(3600), int64Ptr(1), map[string]string{
+			"ns1/old":    gcReasonTTL,
+			"ns1/recent": gcReasonHistoryLimit,
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			deleted := newFinishedPipelineRun("deleted", "ns1", now.Add(-48*time.Hour))
+			deleted.DeletionTimestamp = &metav1.Time{Time: now}
+			ignored := newFinishedPipelineRun("ignored", "ns1", now.Add(-48*time.Hour))
+			ignored.SetLabels(map[string]string{api.LabelIgnore: ""})
+			runs := []*api.PipelineRun{
+				newFinishedPipelineRun("recent", "ns1", now.Add(-10*time.Minute)),
+				newFinishedPipelineRun("old", "ns1", now.Add(-2*time.Hour)),
+				newFinishedPipelineRun("new", "ns1", now),
+				newFinishedPipelineRun("other", "ns2", now.Add(-30*time.Second)),
+				newPipelineRunWithState("running", "ns1", api.StateRunning, now.Add(-48*time.Hour)),
+				deleted,
+				ignored,
+			}
+			for _, run := range runs {
+				run.Spec.TTLSecondsAfterFinished = tc.specTTL
+			}
+			config := &cfg.PipelineRunsConfigStruct{
+				TTLSecondsAfterFinished:           tc.configTTL,
+				KeepFinishedPipelineRunsPerTenant: tc.configKeep,
+			}
+
+			// EXERCISE
+			result := findGarbage(runs, config, now)
+
+			// VERIFY
+			resultMap := map[string]string{}
+			for _, item := range result {
+				resultMap[item.pipelineRun.GetNamespace()+"/"+item.pipelineRun.GetName()] = item.reason
+			}
+			assert.DeepEqual(t, tc.expectedGC, resultMap)
+		})
+	}
+}
+
+func Test_finishedAt(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	created := time.Date(2022, 3, 4, 5, 6, 7, 0, time.UTC)
+	stateStarted := created.Add(time.Minute)
+	finished := created.Add(time.Hour)
+
+	run := newPipelineRunWithState("foo", "ns1", api.StateFinished, created)
+
+	// EXERCISE and VERIFY
+	assert.Equal(t, created, finishedAt(run))
+
+	run.Status.StateDetails.StartedAt = metav1.NewTime(stateStarted)
+	assert.Equal(t, stateStarted, finishedAt(run))
+
+	run.Status.FinishedAt = &metav1.Time{Time: finished}
+	assert.Equal(t, finished, finishedAt(run))
+}
+
+func Test_Controller_collectGarbage(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	ttl := int64(60)
+	expired := newFinishedPipelineRun("expired", "ns1", time.Now().Add(-time.Hour))
+	expired.Spec.TTLSecondsAfterFinished = &ttl
+	kept := newFinishedPipelineRun("kept", "ns1", time.Now())
+	kept.Spec.TTLSecondsAfterFinished = &ttl
+	examinee, cf := newController(expired, kept)
+	examinee.pipelineRunStore.Add(expired)
+	examinee.pipelineRunStore.Add(kept)
+	examinee.testing = &controllerTesting{
+		loadPipelineRunsConfigStub: newEmptyRunsConfig,
+	}
+
+	// EXERCISE
+	examinee.collectGarbage()
+
+	// VERIFY
+	_, err := getAPIPipelineRun(cf, "expired", "ns1")
+	assert.Assert(t, k8serrors.IsNotFound(err))
+	_, err = getAPIPipelineRun(cf, "kept", "ns1")
+	assert.NilError(t, err)
+
+	// deleting an already deleted pipeline run is not an error
+	assert.NilError(t, examinee.deleteGarbage(ctx, gcItem{pipelineRun: expired, reason: gcReasonTTL}))
+}
+
+func Test_Controller_collectGarbage_configError(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	expired := newFinishedPipelineRun("expired", "ns1", time.Now().Add(-time.Hour))
+	ttl := int64(0)
+	expired.Spec.TTLSecondsAfterFinished = &ttl
+	examinee, cf := newController(expired)
+	examinee.pipelineRunStore.Add(expired)
+	examinee.testing = &controllerTesting{
+		loadPipelineRunsConfigStub: func(ctx context.Context) (*cfg.PipelineRunsConfigStruct, error) {
+			return nil, errors.New("some error")
+		},
+	}
+
+	// EXERCISE
+	examinee.collectGarbage()
+
+	// VERIFY
+	_, err := getAPIPipelineRun(cf, "expired", "ns1")
+	assert.NilError(t, err)
+}
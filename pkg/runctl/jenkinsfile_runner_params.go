@@ -0,0 +1,148 @@
+package runctl
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	tekton "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1api "k8s.io/api/core/v1"
+)
+
+// jenkinsfileRunnerParams returns the parameters of the Jenkinsfile Runner
+// keyed by name. They are independent of the run backend: the Tekton run
+// backend passes them as TaskRun parameters to the ClusterTask, the pod run
+// backend as environment variables of the Jenkinsfile Runner container.
+// All values originate from the pipeline run and the pipeline runs
+// configuration.
+func jenkinsfileRunnerParams(runCtx *runContext) (map[string]string, error) {
+	config := runCtx.pipelineRunsConfig
+	params := map[string]string{
+		"RUN_NAMESPACE":                     runCtx.runNamespace,
+		"JAVA_OPTS":                         config.JenkinsfileRunnerJavaOpts,
+		"PIPELINE_CLONE_RETRY_INTERVAL_SEC": config.JenkinsfileRunnerPipelineCloneRetryIntervalSec,
+		"PIPELINE_CLONE_RETRY_TIMEOUT_SEC":  config.JenkinsfileRunnerPipelineCloneRetryTimeoutSec,
+	}
+	addJenkinsfileRunnerImageParams(runCtx, params)
+	if err := addPipelineParams(runCtx, params); err != nil {
+		return nil, err
+	}
+	if err := addLoggingElasticsearchParams(runCtx, params); err != nil {
+		return nil, err
+	}
+	addRunDetailsParams(runCtx, params)
+	return params, nil
+}
+
+func addJenkinsfileRunnerImageParams(runCtx *runContext, params map[string]string) {
+	jfrSpec := runCtx.pipelineRun.GetSpec().JenkinsfileRunner
+	image := runCtx.pipelineRunsConfig.JenkinsfileRunnerImage
+	imagePullPolicy := runCtx.pipelineRunsConfig.JenkinsfileRunnerImagePullPolicy
+
+	if jfrSpec != nil {
+		if jfrSpec.Image != "" {
+			image = jfrSpec.Image
+			if jfrSpec.ImagePullPolicy == "" {
+				imagePullPolicy = string(corev1api.PullIfNotPresent)
+			} else {
+				imagePullPolicy = jfrSpec.ImagePullPolicy
+			}
+		}
+	}
+	if imagePullPolicy == "" {
+		imagePullPolicy = string(corev1api.PullIfNotPresent)
+	}
+	params["JFR_IMAGE"] = image
+	params["JFR_IMAGE_PULL_POLICY"] = imagePullPolicy
+}
+
+func addPipelineParams(runCtx *runContext, params map[string]string) error {
+	var err error
+
+	spec := runCtx.pipelineRun.GetSpec()
+	pipeline := spec.JenkinsFile
+	pipelineArgs := spec.Args
+	pipelineArgsJSON := "{}"
+	if pipelineArgs != nil {
+		if pipelineArgsJSON, err = toJSONString(&pipelineArgs); err != nil {
+			return err
+		}
+	}
+
+	params["PIPELINE_GIT_URL"] = pipeline.URL
+	params["PIPELINE_GIT_REVISION"] = pipeline.Revision
+	params["PIPELINE_FILE"] = pipeline.Path
+	params["PIPELINE_PARAMS_JSON"] = pipelineArgsJSON
+	return nil
+}
+
+func addLoggingElasticsearchParams(runCtx *runContext, params map[string]string) error {
+	spec := runCtx.pipelineRun.GetSpec()
+
+	params["PIPELINE_LOG_ELASTICSEARCH_AUTH_SECRET"] = ""
+	params["PIPELINE_LOG_ELASTICSEARCH_TRUSTEDCERTS_SECRET"] = ""
+
+	if spec.Logging == nil || spec.Logging.Elasticsearch == nil {
+		// an empty index URL disables logging to Elasticsearch
+		params["PIPELINE_LOG_ELASTICSEARCH_INDEX_URL"] = ""
+		params["PIPELINE_LOG_ELASTICSEARCH_RUN_ID_JSON"] = ""
+		return nil
+	}
+
+	runIDJSON, err := toJSONString(&spec.Logging.Elasticsearch.RunID)
+	if err != nil {
+		return errors.WithMessage(err,
+			"could not serialize spec.logging.elasticsearch.runid to JSON",
+		)
+	}
+	params["PIPELINE_LOG_ELASTICSEARCH_RUN_ID_JSON"] = runIDJSON
+
+	if spec.Logging.Elasticsearch.IndexURL != "" {
+		_, err := ensureValidElasticsearchIndexURL(spec.Logging.Elasticsearch.IndexURL)
+		if err != nil {
+			return errors.Wrapf(err,
+				"field \"spec.logging.elasticsearch.indexURL\" has invalid value %q",
+				spec.Logging.Elasticsearch.IndexURL,
+			)
+		}
+		// the index URL of the pipeline runs configuration is used for now
+	}
+	params["PIPELINE_LOG_ELASTICSEARCH_INDEX_URL"] = runCtx.pipelineRunsConfig.ElasticsearchIndexURL
+	return nil
+}
+
+func addRunDetailsParams(runCtx *runContext, params map[string]string) {
+	params["JOB_NAME"] = ""
+	params["RUN_NUMBER"] = "1"
+	params["RUN_CAUSE"] = ""
+
+	details := runCtx.pipelineRun.GetSpec().RunDetails
+	if details == nil {
+		return
+	}
+	if details.JobName != "" {
+		params["JOB_NAME"] = details.JobName
+	}
+	if details.SequenceNumber > 0 {
+		params["RUN_NUMBER"] = fmt.Sprintf("%d", details.SequenceNumber)
+	}
+	if details.Cause != "" {
+		params["RUN_CAUSE"] = details.Cause
+	}
+}
+
+// tektonStringParams converts the given parameters into Tekton string
+// parameters sorted by name.
+func tektonStringParams(params map[string]string) []tekton.Param {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := make([]tekton.Param, 0, len(names))
+	for _, name := range names {
+		result = append(result, tektonStringParam(name, params[name]))
+	}
+	return result
+}
@@ -0,0 +1,168 @@
+package runctl
+
+import (
+	"testing"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	k8smocks "github.com/SAP/stewardci-core/pkg/k8s/mocks"
+	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	gomock "github.com/golang/mock/gomock"
+	"gotest.tools/assert"
+)
+
+func Test_jenkinsfileRunnerParams(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockPipelineRun := k8smocks.NewMockPipelineRun(mockCtrl)
+	mockPipelineRun.EXPECT().GetSpec().Return(&stewardv1alpha1.PipelineSpec{
+		JenkinsFile: stewardv1alpha1.JenkinsFile{
+			URL:      "https://github.com/foo/bar",
+			Revision: "main",
+			Path:     "Jenkinsfile",
+		},
+		Logging: &stewardv1alpha1.Logging{
+			Elasticsearch: &stewardv1alpha1.Elasticsearch{},
+		},
+		RunDetails: &stewardv1alpha1.PipelineRunDetails{
+			JobName: "job1",
+		},
+	}).AnyTimes()
+	runCtx := &runContext{
+		pipelineRun:  mockPipelineRun,
+		runNamespace: "runNamespace1",
+		pipelineRunsConfig: &cfg.PipelineRunsConfigStruct{
+			JenkinsfileRunnerImage:                         "jfrImage1",
+			JenkinsfileRunnerJavaOpts:                      "javaOpts1",
+			JenkinsfileRunnerPipelineCloneRetryIntervalSec: "10",
+			JenkinsfileRunnerPipelineCloneRetryTimeoutSec:  "120",
+			ElasticsearchIndexURL:                          "http://es.example.com/index1/_doc",
+		},
+	}
+
+	// EXERCISE
+	result, resultErr := jenkinsfileRunnerParams(runCtx)
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assert.DeepEqual(t, map[string]string{
+		"RUN_NAMESPACE":                                  "runNamespace1",
+		"JFR_IMAGE":                                      "jfrImage1",
+		"JFR_IMAGE_PULL_POLICY":                          "IfNotPresent",
+		"JAVA_OPTS":                                      "javaOpts1",
+		"PIPELINE_CLONE_RETRY_INTERVAL_SEC":              "10",
+		"PIPELINE_CLONE_RETRY_TIMEOUT_SEC":               "120",
+		"PIPELINE_GIT_URL":                               "https://github.com/foo/bar",
+		"PIPELINE_GIT_REVISION":                          "main",
+		"PIPELINE_FILE":                                  "Jenkinsfile",
+		"PIPELINE_PARAMS_JSON":                           "{}",
+		"PIPELINE_LOG_ELASTICSEARCH_INDEX_URL":           "http://es.example.com/index1/_doc",
+		"PIPELINE_LOG_ELASTICSEARCH_RUN_ID_JSON":         "null",
+		"PIPELINE_LOG_ELASTICSEARCH_AUTH_SECRET":         "",
+		"PIPELINE_LOG_ELASTICSEARCH_TRUSTEDCERTS_SECRET": "",
+		"JOB_NAME":                                       "job1",
+		"RUN_NUMBER":                                     "1",
+		"RUN_CAUSE":                                      "",
+	}, result)
+}
+
+func Test_addJenkinsfileRunnerImageParams(t *testing.T) {
+	t.Parallel()
+
+	const (
+		pipelineRunsConfigDefaultImage  = "defaultImage1"
+		pipelineRunsConfigDefaultPolicy = "defaultPolicy1"
+	)
+	for _, tc := range []struct {
+		name                string
+		spec                *stewardv1alpha1.PipelineSpec
+		expectedAddedParams map[string]string
+	}{
+		{
+			name: "empty",
+			spec: &stewardv1alpha1.PipelineSpec{},
+			expectedAddedParams: map[string]string{
+				"JFR_IMAGE":             pipelineRunsConfigDefaultImage,
+				"JFR_IMAGE_PULL_POLICY": pipelineRunsConfigDefaultPolicy,
+			},
+		},
+		{
+			name: "no_image_no_policy",
+			spec: &stewardv1alpha1.PipelineSpec{
+				JenkinsfileRunner: &stewardv1alpha1.JenkinsfileRunnerSpec{},
+			},
+			expectedAddedParams: map[string]string{
+				"JFR_IMAGE":             pipelineRunsConfigDefaultImage,
+				"JFR_IMAGE_PULL_POLICY": pipelineRunsConfigDefaultPolicy,
+			},
+		},
+		{
+			name: "image_only",
+			spec: &stewardv1alpha1.PipelineSpec{
+				JenkinsfileRunner: &stewardv1alpha1.JenkinsfileRunnerSpec{
+					Image: "foo",
+				},
+			},
+			expectedAddedParams: map[string]string{
+				"JFR_IMAGE":             "foo",
+				"JFR_IMAGE_PULL_POLICY": "IfNotPresent",
+			},
+		},
+		{
+			name: "policy_only",
+			spec: &stewardv1alpha1.PipelineSpec{
+				JenkinsfileRunner: &stewardv1alpha1.JenkinsfileRunnerSpec{
+					ImagePullPolicy: "bar",
+				},
+			},
+			expectedAddedParams: map[string]string{
+				"JFR_IMAGE":             pipelineRunsConfigDefaultImage,
+				"JFR_IMAGE_PULL_POLICY": pipelineRunsConfigDefaultPolicy,
+			},
+		},
+		{
+			name: "image_and_policy",
+			spec: &stewardv1alpha1.PipelineSpec{
+				JenkinsfileRunner: &stewardv1alpha1.JenkinsfileRunnerSpec{
+					Image:           "foo",
+					ImagePullPolicy: "bar",
+				},
+			},
+			expectedAddedParams: map[string]string{
+				"JFR_IMAGE":             "foo",
+				"JFR_IMAGE_PULL_POLICY": "bar",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc
+			t.Parallel()
+
+			// SETUP
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockPipelineRun := k8smocks.NewMockPipelineRun(mockCtrl)
+			mockPipelineRun.EXPECT().GetSpec().Return(tc.spec).AnyTimes()
+			params := map[string]string{"AlreadyExistingParam1": "foo"}
+			runCtx := &runContext{
+				pipelineRun: mockPipelineRun,
+				pipelineRunsConfig: &cfg.PipelineRunsConfigStruct{
+					JenkinsfileRunnerImage:           pipelineRunsConfigDefaultImage,
+					JenkinsfileRunnerImagePullPolicy: pipelineRunsConfigDefaultPolicy,
+				},
+			}
+
+			// EXERCISE
+			addJenkinsfileRunnerImageParams(runCtx, params)
+
+			// VERIFY
+			expectedParams := map[string]string{"AlreadyExistingParam1": "foo"}
+			for name, value := range tc.expectedAddedParams {
+				expectedParams[name] = value
+			}
+			assert.DeepEqual(t, expectedParams, params)
+		})
+	}
+}
@@ -26,3 +26,8 @@ type StateItemsMetric interface {
 type ResultsMetric interface {
 	Observe(result stewardapi.Result)
 }
+
+// DeletionsMetric counts deleted objects by the reason of deletion.
+type DeletionsMetric interface {
+	Inc(reason string)
+}
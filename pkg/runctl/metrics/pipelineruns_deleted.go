@@ -0,0 +1,43 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/SAP/stewardci-core/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// PipelineRunsDeleted counts the finished pipeline runs deleted by the
+	// garbage collector, partitioned by the reason of deletion.
+	PipelineRunsDeleted DeletionsMetric = &pipelineRunsDeleted{}
+)
+
+func init() {
+	PipelineRunsDeleted.(*pipelineRunsDeleted).init()
+}
+
+type pipelineRunsDeleted struct {
+	initOnlyOnce sync.Once
+	metric       *prometheus.CounterVec
+}
+
+func (m *pipelineRunsDeleted) init() {
+	m.initOnlyOnce.Do(func() {
+		m.metric = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Subsystem: subsystem,
+				Name:      "deleted_total",
+				Help:      "The number of finished pipeline runs deleted by the garbage collector partitioned by reason.",
+			},
+			[]string{
+				"reason",
+			},
+		)
+		metrics.Registerer().MustRegister(m.metric)
+	})
+}
+
+func (m *pipelineRunsDeleted) Inc(reason string) {
+	m.metric.WithLabelValues(reason).Inc()
+}
@@ -0,0 +1,16 @@
+package metrics
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func Test_PipelineRunsDeleted_isInitialized(t *testing.T) {
+	t.Parallel()
+
+	// VERIFY
+	assert.Assert(t, *(PipelineRunsDeleted.(*pipelineRunsDeleted)) != pipelineRunsDeleted{})
+}
+
+// TODO add tests for pipelineRunsDeleted
@@ -72,6 +72,9 @@ func (m *pipelineRunsPeriodic) init() {
 func (m *pipelineRunsPeriodic) Observe(run *stewardapi.PipelineRun) {
 	if m.isNewRun(run) {
 		m.observe(stewardapi.StateNew, run.CreationTimestamp)
+	} else if run.Status.State == stewardapi.StateQueued {
+		// the start time of a pipeline run is set when leaving the queue
+		m.observe(stewardapi.StateQueued, run.Status.StateDetails.StartedAt)
 	} else if run.Status.StartedAt != nil {
 		m.observe(run.Status.State, *run.Status.StartedAt)
 	}
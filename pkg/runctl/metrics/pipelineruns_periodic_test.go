@@ -184,6 +184,54 @@ func Test_pipelineRunsPeriodic_NonNewRun(t *testing.T) {
 	}
 }
 
+func Test_pipelineRunsPeriodic_QueuedRun(t *testing.T) {
+	// no parallel: patching global state
+
+	for idx, tc := range []struct {
+		omitStateStartTime bool
+		duration           time.Duration // must be a small duration
+		expectObservation  bool
+	}{
+		{
+			omitStateStartTime: false,
+			duration:           1 * time.Second,
+			expectObservation:  true,
+		},
+		{
+			omitStateStartTime: true,
+			duration:           1 * time.Second,
+			expectObservation:  false,
+		},
+	} {
+		tc := tc
+		t.Run(strconv.Itoa(idx), func(t *testing.T) {
+			// no parallel: patching global state
+
+			// SETUP
+			mockClock := clock.NewMock()
+			mockClock.Set(fakeNow)
+
+			run := &stewardapi.PipelineRun{}
+			run.Status.State = stewardapi.StateQueued
+			// Creation time should be ignored. Set it to see whether it's used anyway.
+			run.CreationTimestamp = metav1.NewTime(mockClock.Now().Add(-24 * time.Hour))
+			if !tc.omitStateStartTime {
+				run.Status.StateDetails.StartedAt = metav1.NewTime(mockClock.Now().Add(-tc.duration))
+			}
+
+			// EXERCISE and VERIFY
+			doTestPipelineRunsPeriodic(
+				t,
+				mockClock,
+				run,
+				tc.duration,
+				tc.expectObservation,
+				stewardapi.StateQueued,
+			)
+		})
+	}
+}
+
 func doTestPipelineRunsPeriodic(
 	t *testing.T,
 	mockClock *clock.Mock,
@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/SAP/stewardci-core/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// PipelineRunsQueued reflects the current number of pipeline runs in
+	// state `queued`, i.e. waiting for being started because the limit of
+	// concurrently active pipeline runs is reached.
+	PipelineRunsQueued SettableGaugeMetric = &pipelineRunsQueued{}
+)
+
+func init() {
+	PipelineRunsQueued.(*pipelineRunsQueued).init()
+}
+
+type pipelineRunsQueued struct {
+	initOnlyOnce sync.Once
+	metric       prometheus.Gauge
+}
+
+func (m *pipelineRunsQueued) init() {
+	m.initOnlyOnce.Do(func() {
+		m.metric = prometheus.NewGauge(prometheus.GaugeOpts{
+			Subsystem: subsystem,
+			Name:      "queued_count",
+			Help:      "The current number of queued pipeline runs.",
+		})
+		metrics.Registerer().MustRegister(m.metric)
+	})
+}
+
+func (m *pipelineRunsQueued) Set(value float64) {
+	m.metric.Set(value)
+}
@@ -0,0 +1,89 @@
+package runctl
+
+import (
+	steward "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	run "github.com/SAP/stewardci-core/pkg/runctl/run"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// podReasonDeadlineExceeded is the reason set by the kubelet at pods
+// which have been terminated because their active deadline is exceeded.
+const podReasonDeadlineExceeded = "DeadlineExceeded"
+
+type podRun struct {
+	pod *corev1.Pod
+}
+
+// newPodRun returns a Run backed by the Jenkinsfile Runner pod.
+func newPodRun(pod *corev1.Pod) run.Run {
+	return &podRun{pod: pod}
+}
+
+// GetStartTime returns start time of run if already started
+func (r *podRun) GetStartTime() *metav1.Time {
+	return r.pod.Status.StartTime
+}
+
+// GetCompletionTime returns completion time of run if already completed
+func (r *podRun) GetCompletionTime() *metav1.Time {
+	containerState := r.GetContainerInfo()
+	if containerState != nil && containerState.Terminated != nil {
+		finishedAt := containerState.Terminated.FinishedAt
+		if !finishedAt.IsZero() {
+			return &finishedAt
+		}
+	}
+
+	now := metav1.Now()
+	return &now
+}
+
+// GetContainerInfo returns the state of the Jenkinsfile Runner container
+// as reported in the pod status.
+func (r *podRun) GetContainerInfo() *corev1.ContainerState {
+	for _, containerStatus := range r.pod.Status.ContainerStatuses {
+		if containerStatus.Name == jenkinsfileRunnerContainerName {
+			state := containerStatus.State
+			return &state
+		}
+	}
+	return nil
+}
+
+// GetSidecarStates returns nil as the pod run backend does not support
+// sidecars.
+func (r *podRun) GetSidecarStates() []steward.SidecarState {
+	return nil
+}
+
+// IsFinished returns true if run is finished
+func (r *podRun) IsFinished() (bool, steward.Result) {
+	switch r.pod.Status.Phase {
+	case corev1.PodSucceeded:
+		return true, steward.ResultSuccess
+	case corev1.PodFailed:
+		if r.pod.Status.Reason == podReasonDeadlineExceeded {
+			return true, steward.ResultTimeout
+		}
+		containerState := r.GetContainerInfo()
+		if containerState != nil && containerState.Terminated != nil && containerState.Terminated.ExitCode != 0 {
+			return true, steward.ResultErrorContent
+		}
+		return true, steward.ResultErrorInfra
+	default:
+		return false, steward.ResultUndefined
+	}
+}
+
+// GetMessage returns the termination message
+func (r *podRun) GetMessage() string {
+	containerState := r.GetContainerInfo()
+	if containerState != nil && containerState.Terminated != nil && containerState.Terminated.Message != "" {
+		return containerState.Terminated.Message
+	}
+	if r.pod.Status.Message != "" {
+		return r.pod.Status.Message
+	}
+	return "internal error"
+}
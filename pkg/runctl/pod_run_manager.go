@@ -0,0 +1,195 @@
+package runctl
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	serrors "github.com/SAP/stewardci-core/pkg/errors"
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	secrets "github.com/SAP/stewardci-core/pkg/k8s/secrets"
+	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	runifc "github.com/SAP/stewardci-core/pkg/runctl/run"
+	slabels "github.com/SAP/stewardci-core/pkg/stewardlabels"
+	corev1api "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// jenkinsfileRunnerPodName is the name of the pod executing the
+	// Jenkinsfile Runner in each run namespace if the pod run backend
+	// is used.
+	jenkinsfileRunnerPodName = "steward-jenkinsfile-runner"
+
+	// jenkinsfileRunnerContainerName is the name of the container
+	// executing the Jenkinsfile Runner in the Jenkinsfile Runner pod.
+	jenkinsfileRunnerContainerName = "jenkinsfile-runner"
+
+	// jenkinsfileRunnerTerminationLogPath is the path of the termination
+	// log file of the Jenkinsfile Runner container.
+	jenkinsfileRunnerTerminationLogPath = "/dev/termination-log"
+)
+
+// podRunManager is a run manager executing the Jenkinsfile Runner as plain
+// Kubernetes pod. It does not require Tekton.
+// The run namespaces are prepared the same way as by the Tekton-based
+// run manager.
+type podRunManager struct {
+	*runManager
+}
+
+// newPodRunManager creates a new podRunManager.
+func newPodRunManager(factory k8s.ClientFactory, secretProvider secrets.SecretProvider) *podRunManager {
+	return &podRunManager{
+		runManager: newRunManager(factory, secretProvider),
+	}
+}
+
+// Start prepares the isolated environment for a new run and starts
+// the run in this environment.
+func (c *podRunManager) Start(ctx context.Context, pipelineRun k8s.PipelineRun, pipelineRunsConfig *cfg.PipelineRunsConfigStruct) (namespace string, auxNamespace string, err error) {
+	return c.start(ctx, pipelineRun, pipelineRunsConfig, c.createJenkinsfileRunnerPod)
+}
+
+// GetRun based on a pipelineRun
+func (c *podRunManager) GetRun(ctx context.Context, pipelineRun k8s.PipelineRun) (runifc.Run, error) {
+	namespace := pipelineRun.GetRunNamespace()
+	pod, err := c.factory.CoreV1().Pods(namespace).Get(ctx, jenkinsfileRunnerPodName, metav1.GetOptions{})
+	if err != nil {
+		return nil, getRunError(err)
+	}
+	return newPodRun(pod), nil
+}
+
+// Cancel stops the Jenkinsfile Runner pod of a pipelineRun by lowering its
+// active deadline to the time it is already running. The kubelet then
+// terminates the pod gracefully, but keeps it, so that the final state of
+// the Jenkinsfile Runner container can still be read.
+func (c *podRunManager) Cancel(ctx context.Context, pipelineRun k8s.PipelineRun) error {
+	namespace := pipelineRun.GetRunNamespace()
+	podIfce := c.factory.CoreV1().Pods(namespace)
+	pod, err := podIfce.Get(ctx, jenkinsfileRunnerPodName, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return getRunError(err)
+	}
+
+	// the active deadline may only be decreased and must be positive
+	var activeDeadlineSeconds int64 = 1
+	if startTime := pod.Status.StartTime; startTime != nil {
+		if elapsed := int64(time.Since(startTime.Time).Seconds()); elapsed > activeDeadlineSeconds {
+			activeDeadlineSeconds = elapsed
+		}
+	}
+	if current := pod.Spec.ActiveDeadlineSeconds; current != nil && *current <= activeDeadlineSeconds {
+		return nil
+	}
+	patch := fmt.Sprintf(`{"spec":{"activeDeadlineSeconds":%d}}`, activeDeadlineSeconds)
+	_, err = podIfce.Patch(ctx, jenkinsfileRunnerPodName, types.StrategicMergePatchType, []byte(patch), metav1.PatchOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	return getRunError(err)
+}
+
+func (c *podRunManager) createJenkinsfileRunnerPod(ctx context.Context, runCtx *runContext) error {
+	if c.testing != nil && c.testing.createJenkinsfileRunnerPodStub != nil {
+		return c.testing.createJenkinsfileRunnerPodStub(ctx, runCtx)
+	}
+
+	namespace := runCtx.runNamespace
+	serviceAccountSecretName, err := c.getServiceAccountSecretName(ctx, runCtx)
+	if err != nil {
+		return err
+	}
+
+	params, err := jenkinsfileRunnerParams(runCtx)
+	if err != nil {
+		return serrors.Classify(err, stewardv1alpha1.ResultErrorConfig)
+	}
+
+	image := params["JFR_IMAGE"]
+	imagePullPolicy := params["JFR_IMAGE_PULL_POLICY"]
+	delete(params, "JFR_IMAGE")
+	delete(params, "JFR_IMAGE_PULL_POLICY")
+	// set by the ClusterTask for the Tekton run backend
+	params["XDG_CONFIG_HOME"] = "/home/jenkins"
+	params["TERMINATION_LOG_PATH"] = jenkinsfileRunnerTerminationLogPath
+
+	timeout := defaultTimeout
+	if effective := effectiveTimeout(runCtx.pipelineRun.GetSpec(), runCtx.pipelineRunsConfig); effective != nil {
+		timeout = effective.Duration
+	}
+	activeDeadlineSeconds := int64(timeout.Seconds())
+
+	resources := corev1api.ResourceRequirements{}
+	if effective := effectiveJenkinsfileRunnerResources(runCtx.pipelineRun.GetSpec(), runCtx.pipelineRunsConfig); effective != nil {
+		resources = *effective
+	}
+
+	pod := &corev1api.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      jenkinsfileRunnerPodName,
+			Namespace: namespace,
+			Annotations: map[string]string{
+				annotationPipelineRunKey: runCtx.pipelineRun.GetKey(),
+			},
+		},
+		Spec: corev1api.PodSpec{
+			ServiceAccountName:    serviceAccountName,
+			RestartPolicy:         corev1api.RestartPolicyNever,
+			ActiveDeadlineSeconds: &activeDeadlineSeconds,
+			SecurityContext:       c.jenkinsfileRunnerPodSecurityContext(runCtx),
+			Volumes:               c.volumesWithServiceAccountSecret(serviceAccountSecretName),
+			Containers: []corev1api.Container{
+				{
+					Name:            jenkinsfileRunnerContainerName,
+					Image:           image,
+					ImagePullPolicy: corev1api.PullPolicy(imagePullPolicy),
+					Command:         []string{"/steward-interface/entrypoint"},
+					Env:             toEnvVars(params),
+					Resources:       resources,
+
+					TerminationMessagePath: jenkinsfileRunnerTerminationLogPath,
+					VolumeMounts: []corev1api.VolumeMount{
+						{
+							Name:      "service-account-token",
+							MountPath: "/var/run/secrets/kubernetes.io/serviceaccount",
+							ReadOnly:  true,
+						},
+					},
+				},
+			},
+		},
+	}
+	slabels.LabelAsSystemManaged(pod)
+
+	profile, err := schedulingProfile(runCtx.pipelineRun.GetSpec(), runCtx.pipelineRunsConfig)
+	if err != nil {
+		return serrors.Classify(err, stewardv1alpha1.ResultErrorConfig)
+	}
+	applySchedulingProfileToPodSpec(profile, &pod.Spec)
+
+	_, err = c.factory.CoreV1().Pods(namespace).Create(ctx, pod, metav1.CreateOptions{})
+	return err
+}
+
+func toEnvVars(values map[string]string) []corev1api.EnvVar {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	env := make([]corev1api.EnvVar, 0, len(names))
+	for _, name := range names {
+		env = append(env, corev1api.EnvVar{Name: name, Value: values[name]})
+	}
+	return env
+}
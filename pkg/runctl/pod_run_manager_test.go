@@ -0,0 +1,311 @@
+package runctl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	serrors "github.com/SAP/stewardci-core/pkg/errors"
+	k8sfake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	cfg "github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	gomock "github.com/golang/mock/gomock"
+	assert "gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	k8sresource "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test__podRunManager_Start__CreatesJenkinsfileRunnerPod(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	h := newTestHelper1(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockFactory, mockPipelineRun, mockSecretProvider := h.prepareMocks(mockCtrl)
+	h.preparePredefinedClusterRole(mockFactory, mockPipelineRun)
+	config := &cfg.PipelineRunsConfigStruct{}
+
+	examinee := newPodRunManager(mockFactory, mockSecretProvider)
+	examinee.testing = newRunManagerTestingWithRequiredStubs()
+
+	// EXERCISE
+	runNamespace, _, resultError := examinee.Start(h.ctx, mockPipelineRun, config)
+	assert.NilError(t, resultError)
+
+	// VERIFY
+	result, err := mockFactory.CoreV1().Pods(runNamespace).Get(
+		h.ctx, jenkinsfileRunnerPodName, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, "key", result.GetAnnotations()[annotationPipelineRunKey])
+	_, isSystemManaged := result.GetLabels()[stewardv1alpha1.LabelSystemManaged]
+	assert.Assert(t, isSystemManaged)
+
+	taskRuns, err := mockFactory.TektonV1beta1().TaskRuns(runNamespace).List(h.ctx, metav1.ListOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(taskRuns.Items))
+}
+
+func Test__podRunManager_createJenkinsfileRunnerPod(t *testing.T) {
+	t.Parallel()
+
+	int32Ptr := func(val int32) *int32 { return &val }
+	int64Ptr := func(val int64) *int64 { return &val }
+
+	// SETUP
+	const serviceAccountSecretName = "foo"
+
+	h := newTestHelper1(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	spec := &stewardv1alpha1.PipelineSpec{
+		JenkinsFile: stewardv1alpha1.JenkinsFile{
+			URL:      "https://github.com/foo/bar",
+			Revision: "main",
+			Path:     "Jenkinsfile",
+		},
+		RunDetails: &stewardv1alpha1.PipelineRunDetails{
+			JobName:        "job1",
+			SequenceNumber: 7,
+		},
+		Timeout: metav1Duration(15 * time.Minute),
+	}
+	_, mockPipelineRun, _ := h.prepareMocksWithSpec(mockCtrl, spec)
+	resources := &corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU: k8sresource.MustParse("3"),
+		},
+	}
+	runCtx := &runContext{
+		pipelineRun:  mockPipelineRun,
+		runNamespace: h.namespace1,
+		pipelineRunsConfig: &cfg.PipelineRunsConfigStruct{
+			Timeout:                                    metav1Duration(10 * time.Minute),
+			JenkinsfileRunnerImage:                     "jfrImage1",
+			JenkinsfileRunnerImagePullPolicy:           "Always",
+			JenkinsfileRunnerJavaOpts:                  "javaOpts1",
+			JenkinsfileRunnerResources:                 resources,
+			JenkinsfileRunnerPodSecurityContextFSGroup: int64Ptr(1111),
+			ElasticsearchIndexURL:                      "http://es.example.com/index1/_doc",
+		},
+	}
+	cf := k8sfake.NewClientFactory()
+
+	examinee := newPodRunManager(cf, nil)
+	examinee.testing = newRunManagerTestingWithAllNoopStubs()
+	examinee.testing.getServiceAccountSecretNameStub = func(context.Context, *runContext) (string, error) {
+		return serviceAccountSecretName, nil
+	}
+
+	// EXERCISE
+	resultError := examinee.createJenkinsfileRunnerPod(h.ctx, runCtx)
+
+	// VERIFY
+	assert.NilError(t, resultError)
+
+	pod, err := cf.CoreV1().Pods(h.namespace1).Get(h.ctx, jenkinsfileRunnerPodName, metav1.GetOptions{})
+	assert.NilError(t, err)
+
+	assert.Equal(t, corev1.RestartPolicyNever, pod.Spec.RestartPolicy)
+	assert.Equal(t, serviceAccountName, pod.Spec.ServiceAccountName)
+	assert.DeepEqual(t, int64Ptr(900), pod.Spec.ActiveDeadlineSeconds)
+	assert.DeepEqual(t, &corev1.PodSecurityContext{FSGroup: int64Ptr(1111)}, pod.Spec.SecurityContext)
+	assert.DeepEqual(t, []corev1.Volume{
+		{
+			Name: "service-account-token",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName:  serviceAccountSecretName,
+					DefaultMode: int32Ptr(0644),
+				},
+			},
+		},
+	}, pod.Spec.Volumes)
+
+	assert.Equal(t, 1, len(pod.Spec.Containers))
+	container := pod.Spec.Containers[0]
+	assert.Equal(t, jenkinsfileRunnerContainerName, container.Name)
+	assert.Equal(t, "jfrImage1", container.Image)
+	assert.Equal(t, corev1.PullAlways, container.ImagePullPolicy)
+	assert.Equal(t, jenkinsfileRunnerTerminationLogPath, container.TerminationMessagePath)
+	assert.DeepEqual(t, *resources, container.Resources)
+
+	env := map[string]string{}
+	for _, envVar := range container.Env {
+		env[envVar.Name] = envVar.Value
+	}
+	for name, expected := range map[string]string{
+		"RUN_NAMESPACE":                        h.namespace1,
+		"JAVA_OPTS":                            "javaOpts1",
+		"PIPELINE_GIT_URL":                     "https://github.com/foo/bar",
+		"PIPELINE_GIT_REVISION":                "main",
+		"PIPELINE_FILE":                        "Jenkinsfile",
+		"PIPELINE_PARAMS_JSON":                 "{}",
+		"PIPELINE_LOG_ELASTICSEARCH_INDEX_URL": "",
+		"JOB_NAME":                             "job1",
+		"RUN_NUMBER":                           "7",
+		"TERMINATION_LOG_PATH":                 jenkinsfileRunnerTerminationLogPath,
+	} {
+		actual, found := env[name]
+		assert.Assert(t, found, "env var %q is missing", name)
+		assert.Equal(t, expected, actual, "env var %q", name)
+	}
+	_, found := env["JFR_IMAGE"]
+	assert.Assert(t, !found)
+}
+
+func Test__podRunManager_createJenkinsfileRunnerPod__InvalidElasticsearchIndexURL(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	h := newTestHelper1(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	spec := &stewardv1alpha1.PipelineSpec{
+		Logging: &stewardv1alpha1.Logging{
+			Elasticsearch: &stewardv1alpha1.Elasticsearch{
+				IndexURL: "ftp://foo",
+			},
+		},
+	}
+	_, mockPipelineRun, _ := h.prepareMocksWithSpec(mockCtrl, spec)
+	runCtx := &runContext{
+		pipelineRun:        mockPipelineRun,
+		runNamespace:       h.namespace1,
+		pipelineRunsConfig: &cfg.PipelineRunsConfigStruct{},
+	}
+	cf := k8sfake.NewClientFactory()
+
+	examinee := newPodRunManager(cf, nil)
+	examinee.testing = newRunManagerTestingWithAllNoopStubs()
+
+	// EXERCISE
+	resultError := examinee.createJenkinsfileRunnerPod(h.ctx, runCtx)
+
+	// VERIFY
+	assert.Assert(t, resultError != nil)
+	assert.Equal(t, stewardv1alpha1.ResultErrorConfig, serrors.GetClass(resultError))
+}
+
+func Test__podRunManager_GetRun(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	h := newTestHelper1(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	_, mockPipelineRun, _ := h.prepareMocks(mockCtrl)
+	mockPipelineRun.UpdateRunNamespace(h.namespace1)
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      jenkinsfileRunnerPodName,
+			Namespace: h.namespace1,
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodSucceeded,
+		},
+	}
+	cf := k8sfake.NewClientFactory(pod)
+	examinee := newPodRunManager(cf, nil)
+
+	// EXERCISE
+	run, resultErr := examinee.GetRun(h.ctx, mockPipelineRun)
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	finished, result := run.IsFinished()
+	assert.Assert(t, finished)
+	assert.Equal(t, stewardv1alpha1.ResultSuccess, result)
+}
+
+func Test__podRunManager_GetRun__NotFound(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	h := newTestHelper1(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	_, mockPipelineRun, _ := h.prepareMocks(mockCtrl)
+	mockPipelineRun.UpdateRunNamespace(h.namespace1)
+	examinee := newPodRunManager(k8sfake.NewClientFactory(), nil)
+
+	// EXERCISE
+	run, resultErr := examinee.GetRun(h.ctx, mockPipelineRun)
+
+	// VERIFY
+	assert.Assert(t, run == nil)
+	assert.Assert(t, resultErr != nil)
+	assert.Assert(t, !serrors.IsRecoverable(resultErr))
+}
+
+func Test__podRunManager_Cancel(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name                  string
+		startedBefore         time.Duration
+		activeDeadlineSeconds int64
+		expectedSeconds       int64
+	}{
+		{"not_started", 0, 3600, 1},
+		{"running", 10 * time.Minute, 3600, 600},
+		{"deadline_not_increased", 10 * time.Minute, 300, 300},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			h := newTestHelper1(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			_, mockPipelineRun, _ := h.prepareMocks(mockCtrl)
+			mockPipelineRun.UpdateRunNamespace(h.namespace1)
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      jenkinsfileRunnerPodName,
+					Namespace: h.namespace1,
+				},
+				Spec: corev1.PodSpec{
+					ActiveDeadlineSeconds: &tc.activeDeadlineSeconds,
+				},
+			}
+			if tc.startedBefore > 0 {
+				startTime := metav1.NewTime(time.Now().Add(-tc.startedBefore))
+				pod.Status.StartTime = &startTime
+			}
+			cf := k8sfake.NewClientFactory(pod)
+			examinee := newPodRunManager(cf, nil)
+
+			// EXERCISE
+			resultErr := examinee.Cancel(h.ctx, mockPipelineRun)
+
+			// VERIFY
+			assert.NilError(t, resultErr)
+			result, err := cf.CoreV1().Pods(h.namespace1).Get(h.ctx, jenkinsfileRunnerPodName, metav1.GetOptions{})
+			assert.NilError(t, err)
+			// allow for a slow test execution
+			assert.Assert(t, *result.Spec.ActiveDeadlineSeconds >= tc.expectedSeconds)
+			assert.Assert(t, *result.Spec.ActiveDeadlineSeconds <= tc.expectedSeconds+5)
+		})
+	}
+}
+
+func Test__podRunManager_Cancel__NotFound(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	h := newTestHelper1(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	_, mockPipelineRun, _ := h.prepareMocks(mockCtrl)
+	mockPipelineRun.UpdateRunNamespace(h.namespace1)
+	examinee := newPodRunManager(k8sfake.NewClientFactory(), nil)
+
+	// EXERCISE
+	resultErr := examinee.Cancel(h.ctx, mockPipelineRun)
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+}
@@ -0,0 +1,148 @@
+package runctl
+
+import (
+	"testing"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newJenkinsfileRunnerPod(phase corev1.PodPhase, reason string, state *corev1.ContainerState) *corev1.Pod {
+	pod := &corev1.Pod{
+		Status: corev1.PodStatus{
+			Phase:  phase,
+			Reason: reason,
+		},
+	}
+	if state != nil {
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+			{Name: "some-other-container"},
+			{Name: jenkinsfileRunnerContainerName, State: *state},
+		}
+	}
+	return pod
+}
+
+func Test__podRun_GetStartTime(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	startTime := metav1.NewTime(time.Date(2022, 3, 4, 5, 6, 7, 0, time.UTC))
+	pod := newJenkinsfileRunnerPod(corev1.PodPending, "", nil)
+	examinee := newPodRun(pod)
+
+	// EXERCISE & VERIFY
+	assert.Assert(t, examinee.GetStartTime() == nil)
+
+	pod.Status.StartTime = &startTime
+	assert.DeepEqual(t, &startTime, examinee.GetStartTime())
+}
+
+func Test__podRun_IsFinished(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name             string
+		phase            corev1.PodPhase
+		reason           string
+		state            *corev1.ContainerState
+		expectedFinished bool
+		expectedResult   api.Result
+	}{
+		{"pending", corev1.PodPending, "", nil, false, api.ResultUndefined},
+		{"running", corev1.PodRunning, "", &corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}, false, api.ResultUndefined},
+		{"succeeded", corev1.PodSucceeded, "", &corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0}}, true, api.ResultSuccess},
+		{"deadline_exceeded", corev1.PodFailed, podReasonDeadlineExceeded, &corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 137}}, true, api.ResultTimeout},
+		{"failed_with_exit_code", corev1.PodFailed, "", &corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1}}, true, api.ResultErrorContent},
+		{"failed_without_container", corev1.PodFailed, "Evicted", nil, true, api.ResultErrorInfra},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			examinee := newPodRun(newJenkinsfileRunnerPod(tc.phase, tc.reason, tc.state))
+
+			// EXERCISE
+			finished, result := examinee.IsFinished()
+
+			// VERIFY
+			assert.Equal(t, tc.expectedFinished, finished)
+			assert.Equal(t, tc.expectedResult, result)
+		})
+	}
+}
+
+func Test__podRun_GetContainerInfo(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	state := &corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}
+
+	// EXERCISE & VERIFY
+	assert.Assert(t, newPodRun(newJenkinsfileRunnerPod(corev1.PodPending, "", nil)).GetContainerInfo() == nil)
+	assert.DeepEqual(t, state, newPodRun(newJenkinsfileRunnerPod(corev1.PodRunning, "", state)).GetContainerInfo())
+}
+
+func Test__podRun_GetCompletionTime(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	finishedAt := metav1.NewTime(time.Date(2022, 3, 4, 5, 6, 7, 0, time.UTC))
+	state := &corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{FinishedAt: finishedAt}}
+	examinee := newPodRun(newJenkinsfileRunnerPod(corev1.PodSucceeded, "", state))
+
+	// EXERCISE
+	result := examinee.GetCompletionTime()
+
+	// VERIFY
+	assert.DeepEqual(t, &finishedAt, result)
+}
+
+func Test__podRun_GetCompletionTime_FallsBackToNow(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	before := metav1.Now().Rfc3339Copy()
+	examinee := newPodRun(newJenkinsfileRunnerPod(corev1.PodFailed, "", nil))
+
+	// EXERCISE
+	result := examinee.GetCompletionTime()
+
+	// VERIFY
+	assert.Assert(t, !result.Before(&before))
+}
+
+func Test__podRun_GetMessage(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name            string
+		podMessage      string
+		state           *corev1.ContainerState
+		expectedMessage string
+	}{
+		{"termination_message", "podMessage1", &corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Message: "message1"}}, "message1"},
+		{"pod_message", "podMessage1", &corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{}}, "podMessage1"},
+		{"no_message", "", nil, "internal error"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			pod := newJenkinsfileRunnerPod(corev1.PodFailed, "", tc.state)
+			pod.Status.Message = tc.podMessage
+			examinee := newPodRun(pod)
+
+			// EXERCISE
+			result := examinee.GetMessage()
+
+			// VERIFY
+			assert.Equal(t, tc.expectedMessage, result)
+		})
+	}
+}
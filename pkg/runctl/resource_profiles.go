@@ -0,0 +1,85 @@
+package runctl
+
+import (
+	"fmt"
+	"strings"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	serrors "github.com/SAP/stewardci-core/pkg/errors"
+	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// resourceProfileName returns the name of the resource profile selected in
+// the pipeline run spec or the name of the default resource profile of the
+// pipeline runs configuration. Returns an empty string if neither is set.
+func resourceProfileName(spec *stewardv1alpha1.PipelineSpec, pipelineRunsConfig *cfg.PipelineRunsConfigStruct) string {
+	if spec.Profiles != nil && spec.Profiles.Resources != "" {
+		return spec.Profiles.Resources
+	}
+	if pipelineRunsConfig == nil {
+		return ""
+	}
+	return pipelineRunsConfig.DefaultResourceProfile
+}
+
+// resourceProfile returns the resource profile selected in the pipeline run
+// spec or the default resource profile of the pipeline runs configuration.
+// Returns `nil` if neither is set.
+func resourceProfile(spec *stewardv1alpha1.PipelineSpec, pipelineRunsConfig *cfg.PipelineRunsConfigStruct) (*cfg.ResourceProfile, error) {
+	name := resourceProfileName(spec, pipelineRunsConfig)
+	if name == "" {
+		return nil, nil
+	}
+	profile, exists := pipelineRunsConfig.ResourceProfiles[name]
+	if !exists {
+		return nil, fmt.Errorf("resource profile %q does not exist", name)
+	}
+	return profile, nil
+}
+
+// effectiveLimitRange returns the manifest of the limit range to be applied
+// to the sandbox namespace of a pipeline run.
+func effectiveLimitRange(profile *cfg.ResourceProfile, pipelineRunsConfig *cfg.PipelineRunsConfigStruct) string {
+	if profile != nil && profile.LimitRange != "" {
+		return profile.LimitRange
+	}
+	return pipelineRunsConfig.LimitRange
+}
+
+// effectiveResourceQuota returns the manifest of the resource quota to be
+// applied to the sandbox namespace of a pipeline run.
+func effectiveResourceQuota(profile *cfg.ResourceProfile, pipelineRunsConfig *cfg.PipelineRunsConfigStruct) string {
+	if profile != nil && profile.ResourceQuota != "" {
+		return profile.ResourceQuota
+	}
+	return pipelineRunsConfig.ResourceQuota
+}
+
+// isResourceProfileAllowed returns whether the given resource profile is
+// contained in the comma-separated list of allowed resource profiles.
+func isResourceProfileAllowed(name string, allowedProfiles string) bool {
+	for _, allowed := range strings.Split(allowedProfiles, ",") {
+		if strings.TrimSpace(allowed) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// checkResourceProfileAllowed returns an error classified as configuration
+// error if the given client namespace the tenant of the given pipeline run
+// belongs to restricts the resource profiles and the one selected by the
+// pipeline run is not among them. `clientNamespace` may be `nil`.
+func checkResourceProfileAllowed(pipelineRun *stewardv1alpha1.PipelineRun, clientNamespace *corev1.Namespace, pipelineRunsConfig *cfg.PipelineRunsConfigStruct) error {
+	name := resourceProfileName(&pipelineRun.Spec, pipelineRunsConfig)
+	if name == "" || clientNamespace == nil {
+		return nil
+	}
+	allowedProfiles, restricted := clientNamespace.GetAnnotations()[stewardv1alpha1.AnnotationAllowedResourceProfiles]
+	if restricted && !isResourceProfileAllowed(name, allowedProfiles) {
+		err := fmt.Errorf("resource profile %q is not allowed for client namespace %q", name, clientNamespace.GetName())
+		return serrors.Classify(err, stewardv1alpha1.ResultErrorConfig)
+	}
+	return nil
+}
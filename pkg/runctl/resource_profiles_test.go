@@ -0,0 +1,147 @@
+package runctl
+
+import (
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	serrors "github.com/SAP/stewardci-core/pkg/errors"
+	"github.com/SAP/stewardci-core/pkg/k8s/fake"
+	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	assert "gotest.tools/assert"
+)
+
+func Test_resourceProfile(t *testing.T) {
+	t.Parallel()
+
+	small := &cfg.ResourceProfile{LimitRange: "limitRange1"}
+	large := &cfg.ResourceProfile{ResourceQuota: "quota1"}
+
+	for _, tc := range []struct {
+		name           string
+		profiles       *api.Profiles
+		defaultProfile string
+		expected       *cfg.ResourceProfile
+		expectedError  string
+	}{
+		{"none", nil, "", nil, ""},
+		{"default", nil, "small", small, ""},
+		{"selected", &api.Profiles{Resources: "large"}, "", large, ""},
+		{"selected_overrides_default", &api.Profiles{Resources: "large"}, "small", large, ""},
+		{"unknown", &api.Profiles{Resources: "unknown"}, "small", nil, `resource profile "unknown" does not exist`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			spec := &api.PipelineSpec{Profiles: tc.profiles}
+			config := &cfg.PipelineRunsConfigStruct{
+				DefaultResourceProfile: tc.defaultProfile,
+				ResourceProfiles: map[string]*cfg.ResourceProfile{
+					"small": small,
+					"large": large,
+				},
+			}
+
+			// EXERCISE
+			result, resultErr := resourceProfile(spec, config)
+
+			// VERIFY
+			if tc.expectedError == "" {
+				assert.NilError(t, resultErr)
+			} else {
+				assert.Error(t, resultErr, tc.expectedError)
+			}
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func Test_effectiveLimitRangeAndResourceQuota(t *testing.T) {
+	t.Parallel()
+
+	config := &cfg.PipelineRunsConfigStruct{
+		LimitRange:    "limitRange1",
+		ResourceQuota: "quota1",
+	}
+
+	for _, tc := range []struct {
+		name               string
+		profile            *cfg.ResourceProfile
+		expectedLimitRange string
+		expectedQuota      string
+	}{
+		{"no_profile", nil, "limitRange1", "quota1"},
+		{"empty_profile", &cfg.ResourceProfile{}, "limitRange1", "quota1"},
+		{"profile_limit_range", &cfg.ResourceProfile{LimitRange: "limitRange2"}, "limitRange2", "quota1"},
+		{"profile_quota", &cfg.ResourceProfile{ResourceQuota: "quota2"}, "limitRange1", "quota2"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// EXERCISE
+			resultLimitRange := effectiveLimitRange(tc.profile, config)
+			resultQuota := effectiveResourceQuota(tc.profile, config)
+
+			// VERIFY
+			assert.Equal(t, tc.expectedLimitRange, resultLimitRange)
+			assert.Equal(t, tc.expectedQuota, resultQuota)
+		})
+	}
+}
+
+func Test_Controller_checkResourceProfileAllowed(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name           string
+		profiles       *api.Profiles
+		defaultProfile string
+		annotations    map[string]string
+		expectedError  string
+	}{
+		{"no_profile", nil, "", map[string]string{api.AnnotationAllowedResourceProfiles: ""}, ""},
+		{"not_restricted", &api.Profiles{Resources: "large"}, "", nil, ""},
+		{"allowed", &api.Profiles{Resources: "large"}, "", map[string]string{api.AnnotationAllowedResourceProfiles: "small, large"}, ""},
+		{"default_not_allowed", nil, "small", map[string]string{api.AnnotationAllowedResourceProfiles: "large"}, `resource profile "small" is not allowed for client namespace "client1"`},
+		{"not_allowed", &api.Profiles{Resources: "large"}, "", map[string]string{api.AnnotationAllowedResourceProfiles: "small"}, `resource profile "large" is not allowed for client namespace "client1"`},
+		{"none_allowed", &api.Profiles{Resources: "small"}, "", map[string]string{api.AnnotationAllowedResourceProfiles: ""}, `resource profile "small" is not allowed for client namespace "client1"`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			clientNamespace := fake.NamespaceWithAnnotations("client1", tc.annotations)
+			pipelineRun := fake.PipelineRun("run1", "tenant1", api.PipelineSpec{Profiles: tc.profiles})
+			config := &cfg.PipelineRunsConfigStruct{DefaultResourceProfile: tc.defaultProfile}
+
+			// EXERCISE
+			resultErr := checkResourceProfileAllowed(pipelineRun, clientNamespace, config)
+
+			// VERIFY
+			if tc.expectedError == "" {
+				assert.NilError(t, resultErr)
+			} else {
+				assert.Error(t, resultErr, tc.expectedError)
+				assert.Equal(t, api.ResultErrorConfig, serrors.GetClass(resultErr))
+			}
+		})
+	}
+}
+
+func Test_Controller_checkResourceProfileAllowed_NoClientNamespace(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	pipelineRun := fake.PipelineRun("run1", "tenant1", api.PipelineSpec{
+		Profiles: &api.Profiles{Resources: "large"},
+	})
+
+	// EXERCISE
+	resultErr := checkResourceProfileAllowed(pipelineRun, nil, &cfg.PipelineRunsConfigStruct{})
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+}
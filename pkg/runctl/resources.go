@@ -0,0 +1,125 @@
+package runctl
+
+import (
+	"fmt"
+	"sort"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	corev1api "k8s.io/api/core/v1"
+)
+
+// requestedJenkinsfileRunnerResources returns the resource requirements of
+// the Jenkinsfile Runner container requested in the pipeline run spec or
+// `nil` if there are none.
+func requestedJenkinsfileRunnerResources(spec *stewardv1alpha1.PipelineSpec) *corev1api.ResourceRequirements {
+	if spec.JenkinsfileRunner == nil {
+		return nil
+	}
+	return spec.JenkinsfileRunner.Resources
+}
+
+// effectiveJenkinsfileRunnerResources returns the resource requirements of
+// the Jenkinsfile Runner container. The requests and limits from the
+// pipeline run spec take precedence over the ones from the selected
+// resource profile or, if the profile does not define any, the pipeline
+// runs configuration per resource.
+// Returns `nil` if none of them defines resource requirements.
+func effectiveJenkinsfileRunnerResources(spec *stewardv1alpha1.PipelineSpec, pipelineRunsConfig *cfg.PipelineRunsConfigStruct) *corev1api.ResourceRequirements {
+	requested := requestedJenkinsfileRunnerResources(spec)
+	var result *corev1api.ResourceRequirements
+	if base := baseJenkinsfileRunnerResources(spec, pipelineRunsConfig); base != nil {
+		result = base.DeepCopy()
+	}
+	if requested == nil {
+		return result
+	}
+	if result == nil {
+		result = &corev1api.ResourceRequirements{}
+	}
+	result.Limits = mergeResourceLists(result.Limits, requested.Limits)
+	result.Requests = mergeResourceLists(result.Requests, requested.Requests)
+	return result
+}
+
+// baseJenkinsfileRunnerResources returns the resource requirements of the
+// Jenkinsfile Runner container defined by the selected resource profile or,
+// if the profile does not define any, by the pipeline runs configuration.
+// A resource profile which does not exist is ignored here, as it gets
+// rejected by the validation anyway.
+func baseJenkinsfileRunnerResources(spec *stewardv1alpha1.PipelineSpec, pipelineRunsConfig *cfg.PipelineRunsConfigStruct) *corev1api.ResourceRequirements {
+	if pipelineRunsConfig == nil {
+		return nil
+	}
+	if profile, _ := resourceProfile(spec, pipelineRunsConfig); profile != nil && profile.JenkinsfileRunnerResources != nil {
+		return profile.JenkinsfileRunnerResources
+	}
+	return pipelineRunsConfig.JenkinsfileRunnerResources
+}
+
+func mergeResourceLists(base, overrides corev1api.ResourceList) corev1api.ResourceList {
+	if len(overrides) == 0 {
+		return base
+	}
+	if base == nil {
+		base = corev1api.ResourceList{}
+	}
+	for name, quantity := range overrides {
+		base[name] = quantity.DeepCopy()
+	}
+	return base
+}
+
+// validateJenkinsfileRunnerResources checks the resource requirements of
+// the Jenkinsfile Runner container requested in the pipeline run spec.
+// If `pipelineRunsConfig` is `nil`, the bounds of the configuration are not
+// checked.
+func validateJenkinsfileRunnerResources(spec *stewardv1alpha1.PipelineSpec, pipelineRunsConfig *cfg.PipelineRunsConfigStruct) error {
+	requested := requestedJenkinsfileRunnerResources(spec)
+	if requested == nil {
+		return nil
+	}
+
+	for _, list := range []struct {
+		field     string
+		resources corev1api.ResourceList
+	}{
+		{"requests", requested.Requests},
+		{"limits", requested.Limits},
+	} {
+		for _, name := range sortedResourceNames(list.resources) {
+			quantity := list.resources[name]
+			fieldName := fmt.Sprintf("spec.jenkinsfileRunner.resources.%s.%s", list.field, name)
+			if quantity.Sign() < 0 {
+				return fmt.Errorf("field %q has invalid value %q: must not be negative", fieldName, quantity.String())
+			}
+			if pipelineRunsConfig == nil {
+				continue
+			}
+			if min, ok := pipelineRunsConfig.JenkinsfileRunnerResourcesMin[name]; ok && quantity.Cmp(min) < 0 {
+				return fmt.Errorf("field %q has invalid value %q: below the minimum %s", fieldName, quantity.String(), min.String())
+			}
+			if max, ok := pipelineRunsConfig.JenkinsfileRunnerResourcesMax[name]; ok && quantity.Cmp(max) > 0 {
+				return fmt.Errorf("field %q has invalid value %q: exceeds the maximum %s", fieldName, quantity.String(), max.String())
+			}
+		}
+	}
+
+	effective := effectiveJenkinsfileRunnerResources(spec, pipelineRunsConfig)
+	for _, name := range sortedResourceNames(effective.Requests) {
+		request := effective.Requests[name]
+		if limit, ok := effective.Limits[name]; ok && request.Cmp(limit) > 0 {
+			return fmt.Errorf("resource requirements of the Jenkinsfile Runner are invalid: request %s of resource %q exceeds the limit %s", request.String(), name, limit.String())
+		}
+	}
+	return nil
+}
+
+func sortedResourceNames(resources corev1api.ResourceList) []corev1api.ResourceName {
+	names := make([]corev1api.ResourceName, 0, len(resources))
+	for name := range resources {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
@@ -0,0 +1,222 @@
+package runctl
+
+import (
+	"testing"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	assert "gotest.tools/assert"
+	corev1api "k8s.io/api/core/v1"
+	k8sresource "k8s.io/apimachinery/pkg/api/resource"
+)
+
+func Test_effectiveJenkinsfileRunnerResources(t *testing.T) {
+	t.Parallel()
+
+	configResources := &corev1api.ResourceRequirements{
+		Limits: corev1api.ResourceList{
+			corev1api.ResourceCPU:    k8sresource.MustParse("3"),
+			corev1api.ResourceMemory: k8sresource.MustParse("2Gi"),
+		},
+		Requests: corev1api.ResourceList{
+			corev1api.ResourceCPU: k8sresource.MustParse("500m"),
+		},
+	}
+
+	for _, tc := range []struct {
+		name      string
+		requested *corev1api.ResourceRequirements
+		config    *cfg.PipelineRunsConfigStruct
+		expected  *corev1api.ResourceRequirements
+	}{
+		{
+			name:     "nothing_set",
+			config:   &cfg.PipelineRunsConfigStruct{},
+			expected: nil,
+		},
+		{
+			name:     "config_only",
+			config:   &cfg.PipelineRunsConfigStruct{JenkinsfileRunnerResources: configResources},
+			expected: configResources,
+		},
+		{
+			name: "spec_only",
+			requested: &corev1api.ResourceRequirements{
+				Limits: corev1api.ResourceList{corev1api.ResourceMemory: k8sresource.MustParse("8Gi")},
+			},
+			config: nil,
+			expected: &corev1api.ResourceRequirements{
+				Limits: corev1api.ResourceList{corev1api.ResourceMemory: k8sresource.MustParse("8Gi")},
+			},
+		},
+		{
+			name: "merged",
+			requested: &corev1api.ResourceRequirements{
+				Limits:   corev1api.ResourceList{corev1api.ResourceMemory: k8sresource.MustParse("8Gi")},
+				Requests: corev1api.ResourceList{corev1api.ResourceMemory: k8sresource.MustParse("4Gi")},
+			},
+			config: &cfg.PipelineRunsConfigStruct{JenkinsfileRunnerResources: configResources},
+			expected: &corev1api.ResourceRequirements{
+				Limits: corev1api.ResourceList{
+					corev1api.ResourceCPU:    k8sresource.MustParse("3"),
+					corev1api.ResourceMemory: k8sresource.MustParse("8Gi"),
+				},
+				Requests: corev1api.ResourceList{
+					corev1api.ResourceCPU:    k8sresource.MustParse("500m"),
+					corev1api.ResourceMemory: k8sresource.MustParse("4Gi"),
+				},
+			},
+		},
+		{
+			name: "resource_profile",
+			config: &cfg.PipelineRunsConfigStruct{
+				JenkinsfileRunnerResources: configResources,
+				DefaultResourceProfile:     "large",
+				ResourceProfiles: map[string]*cfg.ResourceProfile{
+					"large": {JenkinsfileRunnerResources: &corev1api.ResourceRequirements{
+						Limits: corev1api.ResourceList{corev1api.ResourceMemory: k8sresource.MustParse("16Gi")},
+					}},
+				},
+			},
+			expected: &corev1api.ResourceRequirements{
+				Limits: corev1api.ResourceList{corev1api.ResourceMemory: k8sresource.MustParse("16Gi")},
+			},
+		},
+		{
+			name: "resource_profile_without_resources",
+			config: &cfg.PipelineRunsConfigStruct{
+				JenkinsfileRunnerResources: configResources,
+				DefaultResourceProfile:     "large",
+				ResourceProfiles: map[string]*cfg.ResourceProfile{
+					"large": {LimitRange: "limitRange1"},
+				},
+			},
+			expected: configResources,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			spec := &stewardv1alpha1.PipelineSpec{}
+			if tc.requested != nil {
+				spec.JenkinsfileRunner = &stewardv1alpha1.JenkinsfileRunnerSpec{Resources: tc.requested}
+			}
+
+			// EXERCISE
+			result := effectiveJenkinsfileRunnerResources(spec, tc.config)
+
+			// VERIFY
+			assert.DeepEqual(t, tc.expected, result)
+		})
+	}
+
+	// configuration must not be modified
+	assert.Equal(t, 2, len(configResources.Limits))
+	assert.Equal(t, 1, len(configResources.Requests))
+}
+
+func Test_validateJenkinsfileRunnerResources(t *testing.T) {
+	t.Parallel()
+
+	config := &cfg.PipelineRunsConfigStruct{
+		JenkinsfileRunnerResources: &corev1api.ResourceRequirements{
+			Limits: corev1api.ResourceList{corev1api.ResourceMemory: k8sresource.MustParse("2Gi")},
+		},
+		JenkinsfileRunnerResourcesMin: corev1api.ResourceList{
+			corev1api.ResourceMemory: k8sresource.MustParse("512Mi"),
+		},
+		JenkinsfileRunnerResourcesMax: corev1api.ResourceList{
+			corev1api.ResourceCPU:    k8sresource.MustParse("4"),
+			corev1api.ResourceMemory: k8sresource.MustParse("16Gi"),
+		},
+	}
+
+	for _, tc := range []struct {
+		name          string
+		requested     *corev1api.ResourceRequirements
+		config        *cfg.PipelineRunsConfigStruct
+		expectedError string
+	}{
+		{
+			name:   "none",
+			config: config,
+		},
+		{
+			name: "within_bounds",
+			requested: &corev1api.ResourceRequirements{
+				Limits:   corev1api.ResourceList{corev1api.ResourceCPU: k8sresource.MustParse("4")},
+				Requests: corev1api.ResourceList{corev1api.ResourceMemory: k8sresource.MustParse("1Gi")},
+			},
+			config: config,
+		},
+		{
+			name: "unbounded_resource",
+			requested: &corev1api.ResourceRequirements{
+				Limits: corev1api.ResourceList{corev1api.ResourceEphemeralStorage: k8sresource.MustParse("100Gi")},
+			},
+			config: config,
+		},
+		{
+			name: "negative",
+			requested: &corev1api.ResourceRequirements{
+				Requests: corev1api.ResourceList{corev1api.ResourceCPU: k8sresource.MustParse("-1")},
+			},
+			config:        nil,
+			expectedError: `field "spec.jenkinsfileRunner.resources.requests.cpu" has invalid value "-1": must not be negative`,
+		},
+		{
+			name: "below_minimum",
+			requested: &corev1api.ResourceRequirements{
+				Requests: corev1api.ResourceList{corev1api.ResourceMemory: k8sresource.MustParse("256Mi")},
+			},
+			config:        config,
+			expectedError: `field "spec.jenkinsfileRunner.resources.requests.memory" has invalid value "256Mi": below the minimum 512Mi`,
+		},
+		{
+			name: "above_maximum",
+			requested: &corev1api.ResourceRequirements{
+				Limits: corev1api.ResourceList{corev1api.ResourceMemory: k8sresource.MustParse("32Gi")},
+			},
+			config:        config,
+			expectedError: `field "spec.jenkinsfileRunner.resources.limits.memory" has invalid value "32Gi": exceeds the maximum 16Gi`,
+		},
+		{
+			name: "no_config",
+			requested: &corev1api.ResourceRequirements{
+				Limits: corev1api.ResourceList{corev1api.ResourceMemory: k8sresource.MustParse("32Gi")},
+			},
+			config: nil,
+		},
+		{
+			name: "request_exceeds_configured_limit",
+			requested: &corev1api.ResourceRequirements{
+				Requests: corev1api.ResourceList{corev1api.ResourceMemory: k8sresource.MustParse("4Gi")},
+			},
+			config:        config,
+			expectedError: `resource requirements of the Jenkinsfile Runner are invalid: request 4Gi of resource "memory" exceeds the limit 2Gi`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			spec := &stewardv1alpha1.PipelineSpec{}
+			if tc.requested != nil {
+				spec.JenkinsfileRunner = &stewardv1alpha1.JenkinsfileRunnerSpec{Resources: tc.requested}
+			}
+
+			// EXERCISE
+			resultErr := validateJenkinsfileRunnerResources(spec, tc.config)
+
+			// VERIFY
+			if tc.expectedError == "" {
+				assert.NilError(t, resultErr)
+			} else {
+				assert.Error(t, resultErr, tc.expectedError)
+			}
+		})
+	}
+}
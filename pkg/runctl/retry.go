@@ -0,0 +1,77 @@
+package runctl
+
+import (
+	"fmt"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+)
+
+// retryableResults are the results which may be retried by a retry policy.
+// Content and configuration errors are never retried as another attempt
+// would fail the same way.
+var retryableResults = map[api.Result]bool{
+	api.ResultErrorInfra: true,
+	api.ResultTimeout:    true,
+}
+
+// defaultRetryOn are the results retried if the retry policy does not
+// define them explicitly.
+var defaultRetryOn = []api.Result{api.ResultErrorInfra}
+
+// validateRetryPolicy checks the retry policy of the given pipeline run
+// spec.
+func validateRetryPolicy(spec *api.PipelineSpec) error {
+	policy := spec.RetryPolicy
+	if policy == nil {
+		return nil
+	}
+	if policy.MaxAttempts < 0 {
+		return fmt.Errorf("retry policy: maxAttempts %d is invalid: must not be negative", policy.MaxAttempts)
+	}
+	if policy.Backoff != nil && policy.Backoff.Duration < 0 {
+		return fmt.Errorf("retry policy: backoff %s is invalid: must not be negative", policy.Backoff.Duration)
+	}
+	for _, result := range policy.RetryOn {
+		if !retryableResults[result] {
+			return fmt.Errorf("retry policy: result %q cannot be retried", result)
+		}
+	}
+	return nil
+}
+
+// shouldRetry returns whether the current attempt of the given pipeline run
+// finished with the given result should be followed by another attempt.
+func shouldRetry(spec *api.PipelineSpec, status *api.PipelineStatus) bool {
+	policy := spec.RetryPolicy
+	if policy == nil || spec.Intent == api.IntentAbort {
+		return false
+	}
+	if int(policy.MaxAttempts) <= len(status.Attempts)+1 {
+		return false
+	}
+	retryOn := policy.RetryOn
+	if len(retryOn) == 0 {
+		retryOn = defaultRetryOn
+	}
+	for _, result := range retryOn {
+		if result == status.Result && retryableResults[result] {
+			return true
+		}
+	}
+	return false
+}
+
+// retryBackoffRemaining returns the time to wait before the next attempt
+// of the given pipeline run can be started. It is zero or negative if the
+// next attempt can be started immediately.
+func retryBackoffRemaining(spec *api.PipelineSpec, status *api.PipelineStatus, now time.Time) time.Duration {
+	if spec.RetryPolicy == nil || spec.RetryPolicy.Backoff == nil || len(status.Attempts) == 0 {
+		return 0
+	}
+	lastAttempt := status.Attempts[len(status.Attempts)-1]
+	if lastAttempt.FinishedAt == nil {
+		return 0
+	}
+	return lastAttempt.FinishedAt.Add(spec.RetryPolicy.Backoff.Duration).Sub(now)
+}
@@ -0,0 +1,125 @@
+package runctl
+
+import (
+	"testing"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	assert "gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_validateRetryPolicy(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		policy        *api.RetryPolicy
+		expectedError string
+	}{
+		{"not_set", nil, ""},
+		{"empty", &api.RetryPolicy{}, ""},
+		{"valid", &api.RetryPolicy{MaxAttempts: 3, Backoff: metav1Duration(time.Minute), RetryOn: []api.Result{api.ResultErrorInfra, api.ResultTimeout}}, ""},
+		{"negative_max_attempts", &api.RetryPolicy{MaxAttempts: -1}, "retry policy: maxAttempts -1 is invalid: must not be negative"},
+		{"negative_backoff", &api.RetryPolicy{Backoff: metav1Duration(-time.Second)}, "retry policy: backoff -1s is invalid: must not be negative"},
+		{"content_error", &api.RetryPolicy{RetryOn: []api.Result{api.ResultErrorContent}}, `retry policy: result "error_content" cannot be retried`},
+		{"config_error", &api.RetryPolicy{RetryOn: []api.Result{api.ResultErrorInfra, api.ResultErrorConfig}}, `retry policy: result "error_config" cannot be retried`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			spec := &api.PipelineSpec{RetryPolicy: tc.policy}
+
+			// EXERCISE
+			resultErr := validateRetryPolicy(spec)
+
+			// VERIFY
+			if tc.expectedError == "" {
+				assert.NilError(t, resultErr)
+			} else {
+				assert.Error(t, resultErr, tc.expectedError)
+			}
+		})
+	}
+}
+
+func Test_shouldRetry(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     string
+		policy   *api.RetryPolicy
+		intent   api.Intent
+		attempts int
+		result   api.Result
+		expected bool
+	}{
+		{"no_policy", nil, "", 0, api.ResultErrorInfra, false},
+		{"infra_error_default", &api.RetryPolicy{MaxAttempts: 2}, "", 0, api.ResultErrorInfra, true},
+		{"timeout_default", &api.RetryPolicy{MaxAttempts: 2}, "", 0, api.ResultTimeout, false},
+		{"timeout_explicit", &api.RetryPolicy{MaxAttempts: 2, RetryOn: []api.Result{api.ResultTimeout}}, "", 0, api.ResultTimeout, true},
+		{"infra_error_not_listed", &api.RetryPolicy{MaxAttempts: 2, RetryOn: []api.Result{api.ResultTimeout}}, "", 0, api.ResultErrorInfra, false},
+		{"content_error", &api.RetryPolicy{MaxAttempts: 2, RetryOn: []api.Result{api.ResultErrorContent}}, "", 0, api.ResultErrorContent, false},
+		{"config_error", &api.RetryPolicy{MaxAttempts: 2, RetryOn: []api.Result{api.ResultErrorConfig}}, "", 0, api.ResultErrorConfig, false},
+		{"success", &api.RetryPolicy{MaxAttempts: 2}, "", 0, api.ResultSuccess, false},
+		{"aborted", &api.RetryPolicy{MaxAttempts: 2}, api.IntentAbort, 0, api.ResultErrorInfra, false},
+		{"max_attempts_one", &api.RetryPolicy{MaxAttempts: 1}, "", 0, api.ResultErrorInfra, false},
+		{"max_attempts_left", &api.RetryPolicy{MaxAttempts: 3}, "", 1, api.ResultErrorInfra, true},
+		{"max_attempts_reached", &api.RetryPolicy{MaxAttempts: 3}, "", 2, api.ResultErrorInfra, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			spec := &api.PipelineSpec{RetryPolicy: tc.policy, Intent: tc.intent}
+			status := &api.PipelineStatus{
+				Result:   tc.result,
+				Attempts: make([]api.Attempt, tc.attempts),
+			}
+
+			// EXERCISE
+			result := shouldRetry(spec, status)
+
+			// VERIFY
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func Test_retryBackoffRemaining(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2022, 3, 4, 5, 6, 7, 0, time.UTC)
+	finishedAt := metav1.NewTime(now.Add(-time.Minute))
+
+	for _, tc := range []struct {
+		name     string
+		policy   *api.RetryPolicy
+		attempts []api.Attempt
+		expected time.Duration
+	}{
+		{"no_policy", nil, []api.Attempt{{FinishedAt: &finishedAt}}, 0},
+		{"no_backoff", &api.RetryPolicy{}, []api.Attempt{{FinishedAt: &finishedAt}}, 0},
+		{"first_attempt", &api.RetryPolicy{Backoff: metav1Duration(5 * time.Minute)}, nil, 0},
+		{"waiting", &api.RetryPolicy{Backoff: metav1Duration(5 * time.Minute)}, []api.Attempt{{FinishedAt: &finishedAt}}, 4 * time.Minute},
+		{"elapsed", &api.RetryPolicy{Backoff: metav1Duration(30 * time.Second)}, []api.Attempt{{FinishedAt: &finishedAt}}, -30 * time.Second},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			spec := &api.PipelineSpec{RetryPolicy: tc.policy}
+			status := &api.PipelineStatus{Attempts: tc.attempts}
+
+			// EXERCISE
+			result := retryBackoffRemaining(spec, status, now)
+
+			// VERIFY
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
@@ -53,6 +53,19 @@ func (r *tektonRun) GetContainerInfo() *corev1.ContainerState {
 	return &stepState.ContainerState
 }
 
+// GetSidecarStates returns the states of the sidecar containers
+// as reported in the Tekton TaskRun status.
+func (r *tektonRun) GetSidecarStates() []steward.SidecarState {
+	var result []steward.SidecarState
+	for _, sidecarState := range r.tektonTaskRun.Status.Sidecars {
+		result = append(result, steward.SidecarState{
+			Name:      sidecarState.Name,
+			Container: sidecarState.ContainerState,
+		})
+	}
+	return result
+}
+
 func (r *tektonRun) getSucceededCondition() *knativeapis.Condition {
 	return r.tektonTaskRun.Status.GetCondition(knativeapis.ConditionSucceeded)
 }
@@ -14,6 +14,7 @@ import (
 type Manager interface {
 	Start(ctx context.Context, pipelineRun k8s.PipelineRun, pipelineRunsConfig *cfg.PipelineRunsConfigStruct) (string, string, error)
 	GetRun(ctx context.Context, pipelineRun k8s.PipelineRun) (Run, error)
+	Cancel(ctx context.Context, pipelineRun k8s.PipelineRun) error
 	Cleanup(ctx context.Context, pipelineRun k8s.PipelineRun) error
 }
 
@@ -23,6 +24,7 @@ type Run interface {
 	IsFinished() (bool, steward.Result)
 	GetCompletionTime() *metav1.Time
 	GetContainerInfo() *corev1.ContainerState
+	GetSidecarStates() []steward.SidecarState
 	GetMessage() string
 }
 
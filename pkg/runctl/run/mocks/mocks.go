@@ -104,6 +104,20 @@ func (mr *MockRunMockRecorder) GetMessage() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessage", reflect.TypeOf((*MockRun)(nil).GetMessage))
 }
 
+// GetSidecarStates mocks base method
+func (m *MockRun) GetSidecarStates() []v1alpha1.SidecarState {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSidecarStates")
+	ret0, _ := ret[0].([]v1alpha1.SidecarState)
+	return ret0
+}
+
+// GetSidecarStates indicates an expected call of GetSidecarStates
+func (mr *MockRunMockRecorder) GetSidecarStates() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSidecarStates", reflect.TypeOf((*MockRun)(nil).GetSidecarStates))
+}
+
 // GetStartTime mocks base method
 func (m *MockRun) GetStartTime() *v10.Time {
 	m.ctrl.T.Helper()
@@ -156,6 +170,20 @@ func (m *MockManager) EXPECT() *MockManagerMockRecorder {
 	return m.recorder
 }
 
+// Cancel mocks base method
+func (m *MockManager) Cancel(arg0 context.Context, arg1 k8s.PipelineRun) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Cancel indicates an expected call of Cancel
+func (mr *MockManagerMockRecorder) Cancel(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockManager)(nil).Cancel), arg0, arg1)
+}
+
 // Cleanup mocks base method
 func (m *MockManager) Cleanup(arg0 context.Context, arg1 k8s.PipelineRun) error {
 	m.ctrl.T.Helper()
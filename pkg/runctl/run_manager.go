@@ -28,6 +28,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	yamlserial "k8s.io/apimachinery/pkg/runtime/serializer/yaml"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/retry"
 	klog "k8s.io/klog/v2"
 )
@@ -65,6 +66,7 @@ type runManager struct {
 type runManagerTesting struct {
 	cleanupStub                               func(context.Context, *runContext) error
 	copySecretsToRunNamespaceStub             func(context.Context, *runContext) (string, []string, error)
+	createJenkinsfileRunnerPodStub            func(context.Context, *runContext) error
 	createTektonTaskRunStub                   func(context.Context, *runContext) error
 	getSecretManagerStub                      func(*runContext) runifc.SecretManager
 	getServiceAccountSecretNameStub           func(context.Context, *runContext) (string, error)
@@ -85,6 +87,7 @@ type runContext struct {
 	runNamespace       string
 	auxNamespace       string
 	serviceAccount     *k8s.ServiceAccountWrap
+	resourceProfile    *cfg.ResourceProfile
 }
 
 // newRunManager creates a new runManager.
@@ -98,6 +101,19 @@ func newRunManager(factory k8s.ClientFactory, secretProvider secrets.SecretProvi
 // Start prepares the isolated environment for a new run and starts
 // the run in this environment.
 func (c *runManager) Start(ctx context.Context, pipelineRun k8s.PipelineRun, pipelineRunsConfig *cfg.PipelineRunsConfigStruct) (namespace string, auxNamespace string, err error) {
+	return c.start(ctx, pipelineRun, pipelineRunsConfig, c.createTektonTaskRun)
+}
+
+// start prepares the isolated environment for a new run and calls
+// `createRun` to start the run in this environment. It is shared by all
+// run backends, which only differ in the way the Jenkinsfile Runner gets
+// executed.
+func (c *runManager) start(
+	ctx context.Context,
+	pipelineRun k8s.PipelineRun,
+	pipelineRunsConfig *cfg.PipelineRunsConfigStruct,
+	createRun func(context.Context, *runContext) error,
+) (namespace string, auxNamespace string, err error) {
 
 	runCtx := &runContext{
 		pipelineRun:        pipelineRun,
@@ -105,6 +121,10 @@ func (c *runManager) Start(ctx context.Context, pipelineRun k8s.PipelineRun, pip
 		runNamespace:       pipelineRun.GetRunNamespace(),
 		auxNamespace:       pipelineRun.GetAuxNamespace(),
 	}
+	runCtx.resourceProfile, err = resourceProfile(pipelineRun.GetSpec(), pipelineRunsConfig)
+	if err != nil {
+		return "", "", serrors.Classify(err, stewardv1alpha1.ResultErrorConfig)
+	}
 	err = c.cleanupNamespaces(ctx, runCtx)
 	if err != nil {
 		return "", "", err
@@ -122,7 +142,7 @@ func (c *runManager) Start(ctx context.Context, pipelineRun k8s.PipelineRun, pip
 		return "", "", err
 	}
 
-	return runCtx.runNamespace, runCtx.auxNamespace, c.createTektonTaskRun(ctx, runCtx)
+	return runCtx.runNamespace, runCtx.auxNamespace, createRun(ctx, runCtx)
 }
 
 // prepareRunNamespace creates a new namespace for the pipeline run
@@ -348,7 +368,7 @@ func (c *runManager) setupLimitRangeFromConfig(ctx context.Context, runCtx *runC
 		Kind:  "LimitRange",
 	}
 
-	configStr := runCtx.pipelineRunsConfig.LimitRange
+	configStr := effectiveLimitRange(runCtx.resourceProfile, runCtx.pipelineRunsConfig)
 	if configStr == "" {
 		return nil
 	}
@@ -381,7 +401,7 @@ func (c *runManager) setupResourceQuotaFromConfig(ctx context.Context, runCtx *r
 		Kind:  "ResourceQuota",
 	}
 
-	configStr := runCtx.pipelineRunsConfig.ResourceQuota
+	configStr := effectiveResourceQuota(runCtx.resourceProfile, runCtx.pipelineRunsConfig)
 	if configStr == "" {
 		return nil
 	}
@@ -453,6 +473,22 @@ func (c *runManager) volumesWithServiceAccountSecret(secretName string) []corev1
 	}
 }
 
+func (c *runManager) jenkinsfileRunnerPodSecurityContext(runCtx *runContext) *corev1api.PodSecurityContext {
+	copyInt64Ptr := func(ptr *int64) *int64 {
+		if ptr != nil {
+			v := *ptr
+			return &v
+		}
+		return nil
+	}
+
+	return &corev1api.PodSecurityContext{
+		RunAsUser:  copyInt64Ptr(runCtx.pipelineRunsConfig.JenkinsfileRunnerPodSecurityContextRunAsUser),
+		RunAsGroup: copyInt64Ptr(runCtx.pipelineRunsConfig.JenkinsfileRunnerPodSecurityContextRunAsGroup),
+		FSGroup:    copyInt64Ptr(runCtx.pipelineRunsConfig.JenkinsfileRunnerPodSecurityContextFSGroup),
+	}
+}
+
 func (c *runManager) getServiceAccountSecretName(ctx context.Context, runCtx *runContext) (string, error) {
 	if c.testing != nil && c.testing.getServiceAccountSecretNameStub != nil {
 		return c.testing.getServiceAccountSecretNameStub(ctx, runCtx)
@@ -469,14 +505,6 @@ func (c *runManager) createTektonTaskRun(ctx context.Context, runCtx *runContext
 
 	var err error
 
-	copyInt64Ptr := func(ptr *int64) *int64 {
-		if ptr != nil {
-			v := *ptr
-			return &v
-		}
-		return nil
-	}
-
 	namespace := runCtx.runNamespace
 	serviceAccountSecretName, err := c.getServiceAccountSecretName(ctx, runCtx)
 	if err != nil {
@@ -497,155 +525,115 @@ func (c *runManager) createTektonTaskRun(ctx context.Context, runCtx *runContext
 				Kind: tekton.ClusterTaskKind,
 				Name: tektonClusterTaskName,
 			},
-			Params: []tekton.Param{
-				tektonStringParam("RUN_NAMESPACE", namespace),
-			},
-			Timeout: runCtx.pipelineRunsConfig.Timeout,
+			Timeout: effectiveTimeout(runCtx.pipelineRun.GetSpec(), runCtx.pipelineRunsConfig),
 
 			// Always set a non-empty pod template even if we don't have
 			// values to set. Otherwise the Tekton default pod template
 			// would be used only in such cases but not if we have values
 			// to set.
 			PodTemplate: &tekton.PodTemplate{
-				SecurityContext: &corev1api.PodSecurityContext{
-					RunAsUser:  copyInt64Ptr(runCtx.pipelineRunsConfig.JenkinsfileRunnerPodSecurityContextRunAsUser),
-					RunAsGroup: copyInt64Ptr(runCtx.pipelineRunsConfig.JenkinsfileRunnerPodSecurityContextRunAsGroup),
-					FSGroup:    copyInt64Ptr(runCtx.pipelineRunsConfig.JenkinsfileRunnerPodSecurityContextFSGroup),
-				},
-				Volumes: c.volumesWithServiceAccountSecret(serviceAccountSecretName),
+				SecurityContext: c.jenkinsfileRunnerPodSecurityContext(runCtx),
+				Volumes:         c.volumesWithServiceAccountSecret(serviceAccountSecretName),
 			},
 		},
 	}
-	c.addTektonTaskRunParamsForJenkinsfileRunnerImage(runCtx, &tektonTaskRun)
-	err = c.addTektonTaskRunParamsForPipeline(runCtx, &tektonTaskRun)
+	params, err := jenkinsfileRunnerParams(runCtx)
 	if err != nil {
 		return serrors.Classify(err, stewardv1alpha1.ResultErrorConfig)
 	}
-	err = c.addTektonTaskRunParamsForLoggingElasticsearch(runCtx, &tektonTaskRun)
+	tektonTaskRun.Spec.Params = tektonStringParams(params)
+
+	err = c.addTektonTaskRunJenkinsfileRunnerResources(ctx, runCtx, &tektonTaskRun)
+	if err != nil {
+		return err
+	}
+	profile, err := schedulingProfile(runCtx.pipelineRun.GetSpec(), runCtx.pipelineRunsConfig)
 	if err != nil {
 		return serrors.Classify(err, stewardv1alpha1.ResultErrorConfig)
 	}
-
-	c.addTektonTaskRunParamsForRunDetails(runCtx, &tektonTaskRun)
+	applySchedulingProfileToPodTemplate(profile, tektonTaskRun.Spec.PodTemplate)
+	err = c.addTektonTaskRunSidecars(ctx, runCtx, &tektonTaskRun)
+	if err != nil {
+		return err
+	}
 	tektonClient := c.factory.TektonV1beta1()
 	_, err = tektonClient.TaskRuns(tektonTaskRun.GetNamespace()).Create(ctx, &tektonTaskRun, metav1.CreateOptions{})
+	if k8serrors.IsInvalid(err) || k8serrors.IsBadRequest(err) {
+		// e.g. resource requirements of the pipeline run spec rejected
+		// by the Tekton validation, which would fail on every retry
+		return serrors.Classify(err, stewardv1alpha1.ResultErrorConfig)
+	}
 	return err
 }
 
-func (c *runManager) addTektonTaskRunParamsForJenkinsfileRunnerImage(
+// addTektonTaskRunJenkinsfileRunnerResources sets the effective resource
+// requirements of the Jenkinsfile Runner step. The ClusterTask does not
+// define any, so that the pipeline runs configuration is the only source of
+// them. Step overrides would require the Tekton alpha API fields, therefore
+// the spec of the ClusterTask gets embedded into the TaskRun instead.
+func (c *runManager) addTektonTaskRunJenkinsfileRunnerResources(
+	ctx context.Context,
 	runCtx *runContext,
 	tektonTaskRun *tekton.TaskRun,
-) {
-	spec := runCtx.pipelineRun.GetSpec()
-	jfrSpec := spec.JenkinsfileRunner
-	image := runCtx.pipelineRunsConfig.JenkinsfileRunnerImage
-	imagePullPolicy := runCtx.pipelineRunsConfig.JenkinsfileRunnerImagePullPolicy
-
-	if jfrSpec != nil {
-		if jfrSpec.Image != "" {
-			image = jfrSpec.Image
-			if jfrSpec.ImagePullPolicy == "" {
-				imagePullPolicy = "IfNotPresent"
-			} else {
-				imagePullPolicy = jfrSpec.ImagePullPolicy
-			}
-		}
+) error {
+	resources := effectiveJenkinsfileRunnerResources(runCtx.pipelineRun.GetSpec(), runCtx.pipelineRunsConfig)
+	if resources == nil {
+		return nil
 	}
-	params := []tekton.Param{
-		tektonStringParam("JFR_IMAGE", image),
-		tektonStringParam("JFR_IMAGE_PULL_POLICY", imagePullPolicy),
+	taskSpec, err := c.embedTektonClusterTaskSpec(ctx, tektonTaskRun)
+	if err != nil {
+		return err
 	}
-	tektonTaskRun.Spec.Params = append(tektonTaskRun.Spec.Params, params...)
-}
-
-func (c *runManager) addTektonTaskRunParamsForRunDetails(
-	runCtx *runContext,
-	tektonTaskRun *tekton.TaskRun,
-) {
-	spec := runCtx.pipelineRun.GetSpec()
-	details := spec.RunDetails
-	if details != nil {
-		params := []tekton.Param{}
-		if details.JobName != "" {
-			params = append(params, tektonStringParam("JOB_NAME", details.JobName))
+	for i := range taskSpec.Steps {
+		if taskSpec.Steps[i].Name == tektonClusterTaskJenkinsfileRunnerStep {
+			taskSpec.Steps[i].Resources = *resources
+			return nil
 		}
-		if details.SequenceNumber > 0 {
-			params = append(params, tektonStringParam("RUN_NUMBER", fmt.Sprintf("%d", details.SequenceNumber)))
-		}
-		if details.Cause != "" {
-			params = append(params, tektonStringParam("RUN_CAUSE", details.Cause))
-		}
-		tektonTaskRun.Spec.Params = append(tektonTaskRun.Spec.Params, params...)
 	}
+	return errors.Errorf(
+		"Tekton ClusterTask %q does not have step %q",
+		tektonClusterTaskName, tektonClusterTaskJenkinsfileRunnerStep,
+	)
 }
 
-func (c *runManager) addTektonTaskRunParamsForPipeline(
+// addTektonTaskRunSidecars adds the sidecars requested by the pipeline run.
+// Tekton supports sidecars only as part of the task spec. Therefore the
+// spec of the ClusterTask gets embedded into the TaskRun instead of
+// referencing it, if there are sidecars.
+func (c *runManager) addTektonTaskRunSidecars(
+	ctx context.Context,
 	runCtx *runContext,
 	tektonTaskRun *tekton.TaskRun,
 ) error {
-	var err error
-
-	spec := runCtx.pipelineRun.GetSpec()
-	pipeline := spec.JenkinsFile
-	pipelineArgs := spec.Args
-	pipelineArgsJSON := "{}"
-	if pipelineArgs != nil {
-		if pipelineArgsJSON, err = toJSONString(&pipelineArgs); err != nil {
-			return err
-		}
+	sidecars := tektonSidecars(runCtx.pipelineRun.GetSpec())
+	if len(sidecars) == 0 {
+		return nil
 	}
-
-	params := []tekton.Param{
-		tektonStringParam("PIPELINE_GIT_URL", pipeline.URL),
-		tektonStringParam("PIPELINE_GIT_REVISION", pipeline.Revision),
-		tektonStringParam("PIPELINE_FILE", pipeline.Path),
-		tektonStringParam("PIPELINE_PARAMS_JSON", pipelineArgsJSON),
+	taskSpec, err := c.embedTektonClusterTaskSpec(ctx, tektonTaskRun)
+	if err != nil {
+		return err
 	}
-
-	tektonTaskRun.Spec.Params = append(tektonTaskRun.Spec.Params, params...)
+	taskSpec.Sidecars = append(taskSpec.Sidecars, sidecars...)
 	return nil
 }
 
-func (c *runManager) addTektonTaskRunParamsForLoggingElasticsearch(
-	runCtx *runContext,
+// embedTektonClusterTaskSpec replaces the reference to the ClusterTask in
+// the TaskRun by a copy of its spec, if not done already, and returns the
+// embedded spec.
+func (c *runManager) embedTektonClusterTaskSpec(
+	ctx context.Context,
 	tektonTaskRun *tekton.TaskRun,
-) error {
-	spec := runCtx.pipelineRun.GetSpec()
-	var params []tekton.Param
-
-	if spec.Logging == nil || spec.Logging.Elasticsearch == nil {
-		params = []tekton.Param{
-			// overide the index URL hardcoded in the template by
-			// the empty string to effective disable logging to
-			// Elasticsearch
-			tektonStringParam("PIPELINE_LOG_ELASTICSEARCH_INDEX_URL", ""),
-		}
-	} else {
-		runIDJSON, err := toJSONString(&spec.Logging.Elasticsearch.RunID)
-		if err != nil {
-			return errors.WithMessage(err,
-				"could not serialize spec.logging.elasticsearch.runid to JSON",
-			)
-		}
-
-		params = append(params, tektonStringParam("PIPELINE_LOG_ELASTICSEARCH_RUN_ID_JSON", runIDJSON))
-		// use default values from build template for all other params
-
-		if spec.Logging.Elasticsearch.IndexURL != "" {
-
-			_, err := ensureValidElasticsearchIndexURL(spec.Logging.Elasticsearch.IndexURL)
-			if err != nil {
-				return errors.Wrapf(err,
-					"field \"spec.logging.elasticsearch.indexURL\" has invalid value %q",
-					spec.Logging.Elasticsearch.IndexURL,
-				)
-			}
-			// use default values from build template for now
-		}
+) (*tekton.TaskSpec, error) {
+	if tektonTaskRun.Spec.TaskSpec != nil {
+		return tektonTaskRun.Spec.TaskSpec, nil
 	}
-	tektonTaskRun.Spec.Params = append(tektonTaskRun.Spec.Params, params...)
-
-	return nil
+	clusterTask, err := c.factory.TektonV1beta1().ClusterTasks().Get(ctx, tektonClusterTaskName, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get Tekton ClusterTask %q", tektonClusterTaskName)
+	}
+	tektonTaskRun.Spec.TaskRef = nil
+	tektonTaskRun.Spec.TaskSpec = clusterTask.Spec.DeepCopy()
+	return tektonTaskRun.Spec.TaskSpec, nil
 }
 
 // GetRun based on a pipelineRun
@@ -653,17 +641,38 @@ func (c *runManager) GetRun(ctx context.Context, pipelineRun k8s.PipelineRun) (r
 	namespace := pipelineRun.GetRunNamespace()
 	run, err := c.factory.TektonV1beta1().TaskRuns(namespace).Get(ctx, tektonTaskRunName, metav1.GetOptions{})
 	if err != nil {
-		return nil, serrors.RecoverableIf(err,
-			k8serrors.IsServerTimeout(err) ||
-				k8serrors.IsServiceUnavailable(err) ||
-				k8serrors.IsTimeout(err) ||
-				k8serrors.IsTooManyRequests(err) ||
-				k8serrors.IsInternalError(err) ||
-				k8serrors.IsUnexpectedServerError(err))
+		return nil, getRunError(err)
 	}
 	return NewRun(run), nil
 }
 
+// getRunError marks errors from fetching the run object as recoverable
+// if they are caused by temporary problems of the Kubernetes API server.
+func getRunError(err error) error {
+	return serrors.RecoverableIf(err,
+		k8serrors.IsServerTimeout(err) ||
+			k8serrors.IsServiceUnavailable(err) ||
+			k8serrors.IsTimeout(err) ||
+			k8serrors.IsTooManyRequests(err) ||
+			k8serrors.IsInternalError(err) ||
+			k8serrors.IsUnexpectedServerError(err))
+}
+
+// Cancel requests Tekton to cancel the TaskRun of a pipelineRun.
+// Tekton stops the Jenkinsfile Runner step, which can still execute
+// its post actions until the pod's termination grace period expires.
+func (c *runManager) Cancel(ctx context.Context, pipelineRun k8s.PipelineRun) error {
+	namespace := pipelineRun.GetRunNamespace()
+	patch := fmt.Sprintf(`{"spec":{"status":%q}}`, tekton.TaskRunSpecStatusCancelled)
+	_, err := c.factory.TektonV1beta1().TaskRuns(namespace).Patch(
+		ctx, tektonTaskRunName, types.MergePatchType, []byte(patch), metav1.PatchOptions{},
+	)
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	return getRunError(err)
+}
+
 // Cleanup a run based on a pipelineRun
 func (c *runManager) Cleanup(ctx context.Context, pipelineRun k8s.PipelineRun) error {
 	runCtx := &runContext{
@@ -722,6 +731,12 @@ func (c *runManager) createNamespace(ctx context.Context, runCtx *runContext, pu
 		},
 	}
 
+	if err = c.applyRunNamespaceTemplates(wanted, runCtx, purpose); err != nil {
+		// the templates are configured by the Steward operator, not by the
+		// client creating the pipeline run
+		return "", serrors.Classify(err, stewardv1alpha1.ResultErrorInfra)
+	}
+
 	slabels.LabelAsSystemManaged(wanted)
 	err = slabels.LabelAsOwnedByPipelineRun(wanted, runCtx.pipelineRun.GetAPIObject())
 	if err != nil {
@@ -754,6 +769,43 @@ func (c *runManager) createNamespace(ctx context.Context, runCtx *runContext, pu
 	return created.GetName(), err
 }
 
+// runNamespaceTemplateData is the input for the label and annotation
+// templates of run namespaces.
+type runNamespaceTemplateData struct {
+	PipelineRunName string
+	TenantNamespace string
+	Purpose         string
+}
+
+// applyRunNamespaceTemplates sets the labels and annotations configured
+// for run namespaces on the given namespace object.
+func (c *runManager) applyRunNamespaceTemplates(namespace *corev1api.Namespace, runCtx *runContext, purpose string) error {
+	config := runCtx.pipelineRunsConfig
+	if config == nil || (len(config.RunNamespaceLabels) == 0 && len(config.RunNamespaceAnnotations) == 0) {
+		return nil
+	}
+	data := &runNamespaceTemplateData{
+		PipelineRunName: runCtx.pipelineRun.GetName(),
+		TenantNamespace: runCtx.pipelineRun.GetNamespace(),
+		Purpose:         purpose,
+	}
+	labels, err := slabels.RenderLabelTemplates(config.RunNamespaceLabels, data)
+	if err != nil {
+		return errors.Wrap(err, "invalid run namespace label template")
+	}
+	annotations, err := slabels.RenderAnnotationTemplates(config.RunNamespaceAnnotations, data)
+	if err != nil {
+		return errors.Wrap(err, "invalid run namespace annotation template")
+	}
+	if len(labels) > 0 {
+		namespace.SetLabels(labels)
+	}
+	if len(annotations) > 0 {
+		namespace.SetAnnotations(annotations)
+	}
+	return nil
+}
+
 func (c *runManager) deleteNamespace(ctx context.Context, name string, options metav1.DeleteOptions) error {
 	isIgnorable := func(err error) bool {
 		return k8serrors.IsNotFound(err) ||
@@ -29,14 +29,16 @@ import (
 	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	assert "gotest.tools/assert"
 	assertcmp "gotest.tools/assert/cmp"
-	is "gotest.tools/assert/cmp"
 	corev1 "k8s.io/api/core/v1"
 	equality "k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	k8sresource "k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
 	dynamicfake "k8s.io/client-go/dynamic/fake"
 	kubefake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 )
 
 func newRunManagerTestingWithAllNoopStubs() *runManagerTesting {
@@ -125,6 +127,84 @@ func Test__runManager_prepareRunNamespace__CreatesNamespaces(t *testing.T) {
 	}
 }
 
+func Test__runManager_createNamespace__AppliesConfiguredTemplates(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	h := newTestHelper1(t)
+
+	cf := newFakeClientFactory(
+		k8sfake.Namespace(h.namespace1),
+		k8sfake.PipelineRun(h.pipelineRun1, h.namespace1, stewardv1alpha1.PipelineSpec{}),
+	)
+	cf.KubernetesClientset().PrependReactor("create", "namespaces", k8sfake.GenerateNameReactor(7))
+
+	config := &cfg.PipelineRunsConfigStruct{
+		RunNamespaceLabels: map[string]string{
+			"cost-center":         "cc1",
+			"example.com/purpose": "{{ .Purpose }}",
+		},
+		RunNamespaceAnnotations: map[string]string{
+			"example.com/run": "{{ .TenantNamespace }}/{{ .PipelineRunName }}",
+		},
+	}
+	examinee := newRunManager(cf, secretproviderfakes.NewProvider(h.namespace1))
+
+	pipelineRunHelper, err := k8s.NewPipelineRun(h.ctx, h.getPipelineRunFromStorage(cf, h.namespace1, h.pipelineRun1), cf)
+	assert.NilError(t, err)
+	runCtx := &runContext{
+		pipelineRun:        pipelineRunHelper,
+		pipelineRunsConfig: config,
+	}
+
+	// EXERCISE
+	result, resultErr := examinee.createNamespace(h.ctx, runCtx, "main", "abc12")
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	h.verifyNamespace(cf, result, "main")
+	namespace, err := cf.CoreV1().Namespaces().Get(h.ctx, result, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, "cc1", namespace.GetLabels()["cost-center"])
+	assert.Equal(t, "main", namespace.GetLabels()["example.com/purpose"])
+	assert.Equal(t, h.namespace1+"/"+h.pipelineRun1, namespace.GetAnnotations()["example.com/run"])
+}
+
+func Test__runManager_createNamespace__InvalidTemplate(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	h := newTestHelper1(t)
+
+	cf := newFakeClientFactory(
+		k8sfake.Namespace(h.namespace1),
+		k8sfake.PipelineRun(h.pipelineRun1, h.namespace1, stewardv1alpha1.PipelineSpec{}),
+	)
+
+	config := &cfg.PipelineRunsConfigStruct{
+		RunNamespaceLabels: map[string]string{
+			"example.com/run": "{{ .Unknown }}",
+		},
+	}
+	examinee := newRunManager(cf, secretproviderfakes.NewProvider(h.namespace1))
+
+	pipelineRunHelper, err := k8s.NewPipelineRun(h.ctx, h.getPipelineRunFromStorage(cf, h.namespace1, h.pipelineRun1), cf)
+	assert.NilError(t, err)
+	runCtx := &runContext{
+		pipelineRun:        pipelineRunHelper,
+		pipelineRunsConfig: config,
+	}
+
+	// EXERCISE
+	result, resultErr := examinee.createNamespace(h.ctx, runCtx, "main", "abc12")
+
+	// VERIFY
+	assert.ErrorContains(t, resultErr, "invalid run namespace label template")
+	assert.Equal(t, stewardv1alpha1.ResultErrorInfra, serrors.GetClass(resultErr))
+	assert.Equal(t, "", result)
+	h.assertThatExactlyTheseNamespacesExist(cf, h.namespace1)
+}
+
 func Test__runManager_prepareRunNamespace__Calls__copySecretsToRunNamespace__AndPropagatesError(t *testing.T) {
 	t.Parallel()
 
@@ -1166,6 +1246,71 @@ func Test__runManager_setupResourceQuotaFromConfig__UnexpectedKind(t *testing.T)
 			" \"UnexpectedKind\"")
 }
 
+func Test__runManager_setupFromConfig__ResourceProfile(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     string
+		resource string
+		kind     string
+		setup    func(*runManager, context.Context, *runContext) error
+	}{
+		{"limit_range", "limitranges", "LimitRange", (*runManager).setupLimitRangeFromConfig},
+		{"resource_quota", "resourcequotas", "ResourceQuota", (*runManager).setupResourceQuotaFromConfig},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			h := newTestHelper1(t)
+			gvr := schema.GroupVersionResource{
+				Group:    "",
+				Version:  "v1",
+				Resource: tc.resource,
+			}
+			manifest := func(origin string) string {
+				return fmt.Sprintf("apiVersion: v1\nkind: %s\nspec:\n  origin: %s\n", tc.kind, origin)
+			}
+			runCtx := contextWithSpec(t, h.namespace1, stewardv1alpha1.PipelineSpec{})
+			runCtx.pipelineRunsConfig = &cfg.PipelineRunsConfigStruct{
+				LimitRange:    manifest("config"),
+				ResourceQuota: manifest("config"),
+			}
+			runCtx.resourceProfile = &cfg.ResourceProfile{
+				LimitRange:    manifest("profile"),
+				ResourceQuota: manifest("profile"),
+			}
+
+			cf := k8sfake.NewClientFactory()
+			cf.DynamicClient = dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+				runtime.NewScheme(),
+				map[schema.GroupVersionResource]string{
+					gvr: tc.kind + "List",
+				},
+			)
+			cf.DynamicClient.PrependReactor("create", "*", k8sfake.GenerateNameReactor(0))
+
+			examinee := &runManager{
+				factory: cf,
+				testing: newRunManagerTestingWithAllNoopStubs(),
+			}
+			examinee.testing.setupLimitRangeFromConfigStub = nil
+			examinee.testing.setupResourceQuotaFromConfigStub = nil
+
+			// EXERCISE
+			resultError := tc.setup(examinee, h.ctx, runCtx)
+
+			// VERIFY
+			assert.NilError(t, resultError)
+			actualObjects, err := cf.Dynamic().Resource(gvr).List(h.ctx, metav1.ListOptions{})
+			assert.NilError(t, err)
+			assert.Equal(t, 1, len(actualObjects.Items))
+			assert.DeepEqual(t, map[string]interface{}{"origin": "profile"}, actualObjects.Items[0].Object["spec"])
+		})
+	}
+}
+
 func Test__runManager_createTektonTaskRun__PodTemplate_IsNotEmptyIfNoValuesToSet(t *testing.T) {
 	t.Parallel()
 
@@ -1200,6 +1345,37 @@ func Test__runManager_createTektonTaskRun__PodTemplate_IsNotEmptyIfNoValuesToSet
 	}
 }
 
+func Test__runManager_createTektonTaskRun__NoTimeoutConfigured(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	h := newTestHelper1(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	_, mockPipelineRun, _ := h.prepareMocks(mockCtrl)
+	runCtx := &runContext{
+		pipelineRun:        mockPipelineRun,
+		pipelineRunsConfig: &cfg.PipelineRunsConfigStruct{},
+		runNamespace:       h.namespace1,
+	}
+	mockPipelineRun.UpdateRunNamespace(h.namespace1)
+	cf := k8sfake.NewClientFactory()
+	examinee := runManager{
+		factory: cf,
+		testing: newRunManagerTestingWithAllNoopStubs(),
+	}
+
+	// EXERCISE
+	resultError := examinee.createTektonTaskRun(h.ctx, runCtx)
+
+	// VERIFY
+	assert.NilError(t, resultError)
+	taskRun, err := cf.TektonV1beta1().TaskRuns(h.namespace1).Get(h.ctx, tektonClusterTaskName, metav1.GetOptions{})
+	assert.NilError(t, err)
+	// the default timeout configured for Tekton applies
+	assert.Assert(t, taskRun.Spec.Timeout == nil)
+}
+
 func Test__runManager_createTektonTaskRun__PodTemplate_AllValuesSet(t *testing.T) {
 	t.Parallel()
 
@@ -1270,6 +1446,420 @@ func Test__runManager_createTektonTaskRun__PodTemplate_AllValuesSet(t *testing.T
 	assert.DeepEqual(t, metav1Duration(4444), taskRun.Spec.Timeout)
 }
 
+func Test__runManager_createTektonTaskRun__JenkinsfileRunnerResources(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     string
+		spec     *stewardv1alpha1.PipelineSpec
+		config   *corev1.ResourceRequirements
+		expected *corev1.ResourceRequirements
+	}{
+		{
+			name:     "no_resources",
+			spec:     &stewardv1alpha1.PipelineSpec{JenkinsfileRunner: &stewardv1alpha1.JenkinsfileRunnerSpec{}},
+			expected: nil,
+		},
+		{
+			name: "config_only",
+			spec: &stewardv1alpha1.PipelineSpec{},
+			config: &corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceMemory: k8sresource.MustParse("2Gi")},
+			},
+			expected: &corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceMemory: k8sresource.MustParse("2Gi")},
+			},
+		},
+		{
+			name: "spec_overrides_config",
+			spec: &stewardv1alpha1.PipelineSpec{
+				JenkinsfileRunner: &stewardv1alpha1.JenkinsfileRunnerSpec{
+					Resources: &corev1.ResourceRequirements{
+						Limits: corev1.ResourceList{corev1.ResourceMemory: k8sresource.MustParse("8Gi")},
+					},
+				},
+			},
+			config: &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: k8sresource.MustParse("1")},
+				Limits:   corev1.ResourceList{corev1.ResourceMemory: k8sresource.MustParse("2Gi")},
+			},
+			expected: &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: k8sresource.MustParse("1")},
+				Limits:   corev1.ResourceList{corev1.ResourceMemory: k8sresource.MustParse("8Gi")},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			h := newTestHelper1(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			_, mockPipelineRun, _ := h.prepareMocksWithSpec(mockCtrl, tc.spec)
+			mockPipelineRun.UpdateRunNamespace(h.namespace1)
+			runCtx := &runContext{
+				pipelineRun:        mockPipelineRun,
+				pipelineRunsConfig: &cfg.PipelineRunsConfigStruct{JenkinsfileRunnerResources: tc.config},
+				runNamespace:       h.namespace1,
+			}
+			cf := k8sfake.NewClientFactory()
+			clusterTask := newJenkinsfileRunnerClusterTask()
+			_, err := cf.TektonV1beta1().ClusterTasks().Create(h.ctx, clusterTask, metav1.CreateOptions{})
+			assert.NilError(t, err)
+			examinee := runManager{
+				factory: cf,
+				testing: newRunManagerTestingWithAllNoopStubs(),
+			}
+
+			// EXERCISE
+			resultError := examinee.createTektonTaskRun(h.ctx, runCtx)
+
+			// VERIFY
+			assert.NilError(t, resultError)
+			taskRun, err := cf.TektonV1beta1().TaskRuns(h.namespace1).Get(h.ctx, tektonClusterTaskName, metav1.GetOptions{})
+			assert.NilError(t, err)
+			assert.Assert(t, taskRun.Spec.StepOverrides == nil)
+			if tc.expected == nil {
+				assert.Assert(t, taskRun.Spec.TaskSpec == nil)
+				assert.Equal(t, tektonClusterTaskName, taskRun.Spec.TaskRef.Name)
+				return
+			}
+			assert.Assert(t, taskRun.Spec.TaskRef == nil)
+			expectedTaskSpec := clusterTask.Spec.DeepCopy()
+			expectedTaskSpec.Steps[0].Resources = *tc.expected
+			assert.DeepEqual(t, expectedTaskSpec, taskRun.Spec.TaskSpec)
+		})
+	}
+}
+
+func Test__runManager_createTektonTaskRun__ResourceProfile(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	h := newTestHelper1(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	spec := &stewardv1alpha1.PipelineSpec{
+		Profiles: &stewardv1alpha1.Profiles{Resources: "large"},
+		JenkinsfileRunner: &stewardv1alpha1.JenkinsfileRunnerSpec{
+			Resources: &corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceMemory: k8sresource.MustParse("8Gi")},
+			},
+		},
+	}
+	_, mockPipelineRun, _ := h.prepareMocksWithSpec(mockCtrl, spec)
+	mockPipelineRun.UpdateRunNamespace(h.namespace1)
+	profile := &cfg.ResourceProfile{
+		JenkinsfileRunnerResources: &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: k8sresource.MustParse("2")},
+			Limits:   corev1.ResourceList{corev1.ResourceMemory: k8sresource.MustParse("4Gi")},
+		},
+	}
+	runCtx := &runContext{
+		pipelineRun:  mockPipelineRun,
+		runNamespace: h.namespace1,
+		pipelineRunsConfig: &cfg.PipelineRunsConfigStruct{
+			JenkinsfileRunnerResources: &corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceCPU: k8sresource.MustParse("1")},
+			},
+			ResourceProfiles: map[string]*cfg.ResourceProfile{"large": profile},
+		},
+		resourceProfile: profile,
+	}
+	cf := k8sfake.NewClientFactory()
+	_, err := cf.TektonV1beta1().ClusterTasks().Create(h.ctx, newJenkinsfileRunnerClusterTask(), metav1.CreateOptions{})
+	assert.NilError(t, err)
+	examinee := runManager{
+		factory: cf,
+		testing: newRunManagerTestingWithAllNoopStubs(),
+	}
+
+	// EXERCISE
+	resultError := examinee.createTektonTaskRun(h.ctx, runCtx)
+
+	// VERIFY
+	assert.NilError(t, resultError)
+	taskRun, err := cf.TektonV1beta1().TaskRuns(h.namespace1).Get(h.ctx, tektonClusterTaskName, metav1.GetOptions{})
+	assert.NilError(t, err)
+	expected := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceCPU: k8sresource.MustParse("2")},
+		Limits:   corev1.ResourceList{corev1.ResourceMemory: k8sresource.MustParse("8Gi")},
+	}
+	assert.DeepEqual(t, expected, taskRun.Spec.TaskSpec.Steps[0].Resources)
+}
+
+func Test__runManager_createTektonTaskRun__JenkinsfileRunnerStepMissing(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	h := newTestHelper1(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	_, mockPipelineRun, _ := h.prepareMocksWithSpec(mockCtrl, &stewardv1alpha1.PipelineSpec{})
+	mockPipelineRun.UpdateRunNamespace(h.namespace1)
+	runCtx := &runContext{
+		pipelineRun:  mockPipelineRun,
+		runNamespace: h.namespace1,
+		pipelineRunsConfig: &cfg.PipelineRunsConfigStruct{
+			JenkinsfileRunnerResources: &corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceCPU: k8sresource.MustParse("1")},
+			},
+		},
+	}
+	cf := k8sfake.NewClientFactory()
+	clusterTask := newJenkinsfileRunnerClusterTask()
+	clusterTask.Spec.Steps[0].Name = "other"
+	_, err := cf.TektonV1beta1().ClusterTasks().Create(h.ctx, clusterTask, metav1.CreateOptions{})
+	assert.NilError(t, err)
+	examinee := runManager{
+		factory: cf,
+		testing: newRunManagerTestingWithAllNoopStubs(),
+	}
+
+	// EXERCISE
+	resultError := examinee.createTektonTaskRun(h.ctx, runCtx)
+
+	// VERIFY
+	assert.Error(t, resultError, `Tekton ClusterTask "steward-jenkinsfile-runner" does not have step "jenkinsfile-runner"`)
+	_, err = cf.TektonV1beta1().TaskRuns(h.namespace1).Get(h.ctx, tektonClusterTaskName, metav1.GetOptions{})
+	assert.Assert(t, k8serrors.IsNotFound(err), "TaskRun must not be created")
+}
+
+func Test__runManager_createTektonTaskRun__TaskRunRejected(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		err           error
+		expectedClass stewardv1alpha1.Result
+	}{
+		{
+			name: "invalid",
+			err: k8serrors.NewInvalid(
+				tektonv1beta1.SchemeGroupVersion.WithKind("TaskRun").GroupKind(),
+				tektonTaskRunName, nil,
+			),
+			expectedClass: stewardv1alpha1.ResultErrorConfig,
+		},
+		{
+			name:          "bad_request",
+			err:           k8serrors.NewBadRequest("admission webhook denied the request"),
+			expectedClass: stewardv1alpha1.ResultErrorConfig,
+		},
+		{
+			name:          "other",
+			err:           k8serrors.NewServiceUnavailable("unavailable"),
+			expectedClass: stewardv1alpha1.ResultUndefined,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			h := newTestHelper1(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			_, mockPipelineRun, _ := h.prepareMocksWithSpec(mockCtrl, &stewardv1alpha1.PipelineSpec{})
+			mockPipelineRun.UpdateRunNamespace(h.namespace1)
+			runConfig, _ := newEmptyRunsConfig(h.ctx)
+			runCtx := &runContext{
+				pipelineRun:        mockPipelineRun,
+				pipelineRunsConfig: runConfig,
+				runNamespace:       h.namespace1,
+			}
+			cf := k8sfake.NewClientFactory()
+			cf.TektonClientset().PrependReactor("create", "taskruns", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				return true, nil, tc.err
+			})
+			examinee := runManager{
+				factory: cf,
+				testing: newRunManagerTestingWithAllNoopStubs(),
+			}
+
+			// EXERCISE
+			resultError := examinee.createTektonTaskRun(h.ctx, runCtx)
+
+			// VERIFY
+			assert.Assert(t, errors.Is(resultError, tc.err))
+			assert.Equal(t, tc.expectedClass, serrors.GetClass(resultError))
+		})
+	}
+}
+
+func Test__runManager_createTektonTaskRun__SchedulingProfile(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	h := newTestHelper1(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	spec := &stewardv1alpha1.PipelineSpec{
+		Profiles: &stewardv1alpha1.Profiles{Scheduling: "release"},
+	}
+	_, mockPipelineRun, _ := h.prepareMocksWithSpec(mockCtrl, spec)
+	mockPipelineRun.UpdateRunNamespace(h.namespace1)
+	runCtx := &runContext{
+		pipelineRun:  mockPipelineRun,
+		runNamespace: h.namespace1,
+		pipelineRunsConfig: &cfg.PipelineRunsConfigStruct{
+			SchedulingProfiles: map[string]*cfg.SchedulingProfile{
+				"release": {
+					NodeSelector:      map[string]string{"pool": "release"},
+					PriorityClassName: "high",
+				},
+			},
+		},
+	}
+	cf := k8sfake.NewClientFactory()
+	examinee := runManager{
+		factory: cf,
+		testing: newRunManagerTestingWithAllNoopStubs(),
+	}
+
+	// EXERCISE
+	resultError := examinee.createTektonTaskRun(h.ctx, runCtx)
+
+	// VERIFY
+	assert.NilError(t, resultError)
+	taskRun, err := cf.TektonV1beta1().TaskRuns(h.namespace1).Get(h.ctx, tektonClusterTaskName, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, map[string]string{"pool": "release"}, taskRun.Spec.PodTemplate.NodeSelector)
+	assert.Equal(t, "high", *taskRun.Spec.PodTemplate.PriorityClassName)
+}
+
+func Test__runManager_createTektonTaskRun__UnknownSchedulingProfile(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	h := newTestHelper1(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	spec := &stewardv1alpha1.PipelineSpec{
+		Profiles: &stewardv1alpha1.Profiles{Scheduling: "unknown"},
+	}
+	_, mockPipelineRun, _ := h.prepareMocksWithSpec(mockCtrl, spec)
+	mockPipelineRun.UpdateRunNamespace(h.namespace1)
+	runConfig, _ := newEmptyRunsConfig(h.ctx)
+	runCtx := &runContext{
+		pipelineRun:        mockPipelineRun,
+		pipelineRunsConfig: runConfig,
+		runNamespace:       h.namespace1,
+	}
+	cf := k8sfake.NewClientFactory()
+	examinee := runManager{
+		factory: cf,
+		testing: newRunManagerTestingWithAllNoopStubs(),
+	}
+
+	// EXERCISE
+	resultError := examinee.createTektonTaskRun(h.ctx, runCtx)
+
+	// VERIFY
+	assert.Error(t, resultError, `scheduling profile "unknown" does not exist`)
+	assert.Equal(t, stewardv1alpha1.ResultErrorConfig, serrors.GetClass(resultError))
+}
+
+func Test__runManager_createTektonTaskRun__Sidecars_EmbedsClusterTaskSpec(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	h := newTestHelper1(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	spec := &stewardv1alpha1.PipelineSpec{
+		Sidecars: []stewardv1alpha1.Sidecar{
+			{Name: "db", Image: "postgres:14"},
+		},
+	}
+	_, mockPipelineRun, _ := h.prepareMocksWithSpec(mockCtrl, spec)
+	mockPipelineRun.UpdateRunNamespace(h.namespace1)
+	runConfig, _ := newEmptyRunsConfig(h.ctx)
+	runCtx := &runContext{
+		pipelineRun:        mockPipelineRun,
+		pipelineRunsConfig: runConfig,
+		runNamespace:       h.namespace1,
+	}
+	cf := k8sfake.NewClientFactory()
+	clusterTask := &tektonv1beta1.ClusterTask{
+		ObjectMeta: metav1.ObjectMeta{Name: tektonClusterTaskName},
+		Spec: tektonv1beta1.TaskSpec{
+			Steps: []tektonv1beta1.Step{
+				{Container: corev1.Container{Name: "jenkinsfile-runner"}},
+			},
+		},
+	}
+	_, err := cf.TektonV1beta1().ClusterTasks().Create(h.ctx, clusterTask, metav1.CreateOptions{})
+	assert.NilError(t, err)
+	examinee := runManager{
+		factory: cf,
+		testing: newRunManagerTestingWithAllNoopStubs(),
+	}
+
+	// EXERCISE
+	resultError := examinee.createTektonTaskRun(h.ctx, runCtx)
+
+	// VERIFY
+	assert.NilError(t, resultError)
+
+	taskRun, err := cf.TektonV1beta1().TaskRuns(h.namespace1).Get(h.ctx, tektonClusterTaskName, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Assert(t, taskRun.Spec.TaskRef == nil)
+	expectedTaskSpec := clusterTask.Spec.DeepCopy()
+	expectedTaskSpec.Sidecars = []tektonv1beta1.Sidecar{
+		{Container: corev1.Container{Name: "db", Image: "postgres:14"}},
+	}
+	assert.DeepEqual(t, expectedTaskSpec, taskRun.Spec.TaskSpec)
+}
+
+func Test__runManager_createTektonTaskRun__Sidecars_ClusterTaskNotFound(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	h := newTestHelper1(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	spec := &stewardv1alpha1.PipelineSpec{
+		Sidecars: []stewardv1alpha1.Sidecar{
+			{Name: "db", Image: "postgres:14"},
+		},
+	}
+	_, mockPipelineRun, _ := h.prepareMocksWithSpec(mockCtrl, spec)
+	mockPipelineRun.UpdateRunNamespace(h.namespace1)
+	runConfig, _ := newEmptyRunsConfig(h.ctx)
+	runCtx := &runContext{
+		pipelineRun:        mockPipelineRun,
+		pipelineRunsConfig: runConfig,
+		runNamespace:       h.namespace1,
+	}
+	cf := k8sfake.NewClientFactory()
+	examinee := runManager{
+		factory: cf,
+		testing: newRunManagerTestingWithAllNoopStubs(),
+	}
+
+	// EXERCISE
+	resultError := examinee.createTektonTaskRun(h.ctx, runCtx)
+
+	// VERIFY
+	assert.ErrorContains(t, resultError, `failed to get Tekton ClusterTask "steward-jenkinsfile-runner"`)
+	_, err := cf.TektonV1beta1().TaskRuns(h.namespace1).Get(h.ctx, tektonClusterTaskName, metav1.GetOptions{})
+	assert.Assert(t, k8serrors.IsNotFound(err), "TaskRun must not be created")
+}
+
+func newJenkinsfileRunnerClusterTask() *tektonv1beta1.ClusterTask {
+	return &tektonv1beta1.ClusterTask{
+		ObjectMeta: metav1.ObjectMeta{Name: tektonClusterTaskName},
+		Spec: tektonv1beta1.TaskSpec{
+			Steps: []tektonv1beta1.Step{
+				{Container: corev1.Container{Name: tektonClusterTaskJenkinsfileRunnerStep, Image: "jfr"}},
+			},
+		},
+	}
+}
+
 var metav1Duration = func(d time.Duration) *metav1.Duration {
 	return &metav1.Duration{Duration: d}
 }
@@ -1386,109 +1976,6 @@ func Test__runManager_Start__Perform_cleanup_on_error(t *testing.T) {
 	}
 }
 
-func Test__runManager_addTektonTaskRunParamsForJenkinsfileRunnerImage(t *testing.T) {
-	t.Parallel()
-
-	const (
-		pipelineRunsConfigDefaultImage  = "defaultImage1"
-		pipelineRunsConfigDefaultPolicy = "defaultPolicy1"
-	)
-	examinee := runManager{}
-	for _, tc := range []struct {
-		name                string
-		spec                *stewardv1alpha1.PipelineSpec
-		expectedAddedParams []tektonv1beta1.Param
-	}{
-		{
-			name: "empty",
-			spec: &stewardv1alpha1.PipelineSpec{},
-			expectedAddedParams: []tektonv1beta1.Param{
-				tektonStringParam("JFR_IMAGE", pipelineRunsConfigDefaultImage),
-				tektonStringParam("JFR_IMAGE_PULL_POLICY", pipelineRunsConfigDefaultPolicy),
-			},
-		},
-		{
-			name: "no_image_no_policy",
-			spec: &stewardv1alpha1.PipelineSpec{
-				JenkinsfileRunner: &stewardv1alpha1.JenkinsfileRunnerSpec{},
-			},
-			expectedAddedParams: []tektonv1beta1.Param{
-				tektonStringParam("JFR_IMAGE", pipelineRunsConfigDefaultImage),
-				tektonStringParam("JFR_IMAGE_PULL_POLICY", pipelineRunsConfigDefaultPolicy),
-			},
-		},
-		{
-			name: "image_only",
-			spec: &stewardv1alpha1.PipelineSpec{
-				JenkinsfileRunner: &stewardv1alpha1.JenkinsfileRunnerSpec{
-					Image: "foo",
-				},
-			},
-			expectedAddedParams: []tektonv1beta1.Param{
-				tektonStringParam("JFR_IMAGE", "foo"),
-				tektonStringParam("JFR_IMAGE_PULL_POLICY", "IfNotPresent"),
-			},
-		},
-		{
-			name: "policy_only",
-			spec: &stewardv1alpha1.PipelineSpec{
-				JenkinsfileRunner: &stewardv1alpha1.JenkinsfileRunnerSpec{
-					ImagePullPolicy: "bar",
-				},
-			},
-			expectedAddedParams: []tektonv1beta1.Param{
-				tektonStringParam("JFR_IMAGE", pipelineRunsConfigDefaultImage),
-				tektonStringParam("JFR_IMAGE_PULL_POLICY", pipelineRunsConfigDefaultPolicy),
-			},
-		},
-		{
-			name: "image_and_policy",
-			spec: &stewardv1alpha1.PipelineSpec{
-				JenkinsfileRunner: &stewardv1alpha1.JenkinsfileRunnerSpec{
-					Image:           "foo",
-					ImagePullPolicy: "bar",
-				},
-			},
-			expectedAddedParams: []tektonv1beta1.Param{
-				tektonStringParam("JFR_IMAGE", "foo"),
-				tektonStringParam("JFR_IMAGE_PULL_POLICY", "bar"),
-			},
-		},
-	} {
-		t.Run(tc.name, func(t *testing.T) {
-			tc := tc
-			t.Parallel()
-
-			// SETUP
-			mockCtrl := gomock.NewController(t)
-			defer mockCtrl.Finish()
-			mockPipelineRun := k8smocks.NewMockPipelineRun(mockCtrl)
-			mockPipelineRun.EXPECT().GetSpec().Return(tc.spec).AnyTimes()
-			existingParam := tektonStringParam("AlreadyExistingParam1", "foo")
-			tektonTaskRun := tektonv1beta1.TaskRun{
-				Spec: tektonv1beta1.TaskRunSpec{
-					Params: []tektonv1beta1.Param{*existingParam.DeepCopy()},
-				},
-			}
-			runCtx := &runContext{
-				pipelineRun: mockPipelineRun,
-				pipelineRunsConfig: &cfg.PipelineRunsConfigStruct{
-					JenkinsfileRunnerImage:           pipelineRunsConfigDefaultImage,
-					JenkinsfileRunnerImagePullPolicy: pipelineRunsConfigDefaultPolicy,
-				},
-			}
-
-			// EXERCISE
-			examinee.addTektonTaskRunParamsForJenkinsfileRunnerImage(runCtx, &tektonTaskRun)
-
-			// VERIFY
-			expectedParams := []tektonv1beta1.Param{existingParam}
-			expectedParams = append(expectedParams, tc.expectedAddedParams...)
-			assert.DeepEqual(t, expectedParams, tektonTaskRun.Spec.Params)
-		})
-	}
-}
-
 func Test__runManager_Start__DoesNotSetPipelineRunStatus(t *testing.T) {
 	t.Parallel()
 
@@ -1549,6 +2036,54 @@ func Test__runManager_copySecretsToRunNamespace__DoesCopySecret(t *testing.T) {
 	assert.DeepEqual(t, []string{"foo", "bar"}, imagePullSecrets)
 }
 
+func Test__runManager_Cancel__CancelsTektonTaskRun(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	h := newTestHelper1(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	_, mockPipelineRun, _ := h.prepareMocks(mockCtrl)
+	mockPipelineRun.UpdateRunNamespace(h.namespace1)
+	cf := k8sfake.NewClientFactory()
+	taskRun := &tektonv1beta1.TaskRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      tektonTaskRunName,
+			Namespace: h.namespace1,
+		},
+	}
+	_, err := cf.TektonV1beta1().TaskRuns(h.namespace1).Create(h.ctx, taskRun, metav1.CreateOptions{})
+	assert.NilError(t, err)
+	examinee := newRunManager(cf, nil)
+
+	// EXERCISE
+	resultErr := examinee.Cancel(h.ctx, mockPipelineRun)
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	result, err := cf.TektonV1beta1().TaskRuns(h.namespace1).Get(h.ctx, tektonTaskRunName, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Assert(t, result.IsCancelled())
+}
+
+func Test__runManager_Cancel__NotFound(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	h := newTestHelper1(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	_, mockPipelineRun, _ := h.prepareMocks(mockCtrl)
+	mockPipelineRun.UpdateRunNamespace(h.namespace1)
+	examinee := newRunManager(k8sfake.NewClientFactory(), nil)
+
+	// EXERCISE
+	resultErr := examinee.Cancel(h.ctx, mockPipelineRun)
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+}
+
 func Test__runManager_Cleanup__RemovesNamespaces(t *testing.T) {
 	for _, ffEnabled := range []bool{true, false} {
 		t.Run(fmt.Sprintf("featureflag_CreateAuxNamespaceIfUnused_%t", ffEnabled), func(t *testing.T) {
@@ -1624,6 +2159,7 @@ func Test__runManager__Log_Elasticsearch(t *testing.T) {
 	findTaskRunParam := func(taskRun *tektonv1beta1.TaskRun, paramName string) (param *tektonv1beta1.Param) {
 		assert.Assert(t, taskRun.Spec.Params != nil)
 		for _, p := range taskRun.Spec.Params {
+			p := p // do not take the address of the loop variable
 			if p.Name == paramName {
 				if param != nil {
 					t.Fatalf("input param specified twice: %s", paramName)
@@ -1649,10 +2185,12 @@ func Test__runManager__Log_Elasticsearch(t *testing.T) {
 		ctx := context.Background()
 		k8sPipelineRun, err := k8s.NewPipelineRun(ctx, pipelineRun, cf)
 		assert.NilError(t, err)
-		config := &cfg.PipelineRunsConfigStruct{}
+		config := &cfg.PipelineRunsConfigStruct{
+			ElasticsearchIndexURL: "http://es.example.com/index1/_doc",
+		}
 		examinee = newRunManager(
 			cf,
-			k8s.NewTenantNamespace(cf, pipelineRun.GetNamespace()).GetSecretProvider(),
+			k8s.NewTenantNamespace(cf, pipelineRun.GetNamespace(), nil).GetSecretProvider(),
 		)
 		examinee.testing = newRunManagerTestingWithRequiredStubs()
 		runCtx = &runContext{
@@ -1743,7 +2281,8 @@ func Test__runManager__Log_Elasticsearch(t *testing.T) {
 			assert.Equal(t, tc.expectedParamValue, param.Value.StringVal)
 
 			param = findTaskRunParam(taskRun, TaskRunParamNameIndexURL)
-			assert.Assert(t, is.Nil(param))
+			assert.Assert(t, param != nil)
+			assert.Equal(t, "http://es.example.com/index1/_doc", param.Value.StringVal)
 		})
 	}
 
@@ -1798,7 +2337,8 @@ func Test__runManager__Log_Elasticsearch(t *testing.T) {
 			assert.Equal(t, "", param.Value.StringVal)
 
 			param = findTaskRunParam(taskRun, TaskRunParamNameRunIDJSON)
-			assert.Assert(t, is.Nil(param))
+			assert.Assert(t, param != nil)
+			assert.Equal(t, "", param.Value.StringVal)
 		})
 	}
 
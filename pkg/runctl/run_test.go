@@ -153,6 +153,19 @@ func Test__IsFinished_RunningUpdatesContainer(t *testing.T) {
 	assert.Assert(t, finished == false)
 }
 
+func Test__GetSidecarStates(t *testing.T) {
+	run := NewRun(fakeTektonTaskRun(`{"status": {"sidecars": [{"name": "db", "container": "sidecar-db", "running": {"startedAt": "` + time1 + `"}}]}}`))
+	sidecars := run.GetSidecarStates()
+	assert.Equal(t, 1, len(sidecars))
+	assert.Equal(t, "db", sidecars[0].Name)
+	assert.Assert(t, sidecars[0].Container.Running != nil)
+}
+
+func Test__GetSidecarStates_NoSidecars(t *testing.T) {
+	run := NewRun(fakeTektonTaskRun(runningBuild))
+	assert.Assert(t, run.GetSidecarStates() == nil)
+}
+
 func Test__IsFinished_CompletedSuccess(t *testing.T) {
 	build := fakeTektonTaskRunYaml(realCompletedSuccess)
 	run := NewRun(build)
@@ -0,0 +1,84 @@
+package runctl
+
+import (
+	"fmt"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	tekton "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1api "k8s.io/api/core/v1"
+)
+
+// schedulingProfileName returns the name of the scheduling profile selected
+// in the pipeline run spec or the name of the default scheduling profile of
+// the pipeline runs configuration. Returns an empty string if neither is set.
+func schedulingProfileName(spec *stewardv1alpha1.PipelineSpec, pipelineRunsConfig *cfg.PipelineRunsConfigStruct) string {
+	if spec.Profiles != nil && spec.Profiles.Scheduling != "" {
+		return spec.Profiles.Scheduling
+	}
+	return pipelineRunsConfig.DefaultSchedulingProfile
+}
+
+// schedulingProfile returns the scheduling profile selected in the pipeline
+// run spec or the default scheduling profile of the pipeline runs
+// configuration. Returns `nil` if neither is set.
+func schedulingProfile(spec *stewardv1alpha1.PipelineSpec, pipelineRunsConfig *cfg.PipelineRunsConfigStruct) (*cfg.SchedulingProfile, error) {
+	name := schedulingProfileName(spec, pipelineRunsConfig)
+	if name == "" {
+		return nil, nil
+	}
+	profile, exists := pipelineRunsConfig.SchedulingProfiles[name]
+	if !exists {
+		return nil, fmt.Errorf("scheduling profile %q does not exist", name)
+	}
+	return profile, nil
+}
+
+// applySchedulingProfileToPodTemplate sets the scheduling constraints of
+// the given profile in a Tekton pod template.
+func applySchedulingProfileToPodTemplate(profile *cfg.SchedulingProfile, podTemplate *tekton.PodTemplate) {
+	if profile == nil {
+		return
+	}
+	podTemplate.NodeSelector = copyStringMap(profile.NodeSelector)
+	podTemplate.Tolerations = copyTolerations(profile.Tolerations)
+	podTemplate.Affinity = profile.Affinity.DeepCopy()
+	if profile.PriorityClassName != "" {
+		priorityClassName := profile.PriorityClassName
+		podTemplate.PriorityClassName = &priorityClassName
+	}
+}
+
+// applySchedulingProfileToPodSpec sets the scheduling constraints of the
+// given profile in a pod spec.
+func applySchedulingProfileToPodSpec(profile *cfg.SchedulingProfile, podSpec *corev1api.PodSpec) {
+	if profile == nil {
+		return
+	}
+	podSpec.NodeSelector = copyStringMap(profile.NodeSelector)
+	podSpec.Tolerations = copyTolerations(profile.Tolerations)
+	podSpec.Affinity = profile.Affinity.DeepCopy()
+	podSpec.PriorityClassName = profile.PriorityClassName
+}
+
+func copyStringMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	result := make(map[string]string, len(m))
+	for k, v := range m {
+		result[k] = v
+	}
+	return result
+}
+
+func copyTolerations(tolerations []corev1api.Toleration) []corev1api.Toleration {
+	if tolerations == nil {
+		return nil
+	}
+	result := make([]corev1api.Toleration, len(tolerations))
+	for i := range tolerations {
+		tolerations[i].DeepCopyInto(&result[i])
+	}
+	return result
+}
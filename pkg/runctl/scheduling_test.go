@@ -0,0 +1,114 @@
+package runctl
+
+import (
+	"testing"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	tekton "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	assert "gotest.tools/assert"
+	corev1api "k8s.io/api/core/v1"
+)
+
+func Test_schedulingProfile(t *testing.T) {
+	t.Parallel()
+
+	profile1 := &cfg.SchedulingProfile{PriorityClassName: "low"}
+	profile2 := &cfg.SchedulingProfile{PriorityClassName: "high"}
+
+	for _, tc := range []struct {
+		name             string
+		profiles         *stewardv1alpha1.Profiles
+		defaultProfile   string
+		expectedProfile  *cfg.SchedulingProfile
+		expectedErrorMsg string
+	}{
+		{"none", nil, "", nil, ""},
+		{"default", nil, "profile1", profile1, ""},
+		{"default_with_empty_selection", &stewardv1alpha1.Profiles{Network: "net1"}, "profile1", profile1, ""},
+		{"selected", &stewardv1alpha1.Profiles{Scheduling: "profile2"}, "profile1", profile2, ""},
+		{"selected_without_default", &stewardv1alpha1.Profiles{Scheduling: "profile2"}, "", profile2, ""},
+		{"unknown", &stewardv1alpha1.Profiles{Scheduling: "unknown"}, "profile1", nil, `scheduling profile "unknown" does not exist`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			spec := &stewardv1alpha1.PipelineSpec{Profiles: tc.profiles}
+			config := &cfg.PipelineRunsConfigStruct{
+				DefaultSchedulingProfile: tc.defaultProfile,
+				SchedulingProfiles: map[string]*cfg.SchedulingProfile{
+					"profile1": profile1,
+					"profile2": profile2,
+				},
+			}
+
+			// EXERCISE
+			result, resultErr := schedulingProfile(spec, config)
+
+			// VERIFY
+			if tc.expectedErrorMsg == "" {
+				assert.NilError(t, resultErr)
+				assert.Equal(t, tc.expectedProfile, result)
+			} else {
+				assert.Error(t, resultErr, tc.expectedErrorMsg)
+			}
+		})
+	}
+}
+
+func Test_applySchedulingProfile(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	profile := &cfg.SchedulingProfile{
+		NodeSelector: map[string]string{"pool": "release"},
+		Tolerations: []corev1api.Toleration{
+			{Key: "dedicated", Operator: corev1api.TolerationOpEqual, Value: "release", Effect: corev1api.TaintEffectNoSchedule},
+		},
+		Affinity: &corev1api.Affinity{
+			PodAntiAffinity: &corev1api.PodAntiAffinity{},
+		},
+		PriorityClassName: "high",
+	}
+	podTemplate := &tekton.PodTemplate{}
+	podSpec := &corev1api.PodSpec{}
+
+	// EXERCISE
+	applySchedulingProfileToPodTemplate(profile, podTemplate)
+	applySchedulingProfileToPodSpec(profile, podSpec)
+
+	// VERIFY
+	assert.DeepEqual(t, profile.NodeSelector, podTemplate.NodeSelector)
+	assert.DeepEqual(t, profile.Tolerations, podTemplate.Tolerations)
+	assert.DeepEqual(t, profile.Affinity, podTemplate.Affinity)
+	assert.Equal(t, "high", *podTemplate.PriorityClassName)
+
+	assert.DeepEqual(t, profile.NodeSelector, podSpec.NodeSelector)
+	assert.DeepEqual(t, profile.Tolerations, podSpec.Tolerations)
+	assert.DeepEqual(t, profile.Affinity, podSpec.Affinity)
+	assert.Equal(t, "high", podSpec.PriorityClassName)
+
+	// the profile must not be shared with the pod template and the pod
+	podTemplate.NodeSelector["pool"] = "other"
+	podSpec.Tolerations[0].Key = "other"
+	assert.Equal(t, "release", profile.NodeSelector["pool"])
+	assert.Equal(t, "dedicated", profile.Tolerations[0].Key)
+}
+
+func Test_applySchedulingProfile_NoProfile(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	podTemplate := &tekton.PodTemplate{}
+	podSpec := &corev1api.PodSpec{}
+
+	// EXERCISE
+	applySchedulingProfileToPodTemplate(nil, podTemplate)
+	applySchedulingProfileToPodSpec(nil, podSpec)
+
+	// VERIFY
+	assert.DeepEqual(t, &tekton.PodTemplate{}, podTemplate)
+	assert.DeepEqual(t, &corev1api.PodSpec{}, podSpec)
+}
@@ -2,12 +2,14 @@ package secretmgr
 
 import (
 	"context"
+	"strings"
 
 	"github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
 	serrors "github.com/SAP/stewardci-core/pkg/errors"
 	"github.com/SAP/stewardci-core/pkg/k8s"
 	secrets "github.com/SAP/stewardci-core/pkg/k8s/secrets"
 	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	klog "k8s.io/klog/v2"
 )
@@ -64,16 +66,30 @@ func (s SecretManager) copyPipelineCloneSecretToRunNamespace(ctx context.Context
 	if err != nil {
 		return "", serrors.Classify(err, v1alpha1.ResultErrorContent)
 	}
+
+	// Tekton expects the server URL for basic-auth secrets, but only
+	// `<host>[:<port>]` for SSH secrets.
+	secretType, gitServer := v1.SecretTypeBasicAuth, repoServerURL
+	if sshServer := strings.TrimPrefix(repoServerURL, k8s.RepoURLSchemeSSH+"://"); sshServer != repoServerURL {
+		secretType, gitServer = v1.SecretTypeSSHAuth, sshServer
+	}
 	transformers := []secrets.SecretTransformer{
 		secrets.StripAnnotationsTransformer("jenkins.io/"),
 		secrets.StripLabelsTransformer("jenkins.io/"),
 		secrets.UniqueNameTransformer(),
-		secrets.SetAnnotationTransformer("tekton.dev/git-0", repoServerURL),
+		secrets.SetAnnotationTransformer("tekton.dev/git-0", gitServer),
 	}
-	names, err := s.copySecrets(ctx, pipelineRun, []string{secretName}, nil, transformers...)
+	names, err := s.copySecrets(ctx, pipelineRun, []string{secretName}, secrets.OfType(secretType), transformers...)
 	if err != nil {
 		return "", err
 	}
+	if len(names) == 0 {
+		err := errors.Errorf(
+			"pipeline clone secret %q must be of type %q for pipeline repository URL %q",
+			secretName, secretType, pipelineRun.GetSpec().JenkinsFile.URL,
+		)
+		return "", serrors.Classify(err, v1alpha1.ResultErrorConfig)
+	}
 	return names[0], nil
 }
 
@@ -83,19 +99,80 @@ func (s SecretManager) copyPipelineSecretsToRunNamespace(ctx context.Context, pi
 		secrets.StripAnnotationsTransformer("tekton.dev/"),
 		secrets.RenameByAnnotationTransformer(v1alpha1.AnnotationSecretRename),
 	}
-	return s.copySecrets(ctx, pipelineRun, secretNames, nil, transformers...)
+	storedSecretNames, err := s.copySecrets(ctx, pipelineRun, secretNames, nil, transformers...)
+	if err != nil {
+		return storedSecretNames, err
+	}
+	for _, pipelineSecret := range pipelineRun.GetSpec().SecretRefs {
+		storedSecretName, err := s.copyPipelineSecretToRunNamespace(ctx, pipelineRun, pipelineSecret)
+		if err != nil {
+			return storedSecretNames, err
+		}
+		if storedSecretName != "" {
+			storedSecretNames = append(storedSecretNames, storedSecretName)
+		}
+	}
+	return storedSecretNames, nil
+}
+
+// copyPipelineSecretToRunNamespace copies a secret listed in
+// `spec.secretRefs` of the pipeline run. It returns an empty name if an optional secret was
+// skipped.
+func (s SecretManager) copyPipelineSecretToRunNamespace(ctx context.Context, pipelineRun k8s.PipelineRun, pipelineSecret v1alpha1.PipelineSecret) (string, error) {
+	var keyMapping map[string]string
+	var keys []string
+	if len(pipelineSecret.Keys) > 0 {
+		keyMapping = make(map[string]string, len(pipelineSecret.Keys))
+		for _, key := range pipelineSecret.Keys {
+			targetKey := key.TargetKey
+			if targetKey == "" {
+				targetKey = key.Key
+			}
+			keyMapping[key.Key] = targetKey
+			keys = append(keys, key.Key)
+		}
+	}
+	var filter secrets.SecretFilter
+	if !pipelineSecret.Optional {
+		filter = secrets.HasKeys(keys...)
+	}
+	transformers := []secrets.SecretTransformer{
+		secrets.StripAnnotationsTransformer("tekton.dev/"),
+		secrets.RenameByAnnotationTransformer(v1alpha1.AnnotationSecretRename),
+		secrets.RenameTransformer(pipelineSecret.TargetName),
+		secrets.ProjectKeysTransformer(keyMapping),
+	}
+
+	secretNames := []string{pipelineSecret.Name}
+	names, err := s.secretHelper.CopySecrets(ctx, secretNames, filter, transformers...)
+	if err != nil {
+		if pipelineSecret.Optional && s.secretHelper.IsNotFound(err) {
+			return "", nil
+		}
+		return "", s.classifyCopyError(pipelineRun, secretNames, err)
+	}
+	if len(names) == 0 {
+		if pipelineSecret.Optional {
+			return "", nil
+		}
+		err := errors.Errorf("secret %q does not contain all of the keys %q", pipelineSecret.Name, keys)
+		return "", serrors.Classify(err, v1alpha1.ResultErrorContent)
+	}
+	return names[0], nil
 }
 
 func (s SecretManager) copySecrets(ctx context.Context, pipelineRun k8s.PipelineRun, secretNames []string, filter secrets.SecretFilter, transformers ...secrets.SecretTransformer) ([]string, error) {
 	storedSecretNames, err := s.secretHelper.CopySecrets(ctx, secretNames, filter, transformers...)
 	if err != nil {
-		klog.Errorf("Cannot copy secrets %s for [%s]. Error: %s", secretNames, pipelineRun.String(), err)
-		if s.secretHelper.IsNotFound(err) || k8serrors.IsInvalid(err) || k8serrors.IsAlreadyExists(err) {
-			err = serrors.Classify(err, v1alpha1.ResultErrorContent)
-		} else {
-			err = serrors.Classify(err, v1alpha1.ResultErrorInfra)
-		}
-		return storedSecretNames, err
+		return storedSecretNames, s.classifyCopyError(pipelineRun, secretNames, err)
 	}
 	return storedSecretNames, nil
 }
+
+func (s SecretManager) classifyCopyError(pipelineRun k8s.PipelineRun, secretNames []string, err error) error {
+	klog.Errorf("Cannot copy secrets %s for [%s]. Error: %s", secretNames, pipelineRun.String(), err)
+	if s.secretHelper.IsNotFound(err) || k8serrors.IsInvalid(err) || k8serrors.IsAlreadyExists(err) {
+		return serrors.Classify(err, v1alpha1.ResultErrorContent)
+	}
+	return serrors.Classify(err, v1alpha1.ResultErrorInfra)
+}
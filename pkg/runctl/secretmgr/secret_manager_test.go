@@ -7,16 +7,24 @@ import (
 
 	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
 	serrors "github.com/SAP/stewardci-core/pkg/errors"
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/SAP/stewardci-core/pkg/k8s/fake"
 	mocks "github.com/SAP/stewardci-core/pkg/k8s/mocks"
+	secrets "github.com/SAP/stewardci-core/pkg/k8s/secrets"
 	secretMocks "github.com/SAP/stewardci-core/pkg/k8s/secrets/mocks"
+	fakesecretprovider "github.com/SAP/stewardci-core/pkg/k8s/secrets/providers/fake"
 	gomock "github.com/golang/mock/gomock"
 	"gotest.tools/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
 )
 
 type testHelper struct {
 	t                                *testing.T
 	ctx                              context.Context
 	pipelineSecretTransormerMatcher  gomock.Matcher
+	secretRefTransormerMatcher       gomock.Matcher
 	imagePullSecretFilterMatcher     gomock.Matcher
 	imagePullSecretTransormerMatcher gomock.Matcher
 	cloneSecretTransormerMatcher     gomock.Matcher
@@ -28,6 +36,7 @@ func newTestHelper(t *testing.T) *testHelper {
 		t:                                t,
 		ctx:                              context.Background(),
 		pipelineSecretTransormerMatcher:  gomock.Len(2),
+		secretRefTransormerMatcher:       gomock.Len(4),
 		imagePullSecretFilterMatcher:     gomock.Any(),
 		imagePullSecretTransormerMatcher: gomock.Len(4),
 		cloneSecretTransormerMatcher:     gomock.Len(4),
@@ -40,6 +49,9 @@ func newTestHelper(t *testing.T) *testHelper {
 				"secret1",
 				"secret2",
 			},
+			SecretRefs: []stewardv1alpha1.PipelineSecret{
+				{Name: "secret3", TargetName: "target3"},
+			},
 			ImagePullSecrets: []string{
 				"imagePullSecret1",
 				"imagePullSecret2",
@@ -97,13 +109,69 @@ func Test_copyPipelineCloneSecretToRunNamespace_Success(t *testing.T) {
 	// VERIFY
 	mockPipelineRun.EXPECT().GetPipelineRepoServerURL().Return("server", nil).AnyTimes()
 	mockSecretHelper.EXPECT().
-		CopySecrets(th.ctx, []string{"scm_secret1"}, nil, th.cloneSecretTransormerMatcher).
+		CopySecrets(th.ctx, []string{"scm_secret1"}, gomock.Any(), th.cloneSecretTransormerMatcher).
 		Return([]string{"scm_secret1"}, nil)
 
 	// EXERCISE
 	examinee.copyPipelineCloneSecretToRunNamespace(th.ctx, mockPipelineRun)
 }
 
+func Test_copyPipelineCloneSecretToRunNamespace_SecretTypes(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name               string
+		repoURL            string
+		secretType         v1.SecretType
+		expectedAnnotation string
+		expectedError      string
+	}{
+		{"https_basic_auth", "https://github.com/org/repo", v1.SecretTypeBasicAuth, "https://github.com", ""},
+		{"ssh_ssh_auth", "ssh://git@github.com:2222/org/repo", v1.SecretTypeSSHAuth, "github.com:2222", ""},
+		{"scp_like_ssh_auth", "git@github.com:org/repo.git", v1.SecretTypeSSHAuth, "github.com", ""},
+		{"https_ssh_auth", "https://github.com/org/repo", v1.SecretTypeSSHAuth, "",
+			`pipeline clone secret "scm_secret1" must be of type "kubernetes.io/basic-auth" for pipeline repository URL "https://github.com/org/repo"`,
+		},
+		{"ssh_basic_auth", "git@github.com:org/repo.git", v1.SecretTypeBasicAuth, "",
+			`pipeline clone secret "scm_secret1" must be of type "kubernetes.io/ssh-auth" for pipeline repository URL "git@github.com:org/repo.git"`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			ctx := context.Background()
+			source := newSecret("scm_secret1", map[string]string{"ssh-privatekey": "key1", "known_hosts": "hosts1"})
+			source.Type = tc.secretType
+			provider := fakesecretprovider.NewProvider("ns1", source)
+			cs := kubefake.NewSimpleClientset()
+			examinee := NewSecretManager(secrets.NewSecretHelper(provider, "run1", cs.CoreV1().Secrets("run1")))
+			pipelineRun, err := k8s.NewPipelineRun(ctx, fake.PipelineRun("run1", "ns1", stewardv1alpha1.PipelineSpec{
+				JenkinsFile: stewardv1alpha1.JenkinsFile{URL: tc.repoURL, RepoAuthSecret: "scm_secret1"},
+			}), nil)
+			assert.NilError(t, err)
+
+			// EXERCISE
+			_, resultErr := examinee.copyPipelineCloneSecretToRunNamespace(ctx, pipelineRun)
+
+			// VERIFY
+			list, err := cs.CoreV1().Secrets("run1").List(ctx, metav1.ListOptions{})
+			assert.NilError(t, err)
+			if tc.expectedError != "" {
+				assert.Error(t, resultErr, tc.expectedError)
+				assert.Equal(t, stewardv1alpha1.ResultErrorConfig, serrors.GetClass(resultErr))
+				assert.Equal(t, 0, len(list.Items))
+				return
+			}
+			assert.NilError(t, resultErr)
+			assert.Equal(t, 1, len(list.Items))
+			assert.Equal(t, tc.expectedAnnotation, list.Items[0].GetAnnotations()["tekton.dev/git-0"])
+			assert.DeepEqual(t, source.Data, list.Items[0].Data)
+		})
+	}
+}
+
 func Test_copyPipelineCloneSecretToRunNamespace_FailsWithContentErrorOnGetPipelineRepoServerURLError(t *testing.T) {
 	t.Parallel()
 
@@ -130,14 +198,124 @@ func Test_copyPipelineSecretsToRunNamespace(t *testing.T) {
 	mockCtrl, examinee, mockPipelineRun, mockSecretHelper := mockPipelineRunWithSpec(th)
 	defer mockCtrl.Finish()
 
-	// VERIFY
+	// EXPECT
 	mockSecretHelper.EXPECT().
 		CopySecrets(th.ctx, []string{"secret1", "secret2"}, nil, th.pipelineSecretTransormerMatcher).
 		Return([]string{"secret1", "secret2"}, nil)
+	mockSecretHelper.EXPECT().
+		CopySecrets(th.ctx, []string{"secret3"}, gomock.Any(), th.secretRefTransormerMatcher).
+		Return([]string{"target3"}, nil)
 
 	// EXERCISE
-	examinee.copyPipelineSecretsToRunNamespace(th.ctx, mockPipelineRun)
+	names, err := examinee.copyPipelineSecretsToRunNamespace(th.ctx, mockPipelineRun)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{"secret1", "secret2", "target3"}, names)
+
+}
+
+func Test_copyPipelineSecretsToRunNamespace_Projection(t *testing.T) {
+	t.Parallel()
 
+	for _, tc := range []struct {
+		name            string
+		pipelineSecret  stewardv1alpha1.PipelineSecret
+		expectedSecrets []*v1.Secret
+		expectedError   string
+	}{
+		{"all_keys",
+			stewardv1alpha1.PipelineSecret{Name: "secret1"},
+			[]*v1.Secret{newSecret("renamed1", map[string]string{"key1": "value1", "key2": "value2"})},
+			"",
+		},
+		{"target_name",
+			stewardv1alpha1.PipelineSecret{Name: "secret1", TargetName: "target1"},
+			[]*v1.Secret{newSecret("target1", map[string]string{"key1": "value1", "key2": "value2"})},
+			"",
+		},
+		{"keys",
+			stewardv1alpha1.PipelineSecret{Name: "secret1", Keys: []stewardv1alpha1.PipelineSecretKey{
+				{Key: "key1", TargetKey: "username"},
+			}},
+			[]*v1.Secret{newSecret("renamed1", map[string]string{"username": "value1"})},
+			"",
+		},
+		{"missing_key",
+			stewardv1alpha1.PipelineSecret{Name: "secret1", Keys: []stewardv1alpha1.PipelineSecretKey{
+				{Key: "key1"}, {Key: "missing"},
+			}},
+			nil,
+			`secret "secret1" does not contain all of the keys ["key1" "missing"]`,
+		},
+		{"missing_key_optional",
+			stewardv1alpha1.PipelineSecret{Name: "secret1", Optional: true, Keys: []stewardv1alpha1.PipelineSecretKey{
+				{Key: "key1"}, {Key: "missing"},
+			}},
+			[]*v1.Secret{newSecret("renamed1", map[string]string{"key1": "value1"})},
+			"",
+		},
+		{"missing_secret",
+			stewardv1alpha1.PipelineSecret{Name: "missing"},
+			nil,
+			`secret not found: 'missing'`,
+		},
+		{"missing_secret_optional",
+			stewardv1alpha1.PipelineSecret{Name: "missing", Optional: true},
+			nil,
+			"",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			ctx := context.Background()
+			source := newSecret("secret1", map[string]string{"key1": "value1", "key2": "value2"})
+			source.SetAnnotations(map[string]string{stewardv1alpha1.AnnotationSecretRename: "renamed1"})
+			provider := fakesecretprovider.NewProvider("ns1", source)
+			cs := kubefake.NewSimpleClientset()
+			examinee := NewSecretManager(secrets.NewSecretHelper(provider, "run1", cs.CoreV1().Secrets("run1")))
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockPipelineRun := mocks.NewMockPipelineRun(mockCtrl)
+			mockPipelineRun.EXPECT().GetSpec().Return(&stewardv1alpha1.PipelineSpec{
+				SecretRefs: []stewardv1alpha1.PipelineSecret{tc.pipelineSecret},
+			}).AnyTimes()
+			mockPipelineRun.EXPECT().String().AnyTimes() //logging
+
+			// EXERCISE
+			_, resultErr := examinee.copyPipelineSecretsToRunNamespace(ctx, mockPipelineRun)
+
+			// VERIFY
+			if tc.expectedError != "" {
+				assert.Error(t, resultErr, tc.expectedError)
+				assert.Equal(t, stewardv1alpha1.ResultErrorContent, serrors.GetClass(resultErr))
+			} else {
+				assert.NilError(t, resultErr)
+			}
+			list, err := cs.CoreV1().Secrets("run1").List(ctx, metav1.ListOptions{})
+			assert.NilError(t, err)
+			assert.Equal(t, len(tc.expectedSecrets), len(list.Items))
+			for i, expected := range tc.expectedSecrets {
+				assert.Equal(t, expected.GetName(), list.Items[i].GetName())
+				assert.DeepEqual(t, expected.Data, list.Items[i].Data)
+			}
+		})
+	}
+}
+
+func newSecret(name string, data map[string]string) *v1.Secret {
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Data:       map[string][]byte{},
+	}
+	for key, value := range data {
+		secret.Data[key] = []byte(value)
+	}
+	return secret
 }
 
 func Test_copySecrets_FailsWithContentErrorOnNotFound(t *testing.T) {
@@ -0,0 +1,88 @@
+package runctl
+
+import (
+	"fmt"
+	"strings"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	tekton "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1api "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// validateSidecars checks the sidecars requested in the pipeline run spec.
+// If `pipelineRunsConfig` is `nil`, only checks independent of the
+// configuration are performed.
+func validateSidecars(spec *stewardv1alpha1.PipelineSpec, pipelineRunsConfig *cfg.PipelineRunsConfigStruct) error {
+	names := map[string]bool{}
+	for i, sidecar := range spec.Sidecars {
+		if errs := validation.IsDNS1123Label(sidecar.Name); len(errs) > 0 {
+			return fmt.Errorf("field \"spec.sidecars[%d].name\" has invalid value %q: %s", i, sidecar.Name, strings.Join(errs, ", "))
+		}
+		if names[sidecar.Name] {
+			return fmt.Errorf("field \"spec.sidecars[%d].name\" has invalid value %q: must be unique", i, sidecar.Name)
+		}
+		names[sidecar.Name] = true
+		if strings.TrimSpace(sidecar.Image) == "" {
+			return fmt.Errorf("field \"spec.sidecars[%d].image\" must not be empty", i)
+		}
+		// ports on the node would bypass the network policy of the run namespace
+		for j, port := range sidecar.Ports {
+			if port.HostPort != 0 {
+				return fmt.Errorf("field \"spec.sidecars[%d].ports[%d].hostPort\" is not supported", i, j)
+			}
+			if port.HostIP != "" {
+				return fmt.Errorf("field \"spec.sidecars[%d].ports[%d].hostIP\" is not supported", i, j)
+			}
+		}
+	}
+
+	if pipelineRunsConfig == nil || len(spec.Sidecars) == 0 {
+		return nil
+	}
+	if pipelineRunsConfig.RunBackend == cfg.RunBackendPod {
+		return fmt.Errorf("sidecars are not supported by the %q run backend", cfg.RunBackendPod)
+	}
+	for i, sidecar := range spec.Sidecars {
+		if !isSidecarImageAllowed(sidecar.Image, pipelineRunsConfig.SidecarAllowedImages) {
+			return fmt.Errorf("field \"spec.sidecars[%d].image\" has invalid value %q: image is not permitted", i, sidecar.Image)
+		}
+	}
+	return nil
+}
+
+// isSidecarImageAllowed returns whether the given image matches an entry
+// of the allowlist. Entries ending with `*` match all images starting with
+// the part before, all other entries must match exactly.
+func isSidecarImageAllowed(image string, allowedImages []string) bool {
+	for _, allowed := range allowedImages {
+		if prefix := strings.TrimSuffix(allowed, "*"); prefix != allowed {
+			if strings.HasPrefix(image, prefix) {
+				return true
+			}
+		} else if image == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// tektonSidecars returns the Tekton sidecars for the sidecars requested in
+// the pipeline run spec.
+func tektonSidecars(spec *stewardv1alpha1.PipelineSpec) []tekton.Sidecar {
+	var result []tekton.Sidecar
+	for _, sidecar := range spec.Sidecars {
+		container := corev1api.Container{
+			Name:  sidecar.Name,
+			Image: sidecar.Image,
+			Env:   sidecar.Env,
+			Ports: sidecar.Ports,
+		}
+		if sidecar.Resources != nil {
+			container.Resources = *sidecar.Resources.DeepCopy()
+		}
+		result = append(result, tekton.Sidecar{Container: container})
+	}
+	return result
+}
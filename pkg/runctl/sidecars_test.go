@@ -0,0 +1,195 @@
+package runctl
+
+import (
+	"testing"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	tekton "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	assert "gotest.tools/assert"
+	corev1api "k8s.io/api/core/v1"
+	k8sresource "k8s.io/apimachinery/pkg/api/resource"
+)
+
+func Test_validateSidecars(t *testing.T) {
+	t.Parallel()
+
+	tektonConfig := &cfg.PipelineRunsConfigStruct{
+		SidecarAllowedImages: []string{"postgres:*"},
+	}
+	podConfig := &cfg.PipelineRunsConfigStruct{
+		RunBackend:           cfg.RunBackendPod,
+		SidecarAllowedImages: []string{"postgres:*"},
+	}
+
+	for _, tc := range []struct {
+		name          string
+		sidecars      []stewardv1alpha1.Sidecar
+		config        *cfg.PipelineRunsConfigStruct
+		expectedError string
+	}{
+		{
+			name:   "none",
+			config: podConfig,
+		},
+		{
+			name:     "valid",
+			sidecars: []stewardv1alpha1.Sidecar{{Name: "db", Image: "postgres:14"}},
+			config:   tektonConfig,
+		},
+		{
+			name:          "invalid_name",
+			sidecars:      []stewardv1alpha1.Sidecar{{Name: "DB", Image: "postgres:14"}},
+			config:        nil,
+			expectedError: `field "spec.sidecars[0].name" has invalid value "DB": a lowercase RFC 1123 label must consist of lower case alphanumeric characters or '-', and must start and end with an alphanumeric character (e.g. 'my-name',  or '123-abc', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?')`,
+		},
+		{
+			name: "duplicate_name",
+			sidecars: []stewardv1alpha1.Sidecar{
+				{Name: "db", Image: "postgres:14"},
+				{Name: "db", Image: "postgres:13"},
+			},
+			config:        nil,
+			expectedError: `field "spec.sidecars[1].name" has invalid value "db": must be unique`,
+		},
+		{
+			name:          "empty_image",
+			sidecars:      []stewardv1alpha1.Sidecar{{Name: "db", Image: " "}},
+			config:        nil,
+			expectedError: `field "spec.sidecars[0].image" must not be empty`,
+		},
+		{
+			name: "container_port",
+			sidecars: []stewardv1alpha1.Sidecar{{Name: "db", Image: "postgres:14", Ports: []corev1api.ContainerPort{
+				{ContainerPort: 5432},
+			}}},
+			config: tektonConfig,
+		},
+		{
+			name: "host_port",
+			sidecars: []stewardv1alpha1.Sidecar{{Name: "db", Image: "postgres:14", Ports: []corev1api.ContainerPort{
+				{ContainerPort: 5432},
+				{ContainerPort: 5433, HostPort: 5433},
+			}}},
+			config:        nil,
+			expectedError: `field "spec.sidecars[0].ports[1].hostPort" is not supported`,
+		},
+		{
+			name: "host_ip",
+			sidecars: []stewardv1alpha1.Sidecar{{Name: "db", Image: "postgres:14", Ports: []corev1api.ContainerPort{
+				{ContainerPort: 5432, HostIP: "0.0.0.0"},
+			}}},
+			config:        nil,
+			expectedError: `field "spec.sidecars[0].ports[0].hostIP" is not supported`,
+		},
+		{
+			name:     "no_config",
+			sidecars: []stewardv1alpha1.Sidecar{{Name: "db", Image: "mysql"}},
+			config:   nil,
+		},
+		{
+			name:          "pod_backend",
+			sidecars:      []stewardv1alpha1.Sidecar{{Name: "db", Image: "postgres:14"}},
+			config:        podConfig,
+			expectedError: `sidecars are not supported by the "pod" run backend`,
+		},
+		{
+			name:          "image_not_allowed",
+			sidecars:      []stewardv1alpha1.Sidecar{{Name: "db", Image: "mysql"}},
+			config:        tektonConfig,
+			expectedError: `field "spec.sidecars[0].image" has invalid value "mysql": image is not permitted`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			spec := &stewardv1alpha1.PipelineSpec{Sidecars: tc.sidecars}
+
+			// EXERCISE
+			resultErr := validateSidecars(spec, tc.config)
+
+			// VERIFY
+			if tc.expectedError == "" {
+				assert.NilError(t, resultErr)
+			} else {
+				assert.Error(t, resultErr, tc.expectedError)
+			}
+		})
+	}
+}
+
+func Test_isSidecarImageAllowed(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		image         string
+		allowedImages []string
+		expected      bool
+	}{
+		{"empty_allowlist", "postgres:14", nil, false},
+		{"exact_match", "postgres:14", []string{"mysql", "postgres:14"}, true},
+		{"exact_mismatch", "postgres:14", []string{"postgres:13"}, false},
+		{"prefix_match", "docker.io/library/postgres:14", []string{"docker.io/library/postgres:*"}, true},
+		{"prefix_mismatch", "docker.io/evil/postgres:14", []string{"docker.io/library/postgres:*"}, false},
+		{"wildcard_only", "anything", []string{"*"}, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// EXERCISE
+			result := isSidecarImageAllowed(tc.image, tc.allowedImages)
+
+			// VERIFY
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func Test_tektonSidecars(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	resources := &corev1api.ResourceRequirements{
+		Limits: corev1api.ResourceList{
+			corev1api.ResourceMemory: k8sresource.MustParse("1Gi"),
+		},
+	}
+	spec := &stewardv1alpha1.PipelineSpec{
+		Sidecars: []stewardv1alpha1.Sidecar{
+			{
+				Name:      "db",
+				Image:     "postgres:14",
+				Env:       []corev1api.EnvVar{{Name: "POSTGRES_PASSWORD", Value: "secret1"}},
+				Ports:     []corev1api.ContainerPort{{ContainerPort: 5432}},
+				Resources: resources,
+			},
+			{
+				Name:  "browser",
+				Image: "selenium/standalone-chrome",
+			},
+		},
+	}
+
+	// EXERCISE
+	result := tektonSidecars(spec)
+
+	// VERIFY
+	expected := []tekton.Sidecar{
+		{Container: corev1api.Container{
+			Name:      "db",
+			Image:     "postgres:14",
+			Env:       []corev1api.EnvVar{{Name: "POSTGRES_PASSWORD", Value: "secret1"}},
+			Ports:     []corev1api.ContainerPort{{ContainerPort: 5432}},
+			Resources: *resources,
+		}},
+		{Container: corev1api.Container{
+			Name:  "browser",
+			Image: "selenium/standalone-chrome",
+		}},
+	}
+	assert.DeepEqual(t, expected, result)
+}
@@ -0,0 +1,163 @@
+package runctl
+
+import (
+	"context"
+	"fmt"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	serrors "github.com/SAP/stewardci-core/pkg/errors"
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	"github.com/SAP/stewardci-core/pkg/utils"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TenantPipelineRunsConfig returns the pipeline runs configuration
+// effective for pipeline runs in the given tenant namespace, i.e. the
+// given configuration with the pipeline run settings of the tenant
+// merged over it. Returns the given configuration if the namespace does
+// not belong to a tenant or the tenant has no pipeline run settings.
+func TenantPipelineRunsConfig(ctx context.Context, factory k8s.ClientFactory, tenantNamespace string, pipelineRunsConfig *cfg.PipelineRunsConfigStruct) (*cfg.PipelineRunsConfigStruct, error) {
+	namespace, err := factory.CoreV1().Namespaces().Get(ctx, tenantNamespace, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return pipelineRunsConfig, nil
+		}
+		return nil, serrors.Recoverable(errors.Wrapf(err, "failed to get tenant namespace %q", tenantNamespace))
+	}
+	settings, err := getTenantPipelineRunsSettings(ctx, factory, namespace)
+	if err != nil {
+		return nil, err
+	}
+	return applyTenantPipelineRunsSettings(pipelineRunsConfig, settings), nil
+}
+
+// getTenantPipelineRunsSettings returns the pipeline run settings of the
+// tenant owning the given tenant namespace or `nil` if there are none.
+// `tenantNamespace` may be `nil` if the namespace does not exist.
+// Errors from the Kubernetes API are recoverable.
+func getTenantPipelineRunsSettings(ctx context.Context, factory k8s.ClientFactory, tenantNamespace *corev1.Namespace) (*stewardv1alpha1.TenantPipelineRunsSettings, error) {
+	if tenantNamespace == nil {
+		return nil, nil
+	}
+	clientNamespaceName := tenantNamespace.GetLabels()[stewardv1alpha1.LabelOwnerClientNamespace]
+	tenantName := tenantNamespace.GetLabels()[stewardv1alpha1.LabelOwnerTenantName]
+	if clientNamespaceName == "" || tenantName == "" {
+		return nil, nil
+	}
+	tenant, err := factory.StewardV1alpha1().Tenants(clientNamespaceName).Get(ctx, tenantName, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, serrors.Recoverable(errors.Wrapf(err, "failed to get tenant %s/%s", clientNamespaceName, tenantName))
+	}
+	return tenant.Spec.PipelineRuns, nil
+}
+
+// applyTenantPipelineRunsSettings returns a copy of the given pipeline runs
+// configuration with the given tenant settings merged over it. Settings
+// which are not set keep the configured values. The maximum timeout of a
+// tenant can only lower the configured one.
+func applyTenantPipelineRunsSettings(pipelineRunsConfig *cfg.PipelineRunsConfigStruct, settings *stewardv1alpha1.TenantPipelineRunsSettings) *cfg.PipelineRunsConfigStruct {
+	if pipelineRunsConfig == nil || settings == nil {
+		return pipelineRunsConfig
+	}
+	result := *pipelineRunsConfig
+
+	if settings.DefaultNetworkProfile != "" {
+		result.DefaultNetworkProfile = settings.DefaultNetworkProfile
+	}
+
+	if allowed := settings.AllowedProfiles; allowed != nil {
+		result.AllowedNetworkProfiles = allowed.Network
+		result.AllowedSchedulingProfiles = allowed.Scheduling
+		result.AllowedResourceProfiles = allowed.Resources
+	}
+
+	if max := settings.MaxTimeout; max != nil && max.Duration > 0 {
+		if result.MaxTimeout == nil || max.Duration < result.MaxTimeout.Duration {
+			result.MaxTimeout = max.DeepCopy()
+		}
+		// the default timeout must not exceed the maximum timeout
+		if result.Timeout == nil {
+			// the default timeout of the run backend is unknown here
+			result.Timeout = &metav1.Duration{Duration: defaultTimeout}
+		}
+		if result.Timeout.Duration > result.MaxTimeout.Duration {
+			result.Timeout = result.MaxTimeout.DeepCopy()
+		}
+	}
+
+	if settings.MaxActivePipelineRuns != nil {
+		limit := *settings.MaxActivePipelineRuns
+		result.TenantMaxActivePipelineRuns = &limit
+	}
+
+	if settings.JenkinsfileRunnerImage != "" {
+		result.JenkinsfileRunnerImage = settings.JenkinsfileRunnerImage
+		result.JenkinsfileRunnerImagePullPolicy = settings.JenkinsfileRunnerImagePullPolicy
+		if result.JenkinsfileRunnerImagePullPolicy == "" {
+			result.JenkinsfileRunnerImagePullPolicy = defaultJenkinsfileRunnerImagePullPolicy
+		}
+	}
+
+	return &result
+}
+
+// effectiveTenantSettings returns the pipeline run settings effective for
+// a tenant as recorded in the status of its pipeline runs.
+// `maxActivePipelineRuns` is the limit of active pipeline runs in the
+// tenant namespace, where zero means unlimited.
+func effectiveTenantSettings(pipelineRunsConfig *cfg.PipelineRunsConfigStruct, maxActivePipelineRuns int64) *stewardv1alpha1.TenantPipelineRunsSettings {
+	result := &stewardv1alpha1.TenantPipelineRunsSettings{
+		DefaultNetworkProfile:            pipelineRunsConfig.DefaultNetworkProfile,
+		JenkinsfileRunnerImage:           pipelineRunsConfig.JenkinsfileRunnerImage,
+		JenkinsfileRunnerImagePullPolicy: pipelineRunsConfig.JenkinsfileRunnerImagePullPolicy,
+	}
+	if pipelineRunsConfig.MaxTimeout != nil {
+		result.MaxTimeout = pipelineRunsConfig.MaxTimeout.DeepCopy()
+	}
+	if maxActivePipelineRuns > 0 {
+		result.MaxActivePipelineRuns = &maxActivePipelineRuns
+	}
+	if len(pipelineRunsConfig.AllowedNetworkProfiles) > 0 ||
+		len(pipelineRunsConfig.AllowedSchedulingProfiles) > 0 ||
+		len(pipelineRunsConfig.AllowedResourceProfiles) > 0 {
+		result.AllowedProfiles = &stewardv1alpha1.AllowedProfiles{
+			Network:    pipelineRunsConfig.AllowedNetworkProfiles,
+			Scheduling: pipelineRunsConfig.AllowedSchedulingProfiles,
+			Resources:  pipelineRunsConfig.AllowedResourceProfiles,
+		}
+	}
+	return result
+}
+
+// validateAllowedProfiles checks whether the profiles used by the given
+// pipeline run spec are allowed by the settings of the tenant.
+func validateAllowedProfiles(spec *stewardv1alpha1.PipelineSpec, pipelineRunsConfig *cfg.PipelineRunsConfigStruct) error {
+	networkProfile := pipelineRunsConfig.DefaultNetworkProfile
+	if spec.Profiles != nil && spec.Profiles.Network != "" {
+		networkProfile = spec.Profiles.Network
+	}
+	for _, profile := range []struct {
+		kind    string
+		name    string
+		allowed []string
+	}{
+		{"network", networkProfile, pipelineRunsConfig.AllowedNetworkProfiles},
+		{"scheduling", schedulingProfileName(spec, pipelineRunsConfig), pipelineRunsConfig.AllowedSchedulingProfiles},
+		{"resource", resourceProfileName(spec, pipelineRunsConfig), pipelineRunsConfig.AllowedResourceProfiles},
+	} {
+		if profile.name == "" || len(profile.allowed) == 0 {
+			continue
+		}
+		if !utils.StringSliceContains(profile.allowed, profile.name) {
+			return fmt.Errorf("%s profile %q is not allowed for the tenant", profile.kind, profile.name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,348 @@
+package runctl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/k8s/fake"
+	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	runmocks "github.com/SAP/stewardci-core/pkg/runctl/run/mocks"
+	gomock "github.com/golang/mock/gomock"
+	assert "gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func Test_applyTenantPipelineRunsSettings(t *testing.T) {
+	t.Parallel()
+
+	int64Ptr := func(val int64) *int64 { return &val }
+	config := &cfg.PipelineRunsConfigStruct{
+		DefaultNetworkProfile:            "network1",
+		Timeout:                          metav1Duration(30 * time.Minute),
+		MaxTimeout:                       metav1Duration(2 * time.Hour),
+		JenkinsfileRunnerImage:           "jfr:1",
+		JenkinsfileRunnerImagePullPolicy: "Always",
+	}
+
+	for _, tc := range []struct {
+		name     string
+		settings *api.TenantPipelineRunsSettings
+		modify   func(expected *cfg.PipelineRunsConfigStruct)
+	}{
+		{"no_settings", nil, func(expected *cfg.PipelineRunsConfigStruct) {}},
+		{"empty_settings", &api.TenantPipelineRunsSettings{}, func(expected *cfg.PipelineRunsConfigStruct) {}},
+		{"default_network_profile", &api.TenantPipelineRunsSettings{DefaultNetworkProfile: "network2"}, func(expected *cfg.PipelineRunsConfigStruct) {
+			expected.DefaultNetworkProfile = "network2"
+		}},
+		{"allowed_profiles", &api.TenantPipelineRunsSettings{AllowedProfiles: &api.AllowedProfiles{
+			Network:    []string{"network1"},
+			Scheduling: []string{"scheduling1"},
+			Resources:  []string{"resources1"},
+		}}, func(expected *cfg.PipelineRunsConfigStruct) {
+			expected.AllowedNetworkProfiles = []string{"network1"}
+			expected.AllowedSchedulingProfiles = []string{"scheduling1"}
+			expected.AllowedResourceProfiles = []string{"resources1"}
+		}},
+		{"max_timeout_lowered", &api.TenantPipelineRunsSettings{MaxTimeout: metav1Duration(time.Hour)}, func(expected *cfg.PipelineRunsConfigStruct) {
+			expected.MaxTimeout = metav1Duration(time.Hour)
+		}},
+		{"max_timeout_below_default_timeout", &api.TenantPipelineRunsSettings{MaxTimeout: metav1Duration(10 * time.Minute)}, func(expected *cfg.PipelineRunsConfigStruct) {
+			expected.MaxTimeout = metav1Duration(10 * time.Minute)
+			expected.Timeout = metav1Duration(10 * time.Minute)
+		}},
+		{"max_timeout_cannot_be_raised", &api.TenantPipelineRunsSettings{MaxTimeout: metav1Duration(3 * time.Hour)}, func(expected *cfg.PipelineRunsConfigStruct) {}},
+		{"max_active_pipeline_runs", &api.TenantPipelineRunsSettings{MaxActivePipelineRuns: int64Ptr(2)}, func(expected *cfg.PipelineRunsConfigStruct) {
+			expected.TenantMaxActivePipelineRuns = int64Ptr(2)
+		}},
+		{"image", &api.TenantPipelineRunsSettings{JenkinsfileRunnerImage: "jfr:2"}, func(expected *cfg.PipelineRunsConfigStruct) {
+			expected.JenkinsfileRunnerImage = "jfr:2"
+			expected.JenkinsfileRunnerImagePullPolicy = "IfNotPresent"
+		}},
+		{"image_with_pull_policy", &api.TenantPipelineRunsSettings{JenkinsfileRunnerImage: "jfr:2", JenkinsfileRunnerImagePullPolicy: "Never"}, func(expected *cfg.PipelineRunsConfigStruct) {
+			expected.JenkinsfileRunnerImage = "jfr:2"
+			expected.JenkinsfileRunnerImagePullPolicy = "Never"
+		}},
+		{"pull_policy_without_image_ignored", &api.TenantPipelineRunsSettings{JenkinsfileRunnerImagePullPolicy: "Never"}, func(expected *cfg.PipelineRunsConfigStruct) {}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			expected := *config
+			tc.modify(&expected)
+
+			// EXERCISE
+			result := applyTenantPipelineRunsSettings(config, tc.settings)
+
+			// VERIFY
+			assert.DeepEqual(t, &expected, result)
+		})
+	}
+}
+
+func Test_applyTenantPipelineRunsSettings_DoesNotModifyConfig(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	config := &cfg.PipelineRunsConfigStruct{DefaultNetworkProfile: "network1"}
+	settings := &api.TenantPipelineRunsSettings{
+		DefaultNetworkProfile: "network2",
+		MaxTimeout:            metav1Duration(time.Minute),
+	}
+
+	// EXERCISE
+	applyTenantPipelineRunsSettings(config, settings)
+
+	// VERIFY
+	assert.DeepEqual(t, &cfg.PipelineRunsConfigStruct{DefaultNetworkProfile: "network1"}, config)
+}
+
+func Test_TenantPipelineRunsConfig(t *testing.T) {
+	t.Parallel()
+
+	labelledNamespace := func(labels map[string]string) *corev1.Namespace {
+		namespace := fake.Namespace("tenant1")
+		namespace.SetLabels(labels)
+		return namespace
+	}
+	ownerLabels := map[string]string{
+		api.LabelOwnerClientNamespace: "client1",
+		api.LabelOwnerTenantName:      "tenantA",
+	}
+	tenant := fake.Tenant("tenantA", "client1")
+	tenant.Spec.PipelineRuns = &api.TenantPipelineRunsSettings{DefaultNetworkProfile: "network2"}
+
+	for _, tc := range []struct {
+		name                   string
+		objects                []runtime.Object
+		expectedNetworkProfile string
+	}{
+		{"no_namespace", nil, "network1"},
+		{"namespace_without_labels", []runtime.Object{labelledNamespace(nil)}, "network1"},
+		{"no_tenant", []runtime.Object{labelledNamespace(ownerLabels)}, "network1"},
+		{"tenant_without_settings", []runtime.Object{labelledNamespace(ownerLabels), fake.Tenant("tenantA", "client1")}, "network1"},
+		{"tenant_with_settings", []runtime.Object{labelledNamespace(ownerLabels), tenant}, "network2"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			cf := newFakeClientFactory(tc.objects...)
+			config := &cfg.PipelineRunsConfigStruct{DefaultNetworkProfile: "network1"}
+
+			// EXERCISE
+			result, resultErr := TenantPipelineRunsConfig(context.Background(), cf, "tenant1", config)
+
+			// VERIFY
+			assert.NilError(t, resultErr)
+			assert.Equal(t, tc.expectedNetworkProfile, result.DefaultNetworkProfile)
+		})
+	}
+}
+
+func Test_Controller_syncHandler_TenantSettings(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := newPipelineRunWithState("foo", "ns1", api.StateNew, time.Now())
+	examinee, cf := newController(run)
+	examinee.pipelineRunStore.Add(run)
+	tenantNamespace := fake.Namespace("ns1")
+	tenantNamespace.SetLabels(map[string]string{
+		api.LabelOwnerClientNamespace: "client1",
+		api.LabelOwnerTenantName:      "tenant1",
+	})
+	_, err := cf.CoreV1().Namespaces().Create(ctx, tenantNamespace, metav1.CreateOptions{})
+	assert.NilError(t, err)
+	tenant := fake.Tenant("tenant1", "client1")
+	maxActive := int64(2)
+	tenant.Spec.PipelineRuns = &api.TenantPipelineRunsSettings{
+		MaxTimeout:            metav1Duration(10 * time.Minute),
+		MaxActivePipelineRuns: &maxActive,
+	}
+	_, err = cf.StewardV1alpha1().Tenants("client1").Create(ctx, tenant, metav1.CreateOptions{})
+	assert.NilError(t, err)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	runManager := runmocks.NewMockManager(mockCtrl)
+	runManager.EXPECT().Start(gomock.Any(), gomock.Any(), gomock.Any()).Return("", "", nil)
+	examinee.testing = &controllerTesting{
+		createRunManagerStub:       runManager,
+		loadPipelineRunsConfigStub: newRunsConfigWithLimits(0, 5),
+		isMaintenanceModeStub:      newIsMaintenanceModeStub(false, nil),
+	}
+
+	// EXERCISE
+	resultErr := examinee.syncHandler("ns1/foo")
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	result, err := getAPIPipelineRun(cf, "foo", "ns1")
+	assert.NilError(t, err)
+	assert.Equal(t, api.StateWaiting, result.Status.State)
+	assert.DeepEqual(t, &api.TenantPipelineRunsSettings{
+		MaxTimeout:            metav1Duration(10 * time.Minute),
+		MaxActivePipelineRuns: &maxActive,
+	}, result.Status.TenantSettings)
+	assert.DeepEqual(t, metav1Duration(10*time.Minute), result.Status.Timeout)
+}
+
+func Test_Controller_syncHandler_AllowedResourceProfiles_TenantAndClientNamespace(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		profile       string
+		expectedState api.State
+		expectedMsg   string
+	}{
+		{"allowed_by_both", "large", api.StateWaiting, ""},
+		{"not_allowed_by_client_namespace", "small", api.StateFinished, `resource profile "small" is not allowed for client namespace "client1"`},
+		{"not_allowed_by_tenant", "xlarge", api.StateFinished, `resource profile "xlarge" is not allowed for the tenant`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			ctx := context.Background()
+			run := newPipelineRunWithState("foo", "ns1", api.StateNew, time.Now())
+			run.Spec.Profiles = &api.Profiles{Resources: tc.profile}
+			examinee, cf := newController(run)
+			examinee.pipelineRunStore.Add(run)
+			tenantNamespace := fake.Namespace("ns1")
+			tenantNamespace.SetLabels(map[string]string{
+				api.LabelOwnerClientNamespace: "client1",
+				api.LabelOwnerTenantName:      "tenant1",
+			})
+			clientNamespace := fake.NamespaceWithAnnotations("client1", map[string]string{
+				api.AnnotationAllowedResourceProfiles: "large,xlarge",
+			})
+			for _, namespace := range []*corev1.Namespace{tenantNamespace, clientNamespace} {
+				_, err := cf.CoreV1().Namespaces().Create(ctx, namespace, metav1.CreateOptions{})
+				assert.NilError(t, err)
+			}
+			tenant := fake.Tenant("tenant1", "client1")
+			tenant.Spec.PipelineRuns = &api.TenantPipelineRunsSettings{
+				AllowedProfiles: &api.AllowedProfiles{Resources: []string{"small", "large"}},
+			}
+			_, err := cf.StewardV1alpha1().Tenants("client1").Create(ctx, tenant, metav1.CreateOptions{})
+			assert.NilError(t, err)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			runManager := runmocks.NewMockManager(mockCtrl)
+			runManager.EXPECT().Start(gomock.Any(), gomock.Any(), gomock.Any()).Return("", "", nil).AnyTimes()
+			examinee.testing = &controllerTesting{
+				createRunManagerStub: runManager,
+				loadPipelineRunsConfigStub: func(ctx context.Context) (*cfg.PipelineRunsConfigStruct, error) {
+					return &cfg.PipelineRunsConfigStruct{
+						ResourceProfiles: map[string]*cfg.ResourceProfile{
+							"small":  {},
+							"large":  {},
+							"xlarge": {},
+						},
+					}, nil
+				},
+				isMaintenanceModeStub: newIsMaintenanceModeStub(false, nil),
+			}
+
+			// EXERCISE
+			resultErr := examinee.syncHandler("ns1/foo")
+
+			// VERIFY
+			assert.NilError(t, resultErr)
+			result, err := getAPIPipelineRun(cf, "foo", "ns1")
+			assert.NilError(t, err)
+			assert.Equal(t, tc.expectedState, result.Status.State)
+			if tc.expectedMsg != "" {
+				assert.Equal(t, api.ResultErrorConfig, result.Status.Result)
+				assert.Assert(t, is.Contains(result.Status.Message, tc.expectedMsg))
+			}
+		})
+	}
+}
+
+func Test_effectiveTenantSettings(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	config := &cfg.PipelineRunsConfigStruct{
+		DefaultNetworkProfile:            "network1",
+		MaxTimeout:                       metav1Duration(time.Hour),
+		AllowedResourceProfiles:          []string{"resources1"},
+		JenkinsfileRunnerImage:           "jfr:1",
+		JenkinsfileRunnerImagePullPolicy: "IfNotPresent",
+	}
+	maxActive := int64(2)
+
+	// EXERCISE
+	result := effectiveTenantSettings(config, maxActive)
+
+	// VERIFY
+	assert.DeepEqual(t, &api.TenantPipelineRunsSettings{
+		DefaultNetworkProfile:            "network1",
+		AllowedProfiles:                  &api.AllowedProfiles{Resources: []string{"resources1"}},
+		MaxTimeout:                       metav1Duration(time.Hour),
+		MaxActivePipelineRuns:            &maxActive,
+		JenkinsfileRunnerImage:           "jfr:1",
+		JenkinsfileRunnerImagePullPolicy: "IfNotPresent",
+	}, result)
+}
+
+func Test_validateAllowedProfiles(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		profiles      *api.Profiles
+		config        *cfg.PipelineRunsConfigStruct
+		expectedError string
+	}{
+		{"unrestricted", &api.Profiles{Network: "network1"}, &cfg.PipelineRunsConfigStruct{}, ""},
+		{"no_profiles", nil, &cfg.PipelineRunsConfigStruct{AllowedNetworkProfiles: []string{"network1"}}, ""},
+		{"allowed", &api.Profiles{Network: "network1", Resources: "resources1"}, &cfg.PipelineRunsConfigStruct{
+			AllowedNetworkProfiles:  []string{"network2", "network1"},
+			AllowedResourceProfiles: []string{"resources1"},
+		}, ""},
+		{"network_not_allowed", &api.Profiles{Network: "network1"}, &cfg.PipelineRunsConfigStruct{
+			AllowedNetworkProfiles: []string{"network2"},
+		}, `network profile "network1" is not allowed for the tenant`},
+		{"default_network_not_allowed", nil, &cfg.PipelineRunsConfigStruct{
+			DefaultNetworkProfile:  "network1",
+			AllowedNetworkProfiles: []string{"network2"},
+		}, `network profile "network1" is not allowed for the tenant`},
+		{"default_scheduling_not_allowed", nil, &cfg.PipelineRunsConfigStruct{
+			DefaultSchedulingProfile:  "scheduling1",
+			AllowedSchedulingProfiles: []string{"scheduling2"},
+		}, `scheduling profile "scheduling1" is not allowed for the tenant`},
+		{"resources_not_allowed", &api.Profiles{Resources: "resources1"}, &cfg.PipelineRunsConfigStruct{
+			AllowedResourceProfiles: []string{"resources2"},
+		}, `resource profile "resources1" is not allowed for the tenant`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			spec := &api.PipelineSpec{Profiles: tc.profiles}
+
+			// EXERCISE
+			resultErr := validateAllowedProfiles(spec, tc.config)
+
+			// VERIFY
+			if tc.expectedError == "" {
+				assert.NilError(t, resultErr)
+			} else {
+				assert.Error(t, resultErr, tc.expectedError)
+			}
+		})
+	}
+}
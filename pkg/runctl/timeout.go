@@ -0,0 +1,45 @@
+package runctl
+
+import (
+	"fmt"
+	"time"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// defaultTimeout is the timeout of pipeline runs using the pod run backend
+// if neither the pipeline run nor the pipeline runs configuration define
+// one. It equals the Tekton default.
+const defaultTimeout = 60 * time.Minute
+
+// effectiveTimeout returns the maximum execution time of a pipeline run.
+// The timeout from the pipeline run spec takes precedence over the one
+// from the pipeline runs configuration. It returns `nil` if neither defines
+// a timeout, so that the default timeout of the run backend applies, e.g.
+// the default timeout configured for Tekton.
+func effectiveTimeout(spec *stewardv1alpha1.PipelineSpec, pipelineRunsConfig *cfg.PipelineRunsConfigStruct) *metav1.Duration {
+	if spec.Timeout != nil {
+		return spec.Timeout.DeepCopy()
+	}
+	if pipelineRunsConfig.Timeout != nil {
+		return pipelineRunsConfig.Timeout.DeepCopy()
+	}
+	return nil
+}
+
+// validateTimeout checks the timeout requested in the pipeline run spec
+// against the maximum timeout from the pipeline runs configuration.
+func validateTimeout(spec *stewardv1alpha1.PipelineSpec, pipelineRunsConfig *cfg.PipelineRunsConfigStruct) error {
+	if spec.Timeout == nil {
+		return nil
+	}
+	if spec.Timeout.Duration <= 0 {
+		return fmt.Errorf("timeout %s is invalid: must be positive", spec.Timeout.Duration)
+	}
+	if max := pipelineRunsConfig.MaxTimeout; max != nil && spec.Timeout.Duration > max.Duration {
+		return fmt.Errorf("timeout %s exceeds the maximum timeout %s", spec.Timeout.Duration, max.Duration)
+	}
+	return nil
+}
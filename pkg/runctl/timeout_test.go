@@ -0,0 +1,80 @@
+package runctl
+
+import (
+	"testing"
+	"time"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	assert "gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_effectiveTimeout(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name            string
+		specTimeout     *metav1.Duration
+		configTimeout   *metav1.Duration
+		expectedTimeout *metav1.Duration
+	}{
+		{"not_set", nil, nil, nil},
+		{"config", nil, metav1Duration(10 * time.Minute), metav1Duration(10 * time.Minute)},
+		{"spec", metav1Duration(5 * time.Minute), nil, metav1Duration(5 * time.Minute)},
+		{"spec_overrides_config", metav1Duration(5 * time.Minute), metav1Duration(10 * time.Minute), metav1Duration(5 * time.Minute)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			spec := &stewardv1alpha1.PipelineSpec{Timeout: tc.specTimeout}
+			config := &cfg.PipelineRunsConfigStruct{Timeout: tc.configTimeout}
+
+			// EXERCISE
+			result := effectiveTimeout(spec, config)
+
+			// VERIFY
+			assert.DeepEqual(t, tc.expectedTimeout, result)
+		})
+	}
+}
+
+func Test_validateTimeout(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		specTimeout   *metav1.Duration
+		maxTimeout    *metav1.Duration
+		expectedError string
+	}{
+		{"not_set", nil, metav1Duration(time.Hour), ""},
+		{"no_max", metav1Duration(100 * time.Hour), nil, ""},
+		{"below_max", metav1Duration(time.Minute), metav1Duration(time.Hour), ""},
+		{"equals_max", metav1Duration(time.Hour), metav1Duration(time.Hour), ""},
+		{"exceeds_max", metav1Duration(2 * time.Hour), metav1Duration(time.Hour), "timeout 2h0m0s exceeds the maximum timeout 1h0m0s"},
+		{"zero", metav1Duration(0), nil, "timeout 0s is invalid: must be positive"},
+		{"negative", metav1Duration(-time.Minute), nil, "timeout -1m0s is invalid: must be positive"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			spec := &stewardv1alpha1.PipelineSpec{Timeout: tc.specTimeout}
+			config := &cfg.PipelineRunsConfigStruct{MaxTimeout: tc.maxTimeout}
+
+			// EXERCISE
+			resultErr := validateTimeout(spec, config)
+
+			// VERIFY
+			if tc.expectedError == "" {
+				assert.NilError(t, resultErr)
+			} else {
+				assert.Error(t, resultErr, tc.expectedError)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package runctl
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// ValidatePipelineRun checks the spec of the given pipeline run.
+// It is performed by the run controller before a pipeline run gets started
+// and by the validating admission webhook when a pipeline run gets created
+// or its spec gets updated.
+// If `pipelineRunsConfig` is `nil`, the checks depending on the pipeline
+// runs configuration are skipped.
+func ValidatePipelineRun(pipelineRun *api.PipelineRun, pipelineRunsConfig *cfg.PipelineRunsConfigStruct) error {
+	spec := &pipelineRun.Spec
+
+	switch spec.Intent {
+	case "", api.IntentRun, api.IntentAbort:
+	default:
+		return fmt.Errorf("field \"spec.intent\" has invalid value %q", spec.Intent)
+	}
+
+	if spec.JenkinsFile.URL != "" {
+		// a pipeline run wrapper without client factory is read-only
+		wrapper, err := k8s.NewPipelineRun(context.Background(), pipelineRun, nil)
+		if err != nil {
+			return err
+		}
+		if _, err := wrapper.GetPipelineRepoServerURL(); err != nil {
+			return err
+		}
+	}
+
+	if spec.Logging != nil && spec.Logging.Elasticsearch != nil && spec.Logging.Elasticsearch.IndexURL != "" {
+		if _, err := ensureValidElasticsearchIndexURL(spec.Logging.Elasticsearch.IndexURL); err != nil {
+			return errors.Wrapf(err,
+				"field \"spec.logging.elasticsearch.indexURL\" has invalid value %q",
+				spec.Logging.Elasticsearch.IndexURL,
+			)
+		}
+	}
+
+	if spec.TTLSecondsAfterFinished != nil && *spec.TTLSecondsAfterFinished < 0 {
+		return fmt.Errorf("field \"spec.ttlSecondsAfterFinished\" has invalid value %d: must not be negative", *spec.TTLSecondsAfterFinished)
+	}
+
+	if err := validatePipelineSecrets(spec); err != nil {
+		return err
+	}
+
+	if err := validateRetryPolicy(spec); err != nil {
+		return err
+	}
+
+	if err := validateSidecars(spec, pipelineRunsConfig); err != nil {
+		return err
+	}
+
+	if err := validateJenkinsfileRunnerResources(spec, pipelineRunsConfig); err != nil {
+		return err
+	}
+
+	if pipelineRunsConfig == nil {
+		return nil
+	}
+
+	if pipelineRunsConfig.RunBackend == cfg.RunBackendPod && spec.JenkinsFile.RepoAuthSecret != "" {
+		// There is no equivalent to Tekton's credentials initialization
+		// providing the clone secret to the Jenkinsfile Runner.
+		return fmt.Errorf("field \"spec.jenkinsFile.repoAuthSecret\" is not supported by the %q run backend", cfg.RunBackendPod)
+	}
+
+	if spec.Profiles != nil && spec.Profiles.Network != "" {
+		if _, exists := pipelineRunsConfig.NetworkPolicies[spec.Profiles.Network]; !exists {
+			return fmt.Errorf("network profile %q does not exist", spec.Profiles.Network)
+		}
+	}
+
+	if _, err := schedulingProfile(spec, pipelineRunsConfig); err != nil {
+		return err
+	}
+
+	if _, err := resourceProfile(spec, pipelineRunsConfig); err != nil {
+		return err
+	}
+
+	if err := validateAllowedProfiles(spec, pipelineRunsConfig); err != nil {
+		return err
+	}
+
+	return validateTimeout(spec, pipelineRunsConfig)
+}
+
+// validatePipelineSecrets checks the entries of `spec.secrets` and
+// `spec.secretRefs`.
+func validatePipelineSecrets(spec *api.PipelineSpec) error {
+	for i, name := range spec.Secrets {
+		if err := validatePipelineSecretName(fmt.Sprintf("spec.secrets[%d]", i), name); err != nil {
+			return err
+		}
+	}
+	for i, secret := range spec.SecretRefs {
+		if err := validatePipelineSecretName(fmt.Sprintf("spec.secretRefs[%d].name", i), secret.Name); err != nil {
+			return err
+		}
+		if secret.TargetName != "" {
+			if errs := validation.IsDNS1123Subdomain(secret.TargetName); len(errs) > 0 {
+				return fmt.Errorf("field \"spec.secretRefs[%d].targetName\" has invalid value %q: %s", i, secret.TargetName, strings.Join(errs, ", "))
+			}
+		}
+		keys := map[string]bool{}
+		targetKeys := map[string]bool{}
+		for j, key := range secret.Keys {
+			if key.Key == "" {
+				return fmt.Errorf("field \"spec.secretRefs[%d].keys[%d].key\" must not be empty", i, j)
+			}
+			if keys[key.Key] {
+				return fmt.Errorf("field \"spec.secretRefs[%d].keys[%d].key\" has invalid value %q: must be unique", i, j, key.Key)
+			}
+			keys[key.Key] = true
+			targetKey := key.TargetKey
+			if targetKey == "" {
+				targetKey = key.Key
+			}
+			if errs := validation.IsConfigMapKey(targetKey); len(errs) > 0 {
+				return fmt.Errorf("secret key %q of field \"spec.secretRefs[%d].keys[%d]\" is invalid: %s", targetKey, i, j, strings.Join(errs, ", "))
+			}
+			if targetKeys[targetKey] {
+				return fmt.Errorf("secret key %q of field \"spec.secretRefs[%d].keys[%d]\" is invalid: must be unique", targetKey, i, j)
+			}
+			targetKeys[targetKey] = true
+		}
+	}
+	return nil
+}
+
+func validatePipelineSecretName(field, name string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("field %q must not be empty", field)
+	}
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		return fmt.Errorf("field %q has invalid value %q: %s", field, name, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// ValidatePipelineRunUpdate checks whether the spec of a pipeline run may be
+// changed from `oldRun` to `newRun`. Once a pipeline run has been started,
+// only its intent and the abort reason may be changed, e.g. to abort it.
+func ValidatePipelineRunUpdate(oldRun, newRun *api.PipelineRun) error {
+	if isPendingState(oldRun.Status.State) {
+		return nil
+	}
+	oldSpec := oldRun.Spec.DeepCopy()
+	oldSpec.Intent = newRun.Spec.Intent
+	oldSpec.AbortReason = newRun.Spec.AbortReason
+	if !equality.Semantic.DeepEqual(oldSpec, &newRun.Spec) {
+		return fmt.Errorf(
+			"the spec of pipeline run %s/%s must not be changed after it has been started, except fields \"spec.intent\" and \"spec.abortReason\"",
+			newRun.GetNamespace(), newRun.GetName(),
+		)
+	}
+	return nil
+}
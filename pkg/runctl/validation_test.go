@@ -0,0 +1,302 @@
+package runctl
+
+import (
+	"testing"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	cfg "github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	assert "gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	k8sresource "k8s.io/apimachinery/pkg/api/resource"
+)
+
+func Test_ValidatePipelineRun(t *testing.T) {
+	t.Parallel()
+
+	int64Ptr := func(val int64) *int64 { return &val }
+	validConfig := &cfg.PipelineRunsConfigStruct{
+		MaxTimeout:      metav1Duration(time.Hour),
+		NetworkPolicies: map[string]string{"profile1": "policy1"},
+		SchedulingProfiles: map[string]*cfg.SchedulingProfile{
+			"profile2": {PriorityClassName: "high"},
+		},
+		ResourceProfiles: map[string]*cfg.ResourceProfile{
+			"profile3": {LimitRange: "limitRange1"},
+		},
+	}
+
+	for _, tc := range []struct {
+		name          string
+		spec          api.PipelineSpec
+		config        *cfg.PipelineRunsConfigStruct
+		expectedError string
+	}{
+		{
+			name:   "empty_spec",
+			spec:   api.PipelineSpec{},
+			config: validConfig,
+		},
+		{
+			name: "valid_spec",
+			spec: api.PipelineSpec{
+				Intent:      api.IntentRun,
+				JenkinsFile: api.JenkinsFile{URL: "https://github.com/foo/bar"},
+				Logging: &api.Logging{Elasticsearch: &api.Elasticsearch{
+					IndexURL: "http://es.example.com/index1/_doc",
+				}},
+				Profiles:                &api.Profiles{Network: "profile1", Scheduling: "profile2", Resources: "profile3"},
+				Timeout:                 metav1Duration(time.Minute),
+				TTLSecondsAfterFinished: int64Ptr(0),
+			},
+			config: validConfig,
+		},
+		{
+			name:          "unknown_intent",
+			spec:          api.PipelineSpec{Intent: "foo"},
+			config:        validConfig,
+			expectedError: `field "spec.intent" has invalid value "foo"`,
+		},
+		{
+			name:          "repo_url_scheme",
+			spec:          api.PipelineSpec{JenkinsFile: api.JenkinsFile{URL: "ftp://github.com/foo/bar"}},
+			config:        validConfig,
+			expectedError: `value "ftp://github.com/foo/bar" of field spec.jenkinsFile.url is invalid [PipelineRun{name: run1, namespace: ns1, state: }]: scheme not supported: "ftp"`,
+		},
+		{
+			name: "elasticsearch_index_url_scheme",
+			spec: api.PipelineSpec{Logging: &api.Logging{Elasticsearch: &api.Elasticsearch{
+				IndexURL: "ftp://es.example.com/index1",
+			}}},
+			config:        validConfig,
+			expectedError: `field "spec.logging.elasticsearch.indexURL" has invalid value "ftp://es.example.com/index1": scheme not supported: "ftp"`,
+		},
+		{
+			name:          "negative_ttl",
+			spec:          api.PipelineSpec{TTLSecondsAfterFinished: int64Ptr(-1)},
+			config:        validConfig,
+			expectedError: `field "spec.ttlSecondsAfterFinished" has invalid value -1: must not be negative`,
+		},
+		{
+			name: "valid_secrets",
+			spec: api.PipelineSpec{Secrets: []string{"secret1"}, SecretRefs: []api.PipelineSecret{
+				{Name: "secret1"},
+				{Name: "secret1", TargetName: "target1", Keys: []api.PipelineSecretKey{
+					{Key: "key1", TargetKey: "username"},
+					{Key: "key2"},
+				}},
+			}},
+			config: validConfig,
+		},
+		{
+			name:          "secret_without_name",
+			spec:          api.PipelineSpec{SecretRefs: []api.PipelineSecret{{Name: "secret1"}, {}}},
+			config:        validConfig,
+			expectedError: `field "spec.secretRefs[1].name" must not be empty`,
+		},
+		{
+			name:          "secret_name_empty",
+			spec:          api.PipelineSpec{Secrets: []string{"secret1", ""}},
+			config:        validConfig,
+			expectedError: `field "spec.secrets[1]" must not be empty`,
+		},
+		{
+			name:          "secret_name_leading_whitespace",
+			spec:          api.PipelineSpec{Secrets: []string{" secret1"}},
+			config:        validConfig,
+			expectedError: `field "spec.secrets[0]" has invalid value " secret1": a lowercase RFC 1123 subdomain must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character (e.g. 'example.com', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')`,
+		},
+		{
+			name:          "secret_name_path",
+			spec:          api.PipelineSpec{SecretRefs: []api.PipelineSecret{{Name: "../tenant2/secret1"}}},
+			config:        validConfig,
+			expectedError: `field "spec.secretRefs[0].name" has invalid value "../tenant2/secret1": a lowercase RFC 1123 subdomain must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character (e.g. 'example.com', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')`,
+		},
+		{
+			name:          "secret_target_name_invalid",
+			spec:          api.PipelineSpec{SecretRefs: []api.PipelineSecret{{Name: "secret1", TargetName: "Target_1"}}},
+			config:        validConfig,
+			expectedError: `field "spec.secretRefs[0].targetName" has invalid value "Target_1": a lowercase RFC 1123 subdomain must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character (e.g. 'example.com', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')`,
+		},
+		{
+			name: "secret_key_empty",
+			spec: api.PipelineSpec{SecretRefs: []api.PipelineSecret{{Name: "secret1", Keys: []api.PipelineSecretKey{
+				{TargetKey: "key1"},
+			}}}},
+			config:        validConfig,
+			expectedError: `field "spec.secretRefs[0].keys[0].key" must not be empty`,
+		},
+		{
+			name: "secret_target_key_invalid",
+			spec: api.PipelineSpec{SecretRefs: []api.PipelineSecret{{Name: "secret1", Keys: []api.PipelineSecretKey{
+				{Key: "key1", TargetKey: "a/b"},
+			}}}},
+			config:        validConfig,
+			expectedError: `secret key "a/b" of field "spec.secretRefs[0].keys[0]" is invalid: a valid config key must consist of alphanumeric characters, '-', '_' or '.' (e.g. 'key.name',  or 'KEY_NAME',  or 'key-name', regex used for validation is '[-._a-zA-Z0-9]+')`,
+		},
+		{
+			name: "secret_target_key_duplicate",
+			spec: api.PipelineSpec{SecretRefs: []api.PipelineSecret{{Name: "secret1", Keys: []api.PipelineSecretKey{
+				{Key: "key1", TargetKey: "key2"},
+				{Key: "key2"},
+			}}}},
+			config:        validConfig,
+			expectedError: `secret key "key2" of field "spec.secretRefs[0].keys[1]" is invalid: must be unique`,
+		},
+		{
+			name: "secret_key_duplicate",
+			spec: api.PipelineSpec{SecretRefs: []api.PipelineSecret{{Name: "secret1", Keys: []api.PipelineSecretKey{
+				{Key: "key1", TargetKey: "username"},
+				{Key: "key1", TargetKey: "password"},
+			}}}},
+			config:        validConfig,
+			expectedError: `field "spec.secretRefs[0].keys[1].key" has invalid value "key1": must be unique`,
+		},
+		{
+			name:          "retry_policy",
+			spec:          api.PipelineSpec{RetryPolicy: &api.RetryPolicy{MaxAttempts: -1}},
+			config:        validConfig,
+			expectedError: "retry policy: maxAttempts -1 is invalid: must not be negative",
+		},
+		{
+			name:          "invalid_sidecar",
+			spec:          api.PipelineSpec{Sidecars: []api.Sidecar{{Name: "db"}}},
+			config:        validConfig,
+			expectedError: `field "spec.sidecars[0].image" must not be empty`,
+		},
+		{
+			name:          "sidecar_image_not_allowed",
+			spec:          api.PipelineSpec{Sidecars: []api.Sidecar{{Name: "db", Image: "postgres"}}},
+			config:        validConfig,
+			expectedError: `field "spec.sidecars[0].image" has invalid value "postgres": image is not permitted`,
+		},
+		{
+			name:   "repo_auth_secret_tekton_backend",
+			spec:   api.PipelineSpec{JenkinsFile: api.JenkinsFile{URL: "https://github.com/foo/bar", RepoAuthSecret: "secret1"}},
+			config: &cfg.PipelineRunsConfigStruct{RunBackend: cfg.RunBackendTekton},
+		},
+		{
+			name:          "repo_auth_secret_pod_backend",
+			spec:          api.PipelineSpec{JenkinsFile: api.JenkinsFile{URL: "https://github.com/foo/bar", RepoAuthSecret: "secret1"}},
+			config:        &cfg.PipelineRunsConfigStruct{RunBackend: cfg.RunBackendPod},
+			expectedError: `field "spec.jenkinsFile.repoAuthSecret" is not supported by the "pod" run backend`,
+		},
+		{
+			name: "jenkinsfile_runner_resources_exceed_maximum",
+			spec: api.PipelineSpec{JenkinsfileRunner: &api.JenkinsfileRunnerSpec{
+				Resources: &corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{corev1.ResourceCPU: k8sresource.MustParse("16")},
+				},
+			}},
+			config: &cfg.PipelineRunsConfigStruct{
+				JenkinsfileRunnerResourcesMax: corev1.ResourceList{corev1.ResourceCPU: k8sresource.MustParse("8")},
+			},
+			expectedError: `field "spec.jenkinsfileRunner.resources.limits.cpu" has invalid value "16": exceeds the maximum 8`,
+		},
+		{
+			name:          "unknown_network_profile",
+			spec:          api.PipelineSpec{Profiles: &api.Profiles{Network: "unknown"}},
+			config:        validConfig,
+			expectedError: `network profile "unknown" does not exist`,
+		},
+		{
+			name:          "unknown_scheduling_profile",
+			spec:          api.PipelineSpec{Profiles: &api.Profiles{Scheduling: "unknown"}},
+			config:        validConfig,
+			expectedError: `scheduling profile "unknown" does not exist`,
+		},
+		{
+			name:          "unknown_resource_profile",
+			spec:          api.PipelineSpec{Profiles: &api.Profiles{Resources: "unknown"}},
+			config:        validConfig,
+			expectedError: `resource profile "unknown" does not exist`,
+		},
+		{
+			name: "profile_not_allowed_for_tenant",
+			spec: api.PipelineSpec{Profiles: &api.Profiles{Network: "profile1", Scheduling: "profile2"}},
+			config: &cfg.PipelineRunsConfigStruct{
+				NetworkPolicies:           validConfig.NetworkPolicies,
+				SchedulingProfiles:        validConfig.SchedulingProfiles,
+				AllowedNetworkProfiles:    []string{"profile1"},
+				AllowedSchedulingProfiles: []string{"other"},
+			},
+			expectedError: `scheduling profile "profile2" is not allowed for the tenant`,
+		},
+		{
+			name:          "timeout_exceeds_maximum",
+			spec:          api.PipelineSpec{Timeout: metav1Duration(2 * time.Hour)},
+			config:        validConfig,
+			expectedError: "timeout 2h0m0s exceeds the maximum timeout 1h0m0s",
+		},
+		{
+			name: "no_config",
+			spec: api.PipelineSpec{
+				Profiles: &api.Profiles{Network: "unknown"},
+				Sidecars: []api.Sidecar{{Name: "db", Image: "postgres"}},
+				Timeout:  metav1Duration(2 * time.Hour),
+			},
+			config: nil,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			run := fake.PipelineRun("run1", "ns1", tc.spec)
+
+			// EXERCISE
+			resultErr := ValidatePipelineRun(run, tc.config)
+
+			// VERIFY
+			if tc.expectedError == "" {
+				assert.NilError(t, resultErr)
+			} else {
+				assert.Error(t, resultErr, tc.expectedError)
+			}
+		})
+	}
+}
+
+func Test_ValidatePipelineRunUpdate(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		state         api.State
+		modify        func(spec *api.PipelineSpec)
+		expectedError bool
+	}{
+		{"new_spec_changed", api.StateNew, func(spec *api.PipelineSpec) { spec.Args = map[string]string{"a": "b"} }, false},
+		{"queued_spec_changed", api.StateQueued, func(spec *api.PipelineSpec) { spec.Args = map[string]string{"a": "b"} }, false},
+		{"running_unchanged", api.StateRunning, func(spec *api.PipelineSpec) {}, false},
+		{"running_intent_changed", api.StateRunning, func(spec *api.PipelineSpec) { spec.Intent = api.IntentAbort }, false},
+		{"running_abort_reason_changed", api.StateRunning, func(spec *api.PipelineSpec) { spec.Intent, spec.AbortReason = api.IntentAbort, "reason1" }, false},
+		{"running_spec_changed", api.StateRunning, func(spec *api.PipelineSpec) { spec.Args = map[string]string{"a": "b"} }, true},
+		{"finished_spec_changed", api.StateFinished, func(spec *api.PipelineSpec) { spec.Secrets = []string{"secret1"} }, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			oldRun := fake.PipelineRun("run1", "ns1", api.PipelineSpec{
+				JenkinsFile: api.JenkinsFile{URL: "https://github.com/foo/bar"},
+			})
+			oldRun.Status.State = tc.state
+			newRun := oldRun.DeepCopy()
+			tc.modify(&newRun.Spec)
+
+			// EXERCISE
+			resultErr := ValidatePipelineRunUpdate(oldRun, newRun)
+
+			// VERIFY
+			if tc.expectedError {
+				assert.Error(t, resultErr, `the spec of pipeline run ns1/run1 must not be changed after it has been started, except fields "spec.intent" and "spec.abortReason"`)
+			} else {
+				assert.NilError(t, resultErr)
+			}
+		})
+	}
+}
@@ -91,3 +91,8 @@ func TimeEqual(expectedAsRFC3339 string, actual metav1.Time) cmp.Comparison {
 		return cmp.ResultSuccess
 	}
 }
+
+// metav1TimePtr returns a pointer to the given time.
+func metav1TimePtr(t metav1.Time) *metav1.Time {
+	return &t
+}
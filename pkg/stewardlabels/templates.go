@@ -0,0 +1,95 @@
+package stewardlabels
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/SAP/stewardci-core/pkg/apis/steward"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+/*
+ValidateTemplates checks whether the given map of label or annotation
+templates is valid, i.e. all keys are qualified names outside of the
+Steward domain and all values are valid Go text templates.
+
+Steward's own labels and annotations cannot be set via templates to
+prevent interference with the ownership and management labels.
+*/
+func ValidateTemplates(templates map[string]string) error {
+	for _, key := range sortedKeys(templates) {
+		if _, err := parseTemplate(key, templates[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RenderLabelTemplates renders the given label templates with `data` as
+// input and returns the resulting labels. Fails if a template is invalid
+// or the rendered value is not a valid label value.
+func RenderLabelTemplates(templates map[string]string, data interface{}) (map[string]string, error) {
+	return renderTemplates(templates, data, func(key, value string) error {
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return fmt.Errorf("label %q: invalid value %q: %s", key, value, strings.Join(errs, "; "))
+		}
+		return nil
+	})
+}
+
+// RenderAnnotationTemplates renders the given annotation templates with
+// `data` as input and returns the resulting annotations. Fails if a
+// template is invalid.
+func RenderAnnotationTemplates(templates map[string]string, data interface{}) (map[string]string, error) {
+	return renderTemplates(templates, data, nil)
+}
+
+func renderTemplates(templates map[string]string, data interface{}, validateValue func(key, value string) error) (map[string]string, error) {
+	if len(templates) == 0 {
+		return nil, nil
+	}
+	result := make(map[string]string, len(templates))
+	for _, key := range sortedKeys(templates) {
+		tmpl, err := parseTemplate(key, templates[key])
+		if err != nil {
+			return nil, err
+		}
+		var value strings.Builder
+		if err := tmpl.Execute(&value, data); err != nil {
+			return nil, fmt.Errorf("key %q: failed to render template: %s", key, err)
+		}
+		if validateValue != nil {
+			if err := validateValue(key, value.String()); err != nil {
+				return nil, err
+			}
+		}
+		result[key] = value.String()
+	}
+	return result, nil
+}
+
+func parseTemplate(key, text string) (*template.Template, error) {
+	if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+		return nil, fmt.Errorf("key %q: invalid key: %s", key, strings.Join(errs, "; "))
+	}
+	if domain := strings.SplitN(key, "/", 2)[0]; strings.Contains(key, "/") &&
+		(domain == steward.GroupName || strings.HasSuffix(domain, "."+steward.GroupName)) {
+		return nil, fmt.Errorf("key %q: keys of the Steward domain %q must not be used", key, steward.GroupName)
+	}
+	tmpl, err := template.New(key).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("key %q: invalid template: %s", key, err)
+	}
+	return tmpl, nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
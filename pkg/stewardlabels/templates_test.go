@@ -0,0 +1,113 @@
+package stewardlabels
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+type templateTestData struct {
+	Name string
+}
+
+func Test_ValidateTemplates(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		templates     map[string]string
+		expectedError string
+	}{
+		{"nil", nil, ""},
+		{"valid", map[string]string{
+			"key1":                               "value1",
+			"example.com/key2":                   "{{ .Name }}",
+			"istio-injection":                    "enabled",
+			"pod-security.kubernetes.io/enforce": "baseline",
+		}, ""},
+		{"invalid_key", map[string]string{
+			"key 1": "value1",
+		}, `key "key 1": invalid key: name part must consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character (e.g. 'MyName',  or 'my.name',  or '123-abc', regex used for validation is '([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]')`},
+		{"steward_domain", map[string]string{
+			"steward.sap.com/owner-tenant-name": "tenant1",
+		}, `key "steward.sap.com/owner-tenant-name": keys of the Steward domain "steward.sap.com" must not be used`},
+		{"steward_subdomain", map[string]string{
+			"foo.steward.sap.com/key1": "value1",
+		}, `key "foo.steward.sap.com/key1": keys of the Steward domain "steward.sap.com" must not be used`},
+		{"invalid_template", map[string]string{
+			"key1": "{{ .Name ",
+		}, `key "key1": invalid template: template: key1:1: unclosed action`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// EXERCISE
+			resultErr := ValidateTemplates(tc.templates)
+
+			// VERIFY
+			if tc.expectedError == "" {
+				assert.NilError(t, resultErr)
+			} else {
+				assert.Error(t, resultErr, tc.expectedError)
+			}
+		})
+	}
+}
+
+func Test_RenderLabelTemplates(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		templates     map[string]string
+		expected      map[string]string
+		expectedError string
+	}{
+		{"nil", nil, nil, ""},
+		{"rendered", map[string]string{
+			"key1":             "value1",
+			"example.com/key2": "cost-{{ .Name }}",
+		}, map[string]string{
+			"key1":             "value1",
+			"example.com/key2": "cost-tenant1",
+		}, ""},
+		{"unknown_field", map[string]string{
+			"key1": "{{ .Unknown }}",
+		}, nil, `key "key1": failed to render template: template: key1:1:3: executing "key1" at <.Unknown>: can't evaluate field Unknown in type *stewardlabels.templateTestData`},
+		{"invalid_value", map[string]string{
+			"key1": "{{ .Name }} x",
+		}, nil, `label "key1": invalid value "tenant1 x": a valid label must be an empty string or consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character (e.g. 'MyValue',  or 'my_value',  or '12345', regex used for validation is '(([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9])?')`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// EXERCISE
+			result, resultErr := RenderLabelTemplates(tc.templates, &templateTestData{Name: "tenant1"})
+
+			// VERIFY
+			if tc.expectedError == "" {
+				assert.NilError(t, resultErr)
+			} else {
+				assert.Error(t, resultErr, tc.expectedError)
+			}
+			assert.DeepEqual(t, tc.expected, result)
+		})
+	}
+}
+
+func Test_RenderAnnotationTemplates(t *testing.T) {
+	t.Parallel()
+
+	// EXERCISE
+	result, resultErr := RenderAnnotationTemplates(map[string]string{
+		"example.com/description": "Namespace of tenant {{ .Name }}",
+	}, &templateTestData{Name: "tenant1"})
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assert.DeepEqual(t, map[string]string{
+		"example.com/description": "Namespace of tenant tenant1",
+	}, result)
+}
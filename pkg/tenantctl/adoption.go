@@ -0,0 +1,206 @@
+package tenantctl
+
+import (
+	"context"
+	"encoding/json"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	slabels "github.com/SAP/stewardci-core/pkg/stewardlabels"
+	errors "github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klog "k8s.io/klog/v2"
+)
+
+// getNamespaceToAdopt returns the name of the existing namespace to be
+// adopted as tenant namespace or an empty string if a new namespace should
+// be created.
+func getNamespaceToAdopt(tenant *stewardv1alpha1.Tenant) string {
+	return tenant.GetAnnotations()[stewardv1alpha1.AnnotationAdoptNamespace]
+}
+
+/*
+adoptTenantNamespace turns the existing namespace denoted by the tenant's
+annotation `steward.sap.com/adopt-namespace` into the tenant namespace and
+returns its name.
+
+The adoption is refused if
+	- the namespace does not exist or is being deleted,
+	- the namespace is the client namespace,
+	- the namespace is assigned to another tenant already or labelled as
+	  owned by another client or tenant,
+	- the namespace name does not match the tenant namespace prefix
+	  configured for the client.
+
+Adopting a namespace that has been adopted by the same tenant before
+succeeds, so that a failed initialization can be retried.
+*/
+func (c *Controller) adoptTenantNamespace(ctx context.Context, config clientConfig, tenant *stewardv1alpha1.Tenant) (string, error) {
+	nsName := getNamespaceToAdopt(tenant)
+	klog.V(4).Infof(c.formatLogf(tenant, "adopting existing namespace %q as tenant namespace", nsName))
+
+	err := c.checkNamespaceAdoptable(ctx, tenant, nsName)
+	if err == nil {
+		err = c.labelAndAdoptNamespace(ctx, config, tenant, nsName)
+	}
+	if err != nil {
+		err = errors.WithMessagef(err, "failed to adopt namespace %q as tenant namespace", nsName)
+		klog.V(4).Infof(c.formatLog(tenant), err)
+		return "", err
+	}
+	return nsName, nil
+}
+
+func (c *Controller) checkNamespaceAdoptable(ctx context.Context, tenant *stewardv1alpha1.Tenant, nsName string) error {
+	if nsName == tenant.GetNamespace() {
+		return errors.New("the client namespace cannot be adopted")
+	}
+	tenants, err := c.factory.StewardV1alpha1().Tenants(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return errors.WithMessage(err, "failed to list tenants")
+	}
+	for _, other := range tenants.Items {
+		isSameTenant := other.GetNamespace() == tenant.GetNamespace() && other.GetName() == tenant.GetName()
+		if other.Status.TenantNamespaceName == nsName && !isSameTenant {
+			return errors.Errorf(
+				"the namespace is assigned to tenant %q in namespace %q already",
+				other.GetName(), other.GetNamespace(),
+			)
+		}
+	}
+	return nil
+}
+
+func (c *Controller) labelAndAdoptNamespace(ctx context.Context, config clientConfig, tenant *stewardv1alpha1.Tenant, nsName string) error {
+	namespace, err := c.factory.CoreV1().Namespaces().Get(ctx, nsName, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return errors.New("the namespace does not exist")
+		}
+		return errors.WithMessage(err, "failed to get namespace")
+	}
+	if !namespace.GetDeletionTimestamp().IsZero() {
+		return errors.New("the namespace is being deleted")
+	}
+
+	// the tenant namespace label must denote the adopted namespace
+	owner := tenant.DeepCopy()
+	owner.Status.TenantNamespaceName = nsName
+	if err := slabels.LabelAsOwnedByTenant(namespace, owner); err != nil {
+		return errors.WithMessage(err, "the namespace is owned by another client or tenant")
+	}
+
+	// a namespace of a former tenant may be pending deletion
+	clearPendingDeletion(namespace)
+
+	if err := recordOriginalMetadata(namespace, config); err != nil {
+		return err
+	}
+
+	return c.getNamespaceManager(config).Adopt(ctx, namespace, tenant.GetName())
+}
+
+// adoptedNamespaceMetadata holds the values the labels and annotations
+// configured for tenant namespaces had before a namespace got adopted.
+// Keys not contained did not exist.
+type adoptedNamespaceMetadata struct {
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+/*
+recordOriginalMetadata stores the current values of the labels and
+annotations configured for tenant namespaces of the client in annotation
+`steward.sap.com/adopted-namespace-original-metadata` of the given
+namespace. An existing record is kept, as it stems from a previous adoption
+attempt of the same tenant which may have modified the values already.
+*/
+func recordOriginalMetadata(namespace metav1.Object, config clientConfig) error {
+	annotations := namespace.GetAnnotations()
+	if _, found := annotations[stewardv1alpha1.AnnotationAdoptedNamespaceOriginalMetadata]; found {
+		return nil
+	}
+	record := adoptedNamespaceMetadata{
+		Labels:      pickStringMap(namespace.GetLabels(), config.GetTenantNamespaceLabels()),
+		Annotations: pickStringMap(annotations, config.GetTenantNamespaceAnnotations()),
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return errors.WithMessage(err, "failed to record original labels and annotations")
+	}
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[stewardv1alpha1.AnnotationAdoptedNamespaceOriginalMetadata] = string(data)
+	namespace.SetAnnotations(annotations)
+	return nil
+}
+
+// pickStringMap returns the entries of `src` whose keys exist in `keys`.
+func pickStringMap(src map[string]string, keys map[string]string) map[string]string {
+	var result map[string]string
+	for key := range keys {
+		if value, found := src[key]; found {
+			if result == nil {
+				result = map[string]string{}
+			}
+			result[key] = value
+		}
+	}
+	return result
+}
+
+/*
+releaseAdoptedNamespace reverts the adoption of the given namespace after
+the initialization of the tenant failed: the managed role bindings and the
+labels and annotations set by the tenant controller are removed, i.e. the
+owner labels, the labels of the namespace manager and the labels and
+annotations configured for tenant namespaces of the client. Labels and
+annotations configured for tenant namespaces which existed before the
+adoption are restored to their original values. Other labels and
+annotations are kept.
+*/
+func (c *Controller) releaseAdoptedNamespace(ctx context.Context, config clientConfig, tenant *stewardv1alpha1.Tenant, nsName string) error {
+	roleBindings, err := c.listManagedRoleBindings(ctx, nsName)
+	if err != nil {
+		return err
+	}
+	if err := c.deleteRoleBindingsFromList(ctx, roleBindings); err != nil {
+		return err
+	}
+
+	namespace, err := c.factory.CoreV1().Namespaces().Get(ctx, nsName, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.WithMessage(err, "failed to get namespace")
+	}
+	var original adoptedNamespaceMetadata
+	if data, found := namespace.Annotations[stewardv1alpha1.AnnotationAdoptedNamespaceOriginalMetadata]; found {
+		if err := json.Unmarshal([]byte(data), &original); err != nil {
+			return errors.Wrapf(err,
+				"failed to restore original labels and annotations: invalid value of annotation %q",
+				stewardv1alpha1.AnnotationAdoptedNamespaceOriginalMetadata,
+			)
+		}
+	}
+	for _, key := range []string{
+		stewardv1alpha1.LabelOwnerClientName,
+		stewardv1alpha1.LabelOwnerClientNamespace,
+		stewardv1alpha1.LabelOwnerTenantName,
+		stewardv1alpha1.LabelOwnerTenantNamespace,
+	} {
+		delete(namespace.Labels, key)
+	}
+	for key := range config.GetTenantNamespaceLabels() {
+		delete(namespace.Labels, key)
+	}
+	for key := range config.GetTenantNamespaceAnnotations() {
+		delete(namespace.Annotations, key)
+	}
+	delete(namespace.Annotations, stewardv1alpha1.AnnotationAdoptedNamespaceOriginalMetadata)
+	mergeStringMap(&namespace.Labels, original.Labels)
+	mergeStringMap(&namespace.Annotations, original.Annotations)
+	return c.getNamespaceManager(config).Release(ctx, namespace)
+}
@@ -0,0 +1,144 @@
+package tenantctl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	k8sfake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	assert "gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func Test_Controller_adoptTenantNamespace_Success(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	namespace := k8sfake.Namespace("prefix1-legacy")
+	namespace.SetLabels(map[string]string{"key1": "value1"})
+	cf := k8sfake.NewClientFactory(namespace)
+	examinee := NewController(cf, ControllerOpts{})
+	tenant := k8sfake.Tenant("tenant1", "client1")
+	tenant.SetAnnotations(map[string]string{stewardv1alpha1.AnnotationAdoptNamespace: "prefix1-legacy"})
+	config := &clientConfigImpl{tenantNamespacePrefix: "prefix1"}
+
+	// EXERCISE
+	result, resultErr := examinee.adoptTenantNamespace(ctx, config, tenant)
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assert.Equal(t, "prefix1-legacy", result)
+	adoptedNamespace, err := cf.CoreV1().Namespaces().Get(ctx, "prefix1-legacy", metav1.GetOptions{})
+	assert.NilError(t, err)
+	labels := adoptedNamespace.GetLabels()
+	assert.Equal(t, "value1", labels["key1"])
+	assert.Equal(t, "client1", labels[stewardv1alpha1.LabelOwnerClientNamespace])
+	assert.Equal(t, "tenant1", labels[stewardv1alpha1.LabelOwnerTenantName])
+	assert.Equal(t, "prefix1-legacy", labels[stewardv1alpha1.LabelOwnerTenantNamespace])
+
+	// adopting again succeeds
+	result, resultErr = examinee.adoptTenantNamespace(ctx, config, tenant)
+	assert.NilError(t, resultErr)
+	assert.Equal(t, "prefix1-legacy", result)
+}
+
+func Test_Controller_adoptTenantNamespace_RecordsOriginalMetadata(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	namespace := k8sfake.Namespace("prefix1-legacy")
+	namespace.SetLabels(map[string]string{"label1": "value1", "label2": "value2"})
+	namespace.SetAnnotations(map[string]string{"annotation1": "value1"})
+	cf := k8sfake.NewClientFactory(namespace)
+	examinee := NewController(cf, ControllerOpts{})
+	tenant := k8sfake.Tenant("tenant1", "client1")
+	tenant.SetAnnotations(map[string]string{stewardv1alpha1.AnnotationAdoptNamespace: "prefix1-legacy"})
+	config := &clientConfigImpl{
+		tenantNamespacePrefix:      "prefix1",
+		tenantNamespaceLabels:      map[string]string{"label1": "new1", "label3": "new3"},
+		tenantNamespaceAnnotations: map[string]string{"annotation2": "new2"},
+	}
+	const expectedRecord = `{"labels":{"label1":"value1"}}`
+
+	// EXERCISE
+	_, resultErr := examinee.adoptTenantNamespace(ctx, config, tenant)
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	adoptedNamespace, err := cf.CoreV1().Namespaces().Get(ctx, "prefix1-legacy", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, expectedRecord, adoptedNamespace.GetAnnotations()[stewardv1alpha1.AnnotationAdoptedNamespaceOriginalMetadata])
+
+	// the record of a previous attempt is kept
+	adoptedNamespace.Labels["label1"] = "new1"
+	_, err = cf.CoreV1().Namespaces().Update(ctx, adoptedNamespace, metav1.UpdateOptions{})
+	assert.NilError(t, err)
+	_, resultErr = examinee.adoptTenantNamespace(ctx, config, tenant)
+	assert.NilError(t, resultErr)
+	adoptedNamespace, err = cf.CoreV1().Namespaces().Get(ctx, "prefix1-legacy", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, expectedRecord, adoptedNamespace.GetAnnotations()[stewardv1alpha1.AnnotationAdoptedNamespaceOriginalMetadata])
+}
+
+func Test_Controller_adoptTenantNamespace_Refused(t *testing.T) {
+	t.Parallel()
+
+	deletedNamespace := k8sfake.Namespace("prefix1-deleted")
+	deletedNamespace.SetDeletionTimestamp(&metav1.Time{Time: time.Now()})
+
+	ownedNamespace := k8sfake.Namespace("prefix1-owned")
+	ownedNamespace.SetLabels(map[string]string{stewardv1alpha1.LabelOwnerTenantName: "tenant2"})
+
+	assignedNamespace := k8sfake.Namespace("prefix1-assigned")
+	otherTenant := k8sfake.Tenant("tenant2", "client2")
+	otherTenant.Status.TenantNamespaceName = "prefix1-assigned"
+
+	for _, tc := range []struct {
+		name          string
+		nsName        string
+		expectedError string
+	}{
+		{"not_existing", "prefix1-missing", `failed to adopt namespace "prefix1-missing" as tenant namespace: the namespace does not exist`},
+		{"deleted", "prefix1-deleted", `failed to adopt namespace "prefix1-deleted" as tenant namespace: the namespace is being deleted`},
+		{"client_namespace", "client1", `failed to adopt namespace "client1" as tenant namespace: the client namespace cannot be adopted`},
+		{"assigned_to_other_tenant", "prefix1-assigned", `failed to adopt namespace "prefix1-assigned" as tenant namespace: the namespace is assigned to tenant "tenant2" in namespace "client2" already`},
+		{"owned_by_other_tenant", "prefix1-owned", `failed to adopt namespace "prefix1-owned" as tenant namespace: the namespace is owned by another client or tenant: value conflict: destination object label "steward.sap.com/owner-tenant-name" has existing value "tenant2" but "tenant1" is expected`},
+		{"prefix_mismatch", "legacy", `failed to adopt namespace "legacy" as tenant namespace: refused to adopt namespace 'legacy': name does not start with 'prefix1'`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			ctx := context.Background()
+			cf := k8sfake.NewClientFactory([]runtime.Object{
+				k8sfake.Namespace("client1"),
+				k8sfake.Namespace("legacy"),
+				deletedNamespace.DeepCopy(),
+				ownedNamespace.DeepCopy(),
+				assignedNamespace.DeepCopy(),
+				otherTenant.DeepCopy(),
+			}...)
+			examinee := NewController(cf, ControllerOpts{})
+			tenant := k8sfake.Tenant("tenant1", "client1")
+			tenant.SetAnnotations(map[string]string{stewardv1alpha1.AnnotationAdoptNamespace: tc.nsName})
+			config := &clientConfigImpl{tenantNamespacePrefix: "prefix1"}
+
+			// EXERCISE
+			result, resultErr := examinee.adoptTenantNamespace(ctx, config, tenant)
+
+			// VERIFY
+			assert.Error(t, resultErr, tc.expectedError)
+			assert.Equal(t, "", result)
+			namespace, err := cf.CoreV1().Namespaces().Get(ctx, tc.nsName, metav1.GetOptions{})
+			if err == nil {
+				_, found := namespace.GetLabels()[stewardv1alpha1.LabelOwnerClientNamespace]
+				assert.Assert(t, !found)
+			}
+		})
+	}
+}
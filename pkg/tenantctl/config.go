@@ -4,28 +4,67 @@ import (
 	"context"
 	"math"
 	"strconv"
+	"time"
 
 	steward "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
 	k8s "github.com/SAP/stewardci-core/pkg/k8s"
+	slabels "github.com/SAP/stewardci-core/pkg/stewardlabels"
+	"github.com/ghodss/yaml"
 	errors "github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/system"
 )
 
 type clientConfig interface {
 	GetTenantNamespacePrefix() string
 	GetTenantNamespaceSuffixLength() uint8
 	GetTenantRoleName() k8s.RoleName
+	GetTenantNamespaceResourceQuota() string
+	GetTenantNamespaceLimitRange() string
+	GetTenantNamespaceNetworkPolicy() string
+	GetTenantNamespaceRetentionPeriod() time.Duration
+	GetTenantNamespaceLabels() map[string]string
+	GetTenantNamespaceAnnotations() map[string]string
 }
 
 const (
 	tenantNamespaceSuffixLengthDefault uint8 = 6
 	tenantNamespaceSuffixLengthMax     uint8 = 32
+
+	// tenantsConfigMapName is the name of the optional config map
+	// containing the settings for tenant namespaces, e.g. the manifests of
+	// the resource objects to be created in them. It is read from the system namespace and
+	// from the client namespace, where each key present in the latter
+	// overrides the respective key of the former.
+	tenantsConfigMapName = "steward-tenants"
+
+	tenantsConfigKeyResourceQuota = "resourceQuota"
+	tenantsConfigKeyLimitRange    = "limitRange"
+	tenantsConfigKeyNetworkPolicy = "networkPolicy"
+
+	// tenantsConfigKeyNamespaceRetentionPeriod is the key of the duration
+	// for which the namespace of a deleted tenant is kept before it gets
+	// deleted. Zero means immediate deletion.
+	tenantsConfigKeyNamespaceRetentionPeriod = "namespaceRetentionPeriod"
+
+	// tenantsConfigKeyNamespaceLabels and tenantsConfigKeyNamespaceAnnotations
+	// are the keys of YAML maps of label and annotation templates to be
+	// applied to tenant namespaces.
+	tenantsConfigKeyNamespaceLabels      = "namespaceLabels"
+	tenantsConfigKeyNamespaceAnnotations = "namespaceAnnotations"
 )
 
 type clientConfigImpl struct {
-	tenantNamespacePrefix       string
-	tenantNamespaceSuffixLength int64
-	tenantRoleName              k8s.RoleName
+	tenantNamespacePrefix        string
+	tenantNamespaceSuffixLength  int64
+	tenantRoleName               k8s.RoleName
+	tenantNamespaceResourceQuota string
+	tenantNamespaceLimitRange    string
+	tenantNamespaceNetworkPolicy string
+	tenantNamespaceRetention     time.Duration
+	tenantNamespaceLabels        map[string]string
+	tenantNamespaceAnnotations   map[string]string
 }
 
 // getClientConfig returns the configurartion of the Steward client.
@@ -77,9 +116,71 @@ func getClientConfig(ctx context.Context, factory k8s.ClientFactory, clientNames
 		}
 		newConfig.tenantNamespaceSuffixLength = i
 	}
+
+	for _, configMapNamespace := range []string{system.Namespace(), clientNamespace} {
+		if err := loadTenantsConfigMap(ctx, factory, configMapNamespace, &newConfig); err != nil {
+			return nil, err
+		}
+	}
 	return &newConfig, nil
 }
 
+// loadTenantsConfigMap reads the settings for tenant namespaces from the
+// tenants config map in the given namespace, if it exists. Only keys
+// present in the config map are overridden in `dest`, so that an empty
+// value disables a resource object or the retention defined in a config
+// map loaded before.
+func loadTenantsConfigMap(ctx context.Context, factory k8s.ClientFactory, namespace string, dest *clientConfigImpl) error {
+	configMap, err := factory.CoreV1().ConfigMaps(namespace).Get(ctx, tenantsConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.WithMessagef(err, "could not get config map '%s' in namespace '%s'", tenantsConfigMapName, namespace)
+	}
+	for key, field := range map[string]*string{
+		tenantsConfigKeyResourceQuota: &dest.tenantNamespaceResourceQuota,
+		tenantsConfigKeyLimitRange:    &dest.tenantNamespaceLimitRange,
+		tenantsConfigKeyNetworkPolicy: &dest.tenantNamespaceNetworkPolicy,
+	} {
+		if value, ok := configMap.Data[key]; ok {
+			*field = value
+		}
+	}
+	if value, ok := configMap.Data[tenantsConfigKeyNamespaceRetentionPeriod]; ok {
+		dest.tenantNamespaceRetention = 0
+		if value != "" {
+			duration, err := time.ParseDuration(value)
+			if err != nil || duration < 0 {
+				return errors.Errorf(
+					"config map '%s' in namespace '%s' has an invalid value for key '%s': '%s':"+
+						" should be a non-negative duration",
+					tenantsConfigMapName, namespace, tenantsConfigKeyNamespaceRetentionPeriod, value)
+			}
+			dest.tenantNamespaceRetention = duration
+		}
+	}
+	for key, field := range map[string]*map[string]string{
+		tenantsConfigKeyNamespaceLabels:      &dest.tenantNamespaceLabels,
+		tenantsConfigKeyNamespaceAnnotations: &dest.tenantNamespaceAnnotations,
+	} {
+		if value, ok := configMap.Data[key]; ok {
+			templates := map[string]string{}
+			err := yaml.Unmarshal([]byte(value), &templates)
+			if err == nil {
+				err = slabels.ValidateTemplates(templates)
+			}
+			if err != nil {
+				return errors.WithMessagef(err,
+					"config map '%s' in namespace '%s' has an invalid value for key '%s'",
+					tenantsConfigMapName, namespace, key)
+			}
+			*field = templates
+		}
+	}
+	return nil
+}
+
 func (c *clientConfigImpl) GetTenantNamespacePrefix() string {
 	return c.tenantNamespacePrefix
 }
@@ -97,3 +198,27 @@ func (c *clientConfigImpl) GetTenantNamespaceSuffixLength() uint8 {
 func (c *clientConfigImpl) GetTenantRoleName() k8s.RoleName {
 	return c.tenantRoleName
 }
+
+func (c *clientConfigImpl) GetTenantNamespaceResourceQuota() string {
+	return c.tenantNamespaceResourceQuota
+}
+
+func (c *clientConfigImpl) GetTenantNamespaceLimitRange() string {
+	return c.tenantNamespaceLimitRange
+}
+
+func (c *clientConfigImpl) GetTenantNamespaceNetworkPolicy() string {
+	return c.tenantNamespaceNetworkPolicy
+}
+
+func (c *clientConfigImpl) GetTenantNamespaceRetentionPeriod() time.Duration {
+	return c.tenantNamespaceRetention
+}
+
+func (c *clientConfigImpl) GetTenantNamespaceLabels() map[string]string {
+	return c.tenantNamespaceLabels
+}
+
+func (c *clientConfigImpl) GetTenantNamespaceAnnotations() map[string]string {
+	return c.tenantNamespaceAnnotations
+}
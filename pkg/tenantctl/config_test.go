@@ -5,12 +5,15 @@ import (
 	"math"
 	"strconv"
 	"testing"
+	"time"
 
 	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
 	assert "gotest.tools/assert"
 	is "gotest.tools/assert/cmp"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/system"
+	_ "knative.dev/pkg/system/testing"
 )
 
 /*
@@ -390,3 +393,230 @@ func Test_getClientConfig_TwoClients(t *testing.T) {
 	assert.Equal(t, uint8(6), rand1)
 	assert.Equal(t, uint8(4), rand2)
 }
+
+func Test_getClientConfig_TenantNamespaceObjects(t *testing.T) {
+	t.Parallel()
+
+	newConfigMap := func(namespace string, data map[string]string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "steward-tenants",
+				Namespace: namespace,
+			},
+			Data: data,
+		}
+	}
+
+	for _, tc := range []struct {
+		name                  string
+		systemData            map[string]string
+		clientData            map[string]string
+		expectedResourceQuota string
+		expectedLimitRange    string
+		expectedNetworkPolicy string
+	}{
+		{"no_config_maps", nil, nil, "", "", ""},
+		{"system_only", map[string]string{
+			"resourceQuota": "quota1",
+			"limitRange":    "limitRange1",
+			"networkPolicy": "policy1",
+		}, nil, "quota1", "limitRange1", "policy1"},
+		{"client_only", nil, map[string]string{
+			"resourceQuota": "quota2",
+		}, "quota2", "", ""},
+		{"client_overrides_system", map[string]string{
+			"resourceQuota": "quota1",
+			"limitRange":    "limitRange1",
+			"networkPolicy": "policy1",
+		}, map[string]string{
+			"resourceQuota": "quota2",
+			"networkPolicy": "",
+		}, "quota2", "limitRange1", ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			ctx := context.Background()
+			cf := fake.NewClientFactory(
+				fake.NamespaceWithAnnotations("client1", map[string]string{
+					"steward.sap.com/tenant-namespace-prefix": "prefix1",
+					"steward.sap.com/tenant-role":             "role1",
+				}),
+			)
+			for namespace, data := range map[string]map[string]string{
+				system.Namespace(): tc.systemData,
+				"client1":          tc.clientData,
+			} {
+				if data != nil {
+					_, err := cf.CoreV1().ConfigMaps(namespace).Create(ctx, newConfigMap(namespace, data), metav1.CreateOptions{})
+					assert.NilError(t, err)
+				}
+			}
+
+			// EXERCISE
+			config, err := getClientConfig(ctx, cf, "client1")
+
+			// VERIFY
+			assert.NilError(t, err)
+			assert.Equal(t, tc.expectedResourceQuota, config.GetTenantNamespaceResourceQuota())
+			assert.Equal(t, tc.expectedLimitRange, config.GetTenantNamespaceLimitRange())
+			assert.Equal(t, tc.expectedNetworkPolicy, config.GetTenantNamespaceNetworkPolicy())
+		})
+	}
+}
+
+func Test_getClientConfig_TenantNamespaceRetentionPeriod(t *testing.T) {
+	t.Parallel()
+
+	strPtr := func(s string) *string { return &s }
+
+	for _, tc := range []struct {
+		name          string
+		systemValue   *string
+		clientValue   *string
+		expected      time.Duration
+		expectedError string
+	}{
+		{"not_set", nil, nil, 0, ""},
+		{"system_only", strPtr("72h"), nil, 72 * time.Hour, ""},
+		{"client_overrides_system", strPtr("72h"), strPtr("1h30m"), 90 * time.Minute, ""},
+		{"client_disables", strPtr("72h"), strPtr(""), 0, ""},
+		{"invalid", nil, strPtr("3 days"), 0, "config map 'steward-tenants' in namespace 'client1' has an invalid value for key 'namespaceRetentionPeriod': '3 days': should be a non-negative duration"},
+		{"negative", strPtr("-1h"), nil, 0, "config map 'steward-tenants' in namespace '" + system.Namespace() + "' has an invalid value for key 'namespaceRetentionPeriod': '-1h': should be a non-negative duration"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			ctx := context.Background()
+			cf := fake.NewClientFactory(
+				fake.NamespaceWithAnnotations("client1", map[string]string{
+					"steward.sap.com/tenant-namespace-prefix": "prefix1",
+					"steward.sap.com/tenant-role":             "role1",
+				}),
+			)
+			for namespace, value := range map[string]*string{
+				system.Namespace(): tc.systemValue,
+				"client1":          tc.clientValue,
+			} {
+				if value != nil {
+					configMap := &corev1.ConfigMap{
+						ObjectMeta: metav1.ObjectMeta{Name: "steward-tenants", Namespace: namespace},
+						Data:       map[string]string{"namespaceRetentionPeriod": *value},
+					}
+					_, err := cf.CoreV1().ConfigMaps(namespace).Create(ctx, configMap, metav1.CreateOptions{})
+					assert.NilError(t, err)
+				}
+			}
+
+			// EXERCISE
+			config, err := getClientConfig(ctx, cf, "client1")
+
+			// VERIFY
+			if tc.expectedError != "" {
+				assert.Error(t, err, tc.expectedError)
+			} else {
+				assert.NilError(t, err)
+				assert.Equal(t, tc.expected, config.GetTenantNamespaceRetentionPeriod())
+			}
+		})
+	}
+}
+
+func Test_getClientConfig_TenantNamespaceLabelsAndAnnotations(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name                string
+		systemData          map[string]string
+		clientData          map[string]string
+		expectedLabels      map[string]string
+		expectedAnnotations map[string]string
+		expectedError       string
+	}{
+		{"not_set", nil, nil, nil, nil, ""},
+		{"system_only",
+			map[string]string{
+				"namespaceLabels":      "cost-center: cc1\n",
+				"namespaceAnnotations": "example.com/tenant: '{{ .TenantName }}'\n",
+			},
+			nil,
+			map[string]string{"cost-center": "cc1"},
+			map[string]string{"example.com/tenant": "{{ .TenantName }}"},
+			"",
+		},
+		{"client_overrides_system",
+			map[string]string{
+				"namespaceLabels":      "cost-center: cc1\n",
+				"namespaceAnnotations": "example.com/tenant: '{{ .TenantName }}'\n",
+			},
+			map[string]string{
+				"namespaceLabels": "cost-center: cc2\n",
+			},
+			map[string]string{"cost-center": "cc2"},
+			map[string]string{"example.com/tenant": "{{ .TenantName }}"},
+			"",
+		},
+		{"client_disables",
+			map[string]string{"namespaceLabels": "cost-center: cc1\n"},
+			map[string]string{"namespaceLabels": ""},
+			nil,
+			nil,
+			"",
+		},
+		{"invalid_yaml",
+			nil,
+			map[string]string{"namespaceLabels": "- cc1\n"},
+			nil, nil,
+			"config map 'steward-tenants' in namespace 'client1' has an invalid value for key 'namespaceLabels': error unmarshaling JSON: json: cannot unmarshal array into Go value of type map[string]string",
+		},
+		{"steward_key",
+			map[string]string{"namespaceAnnotations": "steward.sap.com/foo: bar\n"},
+			nil,
+			nil, nil,
+			"config map 'steward-tenants' in namespace '" + system.Namespace() + "' has an invalid value for key 'namespaceAnnotations': key \"steward.sap.com/foo\": keys of the Steward domain \"steward.sap.com\" must not be used",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			ctx := context.Background()
+			cf := fake.NewClientFactory(
+				fake.NamespaceWithAnnotations("client1", map[string]string{
+					"steward.sap.com/tenant-namespace-prefix": "prefix1",
+					"steward.sap.com/tenant-role":             "role1",
+				}),
+			)
+			for namespace, data := range map[string]map[string]string{
+				system.Namespace(): tc.systemData,
+				"client1":          tc.clientData,
+			} {
+				if data != nil {
+					configMap := &corev1.ConfigMap{
+						ObjectMeta: metav1.ObjectMeta{Name: "steward-tenants", Namespace: namespace},
+						Data:       data,
+					}
+					_, err := cf.CoreV1().ConfigMaps(namespace).Create(ctx, configMap, metav1.CreateOptions{})
+					assert.NilError(t, err)
+				}
+			}
+
+			// EXERCISE
+			config, err := getClientConfig(ctx, cf, "client1")
+
+			// VERIFY
+			if tc.expectedError != "" {
+				assert.Error(t, err, tc.expectedError)
+			} else {
+				assert.NilError(t, err)
+				assert.DeepEqual(t, tc.expectedLabels, config.GetTenantNamespaceLabels())
+				assert.DeepEqual(t, tc.expectedAnnotations, config.GetTenantNamespaceAnnotations())
+			}
+		})
+	}
+}
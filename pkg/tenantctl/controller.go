@@ -52,16 +52,20 @@ type Controller struct {
 	syncCount    int64
 	testing      *controllerTesting
 
+	pipelineRunSynced cache.InformerSynced
+	pipelineRunLister stewardv1alpha1listers.PipelineRunLister
+
 	heartbeatInterval time.Duration
 	heartbeatLogLevel *klog.Level
 }
 
 type controllerTesting struct {
-	createRoleBindingStub          func(roleBinding *rbacv1.RoleBinding) (*rbacv1.RoleBinding, error)
-	getClientConfigStub            func(factory k8s.ClientFactory, clientNamespace string) (clientConfig, error)
-	listManagedRoleBindingsStub    func(namespace string) (*rbacv1.RoleBindingList, error)
-	reconcileTenantRoleBindingStub func(tenant *stewardv1alpha1.Tenant, namespace string, config clientConfig) (bool, error)
-	updateStatusStub               func(tenant *stewardv1alpha1.Tenant) (*stewardv1alpha1.Tenant, error)
+	createRoleBindingStub               func(roleBinding *rbacv1.RoleBinding) (*rbacv1.RoleBinding, error)
+	getClientConfigStub                 func(factory k8s.ClientFactory, clientNamespace string) (clientConfig, error)
+	listManagedRoleBindingsStub         func(namespace string) (*rbacv1.RoleBindingList, error)
+	reconcileTenantRoleBindingStub      func(tenant *stewardv1alpha1.Tenant, namespace string, config clientConfig) (bool, error)
+	reconcileTenantNamespaceObjectsStub func(tenant *stewardv1alpha1.Tenant, namespace string, config clientConfig) error
+	updateStatusStub                    func(tenant *stewardv1alpha1.Tenant) (*stewardv1alpha1.Tenant, error)
 }
 
 // ControllerOpts stores options for the construction of a Controller
@@ -82,6 +86,7 @@ type ControllerOpts struct {
 func NewController(factory k8s.ClientFactory, opts ControllerOpts) *Controller {
 	informer := factory.StewardInformerFactory().Steward().V1alpha1().Tenants()
 	fetcher := k8s.NewListerBasedTenantFetcher(informer.Lister())
+	pipelineRunInformer := factory.StewardInformerFactory().Steward().V1alpha1().PipelineRuns()
 
 	controller := &Controller{
 		factory:      factory,
@@ -89,6 +94,9 @@ func NewController(factory k8s.ClientFactory, opts ControllerOpts) *Controller {
 		tenantSynced: informer.Informer().HasSynced,
 		tenantLister: informer.Lister(),
 		workqueue:    workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), metrics.WorkqueueName),
+
+		pipelineRunSynced: pipelineRunInformer.Informer().HasSynced,
+		pipelineRunLister: pipelineRunInformer.Lister(),
 	}
 
 	controller.heartbeatInterval = opts.HeartbeatInterval
@@ -123,7 +131,7 @@ func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
 	defer c.workqueue.ShutDown()
 
 	klog.V(2).Infof("Sync cache")
-	if ok := cache.WaitForCacheSync(stopCh, c.tenantSynced); !ok {
+	if ok := cache.WaitForCacheSync(stopCh, c.tenantSynced, c.pipelineRunSynced); !ok {
 		return fmt.Errorf("failed to wait for caches to sync")
 	}
 
@@ -134,6 +142,9 @@ func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
 		klog.V(2).Info("Controller heartbeat is disabled")
 	}
 
+	klog.V(2).Infof("Starting deletion of tenant namespaces with expired retention period")
+	go wait.Until(c.deleteExpiredTenantNamespaces, pendingDeletionCheckInterval, stopCh)
+
 	klog.V(2).Infof("Start workers")
 	for i := 0; i < threadiness; i++ {
 		go wait.Until(c.runWorker, time.Second, stopCh)
@@ -268,7 +279,25 @@ func (c *Controller) syncHandler(key string) error {
 			klog.V(3).Infof(c.formatLog(tenant, "dependent resources cleaned already, nothing to do"))
 			return nil
 		}
-		err = c.deleteTenantNamespace(ctx, tenant.Status.TenantNamespaceName, tenant, config)
+		blocked, err := c.isDeletionBlocked(ctx, tenant)
+		if err != nil {
+			return err
+		}
+		if blocked {
+			if !equality.Semantic.DeepEqual(origTenant.Status, tenant.Status) {
+				if _, err := c.updateStatus(ctx, tenant); err != nil {
+					return err
+				}
+			}
+			// returning an error lets the tenant be requeued with back-off
+			err = errors.Errorf(
+				"deletion of tenant is blocked by active pipeline runs in tenant namespace %q",
+				tenant.Status.TenantNamespaceName,
+			)
+			klog.V(3).Infof(c.formatLog(tenant), err)
+			return err
+		}
+		err = c.removeTenantNamespace(ctx, tenant.Status.TenantNamespaceName, tenant, config)
 		if err != nil {
 			return err
 		}
@@ -290,7 +319,7 @@ func (c *Controller) syncHandler(key string) error {
 	if !equality.Semantic.DeepEqual(origTenant.Status, tenant.Status) {
 		if _, err := c.updateStatus(ctx, tenant); err != nil {
 			if !c.isInitialized(origTenant) && c.isInitialized(tenant) {
-				c.deleteTenantNamespace(ctx, tenant.Status.TenantNamespaceName, tenant, config) // clean-up ignoring error
+				c.rollbackTenantNamespace(ctx, tenant.Status.TenantNamespaceName, tenant, config)
 			}
 			return err
 		}
@@ -321,9 +350,19 @@ func (c *Controller) reconcile(ctx context.Context, config clientConfig, tenant
 func (c *Controller) reconcileUninitialized(ctx context.Context, config clientConfig, tenant *stewardv1alpha1.Tenant) error {
 	klog.V(3).Infof(c.formatLog(tenant, "tenant not initialized yet"))
 
-	nsName, err := c.createTenantNamespace(ctx, config, tenant)
+	var nsName string
+	var err error
+	condMsg := "Failed to create a new tenant namespace."
+	if getNamespaceToAdopt(tenant) != "" {
+		condMsg = "Failed to adopt the existing namespace as tenant namespace."
+		nsName, err = c.adoptTenantNamespace(ctx, config, tenant)
+	} else {
+		nsName, err = c.restoreTenantNamespace(ctx, tenant)
+		if err == nil && nsName == "" {
+			nsName, err = c.createTenantNamespace(ctx, config, tenant)
+		}
+	}
 	if err != nil {
-		condMsg := "Failed to create a new tenant namespace."
 		tenant.Status.SetCondition(&knativeapis.Condition{
 			Type:    knativeapis.ConditionReady,
 			Status:  corev1.ConditionFalse,
@@ -342,7 +381,33 @@ func (c *Controller) reconcileUninitialized(ctx context.Context, config clientCo
 			Reason:  stewardv1alpha1.StatusReasonFailed,
 			Message: condMsg,
 		})
-		c.deleteTenantNamespace(ctx, nsName, tenant, config) // clean-up ignoring error
+		c.rollbackTenantNamespace(ctx, nsName, tenant, config)
+		return err
+	}
+
+	err = c.reconcileTenantNamespaceObjects(ctx, tenant, nsName, config)
+	if err != nil {
+		condMsg := "Failed to initialize a new tenant namespace because the configured resource objects could not be created."
+		tenant.Status.SetCondition(&knativeapis.Condition{
+			Type:    knativeapis.ConditionReady,
+			Status:  corev1.ConditionFalse,
+			Reason:  stewardv1alpha1.StatusReasonFailed,
+			Message: condMsg,
+		})
+		c.rollbackTenantNamespace(ctx, nsName, tenant, config)
+		return err
+	}
+
+	err = c.reconcileTenantNamespaceMetadata(ctx, tenant, nsName, config)
+	if err != nil {
+		condMsg := "Failed to initialize a new tenant namespace because the configured labels and annotations could not be applied."
+		tenant.Status.SetCondition(&knativeapis.Condition{
+			Type:    knativeapis.ConditionReady,
+			Status:  corev1.ConditionFalse,
+			Reason:  stewardv1alpha1.StatusReasonFailed,
+			Message: condMsg,
+		})
+		c.rollbackTenantNamespace(ctx, nsName, tenant, config)
 		return err
 	}
 
@@ -401,6 +466,36 @@ func (c *Controller) reconcileInitialized(ctx context.Context, config clientConf
 		return err
 	}
 
+	err = c.reconcileTenantNamespaceObjects(ctx, tenant, nsName, config)
+	if err != nil {
+		condMsg := fmt.Sprintf(
+			"The configured resource objects in tenant namespace %q could not be reconciled.",
+			nsName,
+		)
+		tenant.Status.SetCondition(&knativeapis.Condition{
+			Type:    knativeapis.ConditionReady,
+			Status:  corev1.ConditionFalse,
+			Reason:  stewardv1alpha1.StatusReasonDependentResourceState,
+			Message: condMsg,
+		})
+		return err
+	}
+
+	err = c.reconcileTenantNamespaceMetadata(ctx, tenant, nsName, config)
+	if err != nil {
+		condMsg := fmt.Sprintf(
+			"The configured labels and annotations of tenant namespace %q could not be applied.",
+			nsName,
+		)
+		tenant.Status.SetCondition(&knativeapis.Condition{
+			Type:    knativeapis.ConditionReady,
+			Status:  corev1.ConditionFalse,
+			Reason:  stewardv1alpha1.StatusReasonDependentResourceState,
+			Message: condMsg,
+		})
+		return err
+	}
+
 	tenant.Status.SetCondition(&knativeapis.Condition{
 		Type:   knativeapis.ConditionReady,
 		Status: corev1.ConditionTrue,
@@ -507,6 +602,20 @@ func (c *Controller) deleteTenantNamespace(ctx context.Context, namespace string
 	return nil
 }
 
+// rollbackTenantNamespace removes the tenant namespace of a tenant whose
+// initialization failed, ignoring errors. Adopted namespaces are kept but
+// released, i.e. the changes of the adoption are reverted.
+func (c *Controller) rollbackTenantNamespace(ctx context.Context, namespace string, tenant *stewardv1alpha1.Tenant, config clientConfig) {
+	if getNamespaceToAdopt(tenant) != "" {
+		klog.V(4).Infof(c.formatLogf(tenant, "releasing adopted tenant namespace %q", namespace))
+		if err := c.releaseAdoptedNamespace(ctx, config, tenant, namespace); err != nil {
+			klog.V(4).Infof(c.formatLog(tenant), errors.WithMessagef(err, "failed to release adopted namespace %q", namespace))
+		}
+		return
+	}
+	c.removeTenantNamespace(ctx, namespace, tenant, config) // clean-up ignoring error
+}
+
 /*
 reconcileTenantRoleBinding compares the actual state of the role binding
 in the tenant namespace with the desired state.
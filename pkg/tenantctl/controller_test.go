@@ -15,9 +15,13 @@ import (
 	errors "github.com/pkg/errors"
 	assert "gotest.tools/assert"
 	is "gotest.tools/assert/cmp"
+	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	k8stesting "k8s.io/client-go/testing"
 	knativeapis "knative.dev/pkg/apis"
 )
 
@@ -354,6 +358,161 @@ func Test_Controller_syncHandler_UninitializedTenant_FailsOnErrorWhenSyncingRole
 	)
 }
 
+func Test_Controller_syncHandler_UninitializedTenant_FailsOnErrorWhenSyncingNamespaceObjects(t *testing.T) {
+	// SETUP
+	const (
+		clientNSName   = "client1"
+		tenantNSPrefix = "prefix1"
+		tenantID       = "tenant1"
+		tenantRoleName = "tenantClusterRole1"
+	)
+
+	cf := k8sfake.NewClientFactory(
+		// the client namespace
+		k8sfake.NamespaceWithAnnotations(clientNSName, map[string]string{
+			stewardv1alpha1.AnnotationTenantNamespacePrefix: tenantNSPrefix,
+			stewardv1alpha1.AnnotationTenantRole:            tenantRoleName,
+		}),
+		// the tenant
+		k8sfake.Tenant(tenantID, clientNSName),
+	)
+	ctl := NewController(cf, ControllerOpts{})
+	ctl.fetcher = k8s.NewClientBasedTenantFetcher(cf)
+
+	injectedError := errors.New("ERR1")
+	ctl.testing = &controllerTesting{
+		reconcileTenantNamespaceObjectsStub: func(*stewardv1alpha1.Tenant, string, clientConfig) error {
+			return injectedError
+		},
+	}
+
+	// EXERCISE
+	resultErr := ctl.syncHandler(makeTenantKey(clientNSName, tenantID))
+
+	// VERIFY
+	assert.Assert(t, resultErr != nil)
+	assert.Assert(t, injectedError == resultErr)
+
+	ctx := context.Background()
+	tenant, err := cf.StewardV1alpha1().Tenants(clientNSName).Get(ctx, tenantID, metav1.GetOptions{})
+	assert.NilError(t, err)
+
+	// tenant
+	{
+		dump := fmt.Sprintf("\n\n%v", spew.Sdump(tenant))
+		{
+			readyCond := tenant.Status.GetCondition(knativeapis.ConditionReady)
+			assert.Assert(t, readyCond.IsFalse(), dump)
+			assert.Equal(t, stewardv1alpha1.StatusReasonFailed, readyCond.Reason, dump)
+			assert.Equal(t, "Failed to initialize a new tenant namespace because the configured resource objects could not be created.", readyCond.Message, dump)
+		}
+		assert.Equal(t, "", tenant.Status.TenantNamespaceName, dump)
+	}
+
+	assertThatExactlyTheseNamespacesExist(t, cf,
+		clientNSName,
+	)
+}
+
+func Test_Controller_syncHandler_UninitializedTenant_AdoptsNamespace(t *testing.T) {
+	// SETUP
+	const (
+		clientNSName   = "client1"
+		tenantNSPrefix = "prefix1"
+		tenantNSName   = "prefix1-legacy"
+		tenantID       = "tenant1"
+		tenantRoleName = "tenantClusterRole1"
+	)
+
+	tenant := k8sfake.Tenant(tenantID, clientNSName)
+	tenant.SetAnnotations(map[string]string{
+		stewardv1alpha1.AnnotationAdoptNamespace: tenantNSName,
+	})
+	cf := k8sfake.NewClientFactory(
+		// the client namespace
+		k8sfake.NamespaceWithAnnotations(clientNSName, map[string]string{
+			stewardv1alpha1.AnnotationTenantNamespacePrefix: tenantNSPrefix,
+			stewardv1alpha1.AnnotationTenantRole:            tenantRoleName,
+		}),
+		// the namespace to adopt
+		k8sfake.Namespace(tenantNSName),
+		tenant,
+	)
+	ctl := NewController(cf, ControllerOpts{})
+	ctl.fetcher = k8s.NewClientBasedTenantFetcher(cf)
+
+	// EXERCISE
+	resultErr := ctl.syncHandler(makeTenantKey(clientNSName, tenantID))
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+
+	ctx := context.Background()
+	tenant, err := cf.StewardV1alpha1().Tenants(clientNSName).Get(ctx, tenantID, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, tenantNSName, tenant.Status.TenantNamespaceName)
+	assert.Assert(t, tenant.Status.GetCondition(knativeapis.ConditionReady).IsTrue())
+
+	assertThatExactlyTheseNamespacesExist(t, cf,
+		clientNSName,
+		tenantNSName,
+	)
+	roleBindings, err := cf.RbacV1().RoleBindings(tenantNSName).List(ctx, metav1.ListOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(roleBindings.Items))
+}
+
+func Test_Controller_syncHandler_UninitializedTenant_KeepsAdoptedNamespaceOnError(t *testing.T) {
+	// SETUP
+	const (
+		clientNSName   = "client1"
+		tenantNSPrefix = "prefix1"
+		tenantNSName   = "prefix1-legacy"
+		tenantID       = "tenant1"
+		tenantRoleName = "tenantClusterRole1"
+	)
+
+	tenant := k8sfake.Tenant(tenantID, clientNSName)
+	tenant.SetAnnotations(map[string]string{
+		stewardv1alpha1.AnnotationAdoptNamespace: tenantNSName,
+	})
+	cf := k8sfake.NewClientFactory(
+		// the client namespace
+		k8sfake.NamespaceWithAnnotations(clientNSName, map[string]string{
+			stewardv1alpha1.AnnotationTenantNamespacePrefix: tenantNSPrefix,
+			stewardv1alpha1.AnnotationTenantRole:            tenantRoleName,
+		}),
+		// the namespace to adopt
+		k8sfake.Namespace(tenantNSName),
+		tenant,
+	)
+	ctl := NewController(cf, ControllerOpts{})
+	ctl.fetcher = k8s.NewClientBasedTenantFetcher(cf)
+
+	injectedError := errors.New("ERR1")
+	ctl.testing = &controllerTesting{
+		reconcileTenantRoleBindingStub: func(*stewardv1alpha1.Tenant, string, clientConfig) (bool, error) {
+			return false, injectedError
+		},
+	}
+
+	// EXERCISE
+	resultErr := ctl.syncHandler(makeTenantKey(clientNSName, tenantID))
+
+	// VERIFY
+	assert.Assert(t, injectedError == resultErr)
+
+	ctx := context.Background()
+	tenant, err := cf.StewardV1alpha1().Tenants(clientNSName).Get(ctx, tenantID, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, "", tenant.Status.TenantNamespaceName)
+
+	assertThatExactlyTheseNamespacesExist(t, cf,
+		clientNSName,
+		tenantNSName, // adopted namespace NOT removed
+	)
+}
+
 func Test_Controller_syncHandler_InitializedTenant_AddsMissingRoleBinding(t *testing.T) {
 	// SETUP
 	const (
@@ -644,6 +803,188 @@ func Test_Controller_syncHandler_CleanupOnDelete_IfFinalizerIsSet(t *testing.T)
 	assertThatExactlyTheseTenantsExistInNamespace(t, cf, clientNSName /*none*/)
 }
 
+func Test_Controller_syncHandler_CleanupOnDelete_BlockedByActivePipelineRuns(t *testing.T) {
+	// SETUP
+	const (
+		clientNSName   = "client1"
+		tenantNSPrefix = "prefix1"
+		tenantID       = "tenant1"
+		tenantRoleName = "tenantClusterRole1"
+	)
+
+	ctx := context.Background()
+	cf := k8sfake.NewClientFactory(
+		// the client namespace
+		k8sfake.NamespaceWithAnnotations(clientNSName, map[string]string{
+			stewardv1alpha1.AnnotationTenantNamespacePrefix: tenantNSPrefix,
+			stewardv1alpha1.AnnotationTenantRole:            tenantRoleName,
+		}),
+		// the tenant
+		k8sfake.Tenant(tenantID, clientNSName),
+	)
+	ctl := NewController(cf, ControllerOpts{})
+	ctl.fetcher = k8s.NewClientBasedTenantFetcher(cf)
+	tenantKey := makeTenantKey(clientNSName, tenantID)
+	tenantsIfc := cf.StewardV1alpha1().Tenants(clientNSName)
+	var tenantNSName string
+
+	// initialize tenant
+	{
+		err := ctl.syncHandler(tenantKey)
+		assert.NilError(t, err)
+
+		initializedTenant, err := tenantsIfc.Get(ctx, tenantID, metav1.GetOptions{})
+		assert.NilError(t, err)
+		tenantNSName = initializedTenant.Status.TenantNamespaceName
+	}
+
+	assert.Assert(t, tenantNSName != "")
+
+	// create running pipeline run
+	pipelineRunStore := cf.StewardInformerFactory().Steward().V1alpha1().PipelineRuns().Informer().GetIndexer()
+	run := k8sfake.PipelineRun("run1", tenantNSName, stewardv1alpha1.PipelineSpec{})
+	run.Status.State = stewardv1alpha1.StateRunning
+	assert.NilError(t, pipelineRunStore.Add(run))
+
+	// mark tenant as deleted
+	{
+		// Fake client deletes immediately -> set deletion timestamp
+		tenant, err := tenantsIfc.Get(ctx, tenantID, metav1.GetOptions{})
+		assert.NilError(t, err)
+		tenant.SetDeletionTimestamp(&metav1.Time{Time: time.Now()})
+		_, err = tenantsIfc.Update(ctx, tenant, metav1.UpdateOptions{})
+		assert.NilError(t, err)
+	}
+
+	// EXERCISE
+	resultErr := ctl.syncHandler(tenantKey)
+
+	// VERIFY
+	assert.Error(t, resultErr, fmt.Sprintf("deletion of tenant is blocked by active pipeline runs in tenant namespace %q", tenantNSName))
+	assertThatExactlyTheseNamespacesExist(t, cf,
+		clientNSName,
+		tenantNSName, // tenant namespace NOT removed
+	)
+	assertThatExactlyTheseTenantsExistInNamespace(t, cf, clientNSName,
+		tenantID, // finalizer NOT removed
+	)
+	tenant, err := tenantsIfc.Get(ctx, tenantID, metav1.GetOptions{})
+	assert.NilError(t, err)
+	readyCond := tenant.Status.GetCondition(knativeapis.ConditionReady)
+	assert.Equal(t, stewardv1alpha1.StatusReasonActivePipelineRuns, readyCond.Reason)
+
+	// abort pipeline run
+	run = run.DeepCopy()
+	run.Spec.Intent = stewardv1alpha1.IntentAbort
+	assert.NilError(t, pipelineRunStore.Update(run))
+
+	// EXERCISE
+	resultErr = ctl.syncHandler(tenantKey)
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assertThatExactlyTheseNamespacesExist(t, cf,
+		clientNSName,
+		// tenant namespace removed
+	)
+	assertThatExactlyTheseTenantsExistInNamespace(t, cf, clientNSName /*none*/)
+}
+
+func Test_Controller_syncHandler_CleanupOnDelete_WithRetentionAndRestore(t *testing.T) {
+	// SETUP
+	const (
+		clientNSName   = "client1"
+		tenantNSPrefix = "prefix1"
+		tenantID       = "tenant1"
+		tenantRoleName = "tenantClusterRole1"
+	)
+
+	ctx := context.Background()
+	cf := k8sfake.NewClientFactory(
+		// the client namespace
+		k8sfake.NamespaceWithAnnotations(clientNSName, map[string]string{
+			stewardv1alpha1.AnnotationTenantNamespacePrefix: tenantNSPrefix,
+			stewardv1alpha1.AnnotationTenantRole:            tenantRoleName,
+		}),
+		// the retention period for the client
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: tenantsConfigMapName, Namespace: clientNSName},
+			Data:       map[string]string{tenantsConfigKeyNamespaceRetentionPeriod: "24h"},
+		},
+		// the tenant
+		k8sfake.Tenant(tenantID, clientNSName),
+	)
+	ctl := NewController(cf, ControllerOpts{})
+	ctl.fetcher = k8s.NewClientBasedTenantFetcher(cf)
+	tenantKey := makeTenantKey(clientNSName, tenantID)
+	tenantsIfc := cf.StewardV1alpha1().Tenants(clientNSName)
+	var tenantNSName string
+
+	// initialize tenant
+	{
+		err := ctl.syncHandler(tenantKey)
+		assert.NilError(t, err)
+
+		initializedTenant, err := tenantsIfc.Get(ctx, tenantID, metav1.GetOptions{})
+		assert.NilError(t, err)
+		tenantNSName = initializedTenant.Status.TenantNamespaceName
+	}
+
+	assert.Assert(t, tenantNSName != "")
+
+	// mark tenant as deleted
+	{
+		// Fake client deletes immediately -> set deletion timestamp
+		tenant, err := tenantsIfc.Get(ctx, tenantID, metav1.GetOptions{})
+		assert.NilError(t, err)
+		tenant.SetDeletionTimestamp(&metav1.Time{Time: time.Now()})
+		_, err = tenantsIfc.Update(ctx, tenant, metav1.UpdateOptions{})
+		assert.NilError(t, err)
+	}
+
+	// EXERCISE
+	resultErr := ctl.syncHandler(tenantKey)
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assertThatExactlyTheseNamespacesExist(t, cf,
+		clientNSName,
+		tenantNSName, // tenant namespace retained
+	)
+	assertThatExactlyTheseTenantsExistInNamespace(t, cf, clientNSName /*none*/)
+	namespace, err := cf.CoreV1().Namespaces().Get(ctx, tenantNSName, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Assert(t, isPendingDeletion(namespace))
+
+	// recreate tenant
+	{
+		// Fake client does not remove finalized objects -> delete explicitly
+		err := tenantsIfc.Delete(ctx, tenantID, metav1.DeleteOptions{})
+		assert.NilError(t, err)
+		_, err = tenantsIfc.Create(ctx, k8sfake.Tenant(tenantID, clientNSName), metav1.CreateOptions{})
+		assert.NilError(t, err)
+	}
+
+	// EXERCISE
+	resultErr = ctl.syncHandler(tenantKey)
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assertThatExactlyTheseNamespacesExist(t, cf,
+		clientNSName,
+		tenantNSName, // no new tenant namespace
+	)
+	tenant, err := tenantsIfc.Get(ctx, tenantID, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, tenantNSName, tenant.Status.TenantNamespaceName)
+	namespace, err = cf.CoreV1().Namespaces().Get(ctx, tenantNSName, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Assert(t, !isPendingDeletion(namespace))
+	roleBindings, err := cf.RbacV1().RoleBindings(tenantNSName).List(ctx, metav1.ListOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(roleBindings.Items)) // permissions granted again
+}
+
 func Test_Controller_syncHandler_CleanupOnDelete_SkippedIfFinalizerIsNotSet(t *testing.T) {
 	// SETUP
 	const (
@@ -830,6 +1171,80 @@ func Test_Controller_syncHandler_CleanupOnStatusUpdateFailure(t *testing.T) {
 	)
 }
 
+func Test_Controller_syncHandler_CleanupOnStatusUpdateFailure_ReleasesAdoptedNamespace(t *testing.T) {
+	// SETUP
+	const (
+		clientNSName   = "client1"
+		tenantNSName   = "prefix1-legacy"
+		tenantID       = "tenant1"
+		tenantRoleName = "tenantClusterRole1"
+	)
+
+	ctx := context.Background()
+	adoptedNamespace := k8sfake.Namespace(tenantNSName)
+	adoptedNamespace.SetLabels(map[string]string{
+		"key1":                               "value1",
+		"pod-security.kubernetes.io/enforce": "baseline", // configured, existing before adoption
+	})
+	adoptedNamespace.SetAnnotations(map[string]string{
+		"annotation1":       "value1",
+		"example.com/owner": "team1", // configured, existing before adoption
+	})
+	tenant := k8sfake.Tenant(tenantID, clientNSName)
+	tenant.SetAnnotations(map[string]string{stewardv1alpha1.AnnotationAdoptNamespace: tenantNSName})
+	cf := k8sfake.NewClientFactory(
+		k8sfake.Namespace(clientNSName),
+		adoptedNamespace,
+		tenant,
+	)
+	// role bindings without name and UID are not deleted
+	cf.KubernetesClientset().PrependReactor("create", "rolebindings", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		action.(k8stesting.CreateAction).GetObject().(*rbacv1.RoleBinding).SetUID(types.UID("uid1"))
+		return false, nil, nil
+	})
+	cf.KubernetesClientset().PrependReactor("create", "rolebindings", k8sfake.GenerateNameReactor(5))
+	ctl := NewController(cf, ControllerOpts{})
+	ctl.fetcher = k8s.NewClientBasedTenantFetcher(cf)
+
+	injectedError := errors.New("ERR1")
+	ctl.testing = &controllerTesting{
+		getClientConfigStub: func(k8s.ClientFactory, string) (clientConfig, error) {
+			return &clientConfigImpl{
+				tenantNamespacePrefix:      "prefix1",
+				tenantRoleName:             tenantRoleName,
+				tenantNamespaceLabels: map[string]string{
+					"example.com/tenant":                 "{{.TenantName}}",
+					"pod-security.kubernetes.io/enforce": "restricted",
+				},
+				tenantNamespaceAnnotations: map[string]string{
+					"example.com/client": "{{.ClientNamespace}}",
+					"example.com/owner":  "steward",
+				},
+			}, nil
+		},
+		updateStatusStub: func(tenant *stewardv1alpha1.Tenant) (*stewardv1alpha1.Tenant, error) {
+			return tenant, injectedError
+		},
+	}
+
+	// EXERCISE
+	resultErr := ctl.syncHandler(makeTenantKey(clientNSName, tenantID))
+
+	// VERIFY
+	assert.Assert(t, injectedError == resultErr)
+	assertThatExactlyTheseNamespacesExist(t, cf,
+		clientNSName,
+		tenantNSName, // adopted namespace is kept
+	)
+	namespace, err := cf.CoreV1().Namespaces().Get(ctx, tenantNSName, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, adoptedNamespace.GetLabels(), namespace.GetLabels())
+	assert.DeepEqual(t, adoptedNamespace.GetAnnotations(), namespace.GetAnnotations())
+	roleBindings, err := cf.RbacV1().RoleBindings(tenantNSName).List(ctx, metav1.ListOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(roleBindings.Items))
+}
+
 func Test_Controller_reconcileTenantRoleBinding_FailsOnErrorIn_listManagedRoleBindings(t *testing.T) {
 	// SETUP
 	const (
@@ -0,0 +1,70 @@
+package tenantctl
+
+import (
+	"context"
+	"fmt"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	errors "github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	klog "k8s.io/klog/v2"
+	knativeapis "knative.dev/pkg/apis"
+)
+
+/*
+isDeletionBlocked returns whether the deletion of the given tenant must be
+postponed because pipeline runs in the tenant namespace are still active.
+A pipeline run is active until it is finished or its intent is `abort`.
+If blocked, the ready condition of the tenant gets set accordingly.
+
+Operators can force the deletion via annotation
+`steward.sap.com/force-deletion: "true"` at the tenant.
+*/
+func (c *Controller) isDeletionBlocked(ctx context.Context, tenant *stewardv1alpha1.Tenant) (bool, error) {
+	nsName := tenant.Status.TenantNamespaceName
+	if nsName == "" {
+		return false, nil
+	}
+	if tenant.GetAnnotations()[stewardv1alpha1.AnnotationForceDeletion] == "true" {
+		klog.V(3).Infof(c.formatLog(tenant, "deletion is forced, active pipeline runs are not checked"))
+		return false, nil
+	}
+
+	activeRuns, err := c.countActivePipelineRuns(nsName)
+	if err != nil {
+		klog.V(3).Infof(c.formatLog(tenant), err)
+		return false, err
+	}
+	if activeRuns == 0 {
+		return false, nil
+	}
+
+	condMsg := fmt.Sprintf(
+		"The tenant cannot be deleted because %d pipeline run(s) in tenant namespace %q are still active."+
+			" The deletion proceeds when they are finished or aborted.",
+		activeRuns, nsName,
+	)
+	tenant.Status.SetCondition(&knativeapis.Condition{
+		Type:    knativeapis.ConditionReady,
+		Status:  corev1.ConditionFalse,
+		Reason:  stewardv1alpha1.StatusReasonActivePipelineRuns,
+		Message: condMsg,
+	})
+	return true, nil
+}
+
+func (c *Controller) countActivePipelineRuns(namespace string) (int, error) {
+	runs, err := c.pipelineRunLister.PipelineRuns(namespace).List(labels.Everything())
+	if err != nil {
+		return 0, errors.WithMessagef(err, "failed to list pipeline runs in tenant namespace %q", namespace)
+	}
+	count := 0
+	for _, run := range runs {
+		if run.Status.State == stewardv1alpha1.StateFinished || run.Spec.Intent == stewardv1alpha1.IntentAbort {
+			continue
+		}
+		count++
+	}
+	return count, nil
+}
@@ -0,0 +1,88 @@
+package tenantctl
+
+import (
+	"context"
+	"testing"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	k8sfake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	assert "gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	knativeapis "knative.dev/pkg/apis"
+)
+
+func Test_Controller_isDeletionBlocked(t *testing.T) {
+	t.Parallel()
+
+	newRun := func(name string, state stewardv1alpha1.State, intent stewardv1alpha1.Intent) *stewardv1alpha1.PipelineRun {
+		run := k8sfake.PipelineRun(name, "tenantNS1", stewardv1alpha1.PipelineSpec{Intent: intent})
+		run.Status.State = state
+		return run
+	}
+
+	for _, tc := range []struct {
+		name            string
+		annotations     map[string]string
+		runs            []*stewardv1alpha1.PipelineRun
+		expectedBlocked bool
+	}{
+		{"no_runs", nil, nil, false},
+		{"finished", nil, []*stewardv1alpha1.PipelineRun{
+			newRun("run1", stewardv1alpha1.StateFinished, ""),
+		}, false},
+		{"aborted_finished", nil, []*stewardv1alpha1.PipelineRun{
+			newRun("run1", stewardv1alpha1.StateFinished, stewardv1alpha1.IntentAbort),
+		}, false},
+		{"aborted_running", nil, []*stewardv1alpha1.PipelineRun{
+			newRun("run1", stewardv1alpha1.StateRunning, stewardv1alpha1.IntentAbort),
+		}, false},
+		{"other_namespace", nil, []*stewardv1alpha1.PipelineRun{
+			k8sfake.PipelineRun("run1", "otherNS", stewardv1alpha1.PipelineSpec{}),
+		}, false},
+		{"new", nil, []*stewardv1alpha1.PipelineRun{
+			newRun("run1", stewardv1alpha1.StateUndefined, ""),
+		}, true},
+		{"running", nil, []*stewardv1alpha1.PipelineRun{
+			newRun("run1", stewardv1alpha1.StateFinished, ""),
+			newRun("run2", stewardv1alpha1.StateRunning, stewardv1alpha1.IntentRun),
+		}, true},
+		{"forced", map[string]string{stewardv1alpha1.AnnotationForceDeletion: "true"}, []*stewardv1alpha1.PipelineRun{
+			newRun("run1", stewardv1alpha1.StateRunning, ""),
+		}, false},
+		{"force_annotation_not_true", map[string]string{stewardv1alpha1.AnnotationForceDeletion: "false"}, []*stewardv1alpha1.PipelineRun{
+			newRun("run1", stewardv1alpha1.StateRunning, ""),
+		}, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			ctx := context.Background()
+			cf := k8sfake.NewClientFactory()
+			examinee := NewController(cf, ControllerOpts{})
+			pipelineRunStore := cf.StewardInformerFactory().Steward().V1alpha1().PipelineRuns().Informer().GetIndexer()
+			for _, run := range tc.runs {
+				assert.NilError(t, pipelineRunStore.Add(run))
+			}
+			tenant := k8sfake.Tenant("tenant1", "client1")
+			tenant.SetAnnotations(tc.annotations)
+			tenant.Status.TenantNamespaceName = "tenantNS1"
+
+			// EXERCISE
+			result, resultErr := examinee.isDeletionBlocked(ctx, tenant)
+
+			// VERIFY
+			assert.NilError(t, resultErr)
+			assert.Equal(t, tc.expectedBlocked, result)
+			condition := tenant.Status.GetCondition(knativeapis.ConditionReady)
+			if tc.expectedBlocked {
+				assert.Assert(t, condition != nil)
+				assert.Equal(t, corev1.ConditionFalse, condition.Status)
+				assert.Equal(t, stewardv1alpha1.StatusReasonActivePipelineRuns, condition.Reason)
+			} else {
+				assert.Assert(t, condition == nil)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package tenantctl
+
+import (
+	"context"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	slabels "github.com/SAP/stewardci-core/pkg/stewardlabels"
+	errors "github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klog "k8s.io/klog/v2"
+)
+
+// tenantNamespaceTemplateData is the input for the label and annotation
+// templates of tenant namespaces.
+type tenantNamespaceTemplateData struct {
+	ClientNamespace string
+	TenantName      string
+	TenantNamespace string
+}
+
+/*
+reconcileTenantNamespaceMetadata sets the labels and annotations configured
+for the client on the tenant namespace. Values modified by others are
+reset. Labels and annotations whose templates have been removed from the
+configuration are left untouched, as the tenant controller cannot tell
+them apart from those set by others.
+*/
+func (c *Controller) reconcileTenantNamespaceMetadata(ctx context.Context, tenant *stewardv1alpha1.Tenant, nsName string, config clientConfig) error {
+	err := c.applyTenantNamespaceMetadata(ctx, tenant, nsName, config)
+	if err != nil {
+		err = errors.WithMessagef(err,
+			"failed to reconcile the labels and annotations of tenant namespace %q",
+			nsName,
+		)
+		klog.V(4).Infof(c.formatLog(tenant), err)
+		return err
+	}
+	return nil
+}
+
+func (c *Controller) applyTenantNamespaceMetadata(ctx context.Context, tenant *stewardv1alpha1.Tenant, nsName string, config clientConfig) error {
+	labelTemplates := config.GetTenantNamespaceLabels()
+	annotationTemplates := config.GetTenantNamespaceAnnotations()
+	if len(labelTemplates) == 0 && len(annotationTemplates) == 0 {
+		return nil
+	}
+
+	data := &tenantNamespaceTemplateData{
+		ClientNamespace: tenant.GetNamespace(),
+		TenantName:      tenant.GetName(),
+		TenantNamespace: nsName,
+	}
+	labels, err := slabels.RenderLabelTemplates(labelTemplates, data)
+	if err != nil {
+		return errors.WithMessage(err, "invalid label template")
+	}
+	annotations, err := slabels.RenderAnnotationTemplates(annotationTemplates, data)
+	if err != nil {
+		return errors.WithMessage(err, "invalid annotation template")
+	}
+
+	namespaces := c.factory.CoreV1().Namespaces()
+	namespace, err := namespaces.Get(ctx, nsName, metav1.GetOptions{})
+	if err != nil {
+		return errors.WithMessage(err, "failed to get namespace")
+	}
+	labelsChanged := mergeStringMap(&namespace.Labels, labels)
+	annotationsChanged := mergeStringMap(&namespace.Annotations, annotations)
+	if !labelsChanged && !annotationsChanged {
+		return nil
+	}
+	if _, err := namespaces.Update(ctx, namespace, metav1.UpdateOptions{}); err != nil {
+		return errors.WithMessage(err, "failed to update namespace")
+	}
+	return nil
+}
+
+// mergeStringMap sets all entries of `src` in `dest` and returns whether
+// `dest` has been changed.
+func mergeStringMap(dest *map[string]string, src map[string]string) bool {
+	changed := false
+	for key, value := range src {
+		if current, found := (*dest)[key]; found && current == value {
+			continue
+		}
+		if *dest == nil {
+			*dest = map[string]string{}
+		}
+		(*dest)[key] = value
+		changed = true
+	}
+	return changed
+}
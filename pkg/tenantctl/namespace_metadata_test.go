@@ -0,0 +1,73 @@
+package tenantctl
+
+import (
+	"context"
+	"testing"
+
+	k8sfake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	assert "gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_Controller_reconcileTenantNamespaceMetadata(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	namespace := k8sfake.Namespace("prefix1-ns1")
+	namespace.SetLabels(map[string]string{
+		"cost-center": "modified",
+		"other":       "value1",
+	})
+	cf := k8sfake.NewClientFactory(namespace)
+	examinee := NewController(cf, ControllerOpts{})
+	config := &clientConfigImpl{
+		tenantNamespaceLabels: map[string]string{
+			"cost-center":        "cc1",
+			"example.com/tenant": "{{ .TenantName }}",
+		},
+		tenantNamespaceAnnotations: map[string]string{
+			"example.com/owner": "{{ .ClientNamespace }}/{{ .TenantName }} in {{ .TenantNamespace }}",
+		},
+	}
+
+	// EXERCISE
+	resultErr := examinee.reconcileTenantNamespaceMetadata(ctx, k8sfake.Tenant("tenant1", "client1"), "prefix1-ns1", config)
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	namespace, err := cf.CoreV1().Namespaces().Get(ctx, "prefix1-ns1", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, map[string]string{
+		"cost-center":        "cc1",
+		"example.com/tenant": "tenant1",
+		"other":              "value1",
+	}, namespace.GetLabels())
+	assert.DeepEqual(t, map[string]string{
+		"example.com/owner": "client1/tenant1 in prefix1-ns1",
+	}, namespace.GetAnnotations())
+}
+
+func Test_Controller_reconcileTenantNamespaceMetadata_InvalidLabelValue(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	cf := k8sfake.NewClientFactory(k8sfake.Namespace("prefix1-ns1"))
+	examinee := NewController(cf, ControllerOpts{})
+	config := &clientConfigImpl{
+		tenantNamespaceLabels: map[string]string{
+			"example.com/tenant": "{{ .ClientNamespace }}/{{ .TenantName }}",
+		},
+	}
+
+	// EXERCISE
+	resultErr := examinee.reconcileTenantNamespaceMetadata(ctx, k8sfake.Tenant("tenant1", "client1"), "prefix1-ns1", config)
+
+	// VERIFY
+	assert.ErrorContains(t, resultErr,
+		`failed to reconcile the labels and annotations of tenant namespace "prefix1-ns1": invalid label template: label "example.com/tenant": invalid value "client1/tenant1"`)
+	namespace, err := cf.CoreV1().Namespaces().Get(ctx, "prefix1-ns1", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(namespace.GetLabels()))
+}
@@ -0,0 +1,319 @@
+package tenantctl
+
+import (
+	"context"
+
+	stewardapis "github.com/SAP/stewardci-core/pkg/apis/steward"
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	slabels "github.com/SAP/stewardci-core/pkg/stewardlabels"
+	"github.com/ghodss/yaml"
+	errors "github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	equality "k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	networkingv1client "k8s.io/client-go/kubernetes/typed/networking/v1"
+	klog "k8s.io/klog/v2"
+)
+
+// tenantNamespaceObjectName is the name of the configured resource objects
+// in tenant namespaces. There is at most one object per resource type.
+const tenantNamespaceObjectName = stewardapis.GroupName + "--tenant"
+
+/*
+reconcileTenantNamespaceObjects creates, updates or deletes the resource
+quota, limit range and network policy in the tenant namespace so that they
+match the manifests configured for the client.
+Only resource objects labelled as system managed are taken into account,
+all others will not be touched.
+*/
+func (c *Controller) reconcileTenantNamespaceObjects(ctx context.Context, tenant *stewardv1alpha1.Tenant, namespace string, config clientConfig) error {
+	if c.testing != nil && c.testing.reconcileTenantNamespaceObjectsStub != nil {
+		return c.testing.reconcileTenantNamespaceObjectsStub(tenant, namespace, config)
+	}
+
+	for _, kind := range []tenantNamespaceObjectKind{
+		&resourceQuotaKind{client: c.factory.CoreV1().ResourceQuotas(namespace)},
+		&limitRangeKind{client: c.factory.CoreV1().LimitRanges(namespace)},
+		&networkPolicyKind{client: c.factory.NetworkingV1().NetworkPolicies(namespace)},
+	} {
+		if err := reconcileTenantNamespaceObject(ctx, kind, namespace, config); err != nil {
+			err = errors.WithMessagef(err,
+				"failed to reconcile the resource objects in tenant namespace %q",
+				namespace,
+			)
+			klog.V(4).Infof(c.formatLog(tenant), err)
+			return err
+		}
+	}
+	return nil
+}
+
+// tenantNamespaceObjectKind adapts a resource type of configured resource
+// objects in tenant namespaces to `reconcileTenantNamespaceObject`.
+// Implementations are bound to the client of a single tenant namespace.
+type tenantNamespaceObjectKind interface {
+	// displayName returns the name of the resource type used in messages,
+	// in singular or plural.
+	displayName(plural bool) string
+
+	// expectedObject returns the resource object configured for the given
+	// tenant namespace, or `nil` if none is configured.
+	expectedObject(namespace string, config clientConfig) (metav1.Object, error)
+
+	// spec returns the part of the given resource object to be compared.
+	spec(obj metav1.Object) interface{}
+
+	listManaged(ctx context.Context) ([]metav1.Object, error)
+	create(ctx context.Context, obj metav1.Object) error
+	update(ctx context.Context, obj metav1.Object) error
+	delete(ctx context.Context, name string) error
+}
+
+// reconcileTenantNamespaceObject creates, updates or deletes the resource
+// objects of the given kind in the tenant namespace so that exactly the
+// configured one exists.
+func reconcileTenantNamespaceObject(ctx context.Context, kind tenantNamespaceObjectKind, namespace string, config clientConfig) error {
+	expected, err := kind.expectedObject(namespace, config)
+	if err != nil {
+		return err
+	}
+
+	list, err := kind.listManaged(ctx)
+	if err != nil {
+		return errors.WithMessagef(err, "failed to list managed %s", kind.displayName(true))
+	}
+	found := false
+	for _, current := range list {
+		if expected != nil && current.GetName() == expected.GetName() {
+			found = true
+			if !isTenantNamespaceObjectUpToDate(current, expected, kind.spec(current), kind.spec(expected)) {
+				expected.SetResourceVersion(current.GetResourceVersion())
+				if err := kind.update(ctx, expected); err != nil {
+					return errors.WithMessagef(err, "failed to update %s %q", kind.displayName(false), current.GetName())
+				}
+			}
+			continue
+		}
+		if err := kind.delete(ctx, current.GetName()); err != nil && !kerrors.IsNotFound(err) {
+			return errors.WithMessagef(err, "failed to delete %s %q", kind.displayName(false), current.GetName())
+		}
+	}
+	if expected != nil && !found {
+		if err := kind.create(ctx, expected); err != nil {
+			return errors.WithMessagef(err, "failed to create %s", kind.displayName(false))
+		}
+	}
+	return nil
+}
+
+type resourceQuotaKind struct {
+	client corev1client.ResourceQuotaInterface
+}
+
+func (k *resourceQuotaKind) displayName(plural bool) string {
+	if plural {
+		return "resource quotas"
+	}
+	return "resource quota"
+}
+
+func (k *resourceQuotaKind) expectedObject(namespace string, config clientConfig) (metav1.Object, error) {
+	manifest := config.GetTenantNamespaceResourceQuota()
+	if manifest == "" {
+		return nil, nil
+	}
+	decoded := &corev1.ResourceQuota{}
+	if err := decodeTenantNamespaceObject(manifest, decoded, schema.GroupKind{Kind: "ResourceQuota"}, k.displayName(false)); err != nil {
+		return nil, err
+	}
+	return &corev1.ResourceQuota{
+		ObjectMeta: newTenantNamespaceObjectMeta(namespace),
+		Spec:       decoded.Spec,
+	}, nil
+}
+
+func (k *resourceQuotaKind) spec(obj metav1.Object) interface{} {
+	return obj.(*corev1.ResourceQuota).Spec
+}
+
+func (k *resourceQuotaKind) listManaged(ctx context.Context) ([]metav1.Object, error) {
+	list, err := k.client.List(ctx, listManagedOptions())
+	if err != nil {
+		return nil, err
+	}
+	result := make([]metav1.Object, len(list.Items))
+	for i := range list.Items {
+		result[i] = &list.Items[i]
+	}
+	return result, nil
+}
+
+func (k *resourceQuotaKind) create(ctx context.Context, obj metav1.Object) error {
+	_, err := k.client.Create(ctx, obj.(*corev1.ResourceQuota), metav1.CreateOptions{})
+	return err
+}
+
+func (k *resourceQuotaKind) update(ctx context.Context, obj metav1.Object) error {
+	_, err := k.client.Update(ctx, obj.(*corev1.ResourceQuota), metav1.UpdateOptions{})
+	return err
+}
+
+func (k *resourceQuotaKind) delete(ctx context.Context, name string) error {
+	return k.client.Delete(ctx, name, metav1.DeleteOptions{})
+}
+
+type limitRangeKind struct {
+	client corev1client.LimitRangeInterface
+}
+
+func (k *limitRangeKind) displayName(plural bool) string {
+	if plural {
+		return "limit ranges"
+	}
+	return "limit range"
+}
+
+func (k *limitRangeKind) expectedObject(namespace string, config clientConfig) (metav1.Object, error) {
+	manifest := config.GetTenantNamespaceLimitRange()
+	if manifest == "" {
+		return nil, nil
+	}
+	decoded := &corev1.LimitRange{}
+	if err := decodeTenantNamespaceObject(manifest, decoded, schema.GroupKind{Kind: "LimitRange"}, k.displayName(false)); err != nil {
+		return nil, err
+	}
+	return &corev1.LimitRange{
+		ObjectMeta: newTenantNamespaceObjectMeta(namespace),
+		Spec:       decoded.Spec,
+	}, nil
+}
+
+func (k *limitRangeKind) spec(obj metav1.Object) interface{} {
+	return obj.(*corev1.LimitRange).Spec
+}
+
+func (k *limitRangeKind) listManaged(ctx context.Context) ([]metav1.Object, error) {
+	list, err := k.client.List(ctx, listManagedOptions())
+	if err != nil {
+		return nil, err
+	}
+	result := make([]metav1.Object, len(list.Items))
+	for i := range list.Items {
+		result[i] = &list.Items[i]
+	}
+	return result, nil
+}
+
+func (k *limitRangeKind) create(ctx context.Context, obj metav1.Object) error {
+	_, err := k.client.Create(ctx, obj.(*corev1.LimitRange), metav1.CreateOptions{})
+	return err
+}
+
+func (k *limitRangeKind) update(ctx context.Context, obj metav1.Object) error {
+	_, err := k.client.Update(ctx, obj.(*corev1.LimitRange), metav1.UpdateOptions{})
+	return err
+}
+
+func (k *limitRangeKind) delete(ctx context.Context, name string) error {
+	return k.client.Delete(ctx, name, metav1.DeleteOptions{})
+}
+
+type networkPolicyKind struct {
+	client networkingv1client.NetworkPolicyInterface
+}
+
+func (k *networkPolicyKind) displayName(plural bool) string {
+	if plural {
+		return "network policies"
+	}
+	return "network policy"
+}
+
+func (k *networkPolicyKind) expectedObject(namespace string, config clientConfig) (metav1.Object, error) {
+	manifest := config.GetTenantNamespaceNetworkPolicy()
+	if manifest == "" {
+		return nil, nil
+	}
+	decoded := &networkingv1.NetworkPolicy{}
+	expectedGroupKind := schema.GroupKind{Group: networkingv1.GroupName, Kind: "NetworkPolicy"}
+	if err := decodeTenantNamespaceObject(manifest, decoded, expectedGroupKind, k.displayName(false)); err != nil {
+		return nil, err
+	}
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: newTenantNamespaceObjectMeta(namespace),
+		Spec:       decoded.Spec,
+	}, nil
+}
+
+func (k *networkPolicyKind) spec(obj metav1.Object) interface{} {
+	return obj.(*networkingv1.NetworkPolicy).Spec
+}
+
+func (k *networkPolicyKind) listManaged(ctx context.Context) ([]metav1.Object, error) {
+	list, err := k.client.List(ctx, listManagedOptions())
+	if err != nil {
+		return nil, err
+	}
+	result := make([]metav1.Object, len(list.Items))
+	for i := range list.Items {
+		result[i] = &list.Items[i]
+	}
+	return result, nil
+}
+
+func (k *networkPolicyKind) create(ctx context.Context, obj metav1.Object) error {
+	_, err := k.client.Create(ctx, obj.(*networkingv1.NetworkPolicy), metav1.CreateOptions{})
+	return err
+}
+
+func (k *networkPolicyKind) update(ctx context.Context, obj metav1.Object) error {
+	_, err := k.client.Update(ctx, obj.(*networkingv1.NetworkPolicy), metav1.UpdateOptions{})
+	return err
+}
+
+func (k *networkPolicyKind) delete(ctx context.Context, name string) error {
+	return k.client.Delete(ctx, name, metav1.DeleteOptions{})
+}
+
+// decodeTenantNamespaceObject decodes the given YAML manifest into `obj`
+// and checks that it denotes a resource object of the expected kind.
+func decodeTenantNamespaceObject(manifest string, obj runtime.Object, expectedGroupKind schema.GroupKind, displayName string) error {
+	if err := yaml.Unmarshal([]byte(manifest), obj); err != nil {
+		return errors.WithMessagef(err, "failed to decode configured %s", displayName)
+	}
+	if groupKind := obj.GetObjectKind().GroupVersionKind().GroupKind(); groupKind != expectedGroupKind {
+		return errors.Errorf(
+			"configured %s does not denote a %q but a %q",
+			displayName, expectedGroupKind.String(), groupKind.String(),
+		)
+	}
+	return nil
+}
+
+// newTenantNamespaceObjectMeta returns the metadata of a configured
+// resource object in the given tenant namespace. Any metadata from the
+// manifest is ignored to prevent side effects.
+func newTenantNamespaceObjectMeta(namespace string) metav1.ObjectMeta {
+	meta := metav1.ObjectMeta{
+		Name:      tenantNamespaceObjectName,
+		Namespace: namespace,
+	}
+	slabels.LabelAsSystemManaged(&meta)
+	return meta
+}
+
+func isTenantNamespaceObjectUpToDate(current, expected metav1.Object, currentSpec, expectedSpec interface{}) bool {
+	return equality.Semantic.DeepEqual(expected.GetLabels(), current.GetLabels()) &&
+		equality.Semantic.DeepEqual(expectedSpec, currentSpec)
+}
+
+func listManagedOptions() metav1.ListOptions {
+	return metav1.ListOptions{
+		LabelSelector: stewardv1alpha1.LabelSystemManaged,
+	}
+}
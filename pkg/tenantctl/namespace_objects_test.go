@@ -0,0 +1,163 @@
+package tenantctl
+
+import (
+	"context"
+	"testing"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	k8sfake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	assert "gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	testResourceQuotaManifest = `
+apiVersion: v1
+kind: ResourceQuota
+metadata:
+  name: ignored
+spec:
+  hard:
+    count/pipelineruns.steward.sap.com: "100"
+    count/secrets: "50"
+`
+	testLimitRangeManifest = `
+apiVersion: v1
+kind: LimitRange
+spec:
+  limits:
+  - type: Container
+    defaultRequest:
+      cpu: 100m
+`
+	testNetworkPolicyManifest = `
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+spec:
+  podSelector: {}
+  policyTypes:
+  - Ingress
+`
+)
+
+func Test_Controller_reconcileTenantNamespaceObjects_CreatesObjects(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	cf := k8sfake.NewClientFactory()
+	examinee := NewController(cf, ControllerOpts{})
+	config := &clientConfigImpl{
+		tenantNamespaceResourceQuota: testResourceQuotaManifest,
+		tenantNamespaceLimitRange:    testLimitRangeManifest,
+		tenantNamespaceNetworkPolicy: testNetworkPolicyManifest,
+	}
+
+	// EXERCISE
+	resultErr := examinee.reconcileTenantNamespaceObjects(ctx, k8sfake.Tenant("tenant1", "client1"), "tenantNS1", config)
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+
+	quota, err := cf.CoreV1().ResourceQuotas("tenantNS1").Get(ctx, tenantNamespaceObjectName, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, map[string]string{stewardv1alpha1.LabelSystemManaged: ""}, quota.GetLabels())
+	assert.Equal(t, 2, len(quota.Spec.Hard))
+	secrets := quota.Spec.Hard["count/secrets"]
+	assert.Equal(t, int64(50), secrets.Value())
+
+	limitRange, err := cf.CoreV1().LimitRanges("tenantNS1").Get(ctx, tenantNamespaceObjectName, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(limitRange.Spec.Limits))
+
+	policy, err := cf.NetworkingV1().NetworkPolicies("tenantNS1").Get(ctx, tenantNamespaceObjectName, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}, policy.Spec.PolicyTypes)
+}
+
+func Test_Controller_reconcileTenantNamespaceObjects_UpdatesAndDeletesObjects(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	managedLabels := map[string]string{stewardv1alpha1.LabelSystemManaged: ""}
+	outdatedQuota := &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: tenantNamespaceObjectName, Namespace: "tenantNS1", Labels: managedLabels},
+		Spec: corev1.ResourceQuotaSpec{Hard: corev1.ResourceList{
+			"count/secrets": resource.MustParse("10"),
+		}},
+	}
+	leftoverQuota := &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "leftover", Namespace: "tenantNS1", Labels: managedLabels},
+	}
+	unmanagedQuota := &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "unmanaged", Namespace: "tenantNS1"},
+	}
+	obsoleteLimitRange := &corev1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{Name: tenantNamespaceObjectName, Namespace: "tenantNS1", Labels: managedLabels},
+	}
+	cf := k8sfake.NewClientFactory(outdatedQuota, leftoverQuota, unmanagedQuota, obsoleteLimitRange)
+	examinee := NewController(cf, ControllerOpts{})
+	config := &clientConfigImpl{
+		tenantNamespaceResourceQuota: testResourceQuotaManifest,
+	}
+
+	// EXERCISE
+	resultErr := examinee.reconcileTenantNamespaceObjects(ctx, k8sfake.Tenant("tenant1", "client1"), "tenantNS1", config)
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+
+	quotas, err := cf.CoreV1().ResourceQuotas("tenantNS1").List(ctx, metav1.ListOptions{})
+	assert.NilError(t, err)
+	names := []string{}
+	for _, quota := range quotas.Items {
+		names = append(names, quota.GetName())
+	}
+	assert.DeepEqual(t, []string{tenantNamespaceObjectName, "unmanaged"}, names)
+	secrets := quotas.Items[0].Spec.Hard["count/secrets"]
+	assert.Equal(t, int64(50), secrets.Value())
+
+	limitRanges, err := cf.CoreV1().LimitRanges("tenantNS1").List(ctx, metav1.ListOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(limitRanges.Items))
+}
+
+func Test_Controller_reconcileTenantNamespaceObjects_InvalidManifest(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		config        *clientConfigImpl
+		expectedError string
+	}{
+		{
+			name:          "wrong_kind",
+			config:        &clientConfigImpl{tenantNamespaceResourceQuota: testLimitRangeManifest},
+			expectedError: `failed to reconcile the resource objects in tenant namespace "tenantNS1": configured resource quota does not denote a "ResourceQuota" but a "LimitRange"`,
+		},
+		{
+			name:          "wrong_group",
+			config:        &clientConfigImpl{tenantNamespaceNetworkPolicy: "apiVersion: v1\nkind: NetworkPolicy\n"},
+			expectedError: `failed to reconcile the resource objects in tenant namespace "tenantNS1": configured network policy does not denote a "NetworkPolicy.networking.k8s.io" but a "NetworkPolicy"`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			ctx := context.Background()
+			examinee := NewController(k8sfake.NewClientFactory(), ControllerOpts{})
+
+			// EXERCISE
+			resultErr := examinee.reconcileTenantNamespaceObjects(ctx, k8sfake.Tenant("tenant1", "client1"), "tenantNS1", tc.config)
+
+			// VERIFY
+			assert.Error(t, resultErr, tc.expectedError)
+		})
+	}
+}
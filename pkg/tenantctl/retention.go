@@ -0,0 +1,198 @@
+package tenantctl
+
+import (
+	"context"
+	"time"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	k8s "github.com/SAP/stewardci-core/pkg/k8s"
+	slabels "github.com/SAP/stewardci-core/pkg/stewardlabels"
+	errors "github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	klog "k8s.io/klog/v2"
+)
+
+// pendingDeletionCheckInterval is the interval in which tenant namespaces
+// pending deletion are checked for an expired retention period.
+const pendingDeletionCheckInterval = 5 * time.Minute
+
+// removeTenantNamespace removes the given tenant namespace. If a retention
+// period is configured for the client, the namespace is only marked as
+// pending deletion and gets deleted when the retention period is over.
+func (c *Controller) removeTenantNamespace(ctx context.Context, namespace string, tenant *stewardv1alpha1.Tenant, config clientConfig) error {
+	if config.GetTenantNamespaceRetentionPeriod() > 0 {
+		return c.softDeleteTenantNamespace(ctx, namespace, tenant, config)
+	}
+	return c.deleteTenantNamespace(ctx, namespace, tenant, config)
+}
+
+/*
+softDeleteTenantNamespace marks the given tenant namespace as pending
+deletion and revokes all permissions granted by the tenant controller in
+that namespace.
+
+The namespace gets labelled as owned by the tenant, so that it can be
+restored if a tenant with the same name gets created in the same client
+namespace within the retention period.
+*/
+func (c *Controller) softDeleteTenantNamespace(ctx context.Context, namespace string, tenant *stewardv1alpha1.Tenant, config clientConfig) error {
+	if namespace == "" {
+		return nil
+	}
+	klog.V(4).Infof(c.formatLogf(tenant, "marking tenant namespace %q as pending deletion", namespace))
+
+	err := c.markTenantNamespaceAsPendingDeletion(ctx, namespace, tenant, config.GetTenantNamespaceRetentionPeriod())
+	if err == nil {
+		var roleBindings *rbacv1.RoleBindingList
+		roleBindings, err = c.listManagedRoleBindings(ctx, namespace)
+		if err == nil {
+			err = c.deleteRoleBindingsFromList(ctx, roleBindings)
+		}
+	}
+	if err != nil {
+		err = errors.WithMessagef(err, "failed to mark tenant namespace %q as pending deletion", namespace)
+		klog.V(4).Infof(c.formatLog(tenant), err)
+		return err
+	}
+	return nil
+}
+
+func (c *Controller) markTenantNamespaceAsPendingDeletion(ctx context.Context, nsName string, tenant *stewardv1alpha1.Tenant, retention time.Duration) error {
+	namespaces := c.factory.CoreV1().Namespaces()
+	namespace, err := namespaces.Get(ctx, nsName, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.WithMessage(err, "failed to get namespace")
+	}
+	if isPendingDeletion(namespace) {
+		// keep the original point in time of deletion
+		return nil
+	}
+
+	owner := tenant.DeepCopy()
+	owner.Status.TenantNamespaceName = nsName
+	if err := slabels.LabelAsOwnedByTenant(namespace, owner); err != nil {
+		return err
+	}
+	namespace.Labels[stewardv1alpha1.LabelPendingDeletion] = ""
+	if namespace.Annotations == nil {
+		namespace.Annotations = map[string]string{}
+	}
+	namespace.Annotations[stewardv1alpha1.AnnotationDeletionDue] = time.Now().Add(retention).UTC().Format(time.RFC3339)
+
+	if _, err := namespaces.Update(ctx, namespace, metav1.UpdateOptions{}); err != nil {
+		return errors.WithMessage(err, "failed to update namespace")
+	}
+	return nil
+}
+
+/*
+restoreTenantNamespace looks for a namespace pending deletion which belonged
+to a former tenant with the same name in the same client namespace. If
+found, the namespace is no longer pending deletion and its name is
+returned, otherwise the empty string. Permissions in the restored
+namespace are not granted here but by the regular reconciliation.
+If there are multiple candidates, the one deleted last is restored.
+*/
+func (c *Controller) restoreTenantNamespace(ctx context.Context, tenant *stewardv1alpha1.Tenant) (string, error) {
+	selector := labels.SelectorFromSet(labels.Set{
+		stewardv1alpha1.LabelOwnerClientNamespace: tenant.GetNamespace(),
+		stewardv1alpha1.LabelOwnerTenantName:      tenant.GetName(),
+	})
+	pendingRequirement, err := labels.NewRequirement(stewardv1alpha1.LabelPendingDeletion, selection.Exists, nil)
+	if err != nil {
+		return "", errors.WithMessage(err, "failed to find tenant namespace pending deletion")
+	}
+	selector = selector.Add(*pendingRequirement)
+
+	namespaces := c.factory.CoreV1().Namespaces()
+	list, err := namespaces.List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return "", errors.WithMessage(err, "failed to find tenant namespace pending deletion")
+	}
+	var candidate *corev1.Namespace
+	for i := range list.Items {
+		namespace := &list.Items[i]
+		if !namespace.GetDeletionTimestamp().IsZero() {
+			continue
+		}
+		if candidate == nil || getDeletionDue(namespace).After(getDeletionDue(candidate)) {
+			candidate = namespace
+		}
+	}
+	if candidate == nil {
+		return "", nil
+	}
+
+	klog.V(3).Infof(c.formatLogf(tenant, "restoring tenant namespace %q pending deletion", candidate.GetName()))
+	clearPendingDeletion(candidate)
+	if _, err := namespaces.Update(ctx, candidate, metav1.UpdateOptions{}); err != nil {
+		return "", errors.WithMessagef(err, "failed to restore tenant namespace %q pending deletion", candidate.GetName())
+	}
+	return candidate.GetName(), nil
+}
+
+// deleteExpiredTenantNamespaces deletes all tenant namespaces pending
+// deletion whose retention period is over. Errors are logged only, as
+// the deletion is retried periodically.
+// The configuration of the client is not required, as the client
+// namespace may have been deleted or reconfigured in the meantime. Instead
+// the namespace prefix is taken from the label set by the namespace
+// manager.
+func (c *Controller) deleteExpiredTenantNamespaces() {
+	ctx := context.Background()
+	list, err := c.factory.CoreV1().Namespaces().List(ctx, metav1.ListOptions{
+		LabelSelector: stewardv1alpha1.LabelPendingDeletion,
+	})
+	if err != nil {
+		klog.Errorf("failed to list tenant namespaces pending deletion: %s", err)
+		return
+	}
+	now := time.Now()
+	for i := range list.Items {
+		namespace := &list.Items[i]
+		if !namespace.GetDeletionTimestamp().IsZero() || getDeletionDue(namespace).After(now) {
+			continue
+		}
+		prefix := k8s.GetManagedNamespacePrefix(namespace)
+		if prefix == "" {
+			klog.Errorf("failed to delete tenant namespace %q pending deletion: not a Steward namespace (prefix label missing)", namespace.GetName())
+			continue
+		}
+		if err := k8s.NewNamespaceManager(c.factory, prefix, 0).Delete(ctx, namespace.GetName()); err != nil {
+			klog.Errorf("failed to delete tenant namespace %q pending deletion: %s", namespace.GetName(), err)
+			continue
+		}
+		klog.V(3).Infof("deleted tenant namespace %q as its retention period is over", namespace.GetName())
+	}
+}
+
+func isPendingDeletion(namespace *corev1.Namespace) bool {
+	_, found := namespace.GetLabels()[stewardv1alpha1.LabelPendingDeletion]
+	return found
+}
+
+// clearPendingDeletion removes the pending deletion marks from the
+// given namespace object.
+func clearPendingDeletion(namespace *corev1.Namespace) {
+	delete(namespace.Labels, stewardv1alpha1.LabelPendingDeletion)
+	delete(namespace.Annotations, stewardv1alpha1.AnnotationDeletionDue)
+}
+
+// getDeletionDue returns the point in time after which the given namespace
+// pending deletion gets deleted. An invalid or missing value is treated as
+// overdue.
+func getDeletionDue(namespace *corev1.Namespace) time.Time {
+	due, err := time.Parse(time.RFC3339, namespace.GetAnnotations()[stewardv1alpha1.AnnotationDeletionDue])
+	if err != nil {
+		return time.Time{}
+	}
+	return due
+}
@@ -0,0 +1,185 @@
+package tenantctl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	k8sfake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	assert "gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_Controller_softDeleteTenantNamespace(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	managedRoleBinding := &rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{
+		Name:      "managed1",
+		Namespace: "prefix1-ns1",
+		UID:       "uid1",
+		Labels:    map[string]string{stewardv1alpha1.LabelSystemManaged: ""},
+	}}
+	unmanagedRoleBinding := &rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{
+		Name:      "unmanaged1",
+		Namespace: "prefix1-ns1",
+		UID:       "uid2",
+	}}
+	cf := k8sfake.NewClientFactory(
+		k8sfake.Namespace("prefix1-ns1"),
+		managedRoleBinding,
+		unmanagedRoleBinding,
+	)
+	examinee := NewController(cf, ControllerOpts{})
+	tenant := k8sfake.Tenant("tenant1", "client1")
+	tenant.Status.TenantNamespaceName = "prefix1-ns1"
+	config := &clientConfigImpl{tenantNamespaceRetention: 24 * time.Hour}
+
+	// EXERCISE
+	resultErr := examinee.softDeleteTenantNamespace(ctx, "prefix1-ns1", tenant, config)
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+
+	namespace, err := cf.CoreV1().Namespaces().Get(ctx, "prefix1-ns1", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, map[string]string{
+		stewardv1alpha1.LabelPendingDeletion:      "",
+		stewardv1alpha1.LabelOwnerClientName:      "client1",
+		stewardv1alpha1.LabelOwnerClientNamespace: "client1",
+		stewardv1alpha1.LabelOwnerTenantName:      "tenant1",
+		stewardv1alpha1.LabelOwnerTenantNamespace: "prefix1-ns1",
+	}, namespace.GetLabels())
+	due := getDeletionDue(namespace)
+	assert.Assert(t, due.After(time.Now().Add(23*time.Hour)))
+	assert.Assert(t, due.Before(time.Now().Add(25*time.Hour)))
+
+	roleBindings, err := cf.RbacV1().RoleBindings("prefix1-ns1").List(ctx, metav1.ListOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(roleBindings.Items))
+	assert.Equal(t, "unmanaged1", roleBindings.Items[0].GetName())
+
+	// repeated soft deletion keeps the point in time of deletion
+	resultErr = examinee.softDeleteTenantNamespace(ctx, "prefix1-ns1", tenant, &clientConfigImpl{tenantNamespaceRetention: time.Hour})
+	assert.NilError(t, resultErr)
+	namespace, err = cf.CoreV1().Namespaces().Get(ctx, "prefix1-ns1", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, due, getDeletionDue(namespace))
+}
+
+func Test_Controller_restoreTenantNamespace(t *testing.T) {
+	t.Parallel()
+
+	newNamespace := func(name, clientNamespace, tenantName string, due *time.Time) *corev1.Namespace {
+		namespace := k8sfake.Namespace(name)
+		namespace.SetLabels(map[string]string{
+			stewardv1alpha1.LabelOwnerClientNamespace: clientNamespace,
+			stewardv1alpha1.LabelOwnerTenantName:      tenantName,
+		})
+		if due != nil {
+			namespace.Labels[stewardv1alpha1.LabelPendingDeletion] = ""
+			namespace.SetAnnotations(map[string]string{
+				stewardv1alpha1.AnnotationDeletionDue: due.Format(time.RFC3339),
+			})
+		}
+		return namespace
+	}
+	earlier := time.Now().Add(time.Hour)
+	later := time.Now().Add(2 * time.Hour)
+
+	for _, tc := range []struct {
+		name       string
+		namespaces []*corev1.Namespace
+		expected   string
+	}{
+		{"none", []*corev1.Namespace{
+			newNamespace("prefix1-active", "client1", "tenant1", nil),
+			newNamespace("prefix1-other-tenant", "client1", "tenant2", &later),
+			newNamespace("prefix1-other-client", "client2", "tenant1", &later),
+		}, ""},
+		{"single", []*corev1.Namespace{
+			newNamespace("prefix1-pending", "client1", "tenant1", &earlier),
+			newNamespace("prefix1-other-tenant", "client1", "tenant2", &later),
+		}, "prefix1-pending"},
+		{"latest", []*corev1.Namespace{
+			newNamespace("prefix1-earlier", "client1", "tenant1", &earlier),
+			newNamespace("prefix1-later", "client1", "tenant1", &later),
+		}, "prefix1-later"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			ctx := context.Background()
+			cf := k8sfake.NewClientFactory()
+			for _, namespace := range tc.namespaces {
+				_, err := cf.CoreV1().Namespaces().Create(ctx, namespace, metav1.CreateOptions{})
+				assert.NilError(t, err)
+			}
+			examinee := NewController(cf, ControllerOpts{})
+
+			// EXERCISE
+			result, resultErr := examinee.restoreTenantNamespace(ctx, k8sfake.Tenant("tenant1", "client1"))
+
+			// VERIFY
+			assert.NilError(t, resultErr)
+			assert.Equal(t, tc.expected, result)
+			if result != "" {
+				namespace, err := cf.CoreV1().Namespaces().Get(ctx, result, metav1.GetOptions{})
+				assert.NilError(t, err)
+				assert.Assert(t, !isPendingDeletion(namespace))
+				_, found := namespace.GetAnnotations()[stewardv1alpha1.AnnotationDeletionDue]
+				assert.Assert(t, !found)
+			}
+		})
+	}
+}
+
+func Test_Controller_deleteExpiredTenantNamespaces(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	newNamespace := func(name, clientNamespace string, due time.Time) *corev1.Namespace {
+		namespace := k8sfake.Namespace(name)
+		namespace.SetLabels(map[string]string{
+			"prefix": "prefix1",
+			stewardv1alpha1.LabelOwnerClientNamespace: clientNamespace,
+			stewardv1alpha1.LabelPendingDeletion:      "",
+		})
+		namespace.SetAnnotations(map[string]string{
+			stewardv1alpha1.AnnotationDeletionDue: due.Format(time.RFC3339),
+		})
+		return namespace
+	}
+	unmanagedNamespace := newNamespace("prefix1-unmanaged", "client1", time.Now().Add(-time.Minute))
+	delete(unmanagedNamespace.Labels, "prefix")
+	cf := k8sfake.NewClientFactory(
+		k8sfake.NamespaceWithAnnotations("client1", map[string]string{
+			stewardv1alpha1.AnnotationTenantNamespacePrefix: "prefix1",
+			stewardv1alpha1.AnnotationTenantRole:            "role1",
+		}),
+		newNamespace("prefix1-expired", "client1", time.Now().Add(-time.Minute)),
+		newNamespace("prefix1-retained", "client1", time.Now().Add(time.Hour)),
+		newNamespace("prefix1-unknown-client", "unknown1", time.Now().Add(-time.Minute)),
+		unmanagedNamespace,
+		k8sfake.Namespace("prefix1-active"),
+	)
+	examinee := NewController(cf, ControllerOpts{})
+
+	// EXERCISE
+	examinee.deleteExpiredTenantNamespaces()
+
+	// VERIFY
+	assertThatExactlyTheseNamespacesExist(t, cf,
+		"client1",
+		"prefix1-retained",
+		// "prefix1-unknown-client" deleted although the client namespace does not exist
+		"prefix1-unmanaged",
+		"prefix1-active",
+	)
+}
@@ -0,0 +1,41 @@
+package tenantctl
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	k8s "github.com/SAP/stewardci-core/pkg/k8s"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// ValidateTenant checks whether a tenant namespace can be created for the
+// given tenant. It is performed by the validating admission webhook when a
+// tenant gets created, so that invalid tenants are rejected instead of
+// failing in the tenant controller.
+func ValidateTenant(ctx context.Context, factory k8s.ClientFactory, tenant *stewardv1alpha1.Tenant) error {
+	config, err := getClientConfig(ctx, factory, tenant.GetNamespace())
+	if err != nil {
+		return err
+	}
+	return validateTenantNamespaceName(config, tenant.GetName())
+}
+
+// validateTenantNamespaceName checks whether the names of tenant namespaces
+// generated for the tenant with the given name are valid namespace names.
+func validateTenantNamespaceName(config clientConfig, tenantName string) error {
+	// a namespace manager joins prefix, tenant name and random suffix
+	parts := []string{config.GetTenantNamespacePrefix(), tenantName}
+	if suffixLength := config.GetTenantNamespaceSuffixLength(); suffixLength > 0 {
+		parts = append(parts, strings.Repeat("x", int(suffixLength)))
+	}
+	name := strings.Join(parts, "-")
+	if errs := validation.IsDNS1123Label(name); len(errs) > 0 {
+		return fmt.Errorf(
+			"tenant name %q results in invalid tenant namespace names like %q: %s",
+			tenantName, name, strings.Join(errs, "; "),
+		)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package tenantctl
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	assert "gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_ValidateTenant(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		tenantName    string
+		expectedError string
+	}{
+		{"valid", "tenant1", ""},
+		{"too_long", strings.Repeat("a", 60), "results in invalid tenant namespace names"},
+		{"dots", "tenant.1", "results in invalid tenant namespace names"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			ctx := context.Background()
+			cf := fake.NewClientFactory(
+				&corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "client1",
+						Annotations: map[string]string{
+							"steward.sap.com/tenant-namespace-prefix": "prefix1",
+							"steward.sap.com/tenant-role":             "role1",
+						},
+					},
+				},
+			)
+			tenant := &stewardv1alpha1.Tenant{
+				ObjectMeta: metav1.ObjectMeta{Name: tc.tenantName, Namespace: "client1"},
+			}
+
+			// EXERCISE
+			resultErr := ValidateTenant(ctx, cf, tenant)
+
+			// VERIFY
+			if tc.expectedError == "" {
+				assert.NilError(t, resultErr)
+			} else {
+				assert.Assert(t, is.ErrorContains(resultErr, tc.expectedError))
+			}
+		})
+	}
+}
+
+func Test_ValidateTenant_ClientNamespaceMissing(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	cf := fake.NewClientFactory()
+	tenant := &stewardv1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "tenant1", Namespace: "client1"},
+	}
+
+	// EXERCISE
+	resultErr := ValidateTenant(ctx, cf, tenant)
+
+	// VERIFY
+	assert.Assert(t, is.ErrorContains(resultErr, "could not get namespace 'client1'"))
+}
@@ -0,0 +1,138 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/apis/steward/v1beta1"
+	"github.com/pkg/errors"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	klog "k8s.io/klog/v2"
+)
+
+// ConversionPath is the URL path the conversion webhook is served at.
+const ConversionPath = "/convert"
+
+// ConversionHandler returns the HTTP handler serving conversion reviews of
+// the conversion webhook, which converts Steward resource objects between
+// the API versions.
+func (w *Webhook) ConversionHandler() http.Handler {
+	return http.HandlerFunc(w.serveConversion)
+}
+
+func (w *Webhook) serveConversion(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		http.Error(rw, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+	review := &apiextensionsv1.ConversionReview{}
+	if err := json.Unmarshal(body, review); err != nil || review.Request == nil {
+		http.Error(rw, "request body is not a valid conversion review", http.StatusBadRequest)
+		return
+	}
+
+	response := &apiextensionsv1.ConversionResponse{
+		UID:    review.Request.UID,
+		Result: metav1.Status{Status: metav1.StatusSuccess},
+	}
+	for _, obj := range review.Request.Objects {
+		converted, err := convert(obj.Raw, review.Request.DesiredAPIVersion)
+		if err != nil {
+			response.ConvertedObjects = nil
+			response.Result = metav1.Status{
+				Status:  metav1.StatusFailure,
+				Message: err.Error(),
+			}
+			break
+		}
+		response.ConvertedObjects = append(response.ConvertedObjects, runtime.RawExtension{Raw: converted})
+	}
+	review.Request = nil
+	review.Response = response
+
+	responseBody, err := json.Marshal(review)
+	if err != nil {
+		http.Error(rw, "failed to serialize conversion review", http.StatusInternalServerError)
+		return
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	if _, err := rw.Write(responseBody); err != nil {
+		klog.Errorf("failed to write conversion review response: %s", err.Error())
+	}
+}
+
+// convert converts the given serialized Steward resource object to the
+// desired API version.
+func convert(raw []byte, desiredAPIVersion string) ([]byte, error) {
+	typeMeta := &metav1.TypeMeta{}
+	if err := json.Unmarshal(raw, typeMeta); err != nil {
+		return nil, errors.Wrap(err, "failed to decode object")
+	}
+	if typeMeta.APIVersion == desiredAPIVersion {
+		return raw, nil
+	}
+
+	alphaVersion := v1alpha1.SchemeGroupVersion.String()
+	betaVersion := v1beta1.SchemeGroupVersion.String()
+	var result interface{}
+
+	switch {
+	case typeMeta.Kind == "PipelineRun" && typeMeta.APIVersion == alphaVersion && desiredAPIVersion == betaVersion:
+		src := &v1alpha1.PipelineRun{}
+		if err := json.Unmarshal(raw, src); err != nil {
+			return nil, errors.Wrap(err, "failed to decode pipeline run")
+		}
+		dst := &v1beta1.PipelineRun{}
+		if err := dst.ConvertFrom(src); err != nil {
+			return nil, err
+		}
+		result = dst
+	case typeMeta.Kind == "PipelineRun" && typeMeta.APIVersion == betaVersion && desiredAPIVersion == alphaVersion:
+		src := &v1beta1.PipelineRun{}
+		if err := json.Unmarshal(raw, src); err != nil {
+			return nil, errors.Wrap(err, "failed to decode pipeline run")
+		}
+		dst := &v1alpha1.PipelineRun{}
+		if err := src.ConvertTo(dst); err != nil {
+			return nil, err
+		}
+		result = dst
+	case typeMeta.Kind == "Tenant" && typeMeta.APIVersion == alphaVersion && desiredAPIVersion == betaVersion:
+		src := &v1alpha1.Tenant{}
+		if err := json.Unmarshal(raw, src); err != nil {
+			return nil, errors.Wrap(err, "failed to decode tenant")
+		}
+		dst := &v1beta1.Tenant{}
+		if err := dst.ConvertFrom(src); err != nil {
+			return nil, err
+		}
+		result = dst
+	case typeMeta.Kind == "Tenant" && typeMeta.APIVersion == betaVersion && desiredAPIVersion == alphaVersion:
+		src := &v1beta1.Tenant{}
+		if err := json.Unmarshal(raw, src); err != nil {
+			return nil, errors.Wrap(err, "failed to decode tenant")
+		}
+		dst := &v1alpha1.Tenant{}
+		if err := src.ConvertTo(dst); err != nil {
+			return nil, err
+		}
+		result = dst
+	default:
+		return nil, fmt.Errorf(
+			"conversion of %s %s to %s is not supported",
+			typeMeta.Kind, typeMeta.APIVersion, desiredAPIVersion,
+		)
+	}
+
+	return json.Marshal(result)
+}
@@ -0,0 +1,122 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/apis/steward/v1beta1"
+	assert "gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func newConversionReview(t *testing.T, desiredAPIVersion string, objects ...interface{}) []byte {
+	t.Helper()
+	review := &apiextensionsv1.ConversionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: "apiextensions.k8s.io/v1", Kind: "ConversionReview"},
+		Request: &apiextensionsv1.ConversionRequest{
+			UID:               types.UID("uid1"),
+			DesiredAPIVersion: desiredAPIVersion,
+		},
+	}
+	for _, obj := range objects {
+		raw, err := json.Marshal(obj)
+		assert.NilError(t, err)
+		review.Request.Objects = append(review.Request.Objects, runtime.RawExtension{Raw: raw})
+	}
+	body, err := json.Marshal(review)
+	assert.NilError(t, err)
+	return body
+}
+
+func serveConversionReview(t *testing.T, body []byte) *apiextensionsv1.ConversionResponse {
+	t.Helper()
+	examinee := newTestWebhook()
+	req := httptest.NewRequest(http.MethodPost, ConversionPath, bytes.NewReader(body))
+	recorder := httptest.NewRecorder()
+	examinee.ConversionHandler().ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	result := &apiextensionsv1.ConversionReview{}
+	assert.NilError(t, json.Unmarshal(recorder.Body.Bytes(), result))
+	assert.Assert(t, result.Request == nil)
+	assert.Equal(t, types.UID("uid1"), result.Response.UID)
+	return result.Response
+}
+
+func Test_Webhook_ConversionHandler_PipelineRun(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	run := newValidPipelineRun(v1alpha1.StateRunning)
+	run.TypeMeta = metav1.TypeMeta{APIVersion: "steward.sap.com/v1alpha1", Kind: "PipelineRun"}
+	run.Status.History = []string{"message1"}
+	body := newConversionReview(t, "steward.sap.com/v1beta1", run)
+
+	// EXERCISE
+	response := serveConversionReview(t, body)
+
+	// VERIFY
+	assert.Equal(t, metav1.StatusSuccess, response.Result.Status)
+	assert.Equal(t, 1, len(response.ConvertedObjects))
+	result := &v1beta1.PipelineRun{}
+	assert.NilError(t, json.Unmarshal(response.ConvertedObjects[0].Raw, result))
+	assert.Equal(t, "steward.sap.com/v1beta1", result.APIVersion)
+	assert.Equal(t, "PipelineRun", result.Kind)
+	assert.Equal(t, "run1", result.Name)
+	assert.Equal(t, "https://github.com/foo/bar", result.Spec.JenkinsFile.URL)
+	assert.Equal(t, v1beta1.StateRunning, result.Status.State)
+	assert.DeepEqual(t, []v1beta1.HistoricMessage{{Message: "message1"}}, result.Status.MessageHistory)
+}
+
+func Test_Webhook_ConversionHandler_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	run := newValidPipelineRun(v1alpha1.StateRunning)
+	run.TypeMeta = metav1.TypeMeta{APIVersion: "steward.sap.com/v1alpha1", Kind: "PipelineRun"}
+	tenant := &v1alpha1.Tenant{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "steward.sap.com/v1alpha1", Kind: "Tenant"},
+		ObjectMeta: metav1.ObjectMeta{Name: "tenant1", Namespace: "ns1"},
+		Status:     v1alpha1.TenantStatus{TenantNamespaceName: "tenant-ns1"},
+	}
+
+	// EXERCISE
+	betaResponse := serveConversionReview(t, newConversionReview(t, "steward.sap.com/v1beta1", run, tenant))
+	alphaResponse := serveConversionReview(t, newConversionReview(t, "steward.sap.com/v1alpha1",
+		betaResponse.ConvertedObjects[0], betaResponse.ConvertedObjects[1],
+	))
+
+	// VERIFY
+	assert.Equal(t, metav1.StatusSuccess, alphaResponse.Result.Status)
+	resultRun := &v1alpha1.PipelineRun{}
+	assert.NilError(t, json.Unmarshal(alphaResponse.ConvertedObjects[0].Raw, resultRun))
+	assert.DeepEqual(t, run, resultRun)
+	resultTenant := &v1alpha1.Tenant{}
+	assert.NilError(t, json.Unmarshal(alphaResponse.ConvertedObjects[1].Raw, resultTenant))
+	assert.DeepEqual(t, tenant, resultTenant)
+}
+
+func Test_Webhook_ConversionHandler_Unsupported(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	obj := &metav1.PartialObjectMetadata{
+		TypeMeta: metav1.TypeMeta{APIVersion: "steward.sap.com/v1alpha1", Kind: "Foo"},
+	}
+	body := newConversionReview(t, "steward.sap.com/v1beta1", obj)
+
+	// EXERCISE
+	response := serveConversionReview(t, body)
+
+	// VERIFY
+	assert.Equal(t, metav1.StatusFailure, response.Result.Status)
+	assert.Assert(t, is.Contains(response.Result.Message, "conversion of Foo steward.sap.com/v1alpha1 to steward.sap.com/v1beta1 is not supported"))
+	assert.Equal(t, 0, len(response.ConvertedObjects))
+}
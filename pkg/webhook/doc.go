@@ -0,0 +1,5 @@
+/*
+Package webhook provides the validating and the mutating admission webhook
+for Steward resources.
+*/
+package webhook
@@ -0,0 +1,127 @@
+package webhook
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// jsonPatchOperation is an operation of a JSON patch (RFC 6902).
+type jsonPatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// createPatch returns a JSON patch transforming `oldObj` into `newObj`,
+// both located at the JSON pointer built from the reference tokens `path`
+// in the serialized admission object `rawObj`. Only members added or
+// changed in `newObj` are patched, as defaulting never removes anything.
+// Serializing `oldObj` may produce members not present in `rawObj`, e.g.
+// empty structs. Patch operations never refer to such members: if the
+// parent of a changed member does not exist in `rawObj`, the parent is
+// added as a whole. The parent of `path` must exist in `rawObj`.
+// The result is `nil` if there is nothing to patch.
+func createPatch(rawObj []byte, path []string, oldObj, newObj interface{}) ([]byte, error) {
+	var rawValue interface{}
+	if err := json.Unmarshal(rawObj, &rawValue); err != nil {
+		return nil, err
+	}
+	pointer := ""
+	for _, token := range path {
+		rawMap, _ := rawValue.(map[string]interface{})
+		rawValue = rawMap[token]
+		pointer += "/" + escapeJSONPointerToken(token)
+	}
+	oldValue, err := toJSONValue(oldObj)
+	if err != nil {
+		return nil, err
+	}
+	newValue, err := toJSONValue(newObj)
+	if err != nil {
+		return nil, err
+	}
+	operations := diff(pointer, rawValue, oldValue, newValue, nil)
+	if len(operations) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(operations)
+}
+
+// createReplacePatch returns a JSON patch setting the member at the JSON
+// pointer built from the reference tokens `path` to `value`, replacing the
+// member if it exists. The parent of `path` must exist.
+func createReplacePatch(path []string, value interface{}) ([]byte, error) {
+	pointer := ""
+	for _, token := range path {
+		pointer += "/" + escapeJSONPointerToken(token)
+	}
+	jsonValue, err := toJSONValue(value)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal([]jsonPatchOperation{{Op: "add", Path: pointer, Value: jsonValue}})
+}
+
+// joinPatches returns a JSON patch with the operations of all given JSON
+// patches in order. `nil` patches are skipped. The result is `nil` if there
+// is nothing to patch.
+func joinPatches(patches ...[]byte) ([]byte, error) {
+	var operations []jsonPatchOperation
+	for _, patch := range patches {
+		if patch == nil {
+			continue
+		}
+		var patchOperations []jsonPatchOperation
+		if err := json.Unmarshal(patch, &patchOperations); err != nil {
+			return nil, err
+		}
+		operations = append(operations, patchOperations...)
+	}
+	if len(operations) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(operations)
+}
+
+func toJSONValue(obj interface{}) (interface{}, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	err = json.Unmarshal(data, &value)
+	return value, err
+}
+
+// diff appends the operations adding all members of `newValue` which
+// differ from `oldValue` to `operations`. `rawValue` is the value at
+// `path` in the admission object, or `nil` if not existing.
+func diff(path string, rawValue, oldValue, newValue interface{}, operations []jsonPatchOperation) []jsonPatchOperation {
+	if reflect.DeepEqual(oldValue, newValue) {
+		return operations
+	}
+	rawMap, rawIsMap := rawValue.(map[string]interface{})
+	oldMap, oldIsMap := oldValue.(map[string]interface{})
+	newMap, newIsMap := newValue.(map[string]interface{})
+	if !rawIsMap || !oldIsMap || !newIsMap {
+		return append(operations, jsonPatchOperation{Op: "add", Path: path, Value: newValue})
+	}
+
+	keys := make([]string, 0, len(newMap))
+	for key := range newMap {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		operations = diff(path+"/"+escapeJSONPointerToken(key), rawMap[key], oldMap[key], newMap[key], operations)
+	}
+	return operations
+}
+
+// escapeJSONPointerToken escapes a reference token of a JSON pointer
+// (RFC 6901).
+func escapeJSONPointerToken(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
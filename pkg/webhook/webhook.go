@@ -0,0 +1,317 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/SAP/stewardci-core/pkg/runctl"
+	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	"github.com/SAP/stewardci-core/pkg/tenantctl"
+	"github.com/pkg/errors"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klog "k8s.io/klog/v2"
+)
+
+// Webhook is an HTTP handler serving admission reviews of the validating
+// and the mutating admission webhook for Steward resources.
+type Webhook struct {
+	factory k8s.ClientFactory
+	testing *webhookTesting
+}
+
+type webhookTesting struct {
+	loadPipelineRunsConfigStub func(ctx context.Context) (*cfg.PipelineRunsConfigStruct, error)
+	validateTenantStub         func(ctx context.Context, tenant *api.Tenant) error
+}
+
+// NewWebhook creates a new Webhook.
+func NewWebhook(factory k8s.ClientFactory) *Webhook {
+	return &Webhook{
+		factory: factory,
+	}
+}
+
+// ServeHTTP handles an admission review request of the validating
+// admission webhook.
+func (w *Webhook) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	w.serve(rw, req, w.admit)
+}
+
+// MutatingHandler returns the HTTP handler serving admission reviews of the
+// mutating admission webhook, which sets defaults in the spec of new
+// pipeline runs.
+func (w *Webhook) MutatingHandler() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		w.serve(rw, req, w.mutate)
+	})
+}
+
+type admitFunc func(ctx context.Context, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse
+
+// serve decodes the admission review from the given HTTP request, lets
+// `admit` decide about it and writes the response.
+func (w *Webhook) serve(rw http.ResponseWriter, req *http.Request, admit admitFunc) {
+	if req.Method != http.MethodPost {
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		http.Error(rw, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+	review := &admissionv1.AdmissionReview{}
+	if err := json.Unmarshal(body, review); err != nil || review.Request == nil {
+		http.Error(rw, "request body is not a valid admission review", http.StatusBadRequest)
+		return
+	}
+
+	response := admit(req.Context(), review.Request)
+	response.UID = review.Request.UID
+	review.Request = nil
+	review.Response = response
+
+	responseBody, err := json.Marshal(review)
+	if err != nil {
+		http.Error(rw, "failed to serialize admission review", http.StatusInternalServerError)
+		return
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	if _, err := rw.Write(responseBody); err != nil {
+		klog.Errorf("failed to write admission review response: %s", err.Error())
+	}
+}
+
+// admit decides about the given admission request.
+func (w *Webhook) admit(ctx context.Context, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	if req.Kind.Group != api.SchemeGroupVersion.Group {
+		return allowed()
+	}
+	switch req.Kind.Kind {
+	case "PipelineRun":
+		return w.admitPipelineRun(ctx, req)
+	case "Tenant":
+		return w.admitTenant(ctx, req)
+	default:
+		return allowed()
+	}
+}
+
+func (w *Webhook) admitPipelineRun(ctx context.Context, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	pipelineRun := &api.PipelineRun{}
+	if err := json.Unmarshal(req.Object.Raw, pipelineRun); err != nil {
+		return denied(errors.Wrap(err, "failed to decode pipeline run"))
+	}
+
+	switch req.Operation {
+	case admissionv1.Create:
+	case admissionv1.Update:
+		oldPipelineRun := &api.PipelineRun{}
+		if err := json.Unmarshal(req.OldObject.Raw, oldPipelineRun); err != nil {
+			return denied(errors.Wrap(err, "failed to decode pipeline run"))
+		}
+		if err := runctl.ValidatePipelineRunUpdate(oldPipelineRun, pipelineRun); err != nil {
+			return denied(err)
+		}
+		// updates not touching the spec (e.g. of finalizers) are always
+		// allowed, even if the spec is not valid any longer due to
+		// configuration changes
+		if equality.Semantic.DeepEqual(oldPipelineRun.Spec, pipelineRun.Spec) {
+			return allowed()
+		}
+	default:
+		return allowed()
+	}
+
+	response := allowed()
+	pipelineRunsConfig, err := w.loadPipelineRunsConfig(ctx, req.Namespace)
+	if err != nil {
+		// the run controller performs all checks before the pipeline
+		// run gets started
+		klog.Warningf("failed to load the pipeline runs configuration: %s", err.Error())
+		response.Warnings = []string{
+			"some checks have been skipped because the pipeline runs configuration could not be loaded",
+		}
+		pipelineRunsConfig = nil
+	}
+	if err := runctl.ValidatePipelineRun(pipelineRun, pipelineRunsConfig); err != nil {
+		return denied(err)
+	}
+	return response
+}
+
+// mutate sets defaults in the spec of pipeline runs to be created and
+// records the user who requests the abortion of a pipeline run, replacing
+// any value set by the client.
+func (w *Webhook) mutate(ctx context.Context, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	if req.Kind.Group != api.SchemeGroupVersion.Group || req.Kind.Kind != "PipelineRun" {
+		return allowed()
+	}
+	switch req.Operation {
+	case admissionv1.Create:
+		return w.mutateNewPipelineRun(ctx, req)
+	case admissionv1.Update:
+		return w.mutateUpdatedPipelineRun(req)
+	default:
+		return allowed()
+	}
+}
+
+func (w *Webhook) mutateNewPipelineRun(ctx context.Context, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	pipelineRun := &api.PipelineRun{}
+	if err := json.Unmarshal(req.Object.Raw, pipelineRun); err != nil {
+		return denied(errors.Wrap(err, "failed to decode pipeline run"))
+	}
+
+	response := allowed()
+	pipelineRunsConfig, err := w.loadPipelineRunsConfig(ctx, req.Namespace)
+	if err != nil {
+		// the run controller falls back to the configuration for
+		// all fields not set in the spec
+		klog.Warningf("failed to load the pipeline runs configuration: %s", err.Error())
+		response.Warnings = []string{
+			"some defaults have not been set because the pipeline runs configuration could not be loaded",
+		}
+		pipelineRunsConfig = nil
+	}
+
+	defaulted := pipelineRun.DeepCopy()
+	runctl.DefaultPipelineRun(defaulted, pipelineRunsConfig)
+	specPatch, err := createPatch(req.Object.Raw, []string{"spec"}, &pipelineRun.Spec, &defaulted.Spec)
+	if err != nil {
+		return denied(errors.Wrap(err, "failed to create patch"))
+	}
+	annotationsPatch, err := createAbortRequestedByPatch(req, pipelineRun, nil)
+	if err != nil {
+		return denied(errors.Wrap(err, "failed to create patch"))
+	}
+	patch, err := joinPatches(specPatch, annotationsPatch)
+	if err != nil {
+		return denied(errors.Wrap(err, "failed to create patch"))
+	}
+	return withPatch(response, patch)
+}
+
+// mutateUpdatedPipelineRun annotates a pipeline run whose intent gets
+// changed to `abort` with the name of the requesting user, which the run
+// controller reports in the status message.
+func (w *Webhook) mutateUpdatedPipelineRun(req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	pipelineRun := &api.PipelineRun{}
+	if err := json.Unmarshal(req.Object.Raw, pipelineRun); err != nil {
+		return denied(errors.Wrap(err, "failed to decode pipeline run"))
+	}
+	oldPipelineRun := &api.PipelineRun{}
+	if err := json.Unmarshal(req.OldObject.Raw, oldPipelineRun); err != nil {
+		return denied(errors.Wrap(err, "failed to decode pipeline run"))
+	}
+	patch, err := createAbortRequestedByPatch(req, pipelineRun, oldPipelineRun)
+	if err != nil {
+		return denied(errors.Wrap(err, "failed to create patch"))
+	}
+	return withPatch(allowed(), patch)
+}
+
+// createAbortRequestedByPatch returns a JSON patch for annotation
+// `AnnotationAbortRequestedBy` of the given pipeline run, or `nil` if it
+// already has the correct value. Values set by clients are never accepted:
+// The annotation is set to the requesting user if the intent gets changed
+// to `abort`. Otherwise the value of `oldPipelineRun` is kept, which is
+// `nil` for pipeline runs to be created.
+func createAbortRequestedByPatch(req *admissionv1.AdmissionRequest, pipelineRun, oldPipelineRun *api.PipelineRun) ([]byte, error) {
+	var oldIntent api.Intent
+	var requester string
+	if oldPipelineRun != nil {
+		oldIntent = oldPipelineRun.Spec.Intent
+		requester = oldPipelineRun.GetAnnotations()[api.AnnotationAbortRequestedBy]
+	}
+	if pipelineRun.Spec.Intent == api.IntentAbort && oldIntent != api.IntentAbort {
+		requester = req.UserInfo.Username
+	}
+
+	value, exists := pipelineRun.GetAnnotations()[api.AnnotationAbortRequestedBy]
+	if exists == (requester != "") && value == requester {
+		return nil, nil
+	}
+	annotations := map[string]string{}
+	for key, value := range pipelineRun.GetAnnotations() {
+		annotations[key] = value
+	}
+	if requester != "" {
+		annotations[api.AnnotationAbortRequestedBy] = requester
+	} else {
+		delete(annotations, api.AnnotationAbortRequestedBy)
+	}
+	return createReplacePatch([]string{"metadata", "annotations"}, annotations)
+}
+
+func (w *Webhook) admitTenant(ctx context.Context, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	if req.Operation != admissionv1.Create {
+		return allowed()
+	}
+	tenant := &api.Tenant{}
+	if err := json.Unmarshal(req.Object.Raw, tenant); err != nil {
+		return denied(errors.Wrap(err, "failed to decode tenant"))
+	}
+	if tenant.GetNamespace() == "" {
+		tenant.SetNamespace(req.Namespace)
+	}
+	if err := w.validateTenant(ctx, tenant); err != nil {
+		return denied(err)
+	}
+	return allowed()
+}
+
+// loadPipelineRunsConfig loads the pipeline runs configuration with the
+// settings of the tenant owning the given namespace merged over it.
+func (w *Webhook) loadPipelineRunsConfig(ctx context.Context, tenantNamespace string) (*cfg.PipelineRunsConfigStruct, error) {
+	var pipelineRunsConfig *cfg.PipelineRunsConfigStruct
+	var err error
+	if w.testing != nil && w.testing.loadPipelineRunsConfigStub != nil {
+		pipelineRunsConfig, err = w.testing.loadPipelineRunsConfigStub(ctx)
+	} else {
+		pipelineRunsConfig, err = cfg.LoadPipelineRunsConfig(ctx, w.factory)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return runctl.TenantPipelineRunsConfig(ctx, w.factory, tenantNamespace, pipelineRunsConfig)
+}
+
+func (w *Webhook) validateTenant(ctx context.Context, tenant *api.Tenant) error {
+	if w.testing != nil && w.testing.validateTenantStub != nil {
+		return w.testing.validateTenantStub(ctx, tenant)
+	}
+	return tenantctl.ValidateTenant(ctx, w.factory, tenant)
+}
+
+func allowed() *admissionv1.AdmissionResponse {
+	return &admissionv1.AdmissionResponse{Allowed: true}
+}
+
+func withPatch(response *admissionv1.AdmissionResponse, patch []byte) *admissionv1.AdmissionResponse {
+	if len(patch) > 0 {
+		patchType := admissionv1.PatchTypeJSONPatch
+		response.PatchType = &patchType
+		response.Patch = patch
+	}
+	return response
+}
+
+func denied(err error) *admissionv1.AdmissionResponse {
+	return &admissionv1.AdmissionResponse{
+		Allowed: false,
+		Result: &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Reason:  metav1.StatusReasonInvalid,
+			Code:    http.StatusUnprocessableEntity,
+			Message: fmt.Sprintf("validation failed: %s", err.Error()),
+		},
+	}
+}
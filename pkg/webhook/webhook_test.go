@@ -0,0 +1,561 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	cfg "github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	jsonpatch "github.com/evanphx/json-patch"
+	assert "gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func newTestWebhook(networkProfiles ...string) *Webhook {
+	networkPolicies := map[string]string{}
+	for _, profile := range networkProfiles {
+		networkPolicies[profile] = "policy"
+	}
+	webhook := NewWebhook(fake.NewClientFactory())
+	webhook.testing = &webhookTesting{
+		loadPipelineRunsConfigStub: func(ctx context.Context) (*cfg.PipelineRunsConfigStruct, error) {
+			return &cfg.PipelineRunsConfigStruct{NetworkPolicies: networkPolicies}, nil
+		},
+	}
+	return webhook
+}
+
+func newAdmissionRequest(t *testing.T, kind string, operation admissionv1.Operation, obj, oldObj interface{}) *admissionv1.AdmissionRequest {
+	t.Helper()
+	req := &admissionv1.AdmissionRequest{
+		UID:       types.UID("uid1"),
+		Kind:      metav1.GroupVersionKind{Group: "steward.sap.com", Version: "v1alpha1", Kind: kind},
+		Operation: operation,
+		Namespace: "ns1",
+	}
+	raw, err := json.Marshal(obj)
+	assert.NilError(t, err)
+	req.Object = runtime.RawExtension{Raw: raw}
+	if oldObj != nil {
+		raw, err := json.Marshal(oldObj)
+		assert.NilError(t, err)
+		req.OldObject = runtime.RawExtension{Raw: raw}
+	}
+	return req
+}
+
+func newValidPipelineRun(state api.State) *api.PipelineRun {
+	run := fake.PipelineRun("run1", "ns1", api.PipelineSpec{
+		JenkinsFile: api.JenkinsFile{
+			URL:      "https://github.com/foo/bar",
+			Revision: "master",
+			Path:     "Jenkinsfile",
+		},
+	})
+	run.Status.State = state
+	return run
+}
+
+func Test_Webhook_admit_PipelineRun(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name            string
+		operation       admissionv1.Operation
+		oldState        api.State
+		modify          func(run *api.PipelineRun)
+		expectedAllowed bool
+	}{
+		{"create_valid", admissionv1.Create, "", func(run *api.PipelineRun) {}, true},
+		{"create_known_network_profile", admissionv1.Create, "", func(run *api.PipelineRun) {
+			run.Spec.Profiles = &api.Profiles{Network: "profile1"}
+		}, true},
+		{"create_unknown_network_profile", admissionv1.Create, "", func(run *api.PipelineRun) {
+			run.Spec.Profiles = &api.Profiles{Network: "unknown"}
+		}, false},
+		{"create_invalid_repo_url", admissionv1.Create, "", func(run *api.PipelineRun) {
+			run.Spec.JenkinsFile.URL = "ftp://github.com/foo/bar.git"
+		}, false},
+		{"create_invalid_intent", admissionv1.Create, "", func(run *api.PipelineRun) {
+			run.Spec.Intent = "foo"
+		}, false},
+		{"update_new_spec_changed", admissionv1.Update, api.StateNew, func(run *api.PipelineRun) {
+			run.Spec.Args = map[string]string{"a": "b"}
+		}, true},
+		{"update_new_spec_invalid", admissionv1.Update, api.StateNew, func(run *api.PipelineRun) {
+			run.Spec.Profiles = &api.Profiles{Network: "unknown"}
+		}, false},
+		{"update_running_spec_changed", admissionv1.Update, api.StateRunning, func(run *api.PipelineRun) {
+			run.Spec.Args = map[string]string{"a": "b"}
+		}, false},
+		{"update_running_abort", admissionv1.Update, api.StateRunning, func(run *api.PipelineRun) {
+			run.Spec.Intent = api.IntentAbort
+		}, true},
+		{"update_running_metadata_only", admissionv1.Update, api.StateRunning, func(run *api.PipelineRun) {
+			run.SetFinalizers([]string{"foo"})
+		}, true},
+		{"delete", admissionv1.Delete, api.StateRunning, func(run *api.PipelineRun) {}, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			examinee := newTestWebhook("profile1")
+			var oldRun *api.PipelineRun
+			if tc.operation == admissionv1.Update {
+				oldRun = newValidPipelineRun(tc.oldState)
+			}
+			run := newValidPipelineRun(tc.oldState)
+			tc.modify(run)
+			req := newAdmissionRequest(t, "PipelineRun", tc.operation, run, oldRun)
+
+			// EXERCISE
+			response := examinee.admit(context.Background(), req)
+
+			// VERIFY
+			assert.Equal(t, tc.expectedAllowed, response.Allowed)
+			if !tc.expectedAllowed {
+				assert.Equal(t, int32(http.StatusUnprocessableEntity), response.Result.Code)
+			}
+		})
+	}
+}
+
+func Test_Webhook_admit_PipelineRun_ConfigLoadFails(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee := newTestWebhook()
+	examinee.testing.loadPipelineRunsConfigStub = func(ctx context.Context) (*cfg.PipelineRunsConfigStruct, error) {
+		return nil, errors.New("error1")
+	}
+	run := newValidPipelineRun("")
+	run.Spec.Profiles = &api.Profiles{Network: "unknown"}
+	req := newAdmissionRequest(t, "PipelineRun", admissionv1.Create, run, nil)
+
+	// EXERCISE
+	response := examinee.admit(context.Background(), req)
+
+	// VERIFY
+	assert.Assert(t, response.Allowed)
+	assert.Equal(t, 1, len(response.Warnings))
+}
+
+func Test_Webhook_admit_Tenant(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name            string
+		operation       admissionv1.Operation
+		validationErr   error
+		expectedAllowed bool
+	}{
+		{"create_valid", admissionv1.Create, nil, true},
+		{"create_invalid", admissionv1.Create, errors.New("error1"), false},
+		{"update", admissionv1.Update, errors.New("error1"), true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			examinee := newTestWebhook()
+			var validatedTenant *api.Tenant
+			examinee.testing.validateTenantStub = func(ctx context.Context, tenant *api.Tenant) error {
+				validatedTenant = tenant
+				return tc.validationErr
+			}
+			tenant := &api.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "tenant1"}}
+			req := newAdmissionRequest(t, "Tenant", tc.operation, tenant, tenant)
+
+			// EXERCISE
+			response := examinee.admit(context.Background(), req)
+
+			// VERIFY
+			assert.Equal(t, tc.expectedAllowed, response.Allowed)
+			if tc.operation == admissionv1.Create {
+				assert.Equal(t, "ns1", validatedTenant.GetNamespace())
+			}
+			if !tc.expectedAllowed {
+				assert.Assert(t, is.Contains(response.Result.Message, "error1"))
+			}
+		})
+	}
+}
+
+func Test_Webhook_admit_OtherGroup(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee := newTestWebhook()
+	req := &admissionv1.AdmissionRequest{
+		Kind:      metav1.GroupVersionKind{Group: "", Version: "v1", Kind: "Pod"},
+		Operation: admissionv1.Create,
+	}
+
+	// EXERCISE
+	response := examinee.admit(context.Background(), req)
+
+	// VERIFY
+	assert.Assert(t, response.Allowed)
+}
+
+func Test_Webhook_mutate_PipelineRun(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee := newTestWebhook()
+	examinee.testing.loadPipelineRunsConfigStub = func(ctx context.Context) (*cfg.PipelineRunsConfigStruct, error) {
+		return &cfg.PipelineRunsConfigStruct{
+			DefaultNetworkProfile:  "default1",
+			JenkinsfileRunnerImage: "jfr:1",
+			Timeout:                &metav1.Duration{Duration: time.Hour},
+		}, nil
+	}
+	run := newValidPipelineRun("")
+	run.Spec.JenkinsFile.Path = ""
+	run.Spec.Args = map[string]string{"a/b": "c"}
+	req := newAdmissionRequest(t, "PipelineRun", admissionv1.Create, run, nil)
+
+	// EXERCISE
+	response := examinee.mutate(context.Background(), req)
+
+	// VERIFY
+	assert.Assert(t, response.Allowed)
+	assert.Equal(t, admissionv1.PatchTypeJSONPatch, *response.PatchType)
+	patch, err := jsonpatch.DecodePatch(response.Patch)
+	assert.NilError(t, err)
+	patched, err := patch.Apply(req.Object.Raw)
+	assert.NilError(t, err)
+	result := &api.PipelineRun{}
+	assert.NilError(t, json.Unmarshal(patched, result))
+	assert.DeepEqual(t, api.PipelineSpec{
+		JenkinsFile: api.JenkinsFile{
+			URL:      "https://github.com/foo/bar",
+			Revision: "master",
+			Path:     "Jenkinsfile",
+		},
+		Args:              map[string]string{"a/b": "c"},
+		Intent:            api.IntentRun,
+		Profiles:          &api.Profiles{Network: "default1"},
+		JenkinsfileRunner: &api.JenkinsfileRunnerSpec{Image: "jfr:1", ImagePullPolicy: "IfNotPresent"},
+		Timeout:           &metav1.Duration{Duration: time.Hour},
+	}, result.Spec)
+}
+
+func Test_Webhook_mutate_PipelineRun_TenantSettings(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	tenantNamespace := fake.Namespace("ns1")
+	tenantNamespace.SetLabels(map[string]string{
+		api.LabelOwnerClientNamespace: "client1",
+		api.LabelOwnerTenantName:      "tenant1",
+	})
+	tenant := fake.Tenant("tenant1", "client1")
+	tenant.Spec.PipelineRuns = &api.TenantPipelineRunsSettings{
+		DefaultNetworkProfile:  "tenantDefault1",
+		MaxTimeout:             &metav1.Duration{Duration: 10 * time.Minute},
+		JenkinsfileRunnerImage: "jfr:tenant",
+	}
+	examinee := NewWebhook(fake.NewClientFactory(tenantNamespace, tenant))
+	examinee.testing = &webhookTesting{
+		loadPipelineRunsConfigStub: func(ctx context.Context) (*cfg.PipelineRunsConfigStruct, error) {
+			return &cfg.PipelineRunsConfigStruct{
+				DefaultNetworkProfile:  "default1",
+				JenkinsfileRunnerImage: "jfr:1",
+				Timeout:                &metav1.Duration{Duration: time.Hour},
+			}, nil
+		},
+	}
+	run := newValidPipelineRun("")
+	req := newAdmissionRequest(t, "PipelineRun", admissionv1.Create, run, nil)
+
+	// EXERCISE
+	response := examinee.mutate(context.Background(), req)
+
+	// VERIFY
+	assert.Assert(t, response.Allowed)
+	patch, err := jsonpatch.DecodePatch(response.Patch)
+	assert.NilError(t, err)
+	patched, err := patch.Apply(req.Object.Raw)
+	assert.NilError(t, err)
+	result := &api.PipelineRun{}
+	assert.NilError(t, json.Unmarshal(patched, result))
+	assert.DeepEqual(t, &api.Profiles{Network: "tenantDefault1"}, result.Spec.Profiles)
+	assert.DeepEqual(t, &api.JenkinsfileRunnerSpec{Image: "jfr:tenant", ImagePullPolicy: "IfNotPresent"}, result.Spec.JenkinsfileRunner)
+	assert.DeepEqual(t, &metav1.Duration{Duration: 10 * time.Minute}, result.Spec.Timeout)
+}
+
+func Test_Webhook_mutate_PipelineRun_MinimalObject(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name string
+		spec string
+	}{
+		{"no_spec", ``},
+		{"empty_spec", `,"spec":{}`},
+		{"empty_jenkinsfile", `,"spec":{"jenkinsFile":{}}`},
+		{"url_only", `,"spec":{"jenkinsFile":{"url":"https://github.com/foo/bar"}}`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			examinee := newTestWebhook()
+			examinee.testing.loadPipelineRunsConfigStub = func(ctx context.Context) (*cfg.PipelineRunsConfigStruct, error) {
+				return &cfg.PipelineRunsConfigStruct{
+					Timeout: &metav1.Duration{Duration: time.Hour},
+				}, nil
+			}
+			req := newAdmissionRequest(t, "PipelineRun", admissionv1.Create, nil, nil)
+			req.Object.Raw = []byte(`{"apiVersion":"steward.sap.com/v1alpha1","kind":"PipelineRun","metadata":{"name":"run1","namespace":"ns1"}` + tc.spec + `}`)
+			original := &api.PipelineRun{}
+			assert.NilError(t, json.Unmarshal(req.Object.Raw, original))
+
+			// EXERCISE
+			response := examinee.mutate(context.Background(), req)
+
+			// VERIFY
+			assert.Assert(t, response.Allowed)
+			patch, err := jsonpatch.DecodePatch(response.Patch)
+			assert.NilError(t, err)
+			patched, err := patch.Apply(req.Object.Raw)
+			assert.NilError(t, err)
+			result := &api.PipelineRun{}
+			assert.NilError(t, json.Unmarshal(patched, result))
+			expectedSpec := original.Spec
+			expectedSpec.JenkinsFile.Path = "Jenkinsfile"
+			expectedSpec.Intent = api.IntentRun
+			expectedSpec.Timeout = &metav1.Duration{Duration: time.Hour}
+			assert.DeepEqual(t, expectedSpec, result.Spec)
+		})
+	}
+}
+
+func Test_Webhook_mutate_NothingToDefault(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee := newTestWebhook()
+	run := newValidPipelineRun("")
+	run.Spec.Intent = api.IntentRun
+	run.Spec.Timeout = &metav1.Duration{Duration: time.Minute}
+	req := newAdmissionRequest(t, "PipelineRun", admissionv1.Create, run, nil)
+
+	// EXERCISE
+	response := examinee.mutate(context.Background(), req)
+
+	// VERIFY
+	assert.Assert(t, response.Allowed)
+	assert.Assert(t, response.PatchType == nil)
+	assert.Assert(t, response.Patch == nil)
+}
+
+func Test_Webhook_mutate_AbortRequester(t *testing.T) {
+	t.Parallel()
+
+	requestedBy := func(user string) map[string]string {
+		return map[string]string{api.AnnotationAbortRequestedBy: user}
+	}
+
+	for _, tc := range []struct {
+		name           string
+		oldIntent      api.Intent
+		newIntent      api.Intent
+		oldAnnotations map[string]string
+		annotations    map[string]string
+		expectedPatch  bool
+		expectedResult map[string]string
+	}{
+		{"abort", api.IntentRun, api.IntentAbort, nil, nil, true, requestedBy("user1")},
+		{"abort_overwrites", "", api.IntentAbort, nil, map[string]string{"a/b": "c", api.AnnotationAbortRequestedBy: "other"}, true, map[string]string{"a/b": "c", api.AnnotationAbortRequestedBy: "user1"}},
+		{"already_aborted", api.IntentAbort, api.IntentAbort, nil, nil, false, nil},
+		{"already_aborted_keeps_requester", api.IntentAbort, api.IntentAbort, requestedBy("user0"), requestedBy("other"), true, requestedBy("user0")},
+		{"already_aborted_restores_requester", api.IntentAbort, api.IntentAbort, requestedBy("user0"), nil, true, requestedBy("user0")},
+		{"not_aborted", "", api.IntentRun, nil, nil, false, nil},
+		{"not_aborted_removes_requester", "", api.IntentRun, nil, map[string]string{"a/b": "c", api.AnnotationAbortRequestedBy: "other"}, true, map[string]string{"a/b": "c"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			examinee := newTestWebhook()
+			oldRun := newValidPipelineRun(api.StateRunning)
+			oldRun.Spec.Intent = tc.oldIntent
+			oldRun.Annotations = tc.oldAnnotations
+			run := oldRun.DeepCopy()
+			run.Spec.Intent = tc.newIntent
+			run.Annotations = tc.annotations
+			req := newAdmissionRequest(t, "PipelineRun", admissionv1.Update, run, oldRun)
+			req.UserInfo.Username = "user1"
+
+			// EXERCISE
+			response := examinee.mutate(context.Background(), req)
+
+			// VERIFY
+			assert.Assert(t, response.Allowed)
+			if !tc.expectedPatch {
+				assert.Assert(t, response.Patch == nil)
+				return
+			}
+			patch, err := jsonpatch.DecodePatch(response.Patch)
+			assert.NilError(t, err)
+			patched, err := patch.Apply(req.Object.Raw)
+			assert.NilError(t, err)
+			result := &api.PipelineRun{}
+			assert.NilError(t, json.Unmarshal(patched, result))
+			assert.DeepEqual(t, tc.expectedResult, result.Annotations)
+			assert.DeepEqual(t, run.Spec, result.Spec)
+		})
+	}
+}
+
+func Test_Webhook_mutate_AbortRequester_Create(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name           string
+		intent         api.Intent
+		annotations    map[string]string
+		expectedResult map[string]string
+	}{
+		{"abort", api.IntentAbort, nil, map[string]string{api.AnnotationAbortRequestedBy: "user1"}},
+		{"abort_overwrites", api.IntentAbort, map[string]string{api.AnnotationAbortRequestedBy: "other"}, map[string]string{api.AnnotationAbortRequestedBy: "user1"}},
+		{"run_removes_requester", api.IntentRun, map[string]string{"a/b": "c", api.AnnotationAbortRequestedBy: "other"}, map[string]string{"a/b": "c"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			examinee := newTestWebhook()
+			run := newValidPipelineRun("")
+			run.Spec.Intent = tc.intent
+			run.Spec.Timeout = &metav1.Duration{Duration: time.Minute}
+			run.Annotations = tc.annotations
+			req := newAdmissionRequest(t, "PipelineRun", admissionv1.Create, run, nil)
+			req.UserInfo.Username = "user1"
+
+			// EXERCISE
+			response := examinee.mutate(context.Background(), req)
+
+			// VERIFY
+			assert.Assert(t, response.Allowed)
+			patch, err := jsonpatch.DecodePatch(response.Patch)
+			assert.NilError(t, err)
+			patched, err := patch.Apply(req.Object.Raw)
+			assert.NilError(t, err)
+			result := &api.PipelineRun{}
+			assert.NilError(t, json.Unmarshal(patched, result))
+			assert.DeepEqual(t, tc.expectedResult, result.Annotations)
+			assert.DeepEqual(t, run.Spec, result.Spec)
+		})
+	}
+}
+
+func Test_Webhook_mutate_Ignored(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name      string
+		kind      string
+		operation admissionv1.Operation
+	}{
+		{"update", "PipelineRun", admissionv1.Update},
+		{"tenant", "Tenant", admissionv1.Create},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			examinee := newTestWebhook()
+			run := newValidPipelineRun("")
+			req := newAdmissionRequest(t, tc.kind, tc.operation, run, run)
+
+			// EXERCISE
+			response := examinee.mutate(context.Background(), req)
+
+			// VERIFY
+			assert.Assert(t, response.Allowed)
+			assert.Assert(t, response.Patch == nil)
+		})
+	}
+}
+
+func Test_Webhook_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee := newTestWebhook()
+	run := newValidPipelineRun("")
+	run.Spec.Intent = "foo"
+	review := &admissionv1.AdmissionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: "admission.k8s.io/v1", Kind: "AdmissionReview"},
+		Request:  newAdmissionRequest(t, "PipelineRun", admissionv1.Create, run, nil),
+	}
+	body, err := json.Marshal(review)
+	assert.NilError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/validate", bytes.NewReader(body))
+	recorder := httptest.NewRecorder()
+
+	// EXERCISE
+	examinee.ServeHTTP(recorder, req)
+
+	// VERIFY
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	result := &admissionv1.AdmissionReview{}
+	assert.NilError(t, json.Unmarshal(recorder.Body.Bytes(), result))
+	assert.Equal(t, "AdmissionReview", result.Kind)
+	assert.Assert(t, result.Request == nil)
+	assert.Equal(t, types.UID("uid1"), result.Response.UID)
+	assert.Assert(t, !result.Response.Allowed)
+	assert.Assert(t, is.Contains(result.Response.Result.Message, `field "spec.intent" has invalid value "foo"`))
+}
+
+func Test_Webhook_ServeHTTP_BadRequest(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name         string
+		method       string
+		body         string
+		expectedCode int
+	}{
+		{"wrong_method", http.MethodGet, "", http.StatusMethodNotAllowed},
+		{"invalid_json", http.MethodPost, "{", http.StatusBadRequest},
+		{"no_request", http.MethodPost, "{}", http.StatusBadRequest},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			examinee := newTestWebhook()
+			req := httptest.NewRequest(tc.method, "/validate", bytes.NewReader([]byte(tc.body)))
+			recorder := httptest.NewRecorder()
+
+			// EXERCISE
+			examinee.ServeHTTP(recorder, req)
+
+			// VERIFY
+			assert.Equal(t, tc.expectedCode, recorder.Code)
+		})
+	}
+}
@@ -798,6 +798,88 @@ func Test_CRDs_PipelineRun_Schema_Spec(t *testing.T) {
 			},
 		},
 
+		/////////////////////////////////////////////////////////////////
+		// spec.secretRefs
+		/////////////////////////////////////////////////////////////////
+
+		{
+			name: "spec.secretRefs filled",
+			spec: fixIndent(`
+				spec:
+					secretRefs:
+						- name: secret1
+						- name: secret2
+						  targetName: target2
+						  keys:
+							- key: key1
+							  targetKey: key2
+						  optional: true
+					jenkinsFile:
+						repoUrl: repoUrl1
+						revision: revision1
+						relativePath: relativePath1
+			`),
+			check: func(t *testing.T, result *stewardv1alpha1.PipelineRun, resultErr error) {
+				assert.NilError(t, resultErr)
+				assert.DeepEqual(t, []stewardv1alpha1.PipelineSecret{
+					{Name: "secret1"},
+					{Name: "secret2", TargetName: "target2", Keys: []stewardv1alpha1.PipelineSecretKey{{Key: "key1", TargetKey: "key2"}}, Optional: true},
+				}, result.Spec.SecretRefs)
+			},
+		},
+
+		{
+			name: "spec.secretRefs.* without name",
+			spec: fixIndent(`
+				spec:
+					secretRefs:
+						- targetName: target1
+					jenkinsFile:
+						repoUrl: repoUrl1
+						revision: revision1
+						relativePath: relativePath1
+			`),
+			check: func(t *testing.T, result *stewardv1alpha1.PipelineRun, resultErr error) {
+				assert.Assert(t, resultErr != nil)
+				assert.Assert(t, errorContainsToken(resultErr, "spec.secretRefs[0].name"))
+			},
+		},
+
+		{
+			name: "spec.secretRefs.* invalid type",
+			spec: fixIndent(`
+				spec:
+					secretRefs:
+						- secret1  # not allowed
+					jenkinsFile:
+						repoUrl: repoUrl1
+						revision: revision1
+						relativePath: relativePath1
+			`),
+			check: func(t *testing.T, result *stewardv1alpha1.PipelineRun, resultErr error) {
+				assert.Assert(t, resultErr != nil)
+				assert.Assert(t, errorContainsToken(resultErr, "spec.secretRefs"))
+			},
+		},
+
+		{
+			name: "spec.secretRefs.*.keys invalid type",
+			spec: fixIndent(`
+				spec:
+					secretRefs:
+						- name: secret1
+						  keys: "key1"  # not allowed
+					jenkinsFile:
+						repoUrl: repoUrl1
+						revision: revision1
+						relativePath: relativePath1
+			`),
+			check: func(t *testing.T, result *stewardv1alpha1.PipelineRun, resultErr error) {
+				assert.Assert(t, resultErr != nil)
+				assert.Assert(t, errorContainsToken(resultErr, "spec.secretRefs[0].keys"))
+			},
+		},
+
 		/////////////////////////////////////////////////////////////////
 		// spec.imagePullSecrets
 		/////////////////////////////////////////////////////////////////